@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/ownership"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/report"
+)
+
+var (
+	compareFormat        string
+	compareOutputFile    string
+	compareHTMLCSPNonce  string
+	compareHTMLCSPHashes bool
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <base.json> <head.json>",
+	Short: "Compare two JSON reports",
+	Long: `Compare two JSON reports, e.g. of the base branch and a pull request build.
+
+The html format is a standalone page with a treemap colored by growth and shrinkage,
+the largest file changes, category sizes and new or resolved optimizations.
+The markdown format is the compact summary posted by the publish command.
+
+Both formats list size changes by owner when both reports have sizes by owner,
+or when an ownership file is given with --owners.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCompare,
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	compareCmd.Flags().StringVarP(&compareFormat, "output", "o", "html",
+		"Output format: html or markdown")
+	compareCmd.Flags().StringVarP(&compareOutputFile, "output-file", "f", "",
+		"Output filename (default: bundle-comparison.<ext>)")
+	compareCmd.Flags().StringVar(&compareHTMLCSPNonce, "html-csp-nonce", "",
+		"Add this nonce to all inline scripts and styles of the HTML report, with a matching Content-Security-Policy")
+	compareCmd.Flags().BoolVar(&compareHTMLCSPHashes, "html-csp-hashes", false,
+		"Add a Content-Security-Policy allowing the inline scripts and styles of the HTML report by hash")
+	compareCmd.Flags().StringVar(&ownersFile, "owners", "",
+		"CODEOWNERS-style file mapping artifact paths to teams, for size changes by owner")
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	if compareFormat != "html" && compareFormat != "markdown" {
+		return fmt.Errorf("unsupported comparison format: %s (supported: html, markdown)", compareFormat)
+	}
+
+	baseline, err := loadJSONReport(args[0])
+	if err != nil {
+		return err
+	}
+	head, err := loadJSONReport(args[1])
+	if err != nil {
+		return err
+	}
+
+	owners, err := loadOwners(ownersFile)
+	if err != nil {
+		return err
+	}
+	if owners != nil {
+		baseline.Ownership = ownership.Attribute(baseline, owners)
+		head.Ownership = ownership.Attribute(head, owners)
+	}
+
+	filename := compareOutputFile
+	if filename == "" {
+		filename = "bundle-comparison." + getFileExtension(compareFormat)
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer f.Close()
+
+	if compareFormat == "html" {
+		formatter := report.NewHTMLDiffFormatter(baseline)
+		formatter.CSPNonce = compareHTMLCSPNonce
+		formatter.CSPHashes = compareHTMLCSPHashes
+		err = formatter.Format(f, head)
+	} else {
+		err = report.NewCompactMarkdownFormatter(baseline).Format(f, head)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to format comparison: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "✓ Comparison report: %s\n", filename)
+	return nil
+}
@@ -11,9 +11,12 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/ios"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/bitrise"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/orchestrator"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/ownership"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/report"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/sdk"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
 )
 
@@ -29,6 +32,20 @@ var (
 	includeDuplicates     bool
 	filterSmallDuplicates bool
 	noAutoDetect          bool
+	envPrefix             string
+	allArtifacts          bool
+	deviceSpecFiles       []string
+	htmlOnline            bool
+	htmlCSPNonce          string
+	htmlCSPHashes         bool
+	ownersFile            string
+	sdkSignaturesFile     string
+	lockfiles             []string
+	csvColumns            string
+	csvColumnList         []string // Parsed csvColumns
+	sizeViewName          string
+	sizeView              report.SizeView // Parsed sizeViewName
+	supportedLocales      string          // Comma-separated allow-list of locales
 )
 
 func main() {
@@ -53,8 +70,13 @@ var analyzeCmd = &cobra.Command{
 generate a detailed size breakdown with optimization recommendations.
 
 If no file path is provided, the tool will auto-detect the bundle path from
-Bitrise environment variables (BITRISE_IPA_PATH, BITRISE_AAB_PATH, BITRISE_APK_PATH).`,
-	Args: cobra.MaximumNArgs(1),
+Bitrise environment variables (BITRISE_IPA_PATH, BITRISE_AAB_PATH, BITRISE_APK_PATH).
+
+With --all-artifacts (or several file paths), every artifact is analyzed in one run:
+per-artifact reports are written along with an index report that summarizes them
+side by side. Auto-detection then also reads BITRISE_APK_PATH_LIST,
+BITRISE_AAB_PATH_LIST, BITRISE_XCARCHIVE_PATH and BITRISE_APP_DIR_PATH.`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runAnalyze,
 }
 
@@ -75,15 +97,39 @@ func init() {
 
 	// Add flags
 	analyzeCmd.Flags().StringVarP(&outputFormats, "output", "o", "text",
-		"Output format(s) - comma-separated for multiple (text, json, markdown, html)")
+		"Output format(s) - comma-separated for multiple (text, json, markdown, html, csv, tsv)")
 	analyzeCmd.Flags().StringVarP(&outputFiles, "output-file", "f", "",
 		"Output filename(s) - comma-separated when using multiple formats (default: auto-generated)")
 	analyzeCmd.Flags().BoolVar(&includeDuplicates, "include-duplicates", true,
-		"Enable duplicate file detection")
+		"Enable duplicate file detection, including symbol-level duplicate code in iOS binaries")
 	analyzeCmd.Flags().BoolVar(&filterSmallDuplicates, "filter-small-duplicates", true,
 		"Filter out duplicate files at or below 4KB (filesystem block size)")
 	analyzeCmd.Flags().BoolVar(&noAutoDetect, "no-auto-detect", false,
 		"Disable auto-detection of bundle path from Bitrise environment")
+	analyzeCmd.Flags().StringVar(&envPrefix, "env-prefix", bitrise.DefaultEnvPrefix,
+		"Name prefix for environment variables exported through envman on Bitrise")
+	analyzeCmd.Flags().BoolVar(&allArtifacts, "all-artifacts", false,
+		"Analyze every detected artifact and generate an index report")
+	analyzeCmd.Flags().StringArrayVar(&deviceSpecFiles, "device-spec", nil,
+		"bundletool device spec JSON file for AAB download size estimation (repeatable, default: built-in device matrix)")
+	analyzeCmd.Flags().BoolVar(&htmlOnline, "html-online", false,
+		"Load HTML report styles and the ECharts library from CDNs instead of embedding them")
+	analyzeCmd.Flags().StringVar(&htmlCSPNonce, "html-csp-nonce", "",
+		"Nonce for inline scripts and styles in the HTML report, added with a matching Content-Security-Policy")
+	analyzeCmd.Flags().BoolVar(&htmlCSPHashes, "html-csp-hashes", false,
+		"Add a Content-Security-Policy to the HTML report that allows its inline scripts and styles by hash")
+	analyzeCmd.Flags().StringVar(&ownersFile, "owners", "",
+		"CODEOWNERS-style file mapping artifact paths to teams, for sizes by owner")
+	analyzeCmd.Flags().StringVar(&sdkSignaturesFile, "sdk-signatures", "",
+		"JSON file of library signatures for Android SDK sizes, added to and overriding the built-in ones")
+	analyzeCmd.Flags().StringArrayVar(&lockfiles, "lockfile", nil,
+		"Package.resolved, Podfile.lock or Cartfile.resolved naming and versioning iOS dependencies (repeatable)")
+	analyzeCmd.Flags().StringVar(&csvColumns, "csv-columns", "",
+		"File tree columns of the csv and tsv formats, comma-separated (default: all)")
+	analyzeCmd.Flags().StringVar(&sizeViewName, "size-view", string(report.SizeViewInstall),
+		"File sizes shown by the text, markdown and html formats: install (uncompressed) or download (compressed)")
+	analyzeCmd.Flags().StringVar(&supportedLocales, "supported-locales", "",
+		"Comma-separated locales the app supports, e.g. en,de,pt-BR; other locales are reported as removable")
 }
 
 // parseFormats parses and validates comma-separated output formats
@@ -94,6 +140,8 @@ func parseFormats(formatsStr string) ([]string, error) {
 		"json":     true,
 		"markdown": true,
 		"html":     true,
+		"csv":      true,
+		"tsv":      true,
 	}
 
 	var result []string
@@ -107,7 +155,7 @@ func parseFormats(formatsStr string) ([]string, error) {
 
 		// Check if valid
 		if !validFormats[format] {
-			return nil, fmt.Errorf("unsupported output format: %s (valid formats: text, json, markdown, html)", format)
+			return nil, fmt.Errorf("unsupported output format: %s (valid formats: text, json, markdown, html, csv, tsv)", format)
 		}
 
 		// Check for duplicates
@@ -126,6 +174,21 @@ func parseFormats(formatsStr string) ([]string, error) {
 	return result, nil
 }
 
+// parseFormatterOptions parses and validates the --csv-columns and --size-view values
+func parseFormatterOptions(columns, view string) ([]string, report.SizeView, error) {
+	columnList, err := report.ParseCSVColumns(columns)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sizeView, err := report.ParseSizeView(view)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return columnList, sizeView, nil
+}
+
 // parseOutputFiles parses comma-separated output filenames
 func parseOutputFiles(filesStr string) []string {
 	if filesStr == "" {
@@ -143,6 +206,68 @@ func parseOutputFiles(filesStr string) []string {
 	return result
 }
 
+// loadDeviceSpecs reads bundletool-format device spec JSON files.
+// Specs without a name are named after their file.
+func loadDeviceSpecs(paths []string) ([]types.DeviceSpec, error) {
+	var specs []types.DeviceSpec
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read device spec: %w", err)
+		}
+
+		var spec types.DeviceSpec
+		if err := json.Unmarshal(data, &spec); err != nil {
+			return nil, fmt.Errorf("failed to parse device spec %s: %w", path, err)
+		}
+		if spec.Name == "" {
+			spec.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// parseSupportedLocales splits the comma-separated --supported-locales value
+func parseSupportedLocales(value string) []string {
+	var locales []string
+	for _, locale := range strings.Split(value, ",") {
+		if locale = strings.TrimSpace(locale); locale != "" {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// loadOwners reads the ownership file, if one is given
+func loadOwners(filename string) (*ownership.Rules, error) {
+	if filename == "" {
+		return nil, nil
+	}
+	return ownership.Load(filename)
+}
+
+// loadLockfiles reads the dependencies of iOS lockfiles
+func loadLockfiles(paths []string) ([]ios.LockedDependency, error) {
+	var dependencies []ios.LockedDependency
+	for _, path := range paths {
+		locked, err := ios.LoadLockfile(path)
+		if err != nil {
+			return nil, err
+		}
+		dependencies = append(dependencies, locked...)
+	}
+	return dependencies, nil
+}
+
+// loadSDKSignatures reads the SDK signature file, if one is given
+func loadSDKSignatures(filename string) (*sdk.Database, error) {
+	if filename == "" {
+		return nil, nil
+	}
+	return sdk.Load(filename)
+}
+
 // getFileExtension returns the appropriate extension for a format
 func getFileExtension(format string) string {
 	switch format {
@@ -154,11 +279,22 @@ func getFileExtension(format string) string {
 		return "txt"
 	case "html":
 		return "html"
+	case "csv":
+		return "csv"
+	case "tsv":
+		return "tsv"
 	default:
 		return "txt"
 	}
 }
 
+// optimizationsFilename returns the name of the optimizations file written next to a
+// csv or tsv report, e.g. report-optimizations.csv for report.csv
+func optimizationsFilename(filename string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "-optimizations" + ext
+}
+
 // detectArtifactPath determines the artifact path from arguments or auto-detection
 func detectArtifactPath(args []string) (string, error) {
 	if len(args) > 0 {
@@ -202,14 +338,17 @@ func determineOutputFiles(artifactPath string, formats []string, explicitFiles [
 
 	var filenames []string
 	for _, format := range formats {
-		extension := getFileExtension(format)
-		filename := fmt.Sprintf("bundle-analysis-%s.%s", artifactName, extension)
-		filenames = append(filenames, filename)
+		filenames = append(filenames, defaultOutputFilename(artifactName, format))
 	}
 
 	return filenames, nil
 }
 
+// defaultOutputFilename returns the auto-generated report filename for an artifact name
+func defaultOutputFilename(artifactName string, format string) string {
+	return fmt.Sprintf("bundle-analysis-%s.%s", artifactName, getFileExtension(format))
+}
+
 // writeReport writes the analysis report to a file using the specified format
 func writeReport(filename string, format string, analysisReport *types.Report) error {
 	f, err := os.Create(filename)
@@ -221,6 +360,7 @@ func writeReport(filename string, format string, analysisReport *types.Report) e
 	switch format {
 	case "text":
 		formatter := report.NewTextFormatter()
+		formatter.SizeView = sizeView
 		if err := formatter.Format(f, analysisReport); err != nil {
 			return fmt.Errorf("failed to format output: %w", err)
 		}
@@ -231,14 +371,31 @@ func writeReport(filename string, format string, analysisReport *types.Report) e
 		}
 	case "markdown":
 		formatter := report.NewMarkdownFormatter()
+		formatter.SizeView = sizeView
 		if err := formatter.Format(f, analysisReport); err != nil {
 			return fmt.Errorf("failed to format output: %w", err)
 		}
 	case "html":
 		formatter := report.NewHTMLFormatter()
+		formatter.Offline = !htmlOnline
+		formatter.CSPNonce = htmlCSPNonce
+		formatter.CSPHashes = htmlCSPHashes
+		formatter.SizeView = sizeView
+		if err := formatter.Format(f, analysisReport); err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+	case "csv", "tsv":
+		formatter := report.NewCSVFormatter()
+		if format == "tsv" {
+			formatter = report.NewTSVFormatter()
+		}
+		formatter.Columns = csvColumnList
 		if err := formatter.Format(f, analysisReport); err != nil {
 			return fmt.Errorf("failed to format output: %w", err)
 		}
+		if err := writeOptimizationsCSV(optimizationsFilename(filename), formatter, analysisReport); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unsupported output format: %s", format)
 	}
@@ -246,7 +403,25 @@ func writeReport(filename string, format string, analysisReport *types.Report) e
 	return nil
 }
 
+// writeOptimizationsCSV writes the optimizations of a csv or tsv report to their own file
+func writeOptimizationsCSV(filename string, formatter *report.CSVFormatter, analysisReport *types.Report) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create optimizations file: %w", err)
+	}
+	defer f.Close()
+
+	if err := formatter.FormatOptimizations(f, analysisReport); err != nil {
+		return fmt.Errorf("failed to format optimizations: %w", err)
+	}
+	return nil
+}
+
 func runAnalyze(cmd *cobra.Command, args []string) error {
+	if allArtifacts || len(args) > 1 {
+		return runMultiAnalyze(args)
+	}
+
 	// Determine artifact path
 	artifactPath, err := detectArtifactPath(args)
 	if err != nil {
@@ -259,6 +434,12 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Validated here so that invalid columns and size views fail before the analysis
+	csvColumnList, sizeView, err = parseFormatterOptions(csvColumns, sizeViewName)
+	if err != nil {
+		return err
+	}
+
 	// Parse explicit output filenames (if provided)
 	explicitFiles := parseOutputFiles(outputFiles)
 
@@ -268,10 +449,35 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	deviceSpecs, err := loadDeviceSpecs(deviceSpecFiles)
+	if err != nil {
+		return err
+	}
+
+	owners, err := loadOwners(ownersFile)
+	if err != nil {
+		return err
+	}
+
+	sdkSignatures, err := loadSDKSignatures(sdkSignaturesFile)
+	if err != nil {
+		return err
+	}
+
+	lockedDependencies, err := loadLockfiles(lockfiles)
+	if err != nil {
+		return err
+	}
+
 	// Create orchestrator and run analysis
 	orch := orchestrator.New()
 	orch.IncludeDuplicates = includeDuplicates
 	orch.FilterSmallDuplicates = filterSmallDuplicates
+	orch.DeviceSpecs = deviceSpecs
+	orch.Owners = owners
+	orch.SDKSignatures = sdkSignatures
+	orch.LockedDependencies = lockedDependencies
+	orch.SupportedLocales = parseSupportedLocales(supportedLocales)
 
 	fmt.Fprintf(os.Stderr, "Analyzing %s...\n", artifactPath)
 	if includeDuplicates {
@@ -286,39 +492,68 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 
 	// Write reports for all formats
 	fmt.Fprintf(os.Stderr, "\nGenerating reports:\n")
+	reportPaths := make(map[string]string)
 	for i, format := range formats {
 		filename := filenames[i]
 		if err := writeReport(filename, format, analysisReport); err != nil {
 			return fmt.Errorf("failed to write %s report: %w", format, err)
 		}
 		fmt.Fprintf(os.Stderr, "  ✓ %s: %s\n", strings.ToUpper(format), filename)
+		if absPath, err := filepath.Abs(filename); err == nil {
+			reportPaths[format] = absPath
+		}
 	}
 
 	// Export to Bitrise deploy directory if in Bitrise environment
 	if bitrise.IsBitriseEnvironment() {
-		if err := exportToBitrise(analysisReport); err != nil {
+		jsonPath, err := exportToBitrise(analysisReport)
+		if err != nil {
 			// Log warning but don't fail
 			fmt.Fprintf(os.Stderr, "Warning: failed to export to Bitrise deploy directory: %v\n", err)
 		}
+		if jsonPath != "" {
+			reportPaths["json"] = jsonPath
+		}
+
+		if err := exportEnvVars(analysisReport, reportPaths); err != nil {
+			// Log warning but don't fail
+			fmt.Fprintf(os.Stderr, "Warning: failed to export environment variables: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// exportEnvVars exports key metrics as environment variables through envman
+func exportEnvVars(analysisReport *types.Report, reportPaths map[string]string) error {
+	metrics := bitrise.NewKeyMetrics(analysisReport, reportPaths["json"], reportPaths["html"])
+	vars, err := bitrise.ExportKeyMetrics(envPrefix, metrics)
+	if err != nil {
+		return err
 	}
 
+	fmt.Fprintf(os.Stderr, "✓ Exported %d environment variables:\n", len(vars))
+	for _, v := range vars {
+		fmt.Fprintf(os.Stderr, "  %s\n", v.Key)
+	}
 	return nil
 }
 
 // exportJSONReport exports the report as JSON to the Bitrise deploy directory
-func exportJSONReport(analysisReport *types.Report) error {
+// Returns the exported file path or error
+func exportJSONReport(analysisReport *types.Report) (string, error) {
 	jsonData, err := json.MarshalIndent(analysisReport, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
 	jsonPath, err := bitrise.WriteToDeployDir("bundle-analysis.json", jsonData)
 	if err != nil {
-		return fmt.Errorf("failed to write JSON report: %w", err)
+		return "", fmt.Errorf("failed to write JSON report: %w", err)
 	}
 
 	fmt.Fprintf(os.Stderr, "✓ Exported JSON report to: %s\n", jsonPath)
-	return nil
+	return jsonPath, nil
 }
 
 // exportTextReport exports the report as text to the Bitrise deploy directory
@@ -330,6 +565,7 @@ func exportTextReport(analysisReport *types.Report) error {
 	defer os.Remove(tmpFile.Name())
 
 	formatter := report.NewTextFormatter()
+	formatter.SizeView = sizeView
 	if err := formatter.Format(tmpFile, analysisReport); err != nil {
 		return fmt.Errorf("failed to format text report: %w", err)
 	}
@@ -354,6 +590,7 @@ func exportMarkdownReport(analysisReport *types.Report) {
 	defer os.Remove(tmpFile.Name())
 
 	formatter := report.NewMarkdownFormatter()
+	formatter.SizeView = sizeView
 	if err := formatter.Format(tmpFile, analysisReport); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to format markdown report: %v\n", err)
 		return
@@ -370,20 +607,22 @@ func exportMarkdownReport(analysisReport *types.Report) {
 }
 
 // exportToBitrise exports analysis results to Bitrise deploy directory
-func exportToBitrise(analysisReport *types.Report) error {
+// Returns the exported JSON report path or error
+func exportToBitrise(analysisReport *types.Report) (string, error) {
 	metadata := bitrise.GetBuildMetadata()
 	if metadata.DeployDir == "" {
-		return nil // Not in Bitrise or BITRISE_DEPLOY_DIR not set
+		return "", nil // Not in Bitrise or BITRISE_DEPLOY_DIR not set
 	}
 
 	// Export JSON report
-	if err := exportJSONReport(analysisReport); err != nil {
-		return err
+	jsonPath, err := exportJSONReport(analysisReport)
+	if err != nil {
+		return "", err
 	}
 
 	// Export text report
 	if err := exportTextReport(analysisReport); err != nil {
-		return err
+		return jsonPath, err
 	}
 
 	// Export markdown report (best-effort, don't fail on errors)
@@ -398,5 +637,5 @@ func exportToBitrise(analysisReport *types.Report) error {
 		fmt.Fprintf(os.Stderr, "\n")
 	}
 
-	return nil
+	return jsonPath, nil
 }
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/bitrise"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/orchestrator"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/report"
+)
+
+// indexBaseName is the file name (without extension) of the multi-artifact index report
+const indexBaseName = "bundle-analysis-index"
+
+// detectArtifactPaths determines all artifact paths from arguments or auto-detection
+func detectArtifactPaths(args []string) ([]string, error) {
+	if len(args) > 0 {
+		for _, artifactPath := range args {
+			if _, err := os.Stat(artifactPath); err != nil {
+				return nil, fmt.Errorf("artifact not found: %w", err)
+			}
+		}
+		return args, nil
+	}
+
+	if noAutoDetect {
+		return nil, fmt.Errorf("no artifact path provided\n\nUsage: bundle-inspector analyze --all-artifacts <file-path>...")
+	}
+
+	detectedPaths, err := bitrise.DetectBundlePaths()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"no artifact path provided and auto-detection failed: %w\n\n"+
+				"Usage: bundle-inspector analyze --all-artifacts <file-path>...", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Auto-detected %d artifact(s) from Bitrise environment:\n", len(detectedPaths))
+	for _, path := range detectedPaths {
+		fmt.Fprintf(os.Stderr, "  - %s\n", path)
+	}
+	return detectedPaths, nil
+}
+
+// uniqueArtifactNames returns a report name per artifact path. Artifacts sharing a
+// file name (e.g. app-release.apk of two flavors) get the first numeric suffix that
+// is not the name of another artifact.
+func uniqueArtifactNames(paths []string) []string {
+	baseNames := make([]string, len(paths))
+	taken := make(map[string]bool)
+	for i, path := range paths {
+		name := filepath.Base(path)
+		baseNames[i] = strings.TrimSuffix(name, filepath.Ext(name))
+		taken[baseNames[i]] = true
+	}
+
+	names := make([]string, len(paths))
+	assigned := make(map[string]bool)
+	for i, name := range baseNames {
+		if assigned[name] {
+			candidate := name
+			for n := 2; taken[candidate]; n++ {
+				candidate = fmt.Sprintf("%s-%d", name, n)
+			}
+			name = candidate
+			taken[name] = true
+		}
+		assigned[name] = true
+		names[i] = name
+	}
+
+	return names
+}
+
+// indexFormats returns the index report formats for the requested output formats.
+// The index is written in markdown and/or HTML; markdown is used when neither is requested.
+func indexFormats(formats []string) []string {
+	var result []string
+	for _, format := range formats {
+		if format == "markdown" || format == "html" {
+			result = append(result, format)
+		}
+	}
+	if len(result) == 0 {
+		result = []string{"markdown"}
+	}
+	return result
+}
+
+// writeIndexReport writes the multi-artifact index to a file using the specified format
+func writeIndexReport(filename string, format string, entries []report.IndexEntry) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer f.Close()
+
+	switch format {
+	case "markdown":
+		formatter := report.NewMarkdownIndexFormatter()
+		if err := formatter.Format(f, entries); err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+	case "html":
+		formatter := report.NewHTMLIndexFormatter()
+		if err := formatter.Format(f, entries); err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported index format: %s", format)
+	}
+
+	return nil
+}
+
+func runMultiAnalyze(args []string) error {
+	artifactPaths, err := detectArtifactPaths(args)
+	if err != nil {
+		return err
+	}
+
+	formats, err := parseFormats(outputFormats)
+	if err != nil {
+		return err
+	}
+
+	csvColumnList, sizeView, err = parseFormatterOptions(csvColumns, sizeViewName)
+	if err != nil {
+		return err
+	}
+
+	if outputFiles != "" {
+		return fmt.Errorf("--output-file is not supported when analyzing multiple artifacts")
+	}
+
+	deviceSpecs, err := loadDeviceSpecs(deviceSpecFiles)
+	if err != nil {
+		return err
+	}
+
+	owners, err := loadOwners(ownersFile)
+	if err != nil {
+		return err
+	}
+
+	sdkSignatures, err := loadSDKSignatures(sdkSignaturesFile)
+	if err != nil {
+		return err
+	}
+
+	lockedDependencies, err := loadLockfiles(lockfiles)
+	if err != nil {
+		return err
+	}
+
+	orch := orchestrator.New()
+	orch.IncludeDuplicates = includeDuplicates
+	orch.FilterSmallDuplicates = filterSmallDuplicates
+	orch.DeviceSpecs = deviceSpecs
+	orch.Owners = owners
+	orch.SDKSignatures = sdkSignatures
+	orch.LockedDependencies = lockedDependencies
+	orch.SupportedLocales = parseSupportedLocales(supportedLocales)
+
+	fmt.Fprintf(os.Stderr, "Analyzing %d artifacts...\n", len(artifactPaths))
+	results := orch.RunMultiAnalysis(context.Background(), artifactPaths)
+	names := uniqueArtifactNames(artifactPaths)
+
+	// Show full paths in the index when artifacts share a file name
+	baseCounts := make(map[string]int)
+	for _, path := range artifactPaths {
+		baseCounts[filepath.Base(path)]++
+	}
+
+	fmt.Fprintf(os.Stderr, "\nGenerating reports:\n")
+	entries := make([]report.IndexEntry, 0, len(results))
+	failed := 0
+	for i, result := range results {
+		entry := report.IndexEntry{
+			Name:        filepath.Base(result.Path),
+			ReportFiles: make(map[string]string),
+		}
+		if baseCounts[entry.Name] > 1 {
+			entry.Name = result.Path
+		}
+
+		if result.Err != nil {
+			failed++
+			entry.Error = result.Err.Error()
+			fmt.Fprintf(os.Stderr, "  ✗ %s: %v\n", entry.Name, result.Err)
+			entries = append(entries, entry)
+			continue
+		}
+		entry.Report = result.Report
+
+		for _, format := range formats {
+			filename := defaultOutputFilename(names[i], format)
+			if err := writeReport(filename, format, result.Report); err != nil {
+				return fmt.Errorf("failed to write %s report for %s: %w", format, entry.Name, err)
+			}
+			entry.ReportFiles[format] = filename
+			fmt.Fprintf(os.Stderr, "  ✓ %s %s: %s\n", entry.Name, strings.ToUpper(format), filename)
+			if format == "csv" || format == "tsv" {
+				optimizations := optimizationsFilename(filename)
+				entry.ReportFiles[format+"-optimizations"] = optimizations
+				fmt.Fprintf(os.Stderr, "  ✓ %s %s optimizations: %s\n", entry.Name, strings.ToUpper(format), optimizations)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	var indexFiles []string
+	for _, format := range indexFormats(formats) {
+		filename := fmt.Sprintf("%s.%s", indexBaseName, getFileExtension(format))
+		if err := writeIndexReport(filename, format, entries); err != nil {
+			return fmt.Errorf("failed to write %s index: %w", format, err)
+		}
+		indexFiles = append(indexFiles, filename)
+		fmt.Fprintf(os.Stderr, "  ✓ Index %s: %s\n", strings.ToUpper(format), filename)
+	}
+
+	// Export to Bitrise deploy directory if in Bitrise environment
+	if bitrise.IsBitriseEnvironment() {
+		jsonPaths, err := exportMultiToBitrise(entries, names, indexFiles)
+		if err != nil {
+			// Log warning but don't fail
+			fmt.Fprintf(os.Stderr, "Warning: failed to export to Bitrise deploy directory: %v\n", err)
+		}
+
+		if err := exportMultiEnvVars(entries, names, jsonPaths); err != nil {
+			// Log warning but don't fail
+			fmt.Fprintf(os.Stderr, "Warning: failed to export environment variables: %v\n", err)
+		}
+	}
+
+	if failed == len(results) {
+		return fmt.Errorf("analysis failed for all %d artifacts", failed)
+	}
+
+	return nil
+}
+
+// exportMultiToBitrise exports per-artifact JSON reports and the index reports
+// to the Bitrise deploy directory. Returns the JSON report path per entry.
+func exportMultiToBitrise(entries []report.IndexEntry, names []string, indexFiles []string) ([]string, error) {
+	jsonPaths := make([]string, len(entries))
+
+	metadata := bitrise.GetBuildMetadata()
+	if metadata.DeployDir == "" {
+		return jsonPaths, nil // Not in Bitrise or BITRISE_DEPLOY_DIR not set
+	}
+
+	for i, entry := range entries {
+		if entry.Report == nil {
+			continue
+		}
+
+		jsonData, err := json.MarshalIndent(entry.Report, "", "  ")
+		if err != nil {
+			return jsonPaths, fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+
+		jsonPath, err := bitrise.WriteToDeployDir(fmt.Sprintf("bundle-analysis-%s.json", names[i]), jsonData)
+		if err != nil {
+			return jsonPaths, fmt.Errorf("failed to write JSON report: %w", err)
+		}
+		jsonPaths[i] = jsonPath
+		fmt.Fprintf(os.Stderr, "✓ Exported JSON report to: %s\n", jsonPath)
+	}
+
+	for _, indexFile := range indexFiles {
+		exportPath, err := bitrise.ExportToDeployDir(indexFile, filepath.Base(indexFile))
+		if err != nil {
+			return jsonPaths, fmt.Errorf("failed to export index report: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "✓ Exported index report to: %s\n", exportPath)
+	}
+
+	return jsonPaths, nil
+}
+
+// exportMultiEnvVars exports the key metrics of every analyzed artifact through envman,
+// with the artifact's report name in the variable names, e.g. BUNDLE_INSPECTOR_APP_RELEASE_SIZE
+func exportMultiEnvVars(entries []report.IndexEntry, names []string, jsonPaths []string) error {
+	var vars []bitrise.EnvVar
+	for i, entry := range entries {
+		if entry.Report == nil {
+			continue
+		}
+
+		htmlPath := entry.ReportFiles["html"]
+		if absPath, err := filepath.Abs(htmlPath); err == nil && htmlPath != "" {
+			htmlPath = absPath
+		}
+
+		metrics := bitrise.NewKeyMetrics(entry.Report, jsonPaths[i], htmlPath)
+		exported, err := bitrise.ExportKeyMetrics(bitrise.ArtifactEnvPrefix(envPrefix, names[i]), metrics)
+		if err != nil {
+			return err
+		}
+		vars = append(vars, exported...)
+	}
+
+	fmt.Fprintf(os.Stderr, "✓ Exported %d environment variables:\n", len(vars))
+	for _, v := range vars {
+		fmt.Fprintf(os.Stderr, "  %s\n", v.Key)
+	}
+	return nil
+}
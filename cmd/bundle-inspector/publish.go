@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/bitrise"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/publish"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/report"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+var (
+	publishBaseline    string
+	publishProvider    string
+	publishBaseURL     string
+	publishRepoURL     string
+	publishPullRequest string
+	publishTokenEnv    string
+	publishDryRun      bool
+)
+
+var publishCmd = &cobra.Command{
+	Use:   "publish [report.json]",
+	Short: "Post the analysis summary as a pull request comment",
+	Long: `Post a compact, delta-focused summary of a JSON report as a single sticky
+comment on a GitHub pull request, GitLab merge request or Bitbucket Cloud pull request.
+Running it again updates the same comment instead of adding a new one.
+
+If no report is given, $BITRISE_DEPLOY_DIR/bundle-analysis.json is used.
+The pull request number is read from BITRISE_PULL_REQUEST and the repository from
+GIT_REPOSITORY_URL. The API token is read from GITHUB_TOKEN, GITLAB_TOKEN or
+BITBUCKET_TOKEN depending on the provider (see --token-env).`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPublish,
+}
+
+func init() {
+	rootCmd.AddCommand(publishCmd)
+
+	publishCmd.Flags().StringVar(&publishBaseline, "baseline", "",
+		"JSON report of the base branch to show size changes against")
+	publishCmd.Flags().StringVar(&publishProvider, "provider", "",
+		"Git hosting provider: github, gitlab or bitbucket (default: detected from the repository URL)")
+	publishCmd.Flags().StringVar(&publishBaseURL, "base-url", "",
+		"API base URL (default: derived from the provider and repository host)")
+	publishCmd.Flags().StringVar(&publishRepoURL, "repo-url", "",
+		"Repository URL (default: $GIT_REPOSITORY_URL)")
+	publishCmd.Flags().StringVar(&publishPullRequest, "pr", "",
+		"Pull request number (default: $BITRISE_PULL_REQUEST)")
+	publishCmd.Flags().StringVar(&publishTokenEnv, "token-env", "",
+		"Environment variable holding the API token (default: GITHUB_TOKEN, GITLAB_TOKEN or BITBUCKET_TOKEN)")
+	publishCmd.Flags().BoolVar(&publishDryRun, "dry-run", false,
+		"Print the comment instead of posting it")
+}
+
+func runPublish(cmd *cobra.Command, args []string) error {
+	reportPath, err := publishReportPath(args)
+	if err != nil {
+		return err
+	}
+
+	current, err := loadJSONReport(reportPath)
+	if err != nil {
+		return err
+	}
+
+	var baseline *types.Report
+	if publishBaseline != "" {
+		if baseline, err = loadJSONReport(publishBaseline); err != nil {
+			return err
+		}
+	}
+
+	var body bytes.Buffer
+	if err := report.NewCompactMarkdownFormatter(baseline).Format(&body, current); err != nil {
+		return fmt.Errorf("failed to format comment: %w", err)
+	}
+
+	if publishDryRun {
+		fmt.Print(body.String())
+		return nil
+	}
+
+	pullRequest := firstNonEmpty(publishPullRequest, os.Getenv("BITRISE_PULL_REQUEST"))
+	if pullRequest == "" {
+		fmt.Fprintf(os.Stderr, "Not a pull request build (BITRISE_PULL_REQUEST not set), skipping publish\n")
+		return nil
+	}
+
+	cfg, err := publishConfig(pullRequest)
+	if err != nil {
+		return err
+	}
+
+	client, err := publish.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	result, err := publish.Publish(context.Background(), client, body.String())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "✓ Pull request comment %s (%s %s#%s)\n", result, cfg.Provider, cfg.Repository, cfg.PullRequest)
+	return nil
+}
+
+// publishReportPath returns the report to publish from arguments or the Bitrise deploy directory
+func publishReportPath(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	deployDir := bitrise.GetBuildMetadata().DeployDir
+	if deployDir == "" {
+		return "", fmt.Errorf("no report path provided and BITRISE_DEPLOY_DIR not set\n\nUsage: bundle-inspector publish <report.json>")
+	}
+	return filepath.Join(deployDir, "bundle-analysis.json"), nil
+}
+
+// publishConfig resolves the provider, repository and token from flags and environment
+func publishConfig(pullRequest string) (publish.Config, error) {
+	provider := publish.Provider(publishProvider)
+	switch provider {
+	case "", publish.ProviderGitHub, publish.ProviderGitLab, publish.ProviderBitbucket:
+	default:
+		return publish.Config{}, fmt.Errorf("unsupported provider: %q (supported: github, gitlab, bitbucket)", provider)
+	}
+
+	repoURL := firstNonEmpty(publishRepoURL, os.Getenv("GIT_REPOSITORY_URL"))
+	if repoURL == "" {
+		return publish.Config{}, fmt.Errorf("repository URL not set (use --repo-url or GIT_REPOSITORY_URL)")
+	}
+
+	host, repoPath, err := publish.ParseRepositoryURL(repoURL)
+	if err != nil {
+		return publish.Config{}, err
+	}
+
+	if provider == "" {
+		if provider, err = publish.DetectProvider(host); err != nil {
+			return publish.Config{}, fmt.Errorf("%w (use --provider)", err)
+		}
+	}
+
+	baseURL := publishBaseURL
+	if baseURL == "" {
+		if baseURL, err = publish.DefaultBaseURL(provider, host); err != nil {
+			return publish.Config{}, fmt.Errorf("%w (use --base-url)", err)
+		}
+	}
+
+	tokenEnv := firstNonEmpty(publishTokenEnv, provider.TokenEnvVar())
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		return publish.Config{}, fmt.Errorf("API token not found in %s", tokenEnv)
+	}
+
+	return publish.Config{
+		Provider:    provider,
+		BaseURL:     baseURL,
+		Repository:  repoPath,
+		PullRequest: pullRequest,
+		Token:       token,
+	}, nil
+}
+
+// loadJSONReport reads a report previously written with the json output format
+func loadJSONReport(path string) (*types.Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	defer f.Close()
+
+	r, err := report.DecodeJSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
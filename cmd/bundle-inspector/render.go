@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/ownership"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/report"
+)
+
+var (
+	renderFormats     string
+	renderOutputFiles string
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render <report.json>",
+	Short: "Render a saved JSON report in other formats",
+	Long: `Render a JSON report written by the analyze command as text, markdown, HTML or CSV,
+without the original artifact. Reports written by a newer release are rejected.
+
+With --owners, sizes by owner are (re)computed from the report's file tree.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRender,
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+
+	renderCmd.Flags().StringVarP(&renderFormats, "output", "o", "html",
+		"Output format(s): text, json, markdown, html, csv, tsv (comma-separated for multiple)")
+	renderCmd.Flags().StringVarP(&renderOutputFiles, "output-file", "f", "",
+		"Output filename(s) (comma-separated, must match format count; default: auto-generated from the artifact name)")
+	renderCmd.Flags().BoolVar(&htmlOnline, "html-online", false,
+		"Load HTML report styles and the ECharts library from CDNs instead of embedding them")
+	renderCmd.Flags().StringVar(&htmlCSPNonce, "html-csp-nonce", "",
+		"Nonce for inline scripts and styles in the HTML report, added with a matching Content-Security-Policy")
+	renderCmd.Flags().BoolVar(&htmlCSPHashes, "html-csp-hashes", false,
+		"Add a Content-Security-Policy to the HTML report that allows its inline scripts and styles by hash")
+	renderCmd.Flags().StringVar(&csvColumns, "csv-columns", "",
+		"File tree columns of the csv and tsv formats, comma-separated (default: all)")
+	renderCmd.Flags().StringVar(&sizeViewName, "size-view", string(report.SizeViewInstall),
+		"File sizes shown by the text, markdown and html formats: install (uncompressed) or download (compressed)")
+	renderCmd.Flags().StringVar(&ownersFile, "owners", "",
+		"CODEOWNERS-style file mapping artifact paths to teams, for sizes by owner")
+}
+
+func runRender(cmd *cobra.Command, args []string) error {
+	formats, err := parseFormats(renderFormats)
+	if err != nil {
+		return err
+	}
+
+	csvColumnList, sizeView, err = parseFormatterOptions(csvColumns, sizeViewName)
+	if err != nil {
+		return err
+	}
+
+	savedReport, err := loadJSONReport(args[0])
+	if err != nil {
+		return err
+	}
+
+	owners, err := loadOwners(ownersFile)
+	if err != nil {
+		return err
+	}
+	if owners != nil {
+		savedReport.Ownership = ownership.Attribute(savedReport, owners)
+	}
+
+	filenames, err := determineOutputFiles(savedReport.ArtifactInfo.Path, formats, parseOutputFiles(renderOutputFiles))
+	if err != nil {
+		return err
+	}
+
+	for i, format := range formats {
+		if err := writeReport(filenames[i], format, savedReport); err != nil {
+			return fmt.Errorf("failed to write %s report: %w", format, err)
+		}
+		fmt.Fprintf(os.Stderr, "  ✓ %s: %s\n", strings.ToUpper(format), filenames[i])
+	}
+
+	return nil
+}
@@ -19,11 +19,16 @@ import (
 )
 
 // AABAnalyzer analyzes Android App Bundle files.
-type AABAnalyzer struct{}
+type AABAnalyzer struct {
+	// DeviceSpecs are the devices split APK download sizes are estimated for
+	DeviceSpecs []types.DeviceSpec
+}
 
 // NewAABAnalyzer creates a new AAB analyzer.
 func NewAABAnalyzer() *AABAnalyzer {
-	return &AABAnalyzer{}
+	return &AABAnalyzer{
+		DeviceSpecs: DefaultDeviceSpecs(),
+	}
 }
 
 // ValidateArtifact checks if the file is a valid AAB.
@@ -84,6 +89,26 @@ func (a *AABAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 	// Find largest files
 	largestFiles := util.FindLargestFiles(fileTree, 10)
 
+	// Account for the signing block and ZIP structures, which hold no entry data.
+	// They are added after the size breakdown since they are not app content.
+	layout, err := readArchiveLayout(path, zipReader.File)
+	if err != nil {
+		// Non-fatal, continue without archive layout
+		fmt.Fprintf(os.Stderr, "ZIP layout analysis failed: %v\n", err)
+	} else {
+		fileTree = append(fileTree, archiveLayoutNodes(layout)...)
+	}
+
+	// Describe modules with their delivery type and size
+	moduleDetails := analyzeModules(&zipReader.Reader)
+
+	// Estimate split APK sizes as served by the Play Store
+	splitEstimate, err := EstimateSplitSizes(&zipReader.Reader, a.DeviceSpecs, moduleDetails)
+	if err != nil {
+		// Non-fatal, continue without split estimates
+		fmt.Fprintf(os.Stderr, "Split size estimation failed: %v\n", err)
+	}
+
 	// Extract app icon (use manifest icon name as hint for custom-named icons)
 	var iconHints *util.IconSearchHints
 	if iconName, ok := manifest["icon_name"].(string); ok && iconName != "" {
@@ -109,13 +134,18 @@ func (a *AABAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 		version = ver
 	}
 
-	// Include modules in metadata
-	metadata := map[string]interface{}{
-		"modules": modules,
+	delivery := summarizeModuleDelivery(moduleDetails)
+	details := &types.AndroidDetails{
+		Modules:        modules,
+		ModuleDetails:  moduleDetails,
+		ModuleDelivery: &delivery,
+		SplitEstimates: splitEstimate,
+		Manifest:       ReadManifestInfo(&zipReader.Reader, "base/manifest/AndroidManifest.xml"),
+		MetaInf:        analyzeMetaInf(zipReader.File),
 	}
-	// Merge manifest data into metadata
-	for k, v := range manifest {
-		metadata[k] = v
+	if layout != nil {
+		details.Signing = layout.signing
+		details.ZIPOverhead = layout.overhead
 	}
 
 	report := &types.Report{
@@ -133,7 +163,11 @@ func (a *AABAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 		SizeBreakdown: sizeBreakdown,
 		FileTree:      fileTree,
 		LargestFiles:  largestFiles,
-		Metadata:      metadata,
+		Optimizations: generateFeatureModuleOptimizations(&zipReader.Reader, moduleDetails),
+		Localization:  AnalyzeLocalization(&zipReader.Reader),
+		Android:       details,
+		Metadata:      manifest,
+		Warnings:      ManifestWarnings(details.Manifest),
 	}
 
 	return report, nil
@@ -180,7 +214,8 @@ func parseAABManifest(aabPath string) (map[string]interface{}, error) {
 			// Parse binary XML
 			xmlFile, err := androidbinary.NewXMLFile(bytes.NewReader(manifestData))
 			if err != nil {
-				// Non-fatal, continue without detailed manifest info
+				// Bundletool stores manifests as aapt2 protobuf XML
+				applyProtoManifestInfo(manifest, manifestData)
 				break
 			}
 
@@ -234,6 +269,34 @@ func parseAABManifest(aabPath string) (map[string]interface{}, error) {
 	return manifest, nil
 }
 
+// applyProtoManifestInfo extracts package, version and icon from a protobuf XML manifest.
+// Failures are ignored, leaving the manifest info incomplete.
+func applyProtoManifestInfo(manifest map[string]interface{}, data []byte) {
+	root, err := parseProtoXML(data)
+	if err != nil {
+		return
+	}
+
+	if pkg := root.attr("package"); pkg != "" {
+		manifest["package"] = pkg
+	}
+	if versionName := root.attr("versionName"); versionName != "" {
+		manifest["version"] = versionName
+	}
+	if versionCode := root.attr("versionCode"); versionCode != "" {
+		manifest["version_code"] = versionCode
+	}
+
+	// Icons are resource references such as "@mipmap/ic_launcher"
+	if app := root.child("application"); app != nil {
+		if icon := app.attr("icon"); strings.HasPrefix(icon, "@") && !strings.HasPrefix(icon, "@0x") {
+			if idx := strings.LastIndex(icon, "/"); idx >= 0 && idx < len(icon)-1 {
+				manifest["icon_name"] = icon[idx+1:]
+			}
+		}
+	}
+}
+
 // detectModules identifies modules in the AAB
 func detectModules(nodes []*types.FileNode) []string {
 	modules := []string{}
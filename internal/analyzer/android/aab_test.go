@@ -126,23 +126,17 @@ func TestAABAnalyzer_Analyze(t *testing.T) {
 		t.Error("Expected largest files list to be non-empty")
 	}
 
-	// Check metadata for modules
-	if report.Metadata == nil {
-		t.Error("Expected metadata, got nil")
+	// Check Android details for modules
+	if report.Android == nil {
+		t.Fatal("Expected Android details, got nil")
 	}
 
-	modules, ok := report.Metadata["modules"]
-	if !ok {
-		t.Error("Expected modules in metadata")
-	}
-
-	modulesList, ok := modules.([]string)
-	if !ok {
-		t.Error("Expected modules to be []string")
+	if len(report.Android.Modules) == 0 {
+		t.Error("Expected at least one module")
 	}
 
-	if len(modulesList) == 0 {
-		t.Error("Expected at least one module")
+	if report.Android.ModuleDelivery == nil {
+		t.Error("Expected module delivery sizes")
 	}
 }
 
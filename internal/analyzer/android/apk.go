@@ -79,6 +79,16 @@ func (a *APKAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 	// Find largest files
 	largestFiles := util.FindLargestFiles(fileTree, 10)
 
+	// Account for the signing block and ZIP structures, which hold no entry data.
+	// They are added after the size breakdown since they are not app content.
+	layout, err := readArchiveLayout(path, zipReader.File)
+	if err != nil {
+		// Non-fatal, continue without archive layout
+		fmt.Fprintf(os.Stderr, "ZIP layout analysis failed: %v\n", err)
+	} else {
+		fileTree = append(fileTree, archiveLayoutNodes(layout)...)
+	}
+
 	// Extract app icon (use manifest icon name as hint for custom-named icons)
 	var iconHints *util.IconSearchHints
 	if iconName, ok := manifest["icon_name"].(string); ok && iconName != "" {
@@ -104,6 +114,18 @@ func (a *APKAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 		version = ver
 	}
 
+	// Read permissions, components and flags of the manifest
+	manifestInfo := ReadManifestInfo(&zipReader.Reader, "AndroidManifest.xml")
+
+	details := &types.AndroidDetails{
+		Manifest: manifestInfo,
+		MetaInf:  analyzeMetaInf(zipReader.File),
+	}
+	if layout != nil {
+		details.Signing = layout.signing
+		details.ZIPOverhead = layout.overhead
+	}
+
 	report := &types.Report{
 		ArtifactInfo: types.ArtifactInfo{
 			Path:             path,
@@ -119,7 +141,10 @@ func (a *APKAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 		SizeBreakdown: sizeBreakdown,
 		FileTree:      fileTree,
 		LargestFiles:  largestFiles,
+		Localization:  AnalyzeLocalization(&zipReader.Reader),
+		Android:       details,
 		Metadata:      manifest,
+		Warnings:      ManifestWarnings(manifestInfo),
 	}
 
 	return report, nil
@@ -0,0 +1,315 @@
+package android
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// ZIP and APK Signing Block layout constants
+const (
+	eocdSignature     = 0x06054b50
+	eocdMinSize       = 22
+	eocdMaxComment    = 0xffff
+	signingBlockMagic = "APK Sig Block 42"
+)
+
+// Virtual file tree nodes for archive bytes that hold no entry data
+const (
+	signingBlockNodeName = "APK Signing Block"
+	zipOverheadNodeName  = "ZIP Overhead"
+)
+
+// signingBlockNames names the known ID-value pairs of the APK Signing Block
+var signingBlockNames = map[uint32]string{
+	0x7109871a: "v2 signature",
+	0xf05368c0: "v3 signature",
+	0x1b93ad61: "v3.1 signature",
+	0x42726577: "verity padding",
+	0x2b09189e: "source stamp v1",
+	0x6dff800d: "source stamp",
+	0x504b4453: "dependency metadata",
+	0x2146444e: "Google Play metadata",
+}
+
+// signingSchemeBlocks maps signature block names to their scheme
+var signingSchemeBlocks = map[string]string{
+	"v2 signature":   "v2",
+	"v3 signature":   "v3",
+	"v3.1 signature": "v3.1",
+}
+
+// archiveLayout is where the bytes of a ZIP archive go besides entry data
+type archiveLayout struct {
+	signing  *types.APKSigningInfo
+	overhead *types.ZIPOverhead
+}
+
+// readArchiveLayout analyzes the layout of the archive at path. A v4 signature is
+// detected from the .idsig file next to the artifact.
+func readArchiveLayout(artifactPath string, files []*zip.File) (*archiveLayout, error) {
+	f, err := os.Open(artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	_, err = os.Stat(artifactPath + ".idsig")
+	return analyzeArchiveLayout(f, info.Size(), files, err == nil)
+}
+
+// analyzeArchiveLayout locates the central directory and the APK Signing Block in front
+// of it, and attributes the remaining bytes to local headers. Schemes found in the
+// signing block are completed with v1 (JAR signature files) and v4 (hasIDSig).
+// Signing info is nil for unsigned archives.
+func analyzeArchiveLayout(r io.ReaderAt, size int64, files []*zip.File, hasIDSig bool) (*archiveLayout, error) {
+	cdOffset, cdSize, eocdSize, err := readEndOfCentralDirectory(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	signing := &types.APKSigningInfo{}
+	blockSize, blocks, err := readSigningBlock(r, cdOffset)
+	if err != nil {
+		return nil, err
+	}
+	signing.BlockSize = blockSize
+	signing.Blocks = blocks
+
+	if hasV1Signature(files) {
+		signing.Schemes = append(signing.Schemes, "v1")
+	}
+	for _, block := range blocks {
+		if scheme := signingSchemeBlocks[block.Name]; scheme != "" {
+			signing.Schemes = append(signing.Schemes, scheme)
+		}
+	}
+	if hasIDSig {
+		signing.Schemes = append(signing.Schemes, "v4")
+	}
+
+	var entryData int64
+	for _, f := range files {
+		entryData += int64(f.CompressedSize64)
+	}
+	overhead := &types.ZIPOverhead{
+		CentralDirectory: cdSize + eocdSize,
+		LocalHeaders:     size - entryData - blockSize - cdSize - eocdSize,
+	}
+	if overhead.LocalHeaders < 0 {
+		return nil, fmt.Errorf("entry data exceeds the archive size")
+	}
+
+	if len(signing.Schemes) == 0 && blockSize == 0 {
+		signing = nil // Unsigned
+	}
+	return &archiveLayout{signing: signing, overhead: overhead}, nil
+}
+
+// readEndOfCentralDirectory finds the end of central directory record and returns the
+// central directory's offset and size, and the size of the record with its comment
+func readEndOfCentralDirectory(r io.ReaderAt, size int64) (offset, cdSize, eocdSize int64, err error) {
+	tailSize := int64(eocdMinSize + eocdMaxComment)
+	if tailSize > size {
+		tailSize = size
+	}
+	tail := make([]byte, tailSize)
+	if _, err := r.ReadAt(tail, size-tailSize); err != nil && err != io.EOF {
+		return 0, 0, 0, fmt.Errorf("failed to read end of central directory: %w", err)
+	}
+
+	for i := len(tail) - eocdMinSize; i >= 0; i-- {
+		if binary.LittleEndian.Uint32(tail[i:]) != eocdSignature {
+			continue
+		}
+		commentLength := int64(binary.LittleEndian.Uint16(tail[i+20:]))
+		if int64(i)+eocdMinSize+commentLength != tailSize {
+			continue // Signature bytes inside the comment
+		}
+		cdSize = int64(binary.LittleEndian.Uint32(tail[i+12:]))
+		offset = int64(binary.LittleEndian.Uint32(tail[i+16:]))
+		if offset == 0xffffffff || cdSize == 0xffffffff {
+			return 0, 0, 0, fmt.Errorf("ZIP64 archives are not supported")
+		}
+		return offset, cdSize, eocdMinSize + commentLength, nil
+	}
+	return 0, 0, 0, fmt.Errorf("end of central directory not found")
+}
+
+// readSigningBlock reads the APK Signing Block that ends at the central directory:
+// size (uint64), ID-value pairs of length (uint64), ID (uint32) and value, the size again
+// and the magic. Returns a zero size without a signing block.
+func readSigningBlock(r io.ReaderAt, cdOffset int64) (int64, []types.SigningBlockEntry, error) {
+	if cdOffset < 32 {
+		return 0, nil, nil
+	}
+	footer := make([]byte, 24)
+	if _, err := r.ReadAt(footer, cdOffset-24); err != nil {
+		return 0, nil, fmt.Errorf("failed to read signing block footer: %w", err)
+	}
+	if string(footer[8:]) != signingBlockMagic {
+		return 0, nil, nil
+	}
+
+	sizeField := binary.LittleEndian.Uint64(footer)
+	if sizeField < 24 || sizeField > uint64(cdOffset-8) {
+		return 0, nil, fmt.Errorf("invalid signing block size %d", sizeField)
+	}
+	blockSize := int64(sizeField) + 8
+	block := make([]byte, blockSize)
+	if _, err := r.ReadAt(block, cdOffset-blockSize); err != nil {
+		return 0, nil, fmt.Errorf("failed to read signing block: %w", err)
+	}
+	if binary.LittleEndian.Uint64(block) != sizeField {
+		return 0, nil, fmt.Errorf("signing block sizes do not match")
+	}
+
+	var entries []types.SigningBlockEntry
+	pairs := block[8 : blockSize-24]
+	for len(pairs) > 0 {
+		if len(pairs) < 12 {
+			return 0, nil, fmt.Errorf("truncated signing block entry")
+		}
+		length := binary.LittleEndian.Uint64(pairs)
+		if length < 4 || length > uint64(len(pairs)-8) {
+			return 0, nil, fmt.Errorf("invalid signing block entry length %d", length)
+		}
+		id := binary.LittleEndian.Uint32(pairs[8:])
+		name := signingBlockNames[id]
+		if name == "" {
+			name = "unknown"
+		}
+		entries = append(entries, types.SigningBlockEntry{
+			ID:   fmt.Sprintf("0x%08x", id),
+			Name: name,
+			Size: int64(length) + 8,
+		})
+		pairs = pairs[8+length:]
+	}
+	return blockSize, entries, nil
+}
+
+// hasV1Signature reports whether the archive has JAR signature files
+func hasV1Signature(files []*zip.File) bool {
+	for _, f := range files {
+		if metaInfKind(f.Name) == "v1-signature" && strings.HasSuffix(strings.ToUpper(f.Name), ".SF") {
+			return true
+		}
+	}
+	return false
+}
+
+// metaInfKind classifies a META-INF/ file, returning "" for other paths
+func metaInfKind(name string) string {
+	if !strings.HasPrefix(name, "META-INF/") || strings.HasSuffix(name, "/") {
+		return ""
+	}
+	rel := strings.TrimPrefix(name, "META-INF/")
+	upper := strings.ToUpper(rel)
+	switch {
+	case strings.HasPrefix(rel, "services/"):
+		return "service"
+	case strings.Contains(rel, "/"):
+		return "other"
+	case upper == "MANIFEST.MF" || path.Ext(upper) == ".SF" || path.Ext(upper) == ".RSA" ||
+		path.Ext(upper) == ".DSA" || path.Ext(upper) == ".EC":
+		return "v1-signature"
+	case strings.HasSuffix(rel, ".version"):
+		return "version"
+	case strings.HasSuffix(rel, ".kotlin_module"):
+		return "kotlin-module"
+	}
+	return "other"
+}
+
+// analyzeMetaInf sums the META-INF/ files by kind, largest first
+func analyzeMetaInf(files []*zip.File) []types.MetaInfGroup {
+	groups := make(map[string]*types.MetaInfGroup)
+	for _, f := range files {
+		kind := metaInfKind(f.Name)
+		if kind == "" {
+			continue
+		}
+		group := groups[kind]
+		if group == nil {
+			group = &types.MetaInfGroup{Kind: kind}
+			groups[kind] = group
+		}
+		group.Files++
+		group.Size += int64(f.UncompressedSize64)
+		group.CompressedSize += int64(f.CompressedSize64)
+	}
+
+	result := make([]types.MetaInfGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Size != result[j].Size {
+			return result[i].Size > result[j].Size
+		}
+		return result[i].Kind < result[j].Kind
+	})
+	return result
+}
+
+// archiveLayoutNodes returns virtual root nodes for the signing block and the ZIP overhead,
+// so that the compressed sizes of the file tree sum up to the artifact size
+func archiveLayoutNodes(layout *archiveLayout) []*types.FileNode {
+	var nodes []*types.FileNode
+
+	if layout.signing != nil && layout.signing.BlockSize > 0 {
+		block := virtualArchiveNode(signingBlockNodeName, "", layout.signing.BlockSize)
+		var pairs int64
+		for _, entry := range layout.signing.Blocks {
+			block.Children = append(block.Children, virtualArchiveNode(entry.Name, signingBlockNodeName, entry.Size))
+			pairs += entry.Size
+		}
+		if len(block.Children) > 0 {
+			block.IsDir = true
+			// Size fields and magic
+			block.Children = append(block.Children, virtualArchiveNode("header", signingBlockNodeName, layout.signing.BlockSize-pairs))
+		}
+		nodes = append(nodes, block)
+	}
+
+	if o := layout.overhead; o != nil {
+		overhead := virtualArchiveNode(zipOverheadNodeName, "", o.LocalHeaders+o.CentralDirectory)
+		overhead.IsDir = true
+		overhead.Children = []*types.FileNode{
+			virtualArchiveNode("Local File Headers", zipOverheadNodeName, o.LocalHeaders),
+			virtualArchiveNode("Central Directory", zipOverheadNodeName, o.CentralDirectory),
+		}
+		nodes = append(nodes, overhead)
+	}
+	return nodes
+}
+
+// virtualArchiveNode creates a virtual node of raw archive bytes
+func virtualArchiveNode(name, parent string, size int64) *types.FileNode {
+	nodePath := name
+	if parent != "" {
+		nodePath = parent + "/" + name
+	}
+	return &types.FileNode{
+		Path:              nodePath,
+		Name:              name,
+		Size:              size,
+		CompressedSize:    size,
+		CompressionMethod: "stored",
+		IsVirtual:         true,
+		Children:          []*types.FileNode{},
+		Metadata:          map[string]interface{}{types.MetadataArchiveStructure: true},
+	}
+}
@@ -0,0 +1,197 @@
+package android
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// testSigningPair is an ID-value pair of a test APK Signing Block
+type testSigningPair struct {
+	id    uint32
+	value int // Value length
+}
+
+// insertTestSigningBlock inserts an APK Signing Block in front of the central directory
+// of a ZIP archive and updates the central directory offset
+func insertTestSigningBlock(t *testing.T, archive []byte, pairs []testSigningPair) []byte {
+	t.Helper()
+	var body []byte
+	for _, p := range pairs {
+		body = binary.LittleEndian.AppendUint64(body, uint64(4+p.value))
+		body = binary.LittleEndian.AppendUint32(body, p.id)
+		body = append(body, make([]byte, p.value)...)
+	}
+	size := uint64(len(body) + 24)
+	block := binary.LittleEndian.AppendUint64(nil, size)
+	block = append(block, body...)
+	block = binary.LittleEndian.AppendUint64(block, size)
+	block = append(block, signingBlockMagic...)
+
+	eocd := len(archive) - eocdMinSize
+	if binary.LittleEndian.Uint32(archive[eocd:]) != eocdSignature {
+		t.Fatal("archive has a comment or no end of central directory record")
+	}
+	cdOffset := binary.LittleEndian.Uint32(archive[eocd+16:])
+
+	result := append([]byte{}, archive[:cdOffset]...)
+	result = append(result, block...)
+	result = append(result, archive[cdOffset:]...)
+	binary.LittleEndian.PutUint32(result[len(result)-eocdMinSize+16:], cdOffset+uint32(len(block)))
+	return result
+}
+
+// createTestArchive writes the files into an in-memory ZIP archive
+func createTestArchive(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestAnalyzeArchiveLayout(t *testing.T) {
+	archive := createTestArchive(t, map[string][]byte{
+		"classes.dex":                              bytes.Repeat([]byte("dex"), 1000),
+		"META-INF/MANIFEST.MF":                     make([]byte, 300),
+		"META-INF/CERT.SF":                         make([]byte, 300),
+		"META-INF/CERT.RSA":                        make([]byte, 1200),
+		"META-INF/androidx.core_core.version":      []byte("1.12.0"),
+		"META-INF/app_release.kotlin_module":       make([]byte, 40),
+		"META-INF/services/kotlinx.coroutines.Foo": []byte("kotlinx.coroutines.Bar"),
+	})
+	archive = insertTestSigningBlock(t, archive, []testSigningPair{
+		{0x7109871a, 1000},
+		{0xf05368c0, 1100},
+		{0x42726577, 1896},
+		{0x12345678, 4},
+	})
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	layout, err := analyzeArchiveLayout(bytes.NewReader(archive), int64(len(archive)), zr.File, true)
+	if err != nil {
+		t.Fatalf("analyzeArchiveLayout() error = %v", err)
+	}
+
+	if want := []string{"v1", "v2", "v3", "v4"}; !reflect.DeepEqual(layout.signing.Schemes, want) {
+		t.Errorf("Schemes = %v, want %v", layout.signing.Schemes, want)
+	}
+	if layout.signing.BlockSize != 4080 {
+		t.Errorf("BlockSize = %d, want 4080", layout.signing.BlockSize)
+	}
+	wantBlocks := []types.SigningBlockEntry{
+		{ID: "0x7109871a", Name: "v2 signature", Size: 1012},
+		{ID: "0xf05368c0", Name: "v3 signature", Size: 1112},
+		{ID: "0x42726577", Name: "verity padding", Size: 1908},
+		{ID: "0x12345678", Name: "unknown", Size: 16},
+	}
+	if !reflect.DeepEqual(layout.signing.Blocks, wantBlocks) {
+		t.Errorf("Blocks = %+v, want %+v", layout.signing.Blocks, wantBlocks)
+	}
+
+	// Entry data, signing block and overhead add up to the archive size
+	total := layout.signing.BlockSize + layout.overhead.LocalHeaders + layout.overhead.CentralDirectory
+	for _, f := range zr.File {
+		total += int64(f.CompressedSize64)
+	}
+	if total != int64(len(archive)) {
+		t.Errorf("layout sums to %d bytes, want %d", total, len(archive))
+	}
+
+	var nodeTotal int64
+	for _, node := range archiveLayoutNodes(layout) {
+		nodeTotal += node.CompressedSize
+		var childTotal int64
+		for _, child := range node.Children {
+			childTotal += child.CompressedSize
+		}
+		if childTotal != node.CompressedSize {
+			t.Errorf("children of %s sum to %d, want %d", node.Name, childTotal, node.CompressedSize)
+		}
+	}
+	if want := layout.signing.BlockSize + layout.overhead.LocalHeaders + layout.overhead.CentralDirectory; nodeTotal != want {
+		t.Errorf("virtual nodes sum to %d, want %d", nodeTotal, want)
+	}
+}
+
+func TestAnalyzeArchiveLayout_Unsigned(t *testing.T) {
+	archive := createTestArchive(t, map[string][]byte{"classes.dex": make([]byte, 100)})
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	layout, err := analyzeArchiveLayout(bytes.NewReader(archive), int64(len(archive)), zr.File, false)
+	if err != nil {
+		t.Fatalf("analyzeArchiveLayout() error = %v", err)
+	}
+	if layout.signing != nil {
+		t.Errorf("signing = %+v, want nil for an unsigned archive", layout.signing)
+	}
+	if nodes := archiveLayoutNodes(layout); len(nodes) != 1 || nodes[0].Name != zipOverheadNodeName {
+		t.Errorf("got %d virtual nodes, want only the ZIP overhead", len(nodes))
+	}
+
+	if _, err := analyzeArchiveLayout(bytes.NewReader([]byte("not a zip")), 9, nil, false); err == nil {
+		t.Error("analyzeArchiveLayout() should fail without an end of central directory record")
+	}
+}
+
+func TestAnalyzeMetaInf(t *testing.T) {
+	archive := createTestArchive(t, map[string][]byte{
+		"META-INF/MANIFEST.MF":                                      make([]byte, 300),
+		"META-INF/CERT.SF":                                          make([]byte, 300),
+		"META-INF/androidx.core_core.version":                       []byte("1.12.0"),
+		"META-INF/com/android/build/gradle/app-metadata.properties": []byte("appMetadataVersion=1.1"),
+		"classes.dex":                                               make([]byte, 100),
+	})
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	groups := analyzeMetaInf(zr.File)
+	if len(groups) != 3 {
+		t.Fatalf("got %d groups, want 3: %+v", len(groups), groups)
+	}
+	if groups[0].Kind != "v1-signature" || groups[0].Files != 2 || groups[0].Size != 600 {
+		t.Errorf("groups[0] = %+v, want the 2 v1 signature files", groups[0])
+	}
+}
+
+func TestMetaInfKind(t *testing.T) {
+	tests := map[string]string{
+		"META-INF/MANIFEST.MF":                  "v1-signature",
+		"META-INF/BNDLTOOL.RSA":                 "v1-signature",
+		"META-INF/cert.ec":                      "v1-signature",
+		"META-INF/kotlinx_coroutines.version":   "version",
+		"META-INF/app_release.kotlin_module":    "kotlin-module",
+		"META-INF/services/java.nio.FooService": "service",
+		"META-INF/proguard/coroutines.pro":      "other",
+		"META-INF/":                             "",
+		"classes.dex":                           "",
+	}
+	for name, want := range tests {
+		if got := metaInfKind(name); got != want {
+			t.Errorf("metaInfKind(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
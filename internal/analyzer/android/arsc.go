@@ -0,0 +1,306 @@
+package android
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/localization"
+)
+
+// Chunk types of the binary resource table (resources.arsc), see ResourceTypes.h of androidfw
+const (
+	resStringPoolType   = 0x0001
+	resTableType        = 0x0002
+	resTablePackageType = 0x0200
+	resTableTypeType    = 0x0201
+)
+
+// Flags of resource table chunks and entries
+const (
+	resStringPoolUTF8   = 1 << 8
+	resTypeFlagSparse   = 0x01
+	resTypeFlagOffset16 = 0x02
+	resEntryFlagCompact = 0x08
+	resNoEntry          = 0xFFFFFFFF
+	resNoEntry16        = 0xFFFF
+)
+
+// resTablePackageHeaderSize is the package header size up to the key string pool offset
+const resTablePackageHeaderSize = 284
+
+// resourceValues are the values of one resource type in one locale, e.g. the German
+// strings. Values of every other qualifier of the locale are included.
+type resourceValues struct {
+	typeName string
+	locale   string   // BCP 47 tag, "" for values without a locale qualifier
+	size     int64    // Raw size of the values in the resource table
+	keys     []string // Names of the resources that have a value
+}
+
+// parseResourceTable reads the resource values of a binary resource table (resources.arsc)
+// by type and configuration. Each type chunk holds the values of one type in one configuration.
+func parseResourceTable(data []byte) ([]resourceValues, error) {
+	if len(data) < 12 || binary.LittleEndian.Uint16(data) != resTableType {
+		return nil, fmt.Errorf("not a resource table")
+	}
+
+	var values []resourceValues
+	err := forEachChunk(data, int(binary.LittleEndian.Uint16(data[2:])), func(chunkType uint16, chunk []byte) error {
+		if chunkType != resTablePackageType {
+			return nil
+		}
+		packageValues, err := parseResourcePackage(chunk)
+		values = append(values, packageValues...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse resource table: %w", err)
+	}
+	return values, nil
+}
+
+// parseResourcePackage reads the type chunks of a package chunk
+func parseResourcePackage(chunk []byte) ([]resourceValues, error) {
+	headerSize := int(binary.LittleEndian.Uint16(chunk[2:]))
+	if headerSize < resTablePackageHeaderSize || headerSize > len(chunk) {
+		return nil, fmt.Errorf("invalid package header")
+	}
+
+	typeNames, err := parseStringPool(chunk, int(binary.LittleEndian.Uint32(chunk[268:])))
+	if err != nil {
+		return nil, fmt.Errorf("type strings: %w", err)
+	}
+	keyNames, err := parseStringPool(chunk, int(binary.LittleEndian.Uint32(chunk[276:])))
+	if err != nil {
+		return nil, fmt.Errorf("key strings: %w", err)
+	}
+
+	var values []resourceValues
+	err = forEachChunk(chunk, headerSize, func(chunkType uint16, typeChunk []byte) error {
+		if chunkType != resTableTypeType {
+			return nil
+		}
+		v, err := parseResourceType(typeChunk, typeNames, keyNames)
+		if err != nil {
+			return err
+		}
+		values = append(values, v)
+		return nil
+	})
+	return values, err
+}
+
+// parseResourceType reads the configuration and entry names of a type chunk
+func parseResourceType(chunk []byte, typeNames, keyNames []string) (resourceValues, error) {
+	if len(chunk) < 20 {
+		return resourceValues{}, fmt.Errorf("truncated type chunk")
+	}
+	headerSize := int(binary.LittleEndian.Uint16(chunk[2:]))
+	typeID := int(chunk[8])
+	flags := chunk[9]
+	entryCount := int(binary.LittleEndian.Uint32(chunk[12:]))
+	entriesStart := int(binary.LittleEndian.Uint32(chunk[16:]))
+	if headerSize < 20 || headerSize > len(chunk) || entriesStart > len(chunk) || typeID == 0 || typeID > len(typeNames) {
+		return resourceValues{}, fmt.Errorf("invalid type chunk header")
+	}
+
+	values := resourceValues{
+		typeName: typeNames[typeID-1],
+		locale:   configLocale(chunk[20:headerSize]),
+		size:     int64(len(chunk)),
+	}
+
+	offsetSize := 4
+	if flags&resTypeFlagOffset16 != 0 && flags&resTypeFlagSparse == 0 {
+		offsetSize = 2
+	}
+	if headerSize+entryCount*offsetSize > len(chunk) {
+		return resourceValues{}, fmt.Errorf("truncated entry offsets")
+	}
+
+	for i := 0; i < entryCount; i++ {
+		var offset int
+		switch {
+		case flags&resTypeFlagSparse != 0:
+			// Sparse entries are pairs of a 16-bit index and a 16-bit offset divided by 4
+			offset = int(binary.LittleEndian.Uint16(chunk[headerSize+4*i+2:])) * 4
+		case offsetSize == 2:
+			o := binary.LittleEndian.Uint16(chunk[headerSize+2*i:])
+			if o == resNoEntry16 {
+				continue
+			}
+			offset = int(o) * 4
+		default:
+			o := binary.LittleEndian.Uint32(chunk[headerSize+4*i:])
+			if o == resNoEntry {
+				continue
+			}
+			offset = int(o)
+		}
+
+		entry := entriesStart + offset
+		if entry+8 > len(chunk) {
+			return resourceValues{}, fmt.Errorf("entry %d out of bounds", i)
+		}
+		key := int(binary.LittleEndian.Uint32(chunk[entry+4:]))
+		if binary.LittleEndian.Uint16(chunk[entry+2:])&resEntryFlagCompact != 0 {
+			key = int(binary.LittleEndian.Uint16(chunk[entry:]))
+		}
+		if key < len(keyNames) {
+			values.keys = append(values.keys, keyNames[key])
+		}
+	}
+
+	return values, nil
+}
+
+// configLocale returns the locale of a ResTable_config as a BCP 47 tag, or ""
+func configLocale(config []byte) string {
+	if len(config) < 12 {
+		return ""
+	}
+	language := unpackLocaleCode(config[8:10], 'a')
+	if language == "" {
+		return ""
+	}
+
+	parts := []string{language}
+	if len(config) >= 40 {
+		if script := strings.TrimRight(string(config[36:40]), "\x00"); script != "" {
+			parts = append(parts, script)
+		}
+	}
+	if region := unpackLocaleCode(config[10:12], '0'); region != "" {
+		parts = append(parts, region)
+	}
+	return localization.Normalize(strings.Join(parts, "-"))
+}
+
+// unpackLocaleCode decodes a language or region of a ResTable_config. Two-letter codes
+// are stored as is; three-letter codes are packed into 5 bits per letter, offset from base.
+func unpackLocaleCode(b []byte, base byte) string {
+	if b[0] == 0 {
+		return ""
+	}
+	if b[0]&0x80 == 0 {
+		return strings.TrimRight(string(b), "\x00")
+	}
+
+	first := b[1] & 0x1f
+	second := (b[1]&0xe0)>>5 | (b[0]&0x03)<<3
+	third := (b[0] & 0x7c) >> 2
+	return string([]byte{base + first, base + second, base + third})
+}
+
+// parseStringPool reads the strings of the string pool chunk at offset
+func parseStringPool(data []byte, offset int) ([]string, error) {
+	if offset <= 0 || offset+28 > len(data) || binary.LittleEndian.Uint16(data[offset:]) != resStringPoolType {
+		return nil, fmt.Errorf("no string pool at offset %d", offset)
+	}
+	chunkSize := int(binary.LittleEndian.Uint32(data[offset+4:]))
+	if chunkSize < 28 || offset+chunkSize > len(data) {
+		return nil, fmt.Errorf("invalid string pool size")
+	}
+	pool := data[offset : offset+chunkSize]
+
+	headerSize := int(binary.LittleEndian.Uint16(pool[2:]))
+	count := int(binary.LittleEndian.Uint32(pool[8:]))
+	utf8 := binary.LittleEndian.Uint32(pool[16:])&resStringPoolUTF8 != 0
+	stringsStart := int(binary.LittleEndian.Uint32(pool[20:]))
+	if headerSize+4*count > len(pool) {
+		return nil, fmt.Errorf("truncated string offsets")
+	}
+
+	result := make([]string, count)
+	for i := range result {
+		start := stringsStart + int(binary.LittleEndian.Uint32(pool[headerSize+4*i:]))
+		var s string
+		var ok bool
+		if utf8 {
+			s, ok = decodeUTF8PoolString(pool, start)
+		} else {
+			s, ok = decodeUTF16PoolString(pool, start)
+		}
+		if !ok {
+			return nil, fmt.Errorf("string %d out of bounds", i)
+		}
+		result[i] = s
+	}
+	return result, nil
+}
+
+// decodeUTF8PoolString reads a string pool entry with its UTF-16 and UTF-8 lengths
+func decodeUTF8PoolString(pool []byte, pos int) (string, bool) {
+	// The UTF-16 length comes first and is not needed
+	if pos >= len(pool) {
+		return "", false
+	}
+	if pool[pos]&0x80 != 0 {
+		pos++
+	}
+	pos++
+
+	if pos+1 >= len(pool) {
+		return "", false
+	}
+	length := int(pool[pos])
+	if length&0x80 != 0 {
+		length = (length&0x7f)<<8 | int(pool[pos+1])
+		pos++
+	}
+	pos++
+
+	if pos+length > len(pool) {
+		return "", false
+	}
+	return string(pool[pos : pos+length]), true
+}
+
+// decodeUTF16PoolString reads a string pool entry with its length in UTF-16 code units
+func decodeUTF16PoolString(pool []byte, pos int) (string, bool) {
+	if pos+2 > len(pool) {
+		return "", false
+	}
+	length := int(binary.LittleEndian.Uint16(pool[pos:]))
+	pos += 2
+	if length&0x8000 != 0 {
+		if pos+2 > len(pool) {
+			return "", false
+		}
+		length = (length&0x7fff)<<16 | int(binary.LittleEndian.Uint16(pool[pos:]))
+		pos += 2
+	}
+
+	if pos+2*length > len(pool) {
+		return "", false
+	}
+	units := make([]uint16, length)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(pool[pos+2*i:])
+	}
+	return string(utf16.Decode(units)), true
+}
+
+// forEachChunk calls fn for every chunk that follows the header of a parent chunk
+func forEachChunk(data []byte, headerSize int, fn func(chunkType uint16, chunk []byte) error) error {
+	if len(data) >= 8 {
+		if size := int(binary.LittleEndian.Uint32(data[4:])); size >= headerSize && size < len(data) {
+			data = data[:size]
+		}
+	}
+
+	for offset := headerSize; offset+8 <= len(data); {
+		chunkType := binary.LittleEndian.Uint16(data[offset:])
+		chunkSize := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		if chunkSize < 8 || offset+chunkSize > len(data) {
+			return fmt.Errorf("invalid chunk size %d at offset %d", chunkSize, offset)
+		}
+		if err := fn(chunkType, data[offset:offset+chunkSize]); err != nil {
+			return err
+		}
+		offset += chunkSize
+	}
+	return nil
+}
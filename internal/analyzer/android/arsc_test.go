@@ -0,0 +1,201 @@
+package android
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+// testResourceType is a type chunk of a test resource table: the entries a configuration has
+type testResourceType struct {
+	typeID   int
+	language string
+	region   string
+	entries  []int // Key indexes of the entries with a value
+}
+
+// encodeTestStringPool builds a string pool chunk in UTF-8 or UTF-16
+func encodeTestStringPool(values []string, utf8 bool) []byte {
+	var data []byte
+	offsets := make([]uint32, len(values))
+	for i, s := range values {
+		offsets[i] = uint32(len(data))
+		if utf8 {
+			data = append(data, byte(len([]rune(s))), byte(len(s)))
+			data = append(data, s...)
+			data = append(data, 0)
+		} else {
+			units := utf16.Encode([]rune(s))
+			data = binary.LittleEndian.AppendUint16(data, uint16(len(units)))
+			for _, u := range units {
+				data = binary.LittleEndian.AppendUint16(data, u)
+			}
+			data = append(data, 0, 0)
+		}
+	}
+	for len(data)%4 != 0 {
+		data = append(data, 0)
+	}
+
+	var flags uint32
+	if utf8 {
+		flags = resStringPoolUTF8
+	}
+	headerSize := 28
+	chunk := binary.LittleEndian.AppendUint16(nil, resStringPoolType)
+	chunk = binary.LittleEndian.AppendUint16(chunk, uint16(headerSize))
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(headerSize+4*len(values)+len(data)))
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(len(values)))
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0)
+	chunk = binary.LittleEndian.AppendUint32(chunk, flags)
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(headerSize+4*len(values)))
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0)
+	for _, offset := range offsets {
+		chunk = binary.LittleEndian.AppendUint32(chunk, offset)
+	}
+	return append(chunk, data...)
+}
+
+// encodeTestResourceType builds a type chunk with a string value for every entry
+func encodeTestResourceType(rt testResourceType, entryCount int) []byte {
+	config := make([]byte, 64)
+	binary.LittleEndian.PutUint32(config, 64)
+	copy(config[8:10], rt.language)
+	copy(config[10:12], rt.region)
+
+	headerSize := 20 + len(config)
+	offsets := make([]uint32, entryCount)
+	for i := range offsets {
+		offsets[i] = resNoEntry
+	}
+	var entries []byte
+	for _, key := range rt.entries {
+		offsets[key] = uint32(len(entries))
+		entries = binary.LittleEndian.AppendUint16(entries, 8) // Entry size
+		entries = binary.LittleEndian.AppendUint16(entries, 0) // Flags
+		entries = binary.LittleEndian.AppendUint32(entries, uint32(key))
+		entries = append(entries, 8, 0, 0, 0x03, 0, 0, 0, 0) // Res_value of a string
+	}
+
+	entriesStart := headerSize + 4*entryCount
+	chunk := binary.LittleEndian.AppendUint16(nil, resTableTypeType)
+	chunk = binary.LittleEndian.AppendUint16(chunk, uint16(headerSize))
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(entriesStart+len(entries)))
+	chunk = append(chunk, byte(rt.typeID), 0, 0, 0)
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(entryCount))
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(entriesStart))
+	chunk = append(chunk, config...)
+	for _, offset := range offsets {
+		chunk = binary.LittleEndian.AppendUint32(chunk, offset)
+	}
+	return append(chunk, entries...)
+}
+
+// encodeTestResourceTable builds a resources.arsc with one package
+func encodeTestResourceTable(typeNames, keyNames []string, resourceTypes []testResourceType) []byte {
+	typePool := encodeTestStringPool(typeNames, false)
+	keyPool := encodeTestStringPool(keyNames, true)
+
+	headerSize := 288
+	var body []byte
+	body = append(body, typePool...)
+	body = append(body, keyPool...)
+	for _, rt := range resourceTypes {
+		body = append(body, encodeTestResourceType(rt, len(keyNames))...)
+	}
+
+	pkg := binary.LittleEndian.AppendUint16(nil, resTablePackageType)
+	pkg = binary.LittleEndian.AppendUint16(pkg, uint16(headerSize))
+	pkg = binary.LittleEndian.AppendUint32(pkg, uint32(headerSize+len(body)))
+	pkg = binary.LittleEndian.AppendUint32(pkg, 0x7f)
+	pkg = append(pkg, make([]byte, 256)...) // Package name
+	pkg = binary.LittleEndian.AppendUint32(pkg, uint32(headerSize))
+	pkg = binary.LittleEndian.AppendUint32(pkg, uint32(len(typeNames)))
+	pkg = binary.LittleEndian.AppendUint32(pkg, uint32(headerSize+len(typePool)))
+	pkg = binary.LittleEndian.AppendUint32(pkg, uint32(len(keyNames)))
+	pkg = binary.LittleEndian.AppendUint32(pkg, 0)
+	pkg = append(pkg, body...)
+
+	globalPool := encodeTestStringPool(nil, true)
+	table := binary.LittleEndian.AppendUint16(nil, resTableType)
+	table = binary.LittleEndian.AppendUint16(table, 12)
+	table = binary.LittleEndian.AppendUint32(table, uint32(12+len(globalPool)+len(pkg)))
+	table = binary.LittleEndian.AppendUint32(table, 1)
+	table = append(table, globalPool...)
+	return append(table, pkg...)
+}
+
+func TestParseResourceTable(t *testing.T) {
+	data := encodeTestResourceTable(
+		[]string{"layout", "string"},
+		[]string{"app_name", "welcome", "abc_action_bar_home_description"},
+		[]testResourceType{
+			{typeID: 2, entries: []int{0, 1, 2}},
+			{typeID: 2, language: "de", entries: []int{0, 2}},
+			{typeID: 2, language: "pt", region: "BR", entries: []int{2}},
+			{typeID: 1, language: "de", entries: []int{1}},
+		},
+	)
+
+	values, err := parseResourceTable(data)
+	if err != nil {
+		t.Fatalf("parseResourceTable() error = %v", err)
+	}
+	if len(values) != 4 {
+		t.Fatalf("got %d resource values, want 4", len(values))
+	}
+
+	want := []struct {
+		typeName, locale string
+		keys             []string
+	}{
+		{"string", "", []string{"app_name", "welcome", "abc_action_bar_home_description"}},
+		{"string", "de", []string{"app_name", "abc_action_bar_home_description"}},
+		{"string", "pt-BR", []string{"abc_action_bar_home_description"}},
+		{"layout", "de", []string{"welcome"}},
+	}
+	for i, w := range want {
+		v := values[i]
+		if v.typeName != w.typeName || v.locale != w.locale || !reflect.DeepEqual(v.keys, w.keys) {
+			t.Errorf("values[%d] = %s/%s %v, want %s/%s %v", i, v.typeName, v.locale, v.keys, w.typeName, w.locale, w.keys)
+		}
+		if v.size == 0 {
+			t.Errorf("values[%d] has no size", i)
+		}
+	}
+}
+
+func TestParseResourceTable_Invalid(t *testing.T) {
+	if _, err := parseResourceTable([]byte("not a table")); err == nil {
+		t.Error("parseResourceTable() should fail for non-table data")
+	}
+
+	data := encodeTestResourceTable([]string{"string"}, []string{"app_name"}, []testResourceType{{typeID: 1, entries: []int{0}}})
+	if _, err := parseResourceTable(data[:len(data)-10]); err == nil {
+		t.Error("parseResourceTable() should fail for truncated data")
+	}
+}
+
+func TestUnpackLocaleCode(t *testing.T) {
+	// "fil" packed into 5 bits per letter: f=5, i=8, l=11
+	packed := []byte{0x80 | 11<<2 | 8>>3, (8&7)<<5 | 5}
+
+	tests := []struct {
+		name string
+		code []byte
+		base byte
+		want string
+	}{
+		{"two letters", []byte("de"), 'a', "de"},
+		{"unset", []byte{0, 0}, 'a', ""},
+		{"three letters", packed, 'a', "fil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unpackLocaleCode(tt.code, tt.base); got != tt.want {
+				t.Errorf("unpackLocaleCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
 )
 
@@ -166,6 +167,8 @@ func findNodeByPath(nodes []*types.FileNode, path string) *types.FileNode {
 func ReplaceDEXFilesWithVirtual(fileTree []*types.FileNode, dexTree *types.FileNode) []*types.FileNode {
 	result := make([]*types.FileNode, 0, len(fileTree))
 	dexAdded := false
+	var size, compressedSize int64
+	var method string
 
 	for _, node := range fileTree {
 		// Skip individual .dex files
@@ -175,10 +178,20 @@ func ReplaceDEXFilesWithVirtual(fileTree []*types.FileNode, dexTree *types.FileN
 				result = append(result, dexTree)
 				dexAdded = true
 			}
+			size += node.Size
+			compressedSize += node.CompressedSize
+			if method == "" {
+				method = node.CompressionMethod
+			}
 			continue
 		}
 		result = append(result, node)
 	}
 
+	// Estimate compressed sizes of classes from the compression of the .dex files
+	if dexAdded && compressedSize > 0 {
+		util.EstimateCompressedSizes(dexTree, compressedSize, size, method)
+	}
+
 	return result
 }
@@ -0,0 +1,248 @@
+package android
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/localization"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// stringResourceTypes are the resource types compared for missing translations
+var stringResourceTypes = map[string]bool{"string": true, "plurals": true, "array": true}
+
+// libraryResourcePrefixes are name prefixes of resources that AndroidX, Material
+// Components and Google Play services merge into apps, translated into dozens of languages
+var libraryResourcePrefixes = []string{
+	"abc_", "androidx_", "appbar_", "bottom_sheet_", "bottomsheet_", "call_notification_",
+	"character_counter_", "clear_text_end_icon_", "common_google_play_services_", "error_icon_",
+	"exo_", "exposed_dropdown_menu_", "fab_", "fcm_", "hide_bottom_view_", "icon_content_description",
+	"item_view_role_description", "m3_", "material_", "mtrl_", "password_toggle_", "path_password_",
+	"search_menu_title", "searchbar_", "searchview_", "side_sheet_", "status_bar_notification_",
+}
+
+// AnalyzeLocalization inventories the locales of an APK or app bundle: the locale-specific
+// values of resources.arsc or of each module's resources.pb, and locale-qualified res/ files.
+// Locales that only have resources of common libraries are marked as third-party.
+// Returns nil when the artifact has no localized resources.
+func AnalyzeLocalization(zr *zip.Reader) *types.LocalizationInfo {
+	collector := localization.NewCollector()
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		switch {
+		case f.Name == "resources.arsc":
+			collectResourceTable(collector, f, "", parseResourceTable)
+
+		case strings.HasSuffix(f.Name, "/resources.pb") && strings.Count(f.Name, "/") == 1:
+			module := strings.TrimSuffix(f.Name, "/resources.pb")
+			collectResourceTable(collector, f, module, parseProtoResourceTable)
+
+		default:
+			if dir, locale := localizedResourceDir(f.Name); locale != "" {
+				collector.AddSize(locale, dir, int64(f.UncompressedSize64), true)
+			}
+		}
+	}
+
+	info := collector.Result(localization.DefaultLocale)
+	if info == nil || (len(info.Locales) == 1 && info.Locales[0].IsBase) {
+		return nil // Nothing localized
+	}
+	return info
+}
+
+// collectResourceTable adds the values of a resource table by locale. Tables are
+// named by module so that app bundle modules are compared separately.
+func collectResourceTable(collector *localization.Collector, f *zip.File, module string, parse func([]byte) ([]resourceValues, error)) {
+	rc, err := f.Open()
+	if err != nil {
+		return
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return
+	}
+
+	values, err := parse(data)
+	if err != nil {
+		return
+	}
+
+	sizes := make(map[string]int64)
+	firstParty := make(map[string]bool)
+	for _, v := range values {
+		locale := v.locale
+		if locale == "" {
+			// Unqualified values of other types are layouts, styles and so on, not translations
+			if !stringResourceTypes[v.typeName] {
+				continue
+			}
+			locale = localization.DefaultLocale
+		}
+		sizes[locale] += v.size
+		firstParty[locale] = firstParty[locale] || locale == localization.DefaultLocale || hasAppResource(v.keys)
+
+		if stringResourceTypes[v.typeName] {
+			tableName := v.typeName
+			if module != "" {
+				tableName = module + "/" + v.typeName
+			}
+			collector.AddKeys(locale, tableName, v.keys, true)
+		}
+	}
+
+	for locale, size := range sizes {
+		collector.AddSize(locale, f.Name, size, firstParty[locale])
+	}
+}
+
+// hasAppResource reports whether any of the resource names is not one of a library
+func hasAppResource(names []string) bool {
+	for _, name := range names {
+		library := false
+		for _, prefix := range libraryResourcePrefixes {
+			if strings.HasPrefix(name, prefix) {
+				library = true
+				break
+			}
+		}
+		if !library {
+			return true
+		}
+	}
+	return false
+}
+
+// localizedResourceDir returns the res/ directory of a locale-qualified resource file and
+// its locale, e.g. "res/raw-de" and "de" for res/raw-de/terms.html or base/res/raw-de/terms.html
+func localizedResourceDir(name string) (string, string) {
+	parts := strings.Split(name, "/")
+	resIndex := -1
+	switch {
+	case len(parts) >= 3 && parts[0] == "res":
+		resIndex = 0
+	case len(parts) >= 4 && parts[1] == "res":
+		resIndex = 1
+	default:
+		return "", ""
+	}
+
+	qualifiers := strings.Split(parts[resIndex+1], "-")
+	for i, q := range qualifiers[1:] {
+		if !isLanguageQualifier(q) {
+			continue
+		}
+		tag := q
+		if next := i + 2; next < len(qualifiers) && len(qualifiers[next]) == 3 && qualifiers[next][0] == 'r' {
+			tag += "-" + qualifiers[next] // Region, e.g. pt-rBR
+		}
+		return strings.Join(parts[:resIndex+2], "/"), localization.Normalize(tag)
+	}
+	return "", ""
+}
+
+// parseProtoResourceTable reads the resource values of an aapt2 ResourceTable (resources.pb)
+// by type and locale: package(2) > type(3) > entry(3) > config_value(6). Sizes are the raw
+// size of the config values.
+func parseProtoResourceTable(data []byte) ([]resourceValues, error) {
+	type valuesKey struct{ typeName, locale string }
+	byKey := make(map[valuesKey]*resourceValues)
+	var order []valuesKey
+
+	err := readProtoFields(data, func(pkg protoField) error {
+		if pkg.Number != 2 || pkg.WireType != wireBytes {
+			return nil
+		}
+		return readProtoFields(pkg.Bytes, func(typ protoField) error {
+			if typ.Number != 3 || typ.WireType != wireBytes {
+				return nil
+			}
+
+			var typeName string
+			var entries [][]byte
+			err := readProtoFields(typ.Bytes, func(f protoField) error {
+				switch {
+				case f.Number == 2 && f.WireType == wireBytes:
+					typeName = string(f.Bytes)
+				case f.Number == 3 && f.WireType == wireBytes:
+					entries = append(entries, f.Bytes)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, entry := range entries {
+				var name string
+				var configValues [][]byte
+				err := readProtoFields(entry, func(f protoField) error {
+					switch {
+					case f.Number == 2 && f.WireType == wireBytes:
+						name = string(f.Bytes)
+					case f.Number == 6 && f.WireType == wireBytes:
+						configValues = append(configValues, f.Bytes)
+					}
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+
+				seen := make(map[string]bool)
+				for _, configValue := range configValues {
+					locale, err := configValueLocale(configValue)
+					if err != nil {
+						return err
+					}
+					key := valuesKey{typeName, locale}
+					values := byKey[key]
+					if values == nil {
+						values = &resourceValues{typeName: typeName, locale: locale}
+						byKey[key] = values
+						order = append(order, key)
+					}
+					values.size += int64(len(configValue))
+					if !seen[locale] {
+						seen[locale] = true
+						values.keys = append(values.keys, name)
+					}
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse resource table: %w", err)
+	}
+
+	values := make([]resourceValues, 0, len(order))
+	for _, key := range order {
+		values = append(values, *byKey[key])
+	}
+	return values, nil
+}
+
+// configValueLocale returns the locale of a ConfigValue's configuration as a BCP 47 tag, or ""
+func configValueLocale(configValue []byte) (string, error) {
+	var locale string
+	err := readProtoFields(configValue, func(f protoField) error {
+		if f.Number != 1 || f.WireType != wireBytes {
+			return nil
+		}
+		return readProtoFields(f.Bytes, func(c protoField) error {
+			if c.Number == 3 && c.WireType == wireBytes && len(c.Bytes) > 0 {
+				locale = localization.Normalize(string(c.Bytes))
+			}
+			return nil
+		})
+	})
+	return locale, err
+}
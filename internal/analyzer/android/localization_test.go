@@ -0,0 +1,129 @@
+package android
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// createLocalizedResourceTable builds a resources.pb with string entries translated into
+// the given locales ("" for default)
+func createLocalizedResourceTable(entries map[string][]string) []byte {
+	var resType []byte
+	resType = appendProtoBytes(resType, 2, []byte("string"))
+	for name, locales := range entries {
+		var entry []byte
+		entry = appendProtoBytes(entry, 2, []byte(name))
+		for _, locale := range locales {
+			var config []byte
+			if locale != "" {
+				config = appendProtoBytes(config, 3, []byte(locale))
+			}
+			var configValue []byte
+			configValue = appendProtoBytes(configValue, 1, config)
+			configValue = appendProtoBytes(configValue, 2, make([]byte, 10))
+			entry = appendProtoBytes(entry, 6, configValue)
+		}
+		resType = appendProtoBytes(resType, 3, entry)
+	}
+
+	pkg := appendProtoBytes(nil, 3, resType)
+	return appendProtoBytes(nil, 2, pkg)
+}
+
+// findLocale returns the inventory entry of a locale
+func findLocale(t *testing.T, info *types.LocalizationInfo, locale string) types.LocaleInfo {
+	t.Helper()
+	for _, l := range info.Locales {
+		if l.Locale == locale {
+			return l
+		}
+	}
+	t.Fatalf("locale %s not found in %+v", locale, info.Locales)
+	return types.LocaleInfo{}
+}
+
+func TestAnalyzeLocalization_APK(t *testing.T) {
+	table := encodeTestResourceTable(
+		[]string{"string"},
+		[]string{"app_name", "welcome", "abc_action_bar_home_description"},
+		[]testResourceType{
+			{typeID: 1, entries: []int{0, 1, 2}},
+			{typeID: 1, language: "de", entries: []int{0, 2}},
+			{typeID: 1, language: "af", entries: []int{2}},
+		},
+	)
+	zr := createSplitTestBundle(t, map[string][]byte{
+		"resources.arsc":           table,
+		"res/raw-pt-rBR/terms.txt": make([]byte, 100),
+		"res/raw/terms.txt":        make([]byte, 100),
+	})
+
+	info := AnalyzeLocalization(zr)
+	if info == nil {
+		t.Fatal("AnalyzeLocalization() = nil")
+	}
+	if info.BaseLocale != "default" || len(info.Locales) != 4 {
+		t.Fatalf("got base %s and %d locales, want default and 4", info.BaseLocale, len(info.Locales))
+	}
+
+	if base := findLocale(t, info, "default"); !base.IsBase || base.Keys != 3 {
+		t.Errorf("default = %+v, want the base locale with 3 keys", base)
+	}
+	if de := findLocale(t, info, "de"); de.Keys != 2 || de.MissingKeys != 1 || de.ThirdPartyOnly {
+		t.Errorf("de = %+v, want 2 keys, 1 missing, shipped by the app", de)
+	}
+	if af := findLocale(t, info, "af"); !af.ThirdPartyOnly || af.MissingKeys != 0 {
+		t.Errorf("af = %+v, want a third-party only locale without missing keys", af)
+	}
+	if pt := findLocale(t, info, "pt-BR"); pt.Size != 100 || pt.Sources[0] != "res/raw-pt-rBR" {
+		t.Errorf("pt-BR = %+v, want the size of res/raw-pt-rBR", pt)
+	}
+}
+
+func TestAnalyzeLocalization_AppBundle(t *testing.T) {
+	zr := createSplitTestBundle(t, map[string][]byte{
+		"base/resources.pb": createLocalizedResourceTable(map[string][]string{
+			"app_name": {"", "fr-CA", "b+sr+Latn"},
+			"welcome":  {"", "sr-Latn"},
+		}),
+		"base/res/raw/terms.txt": make([]byte, 10),
+	})
+
+	info := AnalyzeLocalization(zr)
+	if info == nil {
+		t.Fatal("AnalyzeLocalization() = nil")
+	}
+	if fr := findLocale(t, info, "fr-CA"); fr.Keys != 1 || fr.MissingKeys != 1 || fr.Sources[0] != "base/resources.pb" {
+		t.Errorf("fr-CA = %+v, want 1 key and 1 missing from base/resources.pb", fr)
+	}
+	if sr := findLocale(t, info, "sr-Latn"); sr.Keys != 2 || sr.MissingKeys != 0 {
+		t.Errorf("sr-Latn = %+v, want both keys translated", sr)
+	}
+}
+
+func TestAnalyzeLocalization_NotLocalized(t *testing.T) {
+	zr := createSplitTestBundle(t, map[string][]byte{
+		"base/resources.pb": createLocalizedResourceTable(map[string][]string{"app_name": {""}}),
+	})
+	if info := AnalyzeLocalization(zr); info != nil {
+		t.Errorf("AnalyzeLocalization() = %+v, want nil without locales", info)
+	}
+}
+
+func TestLocalizedResourceDir(t *testing.T) {
+	tests := []struct {
+		name, wantDir, wantLocale string
+	}{
+		{"res/raw-de/terms.html", "res/raw-de", "de"},
+		{"base/res/xml-pt-rBR-v21/config.xml", "base/res/xml-pt-rBR-v21", "pt-BR"},
+		{"res/drawable-xxhdpi/icon.png", "", ""},
+		{"assets/de/terms.html", "", ""},
+	}
+	for _, tt := range tests {
+		dir, locale := localizedResourceDir(tt.name)
+		if dir != tt.wantDir || locale != tt.wantLocale {
+			t.Errorf("localizedResourceDir(%q) = %q, %q, want %q, %q", tt.name, dir, locale, tt.wantDir, tt.wantLocale)
+		}
+	}
+}
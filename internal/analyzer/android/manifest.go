@@ -0,0 +1,214 @@
+package android
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shogo82148/androidbinary"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// manifestComponentTypes are the application elements reported as components
+var manifestComponentTypes = map[string]bool{"activity": true, "activity-alias": true, "service": true, "receiver": true, "provider": true}
+
+// ReadManifestInfo reads the SDK levels, permissions, components and application flags of
+// the manifest at name in an APK (binary XML) or app bundle (protobuf XML).
+// Returns nil when the manifest is missing or cannot be parsed.
+func ReadManifestInfo(zr *zip.Reader, name string) *types.ManifestInfo {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil
+		}
+
+		root, err := parseBinaryXML(data)
+		if err != nil {
+			if root, err = parseProtoXML(data); err != nil {
+				return nil
+			}
+		}
+		return parseManifestInfo(root)
+	}
+	return nil
+}
+
+// parseBinaryXML parses an Android binary XML document into an element tree
+func parseBinaryXML(data []byte) (*protoXMLElement, error) {
+	xmlFile, err := androidbinary.NewXMLFile(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse binary XML: %w", err)
+	}
+
+	var root *protoXMLElement
+	var stack []*protoXMLElement
+	decoder := xml.NewDecoder(xmlFile.Reader())
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode binary XML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			elem := &protoXMLElement{Namespace: t.Name.Space, Name: t.Name.Local}
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" {
+					continue
+				}
+				elem.Attributes = append(elem.Attributes, protoXMLAttribute{Namespace: a.Name.Space, Name: a.Name.Local, Value: a.Value})
+			}
+			if len(stack) == 0 {
+				root = elem
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, elem)
+			}
+			stack = append(stack, elem)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("binary XML has no root element")
+	}
+	return root, nil
+}
+
+// parseManifestInfo extracts the manifest details from the root <manifest> element
+func parseManifestInfo(root *protoXMLElement) *types.ManifestInfo {
+	info := &types.ManifestInfo{
+		CompileSDK: manifestInt(root.attr("compileSdkVersion")),
+	}
+	if info.CompileSDK == 0 {
+		info.CompileSDK = manifestInt(root.attr("platformBuildVersionCode"))
+	}
+	if usesSDK := root.child("uses-sdk"); usesSDK != nil {
+		info.MinSDK = manifestInt(usesSDK.attr("minSdkVersion"))
+		info.TargetSDK = manifestInt(usesSDK.attr("targetSdkVersion"))
+	}
+	if info.TargetSDK == 0 {
+		info.TargetSDK = info.MinSDK // The platform defaults targetSdkVersion to minSdkVersion
+	}
+
+	permissions := make(map[string]bool)
+	for _, child := range root.Children {
+		switch child.Name {
+		case "uses-permission", "uses-permission-sdk-23", "uses-permission-sdk-m":
+			if name := child.attr("name"); name != "" && !permissions[name] {
+				permissions[name] = true
+				info.Permissions = append(info.Permissions, name)
+			}
+		case "permission":
+			if name := child.attr("name"); name != "" {
+				info.CustomPermissions = append(info.CustomPermissions, name)
+			}
+		}
+	}
+	sort.Strings(info.Permissions)
+	sort.Strings(info.CustomPermissions)
+
+	app := root.child("application")
+	if app == nil {
+		return info
+	}
+	info.Debuggable = app.attr("debuggable") == "true"
+	info.ExtractNativeLibs = manifestBool(app.attr("extractNativeLibs"))
+	info.UsesCleartextTraffic = manifestBool(app.attr("usesCleartextTraffic"))
+
+	pkg := root.attr("package")
+	for _, child := range app.Children {
+		switch {
+		case child.Name == "meta-data":
+			if name := child.attr("name"); name != "" {
+				info.MetaDataKeys = append(info.MetaDataKeys, name)
+			}
+		case manifestComponentTypes[child.Name]:
+			info.Components = append(info.Components, parseManifestComponent(child, pkg, info.TargetSDK))
+		}
+	}
+	sort.Strings(info.MetaDataKeys)
+
+	return info
+}
+
+// parseManifestComponent describes an application component. Without an explicit
+// android:exported, components with intent filters are exported, and providers are
+// exported for apps targeting API levels below 17.
+func parseManifestComponent(elem *protoXMLElement, pkg string, targetSDK int) types.ManifestComponent {
+	component := types.ManifestComponent{
+		Type:            elem.Name,
+		Name:            qualifiedClassName(elem.attr("name"), pkg),
+		HasIntentFilter: elem.child("intent-filter") != nil,
+	}
+
+	if exported := manifestBool(elem.attr("exported")); exported != nil {
+		component.Exported = *exported
+	} else if elem.Name == "provider" {
+		component.Exported = targetSDK > 0 && targetSDK < 17
+	} else {
+		component.Exported = component.HasIntentFilter
+	}
+	return component
+}
+
+// qualifiedClassName expands class names relative to the package, e.g. ".MainActivity"
+func qualifiedClassName(name, pkg string) string {
+	switch {
+	case pkg == "" || name == "":
+		return name
+	case strings.HasPrefix(name, "."):
+		return pkg + name
+	case !strings.Contains(name, "."):
+		return pkg + "." + name
+	}
+	return name
+}
+
+// manifestInt parses an integer attribute, returning 0 for missing values and
+// references such as codenames that are not resolved
+func manifestInt(value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// manifestBool parses a boolean attribute, returning nil when it is not set
+func manifestBool(value string) *bool {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil
+	}
+	return &b
+}
+
+// ManifestWarnings reports release risks of the manifest: debuggable builds
+func ManifestWarnings(info *types.ManifestInfo) []string {
+	if info == nil || !info.Debuggable {
+		return nil
+	}
+	return []string{"The application is debuggable (android:debuggable=\"true\"); " +
+		"debuggable builds are rejected by Google Play and expose the app to debuggers"}
+}
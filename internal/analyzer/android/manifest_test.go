@@ -0,0 +1,100 @@
+package android
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func TestParseManifestInfo(t *testing.T) {
+	data := encodeTestXML(testXML{
+		name:  "manifest",
+		attrs: map[string]string{"package": "com.example.app", "compileSdkVersion": "34"},
+		children: []testXML{
+			{name: "uses-sdk", attrs: map[string]string{"minSdkVersion": "24", "targetSdkVersion": "34"}},
+			{name: "uses-permission", attrs: map[string]string{"name": "android.permission.INTERNET"}},
+			{name: "uses-permission-sdk-23", attrs: map[string]string{"name": "android.permission.CAMERA"}},
+			{name: "uses-permission", attrs: map[string]string{"name": "android.permission.INTERNET"}},
+			{name: "permission", attrs: map[string]string{"name": "com.example.app.permission.C2D"}},
+			{name: "application", attrs: map[string]string{"debuggable": "true", "extractNativeLibs": "false"}, children: []testXML{
+				{name: "activity", attrs: map[string]string{"name": ".MainActivity"}, children: []testXML{{name: "intent-filter"}}},
+				{name: "activity", attrs: map[string]string{"name": "SettingsActivity", "exported": "false"}},
+				{name: "service", attrs: map[string]string{"name": "com.example.sync.SyncService", "exported": "true"}},
+				{name: "provider", attrs: map[string]string{"name": "androidx.startup.InitializationProvider"}},
+				{name: "meta-data", attrs: map[string]string{"name": "com.google.android.gms.version"}},
+			}},
+		},
+	})
+
+	root, err := parseProtoXML(data)
+	if err != nil {
+		t.Fatalf("parseProtoXML() error = %v", err)
+	}
+	info := parseManifestInfo(root)
+
+	if info.MinSDK != 24 || info.TargetSDK != 34 || info.CompileSDK != 34 {
+		t.Errorf("SDK levels = %d/%d/%d, want 24/34/34", info.MinSDK, info.TargetSDK, info.CompileSDK)
+	}
+	if want := []string{"android.permission.CAMERA", "android.permission.INTERNET"}; !reflect.DeepEqual(info.Permissions, want) {
+		t.Errorf("Permissions = %v, want %v", info.Permissions, want)
+	}
+	if want := []string{"com.example.app.permission.C2D"}; !reflect.DeepEqual(info.CustomPermissions, want) {
+		t.Errorf("CustomPermissions = %v, want %v", info.CustomPermissions, want)
+	}
+	if !info.Debuggable || info.ExtractNativeLibs == nil || *info.ExtractNativeLibs || info.UsesCleartextTraffic != nil {
+		t.Errorf("flags = debuggable %v, extractNativeLibs %v, usesCleartextTraffic %v", info.Debuggable, info.ExtractNativeLibs, info.UsesCleartextTraffic)
+	}
+	if want := []string{"com.google.android.gms.version"}; !reflect.DeepEqual(info.MetaDataKeys, want) {
+		t.Errorf("MetaDataKeys = %v, want %v", info.MetaDataKeys, want)
+	}
+
+	want := []types.ManifestComponent{
+		{Type: "activity", Name: "com.example.app.MainActivity", Exported: true, HasIntentFilter: true},
+		{Type: "activity", Name: "com.example.app.SettingsActivity"},
+		{Type: "service", Name: "com.example.sync.SyncService", Exported: true},
+		{Type: "provider", Name: "androidx.startup.InitializationProvider"},
+	}
+	if !reflect.DeepEqual(info.Components, want) {
+		t.Errorf("Components = %+v, want %+v", info.Components, want)
+	}
+}
+
+func TestParseManifestComponent_LegacyProvider(t *testing.T) {
+	provider := &protoXMLElement{Name: "provider", Attributes: []protoXMLAttribute{{Name: "name", Value: ".Files"}}}
+	if c := parseManifestComponent(provider, "com.example", 16); !c.Exported || c.Name != "com.example.Files" {
+		t.Errorf("component = %+v, want an exported provider when targeting API 16", c)
+	}
+}
+
+func TestReadManifestInfo_AppBundle(t *testing.T) {
+	zr := createSplitTestBundle(t, map[string][]byte{
+		"base/manifest/AndroidManifest.xml": encodeTestXML(testXML{
+			name:     "manifest",
+			children: []testXML{{name: "uses-sdk", attrs: map[string]string{"minSdkVersion": "21"}}},
+		}),
+	})
+
+	info := ReadManifestInfo(zr, "base/manifest/AndroidManifest.xml")
+	if info == nil {
+		t.Fatal("ReadManifestInfo() = nil")
+	}
+	if info.MinSDK != 21 || info.TargetSDK != 21 {
+		t.Errorf("SDK levels = %d/%d, want target to default to min 21", info.MinSDK, info.TargetSDK)
+	}
+	if ReadManifestInfo(zr, "AndroidManifest.xml") != nil {
+		t.Error("ReadManifestInfo() should be nil for a missing manifest")
+	}
+}
+
+func TestManifestWarnings(t *testing.T) {
+	if warnings := ManifestWarnings(&types.ManifestInfo{Debuggable: true}); len(warnings) != 1 {
+		t.Errorf("ManifestWarnings() = %v, want a debuggable warning", warnings)
+	}
+	if warnings := ManifestWarnings(&types.ManifestInfo{}); warnings != nil {
+		t.Errorf("ManifestWarnings() = %v, want none", warnings)
+	}
+	if ManifestWarnings(nil) != nil {
+		t.Error("ManifestWarnings(nil) should be nil")
+	}
+}
@@ -0,0 +1,263 @@
+package android
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// Module types
+const (
+	moduleTypeBase      = "base"
+	moduleTypeFeature   = "feature"
+	moduleTypeAssetPack = "asset-pack"
+)
+
+// Module delivery types
+const (
+	deliveryInstallTime = "install-time"
+	deliveryConditional = "conditional"
+	deliveryFastFollow  = "fast-follow"
+	deliveryOnDemand    = "on-demand"
+)
+
+// largeBaseContentThreshold is the minimum size of a base module asset suggested for a feature module
+const largeBaseContentThreshold = 1024 * 1024
+
+// analyzeModules describes every module of the bundle with its delivery settings and
+// per-category sizes. Delivery settings come from the module's protobuf manifest.
+func analyzeModules(zr *zip.Reader) []types.ModuleInfo {
+	modules := make(map[string]*types.ModuleInfo)
+	manifests := make(map[string]*zip.File)
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name, path, ok := strings.Cut(f.Name, "/")
+		if !ok || name == "BUNDLE-METADATA" || name == "META-INF" {
+			continue
+		}
+
+		module, exists := modules[name]
+		if !exists {
+			module = &types.ModuleInfo{Name: name, Type: moduleTypeFeature, Delivery: deliveryInstallTime}
+			if name == baseModule {
+				module.Type = moduleTypeBase
+				module.Fusing = true
+			}
+			modules[name] = module
+		}
+
+		size := int64(f.UncompressedSize64)
+		module.Size += size
+		module.CompressedSize += int64(f.CompressedSize64)
+
+		switch {
+		case strings.HasPrefix(path, "dex/"):
+			module.DEXSize += size
+		case strings.HasPrefix(path, "lib/"):
+			module.LibrariesSize += size
+		case strings.HasPrefix(path, "assets/"):
+			module.AssetsSize += size
+		case strings.HasPrefix(path, "res/"), strings.HasPrefix(path, "manifest/"), path == "resources.pb":
+			module.ResourcesSize += size
+		default:
+			module.OtherSize += size
+		}
+
+		if path == "manifest/AndroidManifest.xml" {
+			manifests[name] = f
+		}
+	}
+
+	for name, f := range manifests {
+		if name == baseModule {
+			continue // The base module is always delivered at install time
+		}
+		if err := applyModuleManifest(modules[name], f); err != nil {
+			// Non-fatal, keep install-time defaults
+			continue
+		}
+	}
+
+	result := make([]types.ModuleInfo, 0, len(modules))
+	for _, module := range modules {
+		result = append(result, *module)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name == baseModule || result[j].Name == baseModule {
+			return result[i].Name == baseModule
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// applyModuleManifest reads the dist:module element of a module manifest:
+//
+//	<dist:module dist:type="asset-pack">
+//	  <dist:delivery>
+//	    <dist:install-time><dist:conditions>...</dist:conditions></dist:install-time>
+//	    <dist:on-demand/> or <dist:fast-follow/>
+//	  </dist:delivery>
+//	  <dist:fusing dist:include="true"/>
+//	</dist:module>
+func applyModuleManifest(module *types.ModuleInfo, f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return err
+	}
+
+	root, err := parseProtoXML(data)
+	if err != nil {
+		return err
+	}
+
+	dist := root.child("module")
+	if dist == nil {
+		return nil
+	}
+
+	if dist.attr("type") == moduleTypeAssetPack {
+		module.Type = moduleTypeAssetPack
+	}
+	if fusing := dist.child("fusing"); fusing != nil {
+		module.Fusing = fusing.attr("include") == "true"
+	}
+
+	delivery := dist.child("delivery")
+	if delivery == nil {
+		// Legacy syntax: <dist:module dist:onDemand="true">
+		if dist.attr("onDemand") == "true" {
+			module.Delivery = deliveryOnDemand
+		}
+		return nil
+	}
+
+	switch {
+	case delivery.child("install-time") != nil:
+		module.Delivery = deliveryInstallTime
+		if conditions := delivery.child("install-time").child("conditions"); conditions != nil && len(conditions.Children) > 0 {
+			module.Delivery = deliveryConditional
+			module.Conditions = describeConditions(conditions)
+		}
+	case delivery.child("fast-follow") != nil:
+		module.Delivery = deliveryFastFollow
+	case delivery.child("on-demand") != nil:
+		module.Delivery = deliveryOnDemand
+	}
+
+	return nil
+}
+
+// describeConditions formats conditional delivery requirements, e.g. "min-sdk 24"
+func describeConditions(conditions *protoXMLElement) []string {
+	var result []string
+	for _, c := range conditions.Children {
+		switch c.Name {
+		case "min-sdk", "max-sdk":
+			result = append(result, c.Name+" "+c.attr("value"))
+		case "device-feature":
+			result = append(result, "device-feature "+c.attr("name"))
+		case "user-countries":
+			var codes []string
+			for _, country := range c.Children {
+				codes = append(codes, country.attr("code"))
+			}
+			prefix := "user-countries "
+			if c.attr("exclude") == "true" {
+				prefix = "user-countries excluding "
+			}
+			result = append(result, prefix+strings.Join(codes, ","))
+		default:
+			result = append(result, c.Name)
+		}
+	}
+	return result
+}
+
+// summarizeModuleDelivery sums compressed module sizes by delivery type
+func summarizeModuleDelivery(modules []types.ModuleInfo) types.ModuleDeliverySizes {
+	var sizes types.ModuleDeliverySizes
+	for _, m := range modules {
+		switch m.Delivery {
+		case deliveryConditional:
+			sizes.Conditional += m.CompressedSize
+		case deliveryFastFollow:
+			sizes.FastFollow += m.CompressedSize
+		case deliveryOnDemand:
+			sizes.OnDemand += m.CompressedSize
+		default:
+			sizes.InstallTime += m.CompressedSize
+		}
+	}
+	return sizes
+}
+
+// installTimeModules returns the modules every device downloads at install time.
+// The base module is always included.
+func installTimeModules(modules []types.ModuleInfo) map[string]bool {
+	installed := map[string]bool{baseModule: true}
+	for _, m := range modules {
+		if m.Delivery == deliveryInstallTime {
+			installed[m.Name] = true
+		}
+	}
+	return installed
+}
+
+// generateFeatureModuleOptimizations suggests moving large base module assets and raw
+// resources into a feature module, where they can be delivered on demand
+func generateFeatureModuleOptimizations(zr *zip.Reader, modules []types.ModuleInfo) []types.Optimization {
+	var baseSize int64
+	for _, m := range modules {
+		if m.Name == baseModule {
+			baseSize = m.Size
+		}
+	}
+
+	var files []string
+	var total int64
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || f.UncompressedSize64 < largeBaseContentThreshold {
+			continue
+		}
+		if strings.HasPrefix(f.Name, baseModule+"/assets/") || strings.HasPrefix(f.Name, baseModule+"/res/raw") {
+			files = append(files, f.Name)
+			total += int64(f.UncompressedSize64)
+		}
+	}
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	severity := "low"
+	if baseSize > 0 && total*10 >= baseSize {
+		severity = "medium"
+	}
+
+	return []types.Optimization{{
+		Category: "dynamic-delivery",
+		Severity: severity,
+		Title:    fmt.Sprintf("Move %d large base module files into a feature module", len(files)),
+		Description: fmt.Sprintf("The base module ships %s of large assets and raw resources to every user at install time. "+
+			"Deferring them shrinks the install-time download, not the app, so no savings are counted",
+			util.FormatBytes(total)),
+		Impact: 0,
+		Files:  files,
+		Action: "If this content is rarely used, move it into an on-demand feature module or asset pack",
+	}}
+}
@@ -0,0 +1,183 @@
+package android
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// moduleManifest builds a protobuf module manifest with the given dist:module children
+func moduleManifest(moduleAttrs map[string]string, children ...testXML) []byte {
+	return encodeTestXML(testXML{
+		name: "manifest",
+		children: []testXML{
+			{name: "module", attrs: moduleAttrs, children: children},
+		},
+	})
+}
+
+func TestAnalyzeModules(t *testing.T) {
+	zr := createSplitTestBundle(t, map[string][]byte{
+		"BundleConfig.pb":                         make([]byte, 10),
+		"base/manifest/AndroidManifest.xml":       make([]byte, 100),
+		"base/dex/classes.dex":                    make([]byte, 1000),
+		"base/lib/arm64-v8a/libapp.so":            make([]byte, 500),
+		"base/assets/data.bin":                    make([]byte, 300),
+		"base/root/kotlin/kotlin.kotlin_builtins": make([]byte, 7),
+		"camera/manifest/AndroidManifest.xml": moduleManifest(nil,
+			testXML{name: "delivery", children: []testXML{{name: "on-demand"}}},
+			testXML{name: "fusing", attrs: map[string]string{"include": "true"}},
+		),
+		"camera/dex/classes.dex": make([]byte, 400),
+		"ar/manifest/AndroidManifest.xml": moduleManifest(nil,
+			testXML{name: "delivery", children: []testXML{{name: "install-time", children: []testXML{
+				{name: "conditions", children: []testXML{
+					{name: "device-feature", attrs: map[string]string{"name": "android.hardware.camera.ar"}},
+					{name: "min-sdk", attrs: map[string]string{"value": "24"}},
+					{name: "user-countries", attrs: map[string]string{"exclude": "true"}, children: []testXML{
+						{name: "country", attrs: map[string]string{"code": "CN"}},
+					}},
+				}},
+			}}}},
+		),
+		"onboarding/manifest/AndroidManifest.xml": moduleManifest(nil,
+			testXML{name: "delivery", children: []testXML{{name: "install-time"}}},
+		),
+		"onboarding/res/layout/intro.xml": make([]byte, 50),
+		"textures/manifest/AndroidManifest.xml": moduleManifest(map[string]string{"type": "asset-pack"},
+			testXML{name: "delivery", children: []testXML{{name: "fast-follow"}}},
+		),
+		"textures/assets/t.ktx":               make([]byte, 800),
+		"legacy/manifest/AndroidManifest.xml": moduleManifest(map[string]string{"onDemand": "true"}),
+	})
+
+	modules := analyzeModules(zr)
+
+	byName := make(map[string]types.ModuleInfo)
+	var order []string
+	for _, m := range modules {
+		byName[m.Name] = m
+		order = append(order, m.Name)
+	}
+	if strings.Join(order, ",") != "base,ar,camera,legacy,onboarding,textures" {
+		t.Errorf("module order = %v", order)
+	}
+
+	base := byName["base"]
+	if base.Type != "base" || base.Delivery != "install-time" || !base.Fusing {
+		t.Errorf("base = %+v", base)
+	}
+	if base.DEXSize != 1000 || base.LibrariesSize != 500 || base.AssetsSize != 300 || base.ResourcesSize != 100 || base.OtherSize != 7 {
+		t.Errorf("base sizes = %+v", base)
+	}
+	if base.Size != 1907 || base.CompressedSize != 1907 {
+		t.Errorf("base total = %d/%d, want 1907", base.Size, base.CompressedSize)
+	}
+
+	camera := byName["camera"]
+	if camera.Type != "feature" || camera.Delivery != "on-demand" || !camera.Fusing {
+		t.Errorf("camera = %+v", camera)
+	}
+
+	ar := byName["ar"]
+	wantConditions := "device-feature android.hardware.camera.ar|min-sdk 24|user-countries excluding CN"
+	if ar.Delivery != "conditional" || strings.Join(ar.Conditions, "|") != wantConditions {
+		t.Errorf("ar = %+v", ar)
+	}
+
+	if byName["onboarding"].Delivery != "install-time" {
+		t.Errorf("onboarding = %+v", byName["onboarding"])
+	}
+	if tex := byName["textures"]; tex.Type != "asset-pack" || tex.Delivery != "fast-follow" || tex.AssetsSize != 800 {
+		t.Errorf("textures = %+v", tex)
+	}
+	if byName["legacy"].Delivery != "on-demand" {
+		t.Errorf("legacy = %+v", byName["legacy"])
+	}
+
+	delivery := summarizeModuleDelivery(modules)
+	if delivery.InstallTime != base.CompressedSize+byName["onboarding"].CompressedSize {
+		t.Errorf("install-time = %d", delivery.InstallTime)
+	}
+	if delivery.OnDemand != camera.CompressedSize+byName["legacy"].CompressedSize {
+		t.Errorf("on-demand = %d", delivery.OnDemand)
+	}
+	if delivery.Conditional != ar.CompressedSize || delivery.FastFollow != byName["textures"].CompressedSize {
+		t.Errorf("delivery = %+v", delivery)
+	}
+
+	// Device downloads include install-time modules only
+	estimate, err := EstimateSplitSizes(zr, []types.DeviceSpec{{SupportedABIs: []string{"arm64-v8a"}, SDKVersion: 30}}, modules)
+	if err != nil {
+		t.Fatalf("EstimateSplitSizes() error = %v", err)
+	}
+	want := base.CompressedSize + byName["onboarding"].CompressedSize
+	if got := estimate.Devices[0].DownloadSize; got != want {
+		t.Errorf("download size = %d, want %d", got, want)
+	}
+}
+
+func TestGenerateFeatureModuleOptimizations(t *testing.T) {
+	zr := createSplitTestBundle(t, map[string][]byte{
+		"base/assets/model.tflite":     make([]byte, 2*largeBaseContentThreshold),
+		"base/res/raw/intro.mp4":       make([]byte, largeBaseContentThreshold),
+		"base/assets/small.json":       make([]byte, 100),
+		"base/dex/classes.dex":         make([]byte, largeBaseContentThreshold*2),
+		"feature/assets/big_video.mp4": make([]byte, 3*largeBaseContentThreshold),
+	})
+
+	opts := generateFeatureModuleOptimizations(zr, analyzeModules(zr))
+	if len(opts) != 1 {
+		t.Fatalf("got %d optimizations, want 1", len(opts))
+	}
+
+	opt := opts[0]
+	// Deferred content is still part of the app
+	if opt.Category != "dynamic-delivery" || opt.Impact != 0 {
+		t.Errorf("optimization = %+v", opt)
+	}
+	if !strings.Contains(opt.Description, util.FormatBytes(3*largeBaseContentThreshold)) {
+		t.Errorf("description = %q, want the size of the large base files", opt.Description)
+	}
+	if opt.Severity != "medium" {
+		t.Errorf("severity = %q, want medium for 60%% of the base module", opt.Severity)
+	}
+	if len(opt.Files) != 2 {
+		t.Errorf("files = %v, want the two large base files", opt.Files)
+	}
+}
+
+func TestGenerateFeatureModuleOptimizations_None(t *testing.T) {
+	zr := createSplitTestBundle(t, map[string][]byte{
+		"base/assets/small.json": make([]byte, 100),
+	})
+	if opts := generateFeatureModuleOptimizations(zr, analyzeModules(zr)); len(opts) != 0 {
+		t.Errorf("got %d optimizations, want none", len(opts))
+	}
+}
+
+func TestApplyProtoManifestInfo(t *testing.T) {
+	data := encodeTestXML(testXML{
+		name:  "manifest",
+		attrs: map[string]string{"package": "com.example.app", "versionName": "1.4.2", "versionCode": "142"},
+		children: []testXML{
+			{name: "application", attrs: map[string]string{"icon": "@mipmap/ic_launcher"}},
+		},
+	})
+
+	manifest := make(map[string]interface{})
+	applyProtoManifestInfo(manifest, data)
+
+	for key, want := range map[string]string{
+		"package":      "com.example.app",
+		"version":      "1.4.2",
+		"version_code": "142",
+		"icon_name":    "ic_launcher",
+	} {
+		if manifest[key] != want {
+			t.Errorf("%s = %v, want %q", key, manifest[key], want)
+		}
+	}
+}
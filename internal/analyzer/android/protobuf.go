@@ -0,0 +1,72 @@
+package android
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// protoField is a single field of a protobuf message in wire format
+type protoField struct {
+	Number   int
+	WireType int
+	Varint   uint64 // Set for varint fields
+	Bytes    []byte // Set for length-delimited fields (strings, bytes, messages)
+}
+
+// readProtoFields calls fn for every field of a wire-format protobuf message.
+// AAB files store manifests and resource tables as aapt2 protobufs; this minimal
+// reader avoids pulling in the generated aapt2 message types.
+func readProtoFields(data []byte, fn func(f protoField) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("invalid protobuf field key")
+		}
+		data = data[n:]
+
+		f := protoField{Number: int(key >> 3), WireType: int(key & 7)}
+		switch f.WireType {
+		case wireVarint:
+			v, n := binary.Uvarint(data)
+			if n <= 0 {
+				return fmt.Errorf("invalid varint in field %d", f.Number)
+			}
+			f.Varint = v
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("truncated fixed64 in field %d", f.Number)
+			}
+			f.Varint = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < length {
+				return fmt.Errorf("truncated length-delimited field %d", f.Number)
+			}
+			f.Bytes = data[n : n+int(length)]
+			data = data[n+int(length):]
+		case wireFixed32:
+			if len(data) < 4 {
+				return fmt.Errorf("truncated fixed32 in field %d", f.Number)
+			}
+			f.Varint = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		default:
+			return fmt.Errorf("unsupported wire type %d in field %d", f.WireType, f.Number)
+		}
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
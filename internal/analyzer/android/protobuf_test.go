@@ -0,0 +1,59 @@
+package android
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// appendProtoBytes appends a length-delimited field to a test message
+func appendProtoBytes(buf []byte, number int, value []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(number)<<3|wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+// appendProtoVarint appends a varint field to a test message
+func appendProtoVarint(buf []byte, number int, value uint64) []byte {
+	buf = binary.AppendUvarint(buf, uint64(number)<<3|wireVarint)
+	return binary.AppendUvarint(buf, value)
+}
+
+func TestReadProtoFields(t *testing.T) {
+	nested := appendProtoVarint(nil, 1, 7)
+	var msg []byte
+	msg = appendProtoVarint(msg, 1, 300)
+	msg = appendProtoBytes(msg, 2, []byte("hello"))
+	msg = appendProtoBytes(msg, 3, nested)
+	msg = append(msg, 4<<3|wireFixed32, 1, 0, 0, 0)
+
+	var got []protoField
+	if err := readProtoFields(msg, func(f protoField) error {
+		got = append(got, f)
+		return nil
+	}); err != nil {
+		t.Fatalf("readProtoFields() error = %v", err)
+	}
+
+	if len(got) != 4 {
+		t.Fatalf("got %d fields, want 4", len(got))
+	}
+	if got[0].Number != 1 || got[0].Varint != 300 {
+		t.Errorf("field 1 = %+v", got[0])
+	}
+	if got[1].Number != 2 || string(got[1].Bytes) != "hello" {
+		t.Errorf("field 2 = %+v", got[1])
+	}
+	if got[2].Number != 3 || string(got[2].Bytes) != string(nested) {
+		t.Errorf("field 3 = %+v", got[2])
+	}
+	if got[3].Number != 4 || got[3].Varint != 1 {
+		t.Errorf("field 4 = %+v", got[3])
+	}
+}
+
+func TestReadProtoFields_Truncated(t *testing.T) {
+	msg := appendProtoBytes(nil, 1, []byte("hello"))
+	if err := readProtoFields(msg[:len(msg)-2], func(protoField) error { return nil }); err == nil {
+		t.Error("readProtoFields() expected error for truncated message")
+	}
+}
@@ -0,0 +1,157 @@
+package android
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// protoXMLElement is an element of an aapt2 protobuf XML document, the format AAB
+// modules store their AndroidManifest.xml in. Binary XML documents of APKs are read
+// into the same tree by parseBinaryXML.
+type protoXMLElement struct {
+	Namespace  string
+	Name       string
+	Attributes []protoXMLAttribute
+	Children   []*protoXMLElement
+}
+
+// protoXMLAttribute is an attribute of a protobuf XML element
+type protoXMLAttribute struct {
+	Namespace string
+	Name      string
+	Value     string
+}
+
+// parseProtoXML parses an aapt2 XmlNode and returns its root element
+func parseProtoXML(data []byte) (*protoXMLElement, error) {
+	var root *protoXMLElement
+	err := readProtoFields(data, func(f protoField) error {
+		// XmlNode.element = 1
+		if f.Number != 1 || f.WireType != wireBytes {
+			return nil
+		}
+		var err error
+		root, err = parseProtoXMLElement(f.Bytes)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse protobuf XML: %w", err)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("protobuf XML has no root element")
+	}
+	return root, nil
+}
+
+// parseProtoXMLElement parses an XmlElement:
+// namespace_uri(2), name(3), attribute(4), child(5)
+func parseProtoXMLElement(data []byte) (*protoXMLElement, error) {
+	elem := &protoXMLElement{}
+	err := readProtoFields(data, func(f protoField) error {
+		if f.WireType != wireBytes {
+			return nil
+		}
+		switch f.Number {
+		case 2:
+			elem.Namespace = string(f.Bytes)
+		case 3:
+			elem.Name = string(f.Bytes)
+		case 4:
+			attr, err := parseProtoXMLAttribute(f.Bytes)
+			if err != nil {
+				return err
+			}
+			elem.Attributes = append(elem.Attributes, attr)
+		case 5:
+			// XmlNode child: element(1) or text(2), only elements are kept
+			return readProtoFields(f.Bytes, func(c protoField) error {
+				if c.Number != 1 || c.WireType != wireBytes {
+					return nil
+				}
+				child, err := parseProtoXMLElement(c.Bytes)
+				if err != nil {
+					return err
+				}
+				elem.Children = append(elem.Children, child)
+				return nil
+			})
+		}
+		return nil
+	})
+	return elem, err
+}
+
+// parseProtoXMLAttribute parses an XmlAttribute: namespace_uri(1), name(2), value(3),
+// compiled_item(6). Compiled booleans and integers are used when value is empty.
+func parseProtoXMLAttribute(data []byte) (protoXMLAttribute, error) {
+	var attr protoXMLAttribute
+	var compiled []byte
+	err := readProtoFields(data, func(f protoField) error {
+		if f.WireType != wireBytes {
+			return nil
+		}
+		switch f.Number {
+		case 1:
+			attr.Namespace = string(f.Bytes)
+		case 2:
+			attr.Name = string(f.Bytes)
+		case 3:
+			attr.Value = string(f.Bytes)
+		case 6:
+			compiled = f.Bytes
+		}
+		return nil
+	})
+	if err != nil || attr.Value != "" || compiled == nil {
+		return attr, err
+	}
+
+	attr.Value, err = compiledPrimitiveValue(compiled)
+	return attr, err
+}
+
+// compiledPrimitiveValue formats the primitive(7) of a compiled Item:
+// int_decimal_value(6), int_hexadecimal_value(7) or boolean_value(8)
+func compiledPrimitiveValue(item []byte) (string, error) {
+	var value string
+	err := readProtoFields(item, func(f protoField) error {
+		if f.Number != 7 || f.WireType != wireBytes {
+			return nil
+		}
+		return readProtoFields(f.Bytes, func(p protoField) error {
+			if p.WireType != wireVarint {
+				return nil
+			}
+			switch p.Number {
+			case 6:
+				value = strconv.FormatInt(int64(int32(p.Varint)), 10)
+			case 7:
+				value = "0x" + strconv.FormatUint(p.Varint, 16)
+			case 8:
+				value = strconv.FormatBool(p.Varint != 0)
+			}
+			return nil
+		})
+	})
+	return value, err
+}
+
+// attr returns the value of the attribute with the given local name, ignoring namespaces
+func (e *protoXMLElement) attr(name string) string {
+	for _, attr := range e.Attributes {
+		if attr.Name == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// child returns the first child element with the given local name, or nil
+func (e *protoXMLElement) child(name string) *protoXMLElement {
+	for _, child := range e.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package android
+
+import "testing"
+
+const distNamespace = "http://schemas.android.com/apk/distribution"
+
+// testXML describes an element for building protobuf XML fixtures
+type testXML struct {
+	name     string
+	attrs    map[string]string
+	children []testXML
+}
+
+// encodeTestXMLElement encodes an XmlElement
+func encodeTestXMLElement(e testXML) []byte {
+	var elem []byte
+	elem = appendProtoBytes(elem, 2, []byte(distNamespace))
+	elem = appendProtoBytes(elem, 3, []byte(e.name))
+	for name, value := range e.attrs {
+		var attr []byte
+		attr = appendProtoBytes(attr, 1, []byte(distNamespace))
+		attr = appendProtoBytes(attr, 2, []byte(name))
+		attr = appendProtoBytes(attr, 3, []byte(value))
+		elem = appendProtoBytes(elem, 4, attr)
+	}
+	for _, child := range e.children {
+		elem = appendProtoBytes(elem, 5, encodeTestXML(child))
+	}
+	return elem
+}
+
+// encodeTestXML encodes an XmlNode holding the element
+func encodeTestXML(e testXML) []byte {
+	return appendProtoBytes(nil, 1, encodeTestXMLElement(e))
+}
+
+func TestParseProtoXML(t *testing.T) {
+	data := encodeTestXML(testXML{
+		name:  "manifest",
+		attrs: map[string]string{"package": "com.example.app"},
+		children: []testXML{
+			{name: "module", attrs: map[string]string{"title": "@string/camera"}},
+		},
+	})
+
+	root, err := parseProtoXML(data)
+	if err != nil {
+		t.Fatalf("parseProtoXML() error = %v", err)
+	}
+	if root.Name != "manifest" || root.attr("package") != "com.example.app" {
+		t.Errorf("root = %+v", root)
+	}
+	if module := root.child("module"); module == nil || module.attr("title") != "@string/camera" {
+		t.Errorf("module child = %+v", module)
+	}
+	if root.child("application") != nil {
+		t.Error("child() returned an element for a missing name")
+	}
+}
+
+func TestParseProtoXML_CompiledBoolean(t *testing.T) {
+	// Attribute without a string value but a compiled primitive boolean_value(8)
+	primitive := appendProtoVarint(nil, 8, 1)
+	item := appendProtoBytes(nil, 7, primitive)
+	var attr []byte
+	attr = appendProtoBytes(attr, 2, []byte("onDemand"))
+	attr = appendProtoBytes(attr, 6, item)
+
+	var elem []byte
+	elem = appendProtoBytes(elem, 3, []byte("module"))
+	elem = appendProtoBytes(elem, 4, attr)
+
+	root, err := parseProtoXML(appendProtoBytes(nil, 1, elem))
+	if err != nil {
+		t.Fatalf("parseProtoXML() error = %v", err)
+	}
+	if got := root.attr("onDemand"); got != "true" {
+		t.Errorf("onDemand = %q, want %q", got, "true")
+	}
+}
+
+func TestParseProtoXML_Invalid(t *testing.T) {
+	if _, err := parseProtoXML([]byte("<manifest/>")); err == nil {
+		t.Error("parseProtoXML() expected error for text XML")
+	}
+}
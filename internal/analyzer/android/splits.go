@@ -0,0 +1,497 @@
+package android
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// Split types generated by bundletool
+const (
+	splitMaster   = "master"
+	splitABI      = "abi"
+	splitDensity  = "density"
+	splitLanguage = "language"
+)
+
+// baseModule is the module always installed with the app
+const baseModule = "base"
+
+// standaloneMaxSDK is the highest SDK level served standalone APKs instead of splits
+const standaloneMaxSDK = 20
+
+// densityBuckets are the screen densities bundletool generates density splits for
+var densityBuckets = []struct {
+	name string
+	dpi  int
+}{
+	{"ldpi", 120},
+	{"mdpi", 160},
+	{"tvdpi", 213},
+	{"hdpi", 240},
+	{"xhdpi", 320},
+	{"xxhdpi", 480},
+	{"xxxhdpi", 640},
+}
+
+// DefaultDeviceSpecs returns the device matrix used when no device specs are configured
+func DefaultDeviceSpecs() []types.DeviceSpec {
+	return []types.DeviceSpec{
+		{SupportedABIs: []string{"arm64-v8a", "armeabi-v7a", "armeabi"}, SupportedLocales: []string{"en-US"}, ScreenDensity: 480, SDKVersion: 34},
+		{SupportedABIs: []string{"arm64-v8a", "armeabi-v7a", "armeabi"}, SupportedLocales: []string{"en-US"}, ScreenDensity: 640, SDKVersion: 34},
+		{SupportedABIs: []string{"arm64-v8a", "armeabi-v7a", "armeabi"}, SupportedLocales: []string{"en-US"}, ScreenDensity: 320, SDKVersion: 29},
+		{SupportedABIs: []string{"armeabi-v7a", "armeabi"}, SupportedLocales: []string{"en-US"}, ScreenDensity: 240, SDKVersion: 24},
+		{SupportedABIs: []string{"x86_64", "x86"}, SupportedLocales: []string{"en-US"}, ScreenDensity: 320, SDKVersion: 30},
+	}
+}
+
+// bundleEntry is a file of an app bundle classified by the split APK it ends up in
+type bundleEntry struct {
+	module string
+	split  string
+	name   string // ABI or language for ABI and language splits
+	group  string // Resource identity shared by density variants
+	dpi    int
+	minSDK int
+	size   int64
+}
+
+// EstimateSplitSizes estimates the split APKs bundletool would generate from an app bundle
+// and the download size for each device. Sizes are based on the compressed size of the
+// bundle entries, so they approximate what the Play Store serves rather than match it exactly.
+// All modules are listed in the splits; device download sizes count the base module and
+// modules delivered at install time. Conditional, fast-follow and on-demand modules are excluded.
+func EstimateSplitSizes(zr *zip.Reader, devices []types.DeviceSpec, modules []types.ModuleInfo) (*types.SplitEstimate, error) {
+	entries, err := classifyBundleEntries(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := &types.SplitEstimate{
+		Splits: summarizeSplits(entries),
+	}
+
+	installTime := installTimeModules(modules)
+	installed := func(module string) bool { return installTime[module] }
+	for i, device := range devices {
+		deviceEstimate := estimateDeviceDownload(entries, device, installed)
+		estimate.Devices = append(estimate.Devices, deviceEstimate)
+
+		if i == 0 || deviceEstimate.DownloadSize < estimate.MinDownloadSize {
+			estimate.MinDownloadSize = deviceEstimate.DownloadSize
+		}
+		if deviceEstimate.DownloadSize > estimate.MaxDownloadSize {
+			estimate.MaxDownloadSize = deviceEstimate.DownloadSize
+		}
+	}
+
+	return estimate, nil
+}
+
+// DeviceName returns a short description of a device spec, e.g. "arm64-v8a/xxhdpi/sdk34"
+func DeviceName(device types.DeviceSpec) string {
+	if device.Name != "" {
+		return device.Name
+	}
+
+	abi := "any"
+	if len(device.SupportedABIs) > 0 {
+		abi = device.SupportedABIs[0]
+	}
+	name := abi + "/" + densityBucketName(device.ScreenDensity)
+	if device.SDKVersion > 0 {
+		name += "/sdk" + strconv.Itoa(device.SDKVersion)
+	}
+	return name
+}
+
+// classifyBundleEntries assigns every module file of the bundle to a split
+func classifyBundleEntries(zr *zip.Reader) ([]bundleEntry, error) {
+	var entries []bundleEntry
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		module, path, ok := strings.Cut(f.Name, "/")
+		if !ok || module == "BUNDLE-METADATA" || module == "META-INF" {
+			continue // Bundle-level files are not part of any APK
+		}
+		size := int64(f.CompressedSize64)
+
+		switch {
+		case strings.HasPrefix(path, "lib/"):
+			parts := strings.Split(path, "/")
+			if len(parts) < 3 {
+				entries = append(entries, bundleEntry{module: module, split: splitMaster, size: size})
+				continue
+			}
+			entries = append(entries, bundleEntry{module: module, split: splitABI, name: parts[1], size: size})
+
+		case strings.HasPrefix(path, "res/"):
+			entries = append(entries, classifyResourceFile(module, path, size))
+
+		case path == "resources.pb":
+			resourceEntries, err := classifyResourceTable(f, module)
+			if err != nil {
+				// Keep the whole table in the master split when it can't be parsed
+				entries = append(entries, bundleEntry{module: module, split: splitMaster, size: size})
+				continue
+			}
+			entries = append(entries, resourceEntries...)
+
+		default:
+			entries = append(entries, bundleEntry{module: module, split: splitMaster, size: size})
+		}
+	}
+
+	return entries, nil
+}
+
+// classifyResourceFile assigns a res/ file to the master, density or language split
+// based on its directory qualifiers, e.g. res/drawable-xxhdpi-v4/icon.png
+func classifyResourceFile(module, path string, size int64) bundleEntry {
+	entry := bundleEntry{module: module, split: splitMaster, size: size}
+
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 {
+		return entry
+	}
+
+	qualifiers := strings.Split(parts[1], "-")
+	groupParts := []string{qualifiers[0]}
+	for _, q := range qualifiers[1:] {
+		switch {
+		case densityDPI(q) > 0:
+			entry.dpi = densityDPI(q)
+		case isLanguageQualifier(q):
+			entry.name = languageFromQualifier(q)
+		case isSDKQualifier(q):
+			entry.minSDK, _ = strconv.Atoi(q[1:])
+		default:
+			groupParts = append(groupParts, q)
+		}
+	}
+
+	switch {
+	case entry.name != "":
+		entry.split = splitLanguage
+	case entry.dpi > 0:
+		entry.split = splitDensity
+		entry.group = strings.Join(groupParts, "-") + "/" + strings.Join(parts[2:], "/")
+	}
+	return entry
+}
+
+// classifyResourceTable splits resources.pb into its locale-specific values, which go
+// to language splits, and everything else, which stays in the master split.
+// Compressed size is distributed proportionally to the raw protobuf size.
+func classifyResourceTable(f *zip.File, module string) ([]bundleEntry, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	languageBytes, err := localeResourceSizes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed := int64(f.CompressedSize64)
+	var entries []bundleEntry
+	var languageTotal int64
+	for language, n := range languageBytes {
+		size := compressed * n / int64(len(data))
+		languageTotal += size
+		entries = append(entries, bundleEntry{module: module, split: splitLanguage, name: language, size: size})
+	}
+	entries = append(entries, bundleEntry{module: module, split: splitMaster, size: compressed - languageTotal})
+
+	return entries, nil
+}
+
+// localeResourceSizes returns the raw size of locale-specific resource values per language.
+// It walks the aapt2 ResourceTable: package(2) > type(3) > entry(3) > config_value(6),
+// reading the locale(3) of each value's Configuration(1).
+func localeResourceSizes(data []byte) (map[string]int64, error) {
+	sizes := make(map[string]int64)
+
+	var walk func(msg []byte, path []int) error
+	walk = func(msg []byte, path []int) error {
+		return readProtoFields(msg, func(f protoField) error {
+			if f.WireType != wireBytes || f.Number != path[0] {
+				return nil
+			}
+			if len(path) > 1 {
+				return walk(f.Bytes, path[1:])
+			}
+
+			// f is a ConfigValue
+			language, err := configValueLanguage(f.Bytes)
+			if err != nil {
+				return err
+			}
+			if language != "" {
+				sizes[language] += int64(len(f.Bytes))
+			}
+			return nil
+		})
+	}
+
+	if err := walk(data, []int{2, 3, 3, 6}); err != nil {
+		return nil, fmt.Errorf("failed to parse resource table: %w", err)
+	}
+	return sizes, nil
+}
+
+// configValueLanguage returns the language of a ConfigValue's configuration, or "" if unset
+func configValueLanguage(configValue []byte) (string, error) {
+	var language string
+	err := readProtoFields(configValue, func(f protoField) error {
+		if f.Number != 1 || f.WireType != wireBytes {
+			return nil
+		}
+		return readProtoFields(f.Bytes, func(c protoField) error {
+			if c.Number == 3 && c.WireType == wireBytes && len(c.Bytes) > 0 {
+				language = localeLanguage(string(c.Bytes))
+			}
+			return nil
+		})
+	})
+	return language, err
+}
+
+// estimateDeviceDownload sums the splits a device downloads from the installed modules
+func estimateDeviceDownload(entries []bundleEntry, device types.DeviceSpec, installed func(module string) bool) types.DeviceDownloadEstimate {
+	estimate := types.DeviceDownloadEstimate{Device: device}
+	if estimate.Device.Name == "" {
+		estimate.Device.Name = DeviceName(device)
+	}
+
+	// Devices below Lollipop get standalone APKs containing every language
+	standalone := device.SDKVersion > 0 && device.SDKVersion <= standaloneMaxSDK
+
+	languages := make(map[string]bool)
+	for _, locale := range device.SupportedLocales {
+		languages[localeLanguage(locale)] = true
+	}
+
+	// Group entries by module so the ABI and density choice is made per module
+	byModule := make(map[string][]bundleEntry)
+	for _, e := range entries {
+		if !installed(e.module) {
+			continue
+		}
+		if device.SDKVersion > 0 && e.minSDK > device.SDKVersion {
+			continue
+		}
+		byModule[e.module] = append(byModule[e.module], e)
+	}
+
+	for _, moduleEntries := range byModule {
+		abi := selectABI(moduleEntries, device.SupportedABIs)
+		densityChoice := selectDensityVariants(moduleEntries, device.ScreenDensity)
+
+		for _, e := range moduleEntries {
+			switch e.split {
+			case splitMaster:
+				estimate.MasterSize += e.size
+			case splitABI:
+				if e.name == abi {
+					estimate.ABISize += e.size
+				}
+			case splitDensity:
+				if densityChoice[e.group] == e.dpi {
+					estimate.DensitySize += e.size
+				}
+			case splitLanguage:
+				if standalone || languages[e.name] {
+					estimate.LanguageSize += e.size
+				}
+			}
+		}
+	}
+
+	estimate.DownloadSize = estimate.MasterSize + estimate.ABISize + estimate.DensitySize + estimate.LanguageSize
+	return estimate
+}
+
+// summarizeSplits returns the size of every split APK, grouped by module
+func summarizeSplits(entries []bundleEntry) []types.SplitSize {
+	type splitKey struct{ module, split, name string }
+	sizes := make(map[splitKey]int64)
+	densityModules := make(map[string][]bundleEntry)
+
+	for _, e := range entries {
+		if e.split == splitDensity {
+			densityModules[e.module] = append(densityModules[e.module], e)
+			continue
+		}
+		sizes[splitKey{e.module, e.split, e.name}] += e.size
+	}
+
+	// A density split holds the best variant of each resource for its bucket
+	for module, moduleEntries := range densityModules {
+		for _, bucket := range densityBuckets {
+			choice := selectDensityVariants(moduleEntries, bucket.dpi)
+			var size int64
+			for _, e := range moduleEntries {
+				if choice[e.group] == e.dpi {
+					size += e.size
+				}
+			}
+			sizes[splitKey{module, splitDensity, bucket.name}] = size
+		}
+	}
+
+	splits := make([]types.SplitSize, 0, len(sizes))
+	for key, size := range sizes {
+		splits = append(splits, types.SplitSize{Module: key.module, Type: key.split, Name: key.name, Size: size})
+	}
+
+	splitOrder := map[string]int{splitMaster: 0, splitABI: 1, splitDensity: 2, splitLanguage: 3}
+	sort.Slice(splits, func(i, j int) bool {
+		a, b := splits[i], splits[j]
+		if a.Module != b.Module {
+			// Base module first, then feature modules alphabetically
+			if a.Module == baseModule || b.Module == baseModule {
+				return a.Module == baseModule
+			}
+			return a.Module < b.Module
+		}
+		if a.Type != b.Type {
+			return splitOrder[a.Type] < splitOrder[b.Type]
+		}
+		if a.Type == splitDensity {
+			return densityDPI(a.Name) < densityDPI(b.Name)
+		}
+		return a.Name < b.Name
+	})
+
+	return splits
+}
+
+// selectABI returns the first device ABI the module has native libraries for
+func selectABI(entries []bundleEntry, deviceABIs []string) string {
+	available := make(map[string]bool)
+	for _, e := range entries {
+		if e.split == splitABI {
+			available[e.name] = true
+		}
+	}
+	for _, abi := range deviceABIs {
+		if available[abi] {
+			return abi
+		}
+	}
+	return ""
+}
+
+// selectDensityVariants picks the density variant of each resource a device would use:
+// the lowest density at or above the device density, otherwise the highest available.
+// Returns the selected dpi per resource group.
+func selectDensityVariants(entries []bundleEntry, deviceDPI int) map[string]int {
+	variants := make(map[string][]int)
+	for _, e := range entries {
+		if e.split == splitDensity {
+			variants[e.group] = append(variants[e.group], e.dpi)
+		}
+	}
+
+	choice := make(map[string]int, len(variants))
+	for group, dpis := range variants {
+		best, highest := 0, 0
+		for _, dpi := range dpis {
+			if dpi >= deviceDPI && (best == 0 || dpi < best) {
+				best = dpi
+			}
+			if dpi > highest {
+				highest = dpi
+			}
+		}
+		if best == 0 {
+			best = highest
+		}
+		choice[group] = best
+	}
+	return choice
+}
+
+// densityDPI returns the dpi of a density qualifier such as "xxhdpi" or "420dpi", or 0
+func densityDPI(qualifier string) int {
+	for _, bucket := range densityBuckets {
+		if qualifier == bucket.name {
+			return bucket.dpi
+		}
+	}
+	if strings.HasSuffix(qualifier, "dpi") {
+		if dpi, err := strconv.Atoi(strings.TrimSuffix(qualifier, "dpi")); err == nil {
+			return dpi
+		}
+	}
+	return 0 // Includes nodpi and anydpi, which are not split by density
+}
+
+// densityBucketName returns the density bucket a dpi value falls into
+func densityBucketName(dpi int) string {
+	for _, bucket := range densityBuckets {
+		if dpi <= bucket.dpi {
+			return bucket.name
+		}
+	}
+	if dpi <= 0 {
+		return "anydpi"
+	}
+	return densityBuckets[len(densityBuckets)-1].name
+}
+
+// isLanguageQualifier reports whether a resource qualifier is a language, e.g. "fr" or "b+sr+Latn"
+func isLanguageQualifier(q string) bool {
+	if strings.HasPrefix(q, "b+") {
+		return len(q) > 2
+	}
+	if (len(q) != 2 && len(q) != 3) || q == "car" {
+		return false
+	}
+	for _, r := range q {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// languageFromQualifier returns the language of a language qualifier
+func languageFromQualifier(q string) string {
+	if strings.HasPrefix(q, "b+") {
+		q = strings.TrimPrefix(q, "b+")
+		q, _, _ = strings.Cut(q, "+")
+	}
+	return strings.ToLower(q)
+}
+
+// isSDKQualifier reports whether a resource qualifier is a platform version, e.g. "v21"
+func isSDKQualifier(q string) bool {
+	if len(q) < 2 || q[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(q[1:])
+	return err == nil
+}
+
+// localeLanguage returns the language of a locale such as "en-US", "pt_BR" or "sr-Latn"
+func localeLanguage(locale string) string {
+	language, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	return strings.ToLower(language)
+}
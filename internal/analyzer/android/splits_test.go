@@ -0,0 +1,208 @@
+package android
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// createSplitTestBundle builds an uncompressed AAB so compressed sizes equal file sizes
+func createSplitTestBundle(t *testing.T, files map[string][]byte) *zip.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		fw, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return zr
+}
+
+// createResourceTable builds a resources.pb with one config value per locale ("" for default)
+func createResourceTable(locales map[string]int) []byte {
+	var entry []byte
+	for locale, valueSize := range locales {
+		var config []byte
+		if locale != "" {
+			config = appendProtoBytes(config, 3, []byte(locale))
+		}
+		var configValue []byte
+		configValue = appendProtoBytes(configValue, 1, config)
+		configValue = appendProtoBytes(configValue, 2, make([]byte, valueSize))
+		entry = appendProtoBytes(entry, 6, configValue)
+	}
+
+	resType := appendProtoBytes(nil, 3, entry)
+	pkg := appendProtoBytes(nil, 3, resType)
+	return appendProtoBytes(nil, 2, pkg)
+}
+
+func TestEstimateSplitSizes(t *testing.T) {
+	zr := createSplitTestBundle(t, map[string][]byte{
+		"BundleConfig.pb": make([]byte, 50),
+		"BUNDLE-METADATA/com.android.tools/map.txt":   make([]byte, 5000),
+		"base/manifest/AndroidManifest.xml":           make([]byte, 100),
+		"base/dex/classes.dex":                        make([]byte, 1000),
+		"base/lib/arm64-v8a/libapp.so":                make([]byte, 400),
+		"base/lib/armeabi-v7a/libapp.so":              make([]byte, 300),
+		"base/res/drawable-xhdpi-v4/icon.png":         make([]byte, 20),
+		"base/res/drawable-xxhdpi-v4/icon.png":        make([]byte, 40),
+		"base/res/drawable/shape.xml":                 make([]byte, 10),
+		"base/res/raw-fr/intro.mp3":                   make([]byte, 70),
+		"base/res/layout-v31/main.xml":                make([]byte, 5),
+		"feature_camera/manifest/AndroidManifest.xml": make([]byte, 60),
+		"feature_camera/dex/classes.dex":              make([]byte, 600),
+	})
+
+	devices := []types.DeviceSpec{
+		{SupportedABIs: []string{"arm64-v8a", "armeabi-v7a"}, SupportedLocales: []string{"en-US"}, ScreenDensity: 480, SDKVersion: 33},
+		{Name: "old-phone", SupportedABIs: []string{"armeabi-v7a"}, SupportedLocales: []string{"fr-FR"}, ScreenDensity: 240, SDKVersion: 24},
+	}
+
+	estimate, err := EstimateSplitSizes(zr, devices, nil)
+	if err != nil {
+		t.Fatalf("EstimateSplitSizes() error = %v", err)
+	}
+
+	// Master: manifest + dex + shape.xml + layout-v31
+	first := estimate.Devices[0]
+	if first.MasterSize != 1115 || first.ABISize != 400 || first.DensitySize != 40 || first.LanguageSize != 0 {
+		t.Errorf("device 0 = %+v", first)
+	}
+	if first.Device.Name != "arm64-v8a/xxhdpi/sdk33" {
+		t.Errorf("generated device name = %q", first.Device.Name)
+	}
+
+	// SDK 24 skips the v31 layout, picks armeabi-v7a, xhdpi icon and French audio
+	second := estimate.Devices[1]
+	if second.MasterSize != 1110 || second.ABISize != 300 || second.DensitySize != 20 || second.LanguageSize != 70 {
+		t.Errorf("device 1 = %+v", second)
+	}
+	if second.Device.Name != "old-phone" {
+		t.Errorf("device name = %q", second.Device.Name)
+	}
+
+	if estimate.MinDownloadSize != 1500 || estimate.MaxDownloadSize != 1555 {
+		t.Errorf("min/max = %d/%d, want 1500/1555", estimate.MinDownloadSize, estimate.MaxDownloadSize)
+	}
+
+	splits := make(map[string]int64)
+	for _, s := range estimate.Splits {
+		splits[s.Module+":"+s.Type+":"+s.Name] = s.Size
+	}
+	want := map[string]int64{
+		"base:master:":           1115,
+		"base:abi:arm64-v8a":     400,
+		"base:abi:armeabi-v7a":   300,
+		"base:density:ldpi":      20,
+		"base:density:xxhdpi":    40,
+		"base:density:xxxhdpi":   40,
+		"base:language:fr":       70,
+		"feature_camera:master:": 660,
+	}
+	for key, size := range want {
+		if splits[key] != size {
+			t.Errorf("split %s = %d, want %d", key, splits[key], size)
+		}
+	}
+	if _, ok := splits["BUNDLE-METADATA:master:"]; ok {
+		t.Error("bundle metadata must not be part of any split")
+	}
+	if estimate.Splits[0].Module != "base" || estimate.Splits[len(estimate.Splits)-1].Module != "feature_camera" {
+		t.Error("expected base module splits first")
+	}
+}
+
+func TestEstimateSplitSizes_ResourceTableLanguages(t *testing.T) {
+	table := createResourceTable(map[string]int{"": 300, "de": 200, "pt-BR": 100})
+	zr := createSplitTestBundle(t, map[string][]byte{
+		"base/resources.pb": table,
+	})
+
+	estimate, err := EstimateSplitSizes(zr, []types.DeviceSpec{
+		{SupportedLocales: []string{"de-DE"}, SDKVersion: 30},
+		{SupportedLocales: []string{"de-DE"}, SDKVersion: 19}, // Standalone APK with all languages
+	}, nil)
+	if err != nil {
+		t.Fatalf("EstimateSplitSizes() error = %v", err)
+	}
+
+	var total int64
+	for _, s := range estimate.Splits {
+		total += s.Size
+	}
+	if total != int64(len(table)) {
+		t.Errorf("splits sum to %d, want resources.pb size %d", total, len(table))
+	}
+
+	german := estimate.Devices[0].LanguageSize
+	all := estimate.Devices[1].LanguageSize
+	if german <= 200 || german >= all {
+		t.Errorf("language sizes: de = %d, all = %d", german, all)
+	}
+	if estimate.Devices[0].DownloadSize+all-german != int64(len(table)) {
+		t.Error("standalone device should download the whole resource table")
+	}
+}
+
+func TestLocaleResourceSizes_Invalid(t *testing.T) {
+	if _, err := localeResourceSizes([]byte{0x12, 0xff}); err == nil {
+		t.Error("localeResourceSizes() expected error for truncated table")
+	}
+}
+
+func TestClassifyResourceFile(t *testing.T) {
+	tests := []struct {
+		path      string
+		wantSplit string
+		wantName  string
+		wantDPI   int
+		wantSDK   int
+	}{
+		{path: "res/drawable-xxhdpi-v4/a.png", wantSplit: splitDensity, wantDPI: 480, wantSDK: 4},
+		{path: "res/mipmap-420dpi/a.png", wantSplit: splitDensity, wantDPI: 420},
+		{path: "res/drawable-nodpi/a.png", wantSplit: splitMaster},
+		{path: "res/raw-pt-rBR/a.txt", wantSplit: splitLanguage, wantName: "pt"},
+		{path: "res/raw-b+sr+Latn/a.txt", wantSplit: splitLanguage, wantName: "sr"},
+		{path: "res/layout-car/a.xml", wantSplit: splitMaster},
+		{path: "res/layout-land-v21/a.xml", wantSplit: splitMaster, wantSDK: 21},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			e := classifyResourceFile("base", tt.path, 1)
+			if e.split != tt.wantSplit || e.name != tt.wantName || e.dpi != tt.wantDPI || e.minSDK != tt.wantSDK {
+				t.Errorf("classifyResourceFile() = %+v", e)
+			}
+		})
+	}
+}
+
+func TestSelectDensityVariants(t *testing.T) {
+	entries := []bundleEntry{
+		{split: splitDensity, group: "icon", dpi: 240},
+		{split: splitDensity, group: "icon", dpi: 480},
+	}
+
+	for deviceDPI, want := range map[int]int{120: 240, 240: 240, 320: 480, 640: 480} {
+		if got := selectDensityVariants(entries, deviceDPI)["icon"]; got != want {
+			t.Errorf("device %d dpi selected %d, want %d", deviceDPI, got, want)
+		}
+	}
+}
@@ -15,6 +15,9 @@ import (
 // AppAnalyzer analyzes iOS .app bundles (uncompressed directories).
 type AppAnalyzer struct {
 	Logger logger.Logger
+
+	// LockedDependencies supply the names, sources and versions of dependencies
+	LockedDependencies []LockedDependency
 }
 
 // NewAppAnalyzer creates a new .app analyzer.
@@ -64,6 +67,12 @@ func (a *AppAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 		unusedFrameworks = macho.DetectUnusedFrameworks(depGraph, mainBinaryPath)
 	}
 
+	// Attribute frameworks, resource bundles and static code to dependencies
+	dependencies := AttributeDependencies(path, fileTree, frameworks, mainBinaryPath, a.LockedDependencies)
+
+	// Find C strings compiled into several binaries
+	duplicateStrings := FindDuplicateStrings(path, binaries)
+
 	// Parse asset catalogs
 	assetCatalogs := parseAssetCatalogs(fileTree, path, a.Logger)
 
@@ -90,6 +99,24 @@ func (a *AppAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 	frameworkOpts := GenerateUnusedFrameworkOptimizations(unusedFrameworks, frameworks)
 	optimizations = append(optimizations, frameworkOpts...)
 
+	// Add framework linking optimizations
+	optimizations = append(optimizations, GenerateFrameworkLinkingOptimizations(fileTree, depGraph, frameworks, mainBinaryPath)...)
+
+	// Add simulator slice optimizations
+	optimizations = append(optimizations, GenerateSimulatorSliceOptimizations(binaries)...)
+
+	// Add Swift reflection metadata optimizations
+	optimizations = append(optimizations, GenerateReflectionMetadataOptimizations(binaries)...)
+
+	// Read provisioning profiles and entitlements
+	signing := ParseSigning(path)
+
+	// Add embedded bitcode optimizations
+	optimizations = append(optimizations, GenerateBitcodeOptimizations(binaries, signingProfile(signing))...)
+
+	// Add build machine path optimizations
+	optimizations = append(optimizations, GenerateBuildPathOptimizations(binaries)...)
+
 	// Add optimization suggestions for oversized assets
 	assetOpts := GenerateLargeAssetOptimizations(assetCatalogs)
 	optimizations = append(optimizations, assetOpts...)
@@ -98,15 +125,22 @@ func (a *AppAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 	typedFrameworks := ConvertFrameworksToTypes(frameworks)
 	typedAssetCatalogs := ConvertAssetCatalogsToTypes(assetCatalogs)
 
+	// Build iOS details
+	details := &types.IOSDetails{
+		AppBundle:       filepath.Base(path),
+		Binaries:        binaries,
+		Frameworks:      typedFrameworks,
+		DependencyGraph: depGraph,
+		AssetCatalogs:   typedAssetCatalogs,
+		Dependencies:    dependencies,
+
+		DuplicateStrings: duplicateStrings,
+	}
+
 	// Build metadata map
 	metadata := map[string]interface{}{
-		"app_bundle":       filepath.Base(path),
-		"is_directory":     true,
-		"binaries":         binaries,
-		"frameworks":       typedFrameworks,
-		"dependency_graph": depGraph,
-		"asset_catalogs":   typedAssetCatalogs,
-		"platform":         "iOS",
+		"is_directory": true,
+		"platform":     "iOS",
 	}
 
 	// Add app metadata if available
@@ -120,12 +154,8 @@ func (a *AppAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 		if appMetadata.Version != "" {
 			metadata["version"] = appMetadata.Version
 		}
-		if appMetadata.BuildVersion != "" {
-			metadata["build_version"] = appMetadata.BuildVersion
-		}
-		if appMetadata.MinOSVersion != "" {
-			metadata["min_os_version"] = appMetadata.MinOSVersion
-		}
+		details.BuildVersion = appMetadata.BuildVersion
+		details.MinOSVersion = appMetadata.MinOSVersion
 	}
 
 	// Extract app icon with Info.plist-guided search
@@ -156,6 +186,7 @@ func (a *AppAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 		UncompressedSize: totalSize,
 		AnalyzedAt:       time.Now(),
 		IconData:         iconData,
+		Signing:          signing,
 	}
 
 	// Add app metadata to artifact info if available
@@ -171,7 +202,10 @@ func (a *AppAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 		FileTree:      fileTree,
 		LargestFiles:  largestFiles,
 		Optimizations: optimizations,
+		IOS:           details,
+		Localization:  AnalyzeLocalization(path),
 		Metadata:      metadata,
+		Warnings:      append(EncryptionWarnings(binaries), SigningWarnings(signing, time.Now())...),
 	}
 
 	return report, nil
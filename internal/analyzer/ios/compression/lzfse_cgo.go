@@ -8,10 +8,12 @@ import (
 	"fmt"
 
 	"github.com/blacktop/lzfse-cgo"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/zipmethod"
 )
 
 // LZFSE compression method code used in ZIP files
-const CompressionMethodLZFSE = 99
+const CompressionMethodLZFSE = zipmethod.LZFSE
 
 // LZFSE magic bytes
 var lzfseMagic = [][]byte{
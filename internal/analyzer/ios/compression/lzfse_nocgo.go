@@ -6,10 +6,12 @@ package compression
 import (
 	"bytes"
 	"fmt"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/zipmethod"
 )
 
 // LZFSE compression method code used in ZIP files
-const CompressionMethodLZFSE = 99
+const CompressionMethodLZFSE = zipmethod.LZFSE
 
 // LZFSE magic bytes
 var lzfseMagic = [][]byte{
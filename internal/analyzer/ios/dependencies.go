@@ -0,0 +1,153 @@
+package ios
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/ios/macho"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// dependencyIndex resolves frameworks, resource bundles and Swift modules to dependencies
+type dependencyIndex struct {
+	locked  map[string]LockedDependency // Locked dependencies by dependencyKey
+	targets map[string]string           // Dependency names by the dependencyKey of a bundled target
+	sizes   map[string]*types.DependencySize
+}
+
+// AttributeDependencies sums the embedded frameworks, the resource bundles at the root of
+// the app bundle and the Swift code statically linked into the main executable per
+// dependency, largest first.
+//
+// Resource bundles are named <Package>_<Target>.bundle by Swift Package Manager and
+// <Pod>.bundle or <Pod>_<Name>.bundle by CocoaPods; both map the target to its package.
+// Static code is only found when the main executable has a symbol table, and the app's
+// own module is left out. Locked dependencies, e.g. from a Package.resolved, supply
+// dependency names, sources and versions.
+func AttributeDependencies(appPath string, fileTree []*types.FileNode, frameworks []*FrameworkInfo, mainBinaryPath string, locked []LockedDependency) []types.DependencySize {
+	var codeSizes map[string]int64
+	appModule := ""
+	if mainBinaryPath != "" {
+		appModule = filepath.Base(mainBinaryPath)
+		if sizes, err := macho.SwiftModuleCodeSizes(filepath.Join(appPath, mainBinaryPath)); err == nil {
+			codeSizes = sizes
+		}
+	}
+	return attributeDependencies(fileTree, frameworks, codeSizes, appModule, locked)
+}
+
+// attributeDependencies attributes root resource bundles, frameworks and the code sizes of
+// Swift modules other than appModule
+func attributeDependencies(fileTree []*types.FileNode, frameworks []*FrameworkInfo, codeSizes map[string]int64, appModule string, locked []LockedDependency) []types.DependencySize {
+	index := &dependencyIndex{
+		locked:  make(map[string]LockedDependency),
+		targets: make(map[string]string),
+		sizes:   make(map[string]*types.DependencySize),
+	}
+	for _, dep := range locked {
+		index.locked[dependencyKey(dep.Name)] = dep
+		if trimmed := strings.TrimPrefix(dependencyKey(dep.Name), "swift"); trimmed != "" {
+			if _, ok := index.locked[trimmed]; !ok {
+				index.locked[trimmed] = dep // swift-argument-parser provides ArgumentParser
+			}
+		}
+	}
+
+	for _, node := range fileTree {
+		if !node.IsDir || filepath.Ext(node.Name) != ".bundle" || node.Name == "Settings.bundle" {
+			continue
+		}
+		name := strings.TrimSuffix(node.Name, ".bundle")
+		target := ""
+		if i := strings.Index(name, "_"); i > 0 {
+			name, target = name[:i], name[i+1:]
+		}
+		dep := index.add(name, node.Path)
+		dep.BundleSize += node.Size
+		dep.Size += node.Size
+		if target != "" {
+			index.targets[dependencyKey(target)] = dep.Name
+			dep.Modules = appendUnique(dep.Modules, target)
+		}
+	}
+
+	for _, fw := range frameworks {
+		dep := index.add(strings.TrimSuffix(fw.Name, ".framework"), fw.Path)
+		dep.FrameworkSize += fw.Size
+		dep.Size += fw.Size
+		if dep.Version == "" {
+			dep.Version = fw.Version
+		}
+	}
+
+	for module, size := range codeSizes {
+		if dependencyKey(module) == dependencyKey(appModule) {
+			continue
+		}
+		name := module
+		if target, ok := index.targets[dependencyKey(module)]; ok {
+			name = target
+		}
+		dep := index.add(name, "")
+		dep.CodeSize += size
+		dep.Size += size
+		dep.Modules = appendUnique(dep.Modules, module)
+	}
+
+	result := make([]types.DependencySize, 0, len(index.sizes))
+	for _, dep := range index.sizes {
+		sort.Strings(dep.Modules)
+		sort.Strings(dep.Paths)
+		result = append(result, *dep)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Size != result[j].Size {
+			return result[i].Size > result[j].Size
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// add returns the dependency of a framework, bundle or module name, resolved through
+// the locked dependencies, and records the path of the framework or bundle
+func (index *dependencyIndex) add(name, path string) *types.DependencySize {
+	dep := types.DependencySize{Name: name}
+	if l, ok := index.locked[dependencyKey(name)]; ok {
+		dep = types.DependencySize{Name: l.Name, Source: l.Source, Version: l.Version}
+	}
+
+	size := index.sizes[dep.Name]
+	if size == nil {
+		size = &dep
+		index.sizes[dep.Name] = size
+	}
+	if path != "" {
+		size.Paths = appendUnique(size.Paths, path)
+	}
+	return size
+}
+
+// dependencyKey normalizes a dependency, target or module name for matching, e.g.
+// swift-collections and Swift_Collections both become swiftcollections
+func dependencyKey(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// appendUnique appends a value unless the list contains it
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
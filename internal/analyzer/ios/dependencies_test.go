@@ -0,0 +1,63 @@
+package ios
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func TestAttributeDependencies(t *testing.T) {
+	fileTree := []*types.FileNode{
+		{Path: "MyApp", Name: "MyApp", Size: 900000},
+		{Path: "Alamofire_Alamofire.bundle", Name: "Alamofire_Alamofire.bundle", IsDir: true, Size: 2000},
+		{Path: "swift-collections_OrderedCollections.bundle", Name: "swift-collections_OrderedCollections.bundle", IsDir: true, Size: 300},
+		{Path: "GoogleMaps.bundle", Name: "GoogleMaps.bundle", IsDir: true, Size: 50000},
+		{Path: "Settings.bundle", Name: "Settings.bundle", IsDir: true, Size: 800},
+		{Path: "Frameworks", Name: "Frameworks", IsDir: true, Size: 120000},
+	}
+	frameworks := []*FrameworkInfo{
+		{Name: "Sentry.framework", Path: "Frameworks/Sentry.framework", Size: 100000, Version: "8.17.0"},
+		{Name: "AcmeKit.framework", Path: "Frameworks/AcmeKit.framework", Size: 20000, Version: "2.0"},
+	}
+	codeSizes := map[string]int64{
+		"MyApp":              400000,
+		"Alamofire":          150000,
+		"OrderedCollections": 30000,
+		"ArgumentParser":     10000,
+		"Kingfisher":         5000,
+	}
+	locked := []LockedDependency{
+		{Name: "Alamofire", Source: SourceSwiftPM, Version: "5.9.1"},
+		{Name: "swift-collections", Source: SourceSwiftPM, Version: "1.1.0"},
+		{Name: "swift-argument-parser", Source: SourceSwiftPM, Version: "1.3.0"},
+		{Name: "Sentry", Source: SourceCarthage, Version: "8.17.1"},
+	}
+
+	got := attributeDependencies(fileTree, frameworks, codeSizes, "MyApp", locked)
+	assert.Equal(t, []types.DependencySize{
+		{Name: "Alamofire", Source: SourceSwiftPM, Version: "5.9.1", Size: 152000, BundleSize: 2000, CodeSize: 150000,
+			Modules: []string{"Alamofire"}, Paths: []string{"Alamofire_Alamofire.bundle"}},
+		{Name: "Sentry", Source: SourceCarthage, Version: "8.17.1", Size: 100000, FrameworkSize: 100000,
+			Paths: []string{"Frameworks/Sentry.framework"}},
+		{Name: "GoogleMaps", Size: 50000, BundleSize: 50000, Paths: []string{"GoogleMaps.bundle"}},
+		{Name: "swift-collections", Source: SourceSwiftPM, Version: "1.1.0", Size: 30300, BundleSize: 300, CodeSize: 30000,
+			Modules: []string{"OrderedCollections"}, Paths: []string{"swift-collections_OrderedCollections.bundle"}},
+		{Name: "AcmeKit", Version: "2.0", Size: 20000, FrameworkSize: 20000, Paths: []string{"Frameworks/AcmeKit.framework"}},
+		{Name: "swift-argument-parser", Source: SourceSwiftPM, Version: "1.3.0", Size: 10000, CodeSize: 10000,
+			Modules: []string{"ArgumentParser"}},
+		{Name: "Kingfisher", Size: 5000, CodeSize: 5000, Modules: []string{"Kingfisher"}},
+	}, got)
+}
+
+func TestAttributeDependencies_Empty(t *testing.T) {
+	fileTree := []*types.FileNode{{Path: "MyApp", Name: "MyApp", Size: 900000}}
+	assert.Empty(t, AttributeDependencies(t.TempDir(), fileTree, nil, "MyApp", nil))
+}
+
+func TestDependencyKey(t *testing.T) {
+	assert.Equal(t, "swiftcollections", dependencyKey("swift-collections"))
+	assert.Equal(t, "swiftcollections", dependencyKey("Swift_Collections"))
+	assert.Equal(t, "firebasecore", dependencyKey("FirebaseCore"))
+}
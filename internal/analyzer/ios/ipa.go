@@ -2,6 +2,7 @@
 package ios
 
 import (
+	"archive/zip"
 	"context"
 	"encoding/base64"
 	"fmt"
@@ -21,6 +22,9 @@ import (
 // IPAAnalyzer analyzes iOS IPA files.
 type IPAAnalyzer struct {
 	Logger logger.Logger
+
+	// LockedDependencies supply the names, sources and versions of dependencies
+	LockedDependencies []LockedDependency
 }
 
 // NewIPAAnalyzer creates a new IPA analyzer.
@@ -43,8 +47,12 @@ type appBundleAnalysis struct {
 	totalSize        int64
 	binaries         map[string]*types.BinaryInfo
 	frameworks       []*FrameworkInfo
+	dependencyGraph  macho.DependencyGraph
+	mainBinaryPath   string
 	unusedFrameworks []string
 	assetCatalogs    []*assets.AssetCatalogInfo
+	dependencies     []types.DependencySize
+	duplicateStrings []types.DuplicateString
 	appMetadata      *AppMetadata
 	sizeBreakdown    types.SizeBreakdown
 	largestFiles     []types.FileNode
@@ -67,7 +75,8 @@ func (a *IPAAnalyzer) extractAndFindAppBundle(path string) (tempDir, appBundlePa
 }
 
 // analyzeAppBundleContents performs comprehensive analysis of the app bundle
-func (a *IPAAnalyzer) analyzeAppBundleContents(appBundlePath string) (*appBundleAnalysis, error) {
+// extracted from an IPA to tempDir
+func (a *IPAAnalyzer) analyzeAppBundleContents(ipaPath, tempDir, appBundlePath string) (*appBundleAnalysis, error) {
 	// Analyze directory structure
 	fileTree, totalSize, err := analyzeDirectory(appBundlePath, "")
 	if err != nil {
@@ -90,6 +99,12 @@ func (a *IPAAnalyzer) analyzeAppBundleContents(appBundlePath string) (*appBundle
 		unusedFrameworks = macho.DetectUnusedFrameworks(depGraph, mainBinaryPath)
 	}
 
+	// Attribute frameworks, resource bundles and static code to dependencies
+	dependencies := AttributeDependencies(appBundlePath, fileTree, frameworks, mainBinaryPath, a.LockedDependencies)
+
+	// Find C strings compiled into several binaries
+	duplicateStrings := FindDuplicateStrings(appBundlePath, binaries)
+
 	// Analyze assets
 	assetCatalogs := parseAssetCatalogs(fileTree, appBundlePath, a.Logger)
 
@@ -103,14 +118,23 @@ func (a *IPAAnalyzer) analyzeAppBundleContents(appBundlePath string) (*appBundle
 	// Expand Mach-O binary segments as virtual children
 	expandMachOSegments(fileTree, appBundlePath, a.Logger)
 
+	// Add compressed sizes from the IPA entries
+	if err := applyIPACompressedSizes(ipaPath, tempDir, appBundlePath, fileTree); err != nil {
+		a.Logger.Warn("Failed to read compressed sizes: %v", err)
+	}
+
 	return &appBundleAnalysis{
 		appBundlePath:    appBundlePath,
 		fileTree:         fileTree,
 		totalSize:        totalSize,
 		binaries:         binaries,
 		frameworks:       frameworks,
+		dependencyGraph:  depGraph,
+		mainBinaryPath:   mainBinaryPath,
 		unusedFrameworks: unusedFrameworks,
 		assetCatalogs:    assetCatalogs,
+		dependencies:     dependencies,
+		duplicateStrings: duplicateStrings,
 		appMetadata:      appMetadata,
 		sizeBreakdown:    categorizeSizes(fileTree),
 		largestFiles:     util.FindLargestFiles(fileTree, 10),
@@ -118,7 +142,7 @@ func (a *IPAAnalyzer) analyzeAppBundleContents(appBundlePath string) (*appBundle
 }
 
 // generateAllOptimizations creates optimization suggestions from analysis results
-func (a *IPAAnalyzer) generateAllOptimizations(analysis *appBundleAnalysis) []types.Optimization {
+func (a *IPAAnalyzer) generateAllOptimizations(analysis *appBundleAnalysis, signing *types.SigningInfo) []types.Optimization {
 	var optimizations []types.Optimization
 
 	// Symbol stripping optimizations
@@ -127,6 +151,18 @@ func (a *IPAAnalyzer) generateAllOptimizations(analysis *appBundleAnalysis) []ty
 		optimizations = append(optimizations, *opt)
 	}
 
+	// Simulator slice optimizations
+	optimizations = append(optimizations, GenerateSimulatorSliceOptimizations(analysis.binaries)...)
+
+	// Swift reflection metadata optimizations
+	optimizations = append(optimizations, GenerateReflectionMetadataOptimizations(analysis.binaries)...)
+
+	// Embedded bitcode optimizations
+	optimizations = append(optimizations, GenerateBitcodeOptimizations(analysis.binaries, signingProfile(signing))...)
+
+	// Build machine path optimizations
+	optimizations = append(optimizations, GenerateBuildPathOptimizations(analysis.binaries)...)
+
 	// Unused framework optimizations
 	frameworkOpts := GenerateUnusedFrameworkOptimizations(
 		analysis.unusedFrameworks,
@@ -134,6 +170,14 @@ func (a *IPAAnalyzer) generateAllOptimizations(analysis *appBundleAnalysis) []ty
 	)
 	optimizations = append(optimizations, frameworkOpts...)
 
+	// Framework linking optimizations
+	optimizations = append(optimizations, GenerateFrameworkLinkingOptimizations(
+		analysis.fileTree,
+		analysis.dependencyGraph,
+		analysis.frameworks,
+		analysis.mainBinaryPath,
+	)...)
+
 	// Oversized asset optimizations
 	assetOpts := GenerateLargeAssetOptimizations(analysis.assetCatalogs)
 	optimizations = append(optimizations, assetOpts...)
@@ -161,22 +205,32 @@ func (a *IPAAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 	defer os.RemoveAll(tempDir)
 
 	// Analyze app bundle contents
-	analysis, err := a.analyzeAppBundleContents(appBundlePath)
+	analysis, err := a.analyzeAppBundleContents(path, tempDir, appBundlePath)
 	if err != nil {
 		return nil, err
 	}
 
+	// Read provisioning profiles and entitlements
+	signing := ParseSigning(appBundlePath)
+
 	// Generate optimizations
-	optimizations := a.generateAllOptimizations(analysis)
+	optimizations := a.generateAllOptimizations(analysis, signing)
+
+	// Build iOS details
+	details := &types.IOSDetails{
+		AppBundle:       filepath.Base(analysis.appBundlePath),
+		Binaries:        analysis.binaries,
+		Frameworks:      ConvertFrameworksToTypes(analysis.frameworks),
+		DependencyGraph: analysis.dependencyGraph,
+		AssetCatalogs:   ConvertAssetCatalogsToTypes(analysis.assetCatalogs),
+		Dependencies:    analysis.dependencies,
+
+		DuplicateStrings: analysis.duplicateStrings,
+	}
 
 	// Build metadata map
 	metadata := map[string]interface{}{
-		"app_bundle":       filepath.Base(analysis.appBundlePath),
-		"binaries":         analysis.binaries,
-		"frameworks":       ConvertFrameworksToTypes(analysis.frameworks),
-		"dependency_graph": macho.BuildDependencyGraph(analysis.binaries),
-		"asset_catalogs":   ConvertAssetCatalogsToTypes(analysis.assetCatalogs),
-		"platform":         "iOS",
+		"platform": "iOS",
 	}
 
 	// Add app metadata if available
@@ -190,12 +244,8 @@ func (a *IPAAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 		if analysis.appMetadata.Version != "" {
 			metadata["version"] = analysis.appMetadata.Version
 		}
-		if analysis.appMetadata.BuildVersion != "" {
-			metadata["build_version"] = analysis.appMetadata.BuildVersion
-		}
-		if analysis.appMetadata.MinOSVersion != "" {
-			metadata["min_os_version"] = analysis.appMetadata.MinOSVersion
-		}
+		details.BuildVersion = analysis.appMetadata.BuildVersion
+		details.MinOSVersion = analysis.appMetadata.MinOSVersion
 	}
 
 	// Extract app icon with Info.plist-guided search
@@ -226,6 +276,7 @@ func (a *IPAAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 		UncompressedSize: analysis.totalSize,
 		AnalyzedAt:       time.Now(),
 		IconData:         iconData,
+		Signing:          signing,
 	}
 
 	// Add app metadata to artifact info if available
@@ -242,12 +293,33 @@ func (a *IPAAnalyzer) Analyze(ctx context.Context, path string) (*types.Report,
 		FileTree:      analysis.fileTree,
 		LargestFiles:  analysis.largestFiles,
 		Optimizations: optimizations,
+		IOS:           details,
+		Localization:  AnalyzeLocalization(appBundlePath),
 		Metadata:      metadata,
+		Warnings:      append(EncryptionWarnings(analysis.binaries), SigningWarnings(signing, time.Now())...),
 	}
 
 	return report, nil
 }
 
+// applyIPACompressedSizes sets the compressed sizes of the app bundle tree from the
+// IPA entries under the bundle path, e.g. Payload/App.app/
+func applyIPACompressedSizes(ipaPath, tempDir, appBundlePath string, fileTree []*types.FileNode) error {
+	relPath, err := filepath.Rel(tempDir, appBundlePath)
+	if err != nil {
+		return err
+	}
+
+	reader, err := zip.OpenReader(ipaPath)
+	if err != nil {
+		return fmt.Errorf("failed to open IPA: %w", err)
+	}
+	defer reader.Close()
+
+	util.ApplyCompressedSizes(fileTree, reader.File, filepath.ToSlash(relPath)+"/")
+	return nil
+}
+
 // findAppBundle locates the .app bundle within the extracted IPA.
 func findAppBundle(root string) (string, error) {
 	var appPath string
@@ -484,6 +556,16 @@ func analyzeMachOBinaries(nodes []*types.FileNode, rootPath string) map[string]*
 				RPaths:           info.RPaths,
 				HasDebugSymbols:  info.HasDebugSymbols,
 				DebugSymbolsSize: info.DebugSymbolsSize,
+				RuntimeMetadata:  info.RuntimeMetadata,
+				Strings:          info.Strings,
+
+				Platform:              info.Platform,
+				Slices:                info.Slices,
+				SimulatorSliceSavings: info.SimulatorSliceSavings,
+
+				BitcodeSize:       info.BitcodeSize,
+				CodeSignatureSize: info.CodeSignatureSize,
+				IsEncrypted:       info.IsEncrypted,
 			}
 		}
 	}
@@ -0,0 +1,262 @@
+package ios
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/ios/macho"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// tinyFrameworkSize is the code and data size below which a dynamic framework holds less
+// than the page-aligned segments, load commands and code signature it costs as a dylib
+const tinyFrameworkSize = 64 * 1024
+
+// dylibLoadTime is a rough estimate of the launch time dyld spends mapping, validating
+// and binding one embedded dylib before main
+const dylibLoadTime = time.Millisecond
+
+// GenerateFrameworkLinkingOptimizations suggests restructuring embedded dynamic frameworks:
+// frameworks embedded by both the app and its extensions, frameworks linked only by the
+// main executable, which can be linked statically or as mergeable libraries, and tiny
+// frameworks that cost more as a dylib than they contain. Each framework is reported
+// once, in that order of precedence.
+func GenerateFrameworkLinkingOptimizations(fileTree []*types.FileNode, graph macho.DependencyGraph, frameworks []*FrameworkInfo, mainBinaryPath string) []types.Optimization {
+	nodes := make(map[string]*types.FileNode)
+	indexFileNodes(fileTree, nodes)
+
+	linkers := make(map[string][]string)
+	for binary, deps := range graph {
+		for _, dep := range deps {
+			linkers[dep] = appendUnique(linkers[dep], binary)
+		}
+	}
+
+	dylibs := 0
+	for _, fw := range frameworks {
+		if isDynamicFramework(fw) {
+			dylibs++
+		}
+	}
+
+	var optimizations []types.Optimization
+	reported := make(map[string]bool)
+
+	for _, opt := range duplicateFrameworkOptimizations(nodes, frameworks) {
+		optimizations = append(optimizations, opt)
+		reported[filepath.Base(opt.Files[0])] = true
+	}
+
+	for _, fw := range frameworks {
+		if reported[fw.Name] || !isDynamicFramework(fw) {
+			continue
+		}
+		binaryPath := frameworkBinaryPath(fw)
+		clients := linkers[binaryPath]
+		overhead := frameworkOverhead(fw, nodes)
+		contents := fw.BinaryInfo.CodeSize + fw.BinaryInfo.DataSize
+
+		if mainBinaryPath != "" && len(clients) == 1 && clients[0] == mainBinaryPath {
+			optimizations = append(optimizations, staticLinkingOptimization(fw, binaryPath, overhead, contents, frameworkHasResources(fw, nodes), dylibs))
+			continue
+		}
+
+		if len(clients) > 0 && contents < tinyFrameworkSize {
+			sort.Strings(clients)
+			// Each client beyond the first gets its own copy of the code
+			impact := overhead - int64(len(clients)-1)*contents
+			if impact < 0 {
+				impact = 0
+			}
+			optimizations = append(optimizations, types.Optimization{
+				Category: "framework-linking",
+				Severity: "low",
+				Title:    fmt.Sprintf("Tiny dynamic framework: %s", fw.Name),
+				Description: fmt.Sprintf("Framework has %s of code and data but costs %s as a dylib, linked by %s. "+
+					"Estimated dyld impact: one fewer dylib to load at launch (≈%v) and %d fewer load commands",
+					util.FormatBytes(contents), util.FormatBytes(overhead), strings.Join(clients, ", "), dylibLoadTime, len(clients)),
+				Impact: impact,
+				Files:  []string{fw.Path},
+				Action: "Link the framework statically into its clients (MACH_O_TYPE = staticlib) or merge its " +
+					"sources into a larger framework",
+			})
+		}
+	}
+
+	// Sort by impact (largest first)
+	sort.SliceStable(optimizations, func(i, j int) bool {
+		return optimizations[i].Impact > optimizations[j].Impact
+	})
+
+	return optimizations
+}
+
+// staticLinkingOptimization suggests linking a framework used only by the main
+// executable statically, or as a mergeable library when it ships resources
+func staticLinkingOptimization(fw *FrameworkInfo, binaryPath string, overhead, contents int64, hasResources bool, dylibs int) types.Optimization {
+	action := "Link the framework statically (MACH_O_TYPE = staticlib, or a static XCFramework or " +
+		"Swift package product) so its code is dead-stripped into the main executable"
+	if hasResources {
+		action = "Build the framework as a mergeable library (MERGEABLE_LIBRARY = YES) and set " +
+			"MERGED_BINARY_TYPE = automatic on the app target: Xcode 15 merges its code into the main " +
+			"executable in release builds and keeps its resources in place"
+	}
+
+	description := fmt.Sprintf("Dynamic framework linked only by the main executable. Linking it into the "+
+		"executable removes an estimated %s of Mach-O and signing overhead. Estimated dyld impact: one of %d "+
+		"embedded dylibs less to load at launch (≈%v)", util.FormatBytes(overhead), dylibs, dylibLoadTime)
+	if contents < tinyFrameworkSize {
+		description += fmt.Sprintf("; it has only %s of code and data", util.FormatBytes(contents))
+	}
+
+	return types.Optimization{
+		Category:    "framework-linking",
+		Severity:    "medium",
+		Title:       fmt.Sprintf("Link %s statically", fw.Name),
+		Description: description,
+		Impact:      overhead,
+		Files:       []string{fw.Path, binaryPath},
+		Action:      action,
+	}
+}
+
+// duplicateFrameworkOptimizations suggests embedding frameworks copied into both the app
+// and its extensions, or into several extensions, only once in the app's Frameworks
+// directory
+func duplicateFrameworkOptimizations(nodes map[string]*types.FileNode, frameworks []*FrameworkInfo) []types.Optimization {
+	appFrameworks := make(map[string]*FrameworkInfo)
+	for _, fw := range frameworks {
+		appFrameworks[fw.Name] = fw
+	}
+
+	copies := make(map[string][]*types.FileNode)
+	for path, node := range nodes {
+		if node.IsDir && isExtensionFramework(path) {
+			copies[node.Name] = append(copies[node.Name], node)
+		}
+	}
+
+	names := make([]string, 0, len(copies))
+	for name := range copies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var optimizations []types.Optimization
+	for _, name := range names {
+		extensionCopies := copies[name]
+		sort.Slice(extensionCopies, func(i, j int) bool { return extensionCopies[i].Path < extensionCopies[j].Path })
+
+		var files []string
+		var impact, largest int64
+		for _, node := range extensionCopies {
+			files = append(files, node.Path)
+			impact += node.Size
+			if node.Size > largest {
+				largest = node.Size
+			}
+		}
+
+		fw, inApp := appFrameworks[name]
+		if inApp {
+			files = append([]string{fw.Path}, files...)
+		} else if len(extensionCopies) > 1 {
+			impact -= largest // One copy moves to the app
+		} else {
+			continue
+		}
+
+		optimizations = append(optimizations, types.Optimization{
+			Category: "framework-linking",
+			Severity: "medium",
+			Title:    fmt.Sprintf("Framework embedded %d times: %s", len(files), name),
+			Description: fmt.Sprintf("Framework is copied into %d bundles of the app. Estimated dyld impact: none, "+
+				"each process still loads one copy", len(files)),
+			Impact: impact,
+			Files:  files,
+			Action: "Embed the framework only in the app target and add @executable_path/../../Frameworks to " +
+				"LD_RUNPATH_SEARCH_PATHS of the extensions so that they load the app's copy",
+		})
+	}
+
+	return optimizations
+}
+
+// isExtensionFramework reports whether a path is a framework embedded by an app
+// extension, e.g. PlugIns/Widget.appex/Frameworks/Kit.framework
+func isExtensionFramework(path string) bool {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	if len(parts) != 4 || filepath.Ext(parts[1]) != ".appex" || parts[2] != "Frameworks" || filepath.Ext(parts[3]) != ".framework" {
+		return false
+	}
+	for _, dir := range extensionDirs {
+		if parts[0] == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// isDynamicFramework reports whether a framework contains a dynamic library
+func isDynamicFramework(fw *FrameworkInfo) bool {
+	return fw.BinaryInfo != nil && fw.BinaryInfo.Type == "dylib"
+}
+
+// frameworkBinaryPath returns the path of a framework's binary as used by the dependency
+// graph, e.g. Frameworks/Kit.framework/Kit
+func frameworkBinaryPath(fw *FrameworkInfo) string {
+	return filepath.Join(fw.Path, strings.TrimSuffix(fw.Name, ".framework"))
+}
+
+// frameworkOverhead estimates the bytes a framework stops costing when its code is linked
+// into a client: the binary without its code, data and simulator slices, which is headers,
+// load commands, export and symbol tables, page padding and the code signature, plus the
+// Info.plist and _CodeSignature of the bundle
+func frameworkOverhead(fw *FrameworkInfo, nodes map[string]*types.FileNode) int64 {
+	binary := fw.BinaryInfo
+	overhead := binary.CodeSignatureSize
+	if node, ok := nodes[frameworkBinaryPath(fw)]; ok {
+		overhead = node.Size - binary.CodeSize - binary.DataSize - binary.SimulatorSliceSavings
+	}
+	if overhead < 0 {
+		overhead = 0
+	}
+
+	if node, ok := nodes[filepath.Join(fw.Path, "Info.plist")]; ok {
+		overhead += node.Size
+	}
+	if node, ok := nodes[filepath.Join(fw.Path, "_CodeSignature")]; ok {
+		overhead += node.Size
+	}
+	return overhead
+}
+
+// frameworkHasResources reports whether a framework contains files besides its binary,
+// bundle metadata, headers and modules
+func frameworkHasResources(fw *FrameworkInfo, nodes map[string]*types.FileNode) bool {
+	node, ok := nodes[fw.Path]
+	if !ok {
+		return false
+	}
+	binaryName := strings.TrimSuffix(fw.Name, ".framework")
+	for _, child := range node.Children {
+		switch child.Name {
+		case binaryName, "Info.plist", "_CodeSignature", "Headers", "PrivateHeaders", "Modules":
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// indexFileNodes adds every node of a file tree to a map by path
+func indexFileNodes(fileTree []*types.FileNode, nodes map[string]*types.FileNode) {
+	for _, node := range fileTree {
+		nodes[node.Path] = node
+		indexFileNodes(node.Children, nodes)
+	}
+}
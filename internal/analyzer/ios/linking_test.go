@@ -0,0 +1,151 @@
+package ios
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/ios/macho"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// linkingTestNode builds a file tree node from its path, with children for directories
+func linkingTestNode(path string, size int64, children ...*types.FileNode) *types.FileNode {
+	node := &types.FileNode{Path: path, Name: filepath.Base(path), Size: size, IsDir: len(children) > 0, Children: children}
+	for _, child := range children {
+		node.Size += child.Size
+	}
+	return node
+}
+
+// linkingTestFramework builds an app framework node with a binary, an Info.plist and
+// optional resources
+func linkingTestFramework(name string, binarySize int64, resources ...*types.FileNode) *types.FileNode {
+	path := "Frameworks/" + name + ".framework"
+	children := append([]*types.FileNode{
+		linkingTestNode(path+"/"+name, binarySize),
+		linkingTestNode(path+"/Info.plist", 1000),
+	}, resources...)
+	return linkingTestNode(path, 0, children...)
+}
+
+func TestGenerateFrameworkLinkingOptimizations(t *testing.T) {
+	fileTree := []*types.FileNode{
+		linkingTestNode("MyApp", 5000000),
+		linkingTestNode("Frameworks", 0,
+			linkingTestFramework("Analytics", 400000,
+				linkingTestNode("Frameworks/Analytics.framework/_CodeSignature", 0,
+					linkingTestNode("Frameworks/Analytics.framework/_CodeSignature/CodeResources", 2000))),
+			linkingTestFramework("Themes", 200000, linkingTestNode("Frameworks/Themes.framework/Assets.car", 90000)),
+			linkingTestFramework("Tiny", 90000),
+			linkingTestFramework("Shared", 500000),
+			linkingTestFramework("Core", 900000),
+		),
+		linkingTestNode("PlugIns", 0,
+			linkingTestNode("PlugIns/Widget.appex", 0,
+				linkingTestNode("PlugIns/Widget.appex/Widget", 300000),
+				linkingTestNode("PlugIns/Widget.appex/Frameworks", 0,
+					linkingTestNode("PlugIns/Widget.appex/Frameworks/Shared.framework", 0,
+						linkingTestNode("PlugIns/Widget.appex/Frameworks/Shared.framework/Shared", 500000)),
+					linkingTestNode("PlugIns/Widget.appex/Frameworks/Only.framework", 0,
+						linkingTestNode("PlugIns/Widget.appex/Frameworks/Only.framework/Only", 3000)),
+				),
+			),
+			linkingTestNode("PlugIns/Intents.appex", 0,
+				linkingTestNode("PlugIns/Intents.appex/Frameworks", 0,
+					linkingTestNode("PlugIns/Intents.appex/Frameworks/Only.framework", 0,
+						linkingTestNode("PlugIns/Intents.appex/Frameworks/Only.framework/Only", 3000)),
+					linkingTestNode("PlugIns/Intents.appex/Frameworks/Own.framework", 0,
+						linkingTestNode("PlugIns/Intents.appex/Frameworks/Own.framework/Own", 8000)),
+				),
+			),
+		),
+	}
+	frameworks := []*FrameworkInfo{
+		{Name: "Analytics.framework", Path: "Frameworks/Analytics.framework", BinaryInfo: &types.BinaryInfo{Type: "dylib", CodeSize: 300000, DataSize: 50000}},
+		{Name: "Themes.framework", Path: "Frameworks/Themes.framework", BinaryInfo: &types.BinaryInfo{Type: "dylib", CodeSize: 150000, DataSize: 20000}},
+		{Name: "Tiny.framework", Path: "Frameworks/Tiny.framework", BinaryInfo: &types.BinaryInfo{Type: "dylib", CodeSize: 20000, DataSize: 4000}},
+		{Name: "Shared.framework", Path: "Frameworks/Shared.framework", BinaryInfo: &types.BinaryInfo{Type: "dylib", CodeSize: 400000, DataSize: 20000}},
+		{Name: "Core.framework", Path: "Frameworks/Core.framework", BinaryInfo: &types.BinaryInfo{Type: "dylib", CodeSize: 800000, DataSize: 50000}},
+		{Name: "Prebuilt.framework", Path: "Frameworks/Prebuilt.framework"},
+	}
+	graph := macho.DependencyGraph{
+		"MyApp": {
+			"Frameworks/Analytics.framework/Analytics",
+			"Frameworks/Themes.framework/Themes",
+			"Frameworks/Tiny.framework/Tiny",
+			"Frameworks/Shared.framework/Shared",
+			"Frameworks/Core.framework/Core",
+		},
+		"PlugIns/Widget.appex/Widget":                         {"Frameworks/Tiny.framework/Tiny", "Frameworks/Shared.framework/Shared"},
+		"Frameworks/Analytics.framework/Analytics":            {"Frameworks/Core.framework/Core"},
+		"Frameworks/Themes.framework/Themes":                  {},
+		"Frameworks/Tiny.framework/Tiny":                      {},
+		"Frameworks/Shared.framework/Shared":                  {},
+		"Frameworks/Core.framework/Core":                      {},
+		"PlugIns/Widget.appex/Frameworks/Only.framework/Only": {},
+	}
+
+	opts := GenerateFrameworkLinkingOptimizations(fileTree, graph, frameworks, "MyApp")
+	titles := make([]string, len(opts))
+	for i, opt := range opts {
+		titles[i] = opt.Title
+		assert.Equal(t, "framework-linking", opt.Category)
+	}
+	require.Equal(t, []string{
+		"Framework embedded 2 times: Shared.framework",
+		"Link Analytics.framework statically",
+		"Tiny dynamic framework: Tiny.framework",
+		"Link Themes.framework statically",
+		"Framework embedded 2 times: Only.framework",
+	}, titles)
+
+	shared := opts[0]
+	assert.Equal(t, int64(500000), shared.Impact)
+	assert.Equal(t, []string{"Frameworks/Shared.framework", "PlugIns/Widget.appex/Frameworks/Shared.framework"}, shared.Files)
+	assert.Contains(t, shared.Action, "@executable_path/../../Frameworks")
+
+	// Binary without code and data, plus Info.plist and _CodeSignature
+	analytics := opts[1]
+	assert.Equal(t, int64(53000), analytics.Impact)
+	assert.Equal(t, []string{"Frameworks/Analytics.framework", "Frameworks/Analytics.framework/Analytics"}, analytics.Files)
+	assert.Contains(t, analytics.Description, "one of 5 embedded dylibs less to load at launch")
+	assert.Contains(t, analytics.Action, "MACH_O_TYPE = staticlib")
+
+	// Linked by two clients, each of which gets its 24000 bytes of code and data
+	tiny := opts[2]
+	assert.Equal(t, int64(90000-24000+1000-24000), tiny.Impact)
+	assert.Contains(t, tiny.Description, "linked by MyApp, PlugIns/Widget.appex/Widget")
+
+	themes := opts[3]
+	assert.Equal(t, int64(31000), themes.Impact)
+	assert.Contains(t, themes.Action, "MERGEABLE_LIBRARY = YES")
+
+	// Copied into two extensions only: one copy moves to the app
+	only := opts[4]
+	assert.Equal(t, int64(3000), only.Impact)
+	assert.Len(t, only.Files, 2)
+	for _, file := range only.Files {
+		assert.True(t, strings.HasPrefix(file, "PlugIns/"), file)
+	}
+}
+
+func TestGenerateFrameworkLinkingOptimizations_NoMainBinary(t *testing.T) {
+	frameworks := []*FrameworkInfo{
+		{Name: "Kit.framework", Path: "Frameworks/Kit.framework", BinaryInfo: &types.BinaryInfo{Type: "dylib", CodeSize: 500000}},
+	}
+	graph := macho.DependencyGraph{"MyApp": {"Frameworks/Kit.framework/Kit"}}
+
+	assert.Empty(t, GenerateFrameworkLinkingOptimizations(nil, graph, frameworks, ""))
+}
+
+func TestIsExtensionFramework(t *testing.T) {
+	assert.True(t, isExtensionFramework("PlugIns/Widget.appex/Frameworks/Kit.framework"))
+	assert.True(t, isExtensionFramework("Extensions/Share.appex/Frameworks/Kit.framework"))
+	assert.False(t, isExtensionFramework("Frameworks/Kit.framework"))
+	assert.False(t, isExtensionFramework("PlugIns/Widget.appex/Frameworks/Kit.framework/Kit"))
+	assert.False(t, isExtensionFramework("Watch/App.app/Frameworks/Kit.framework"))
+}
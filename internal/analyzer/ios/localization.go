@@ -0,0 +1,192 @@
+package ios
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/localization"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+	"howett.net/plist"
+)
+
+// baseLocalization is the .lproj of Interface Builder base internationalization,
+// which belongs to the development region
+const baseLocalization = "Base"
+
+// stringCatalog holds the .xcstrings keys used by the localization inventory
+type stringCatalog struct {
+	SourceLanguage string `json:"sourceLanguage"`
+	Strings        map[string]struct {
+		Localizations map[string]json.RawMessage `json:"localizations"`
+	} `json:"strings"`
+}
+
+// AnalyzeLocalization inventories the .lproj directories and string catalogs of an
+// app bundle. Locales that only frameworks and resource bundles ship are marked as
+// third-party. Returns nil when the app is not localized.
+func AnalyzeLocalization(appPath string) *types.LocalizationInfo {
+	baseLocale := developmentRegion(appPath)
+	collector := localization.NewCollector()
+
+	_ = filepath.WalkDir(appPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		relPath, err := filepath.Rel(appPath, path)
+		if err != nil {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+		firstParty := !isThirdPartyPath(relPath)
+
+		switch {
+		case d.IsDir() && strings.HasSuffix(d.Name(), ".lproj"):
+			locale := lprojLocale(d.Name(), baseLocale)
+			if locale == "" {
+				return fs.SkipDir
+			}
+			collectLproj(collector, path, relPath, locale, firstParty)
+			return fs.SkipDir
+
+		case !d.IsDir() && strings.HasSuffix(d.Name(), ".xcstrings"):
+			collectStringCatalog(collector, path, relPath, baseLocale, firstParty)
+		}
+		return nil
+	})
+
+	return collector.Result(baseLocale)
+}
+
+// collectLproj adds the files of an .lproj directory and the keys of its string tables
+func collectLproj(collector *localization.Collector, lprojPath, relPath, locale string, firstParty bool) {
+	bundle := filepath.ToSlash(filepath.Dir(relPath))
+
+	var size int64
+	_ = filepath.WalkDir(lprojPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+
+		ext := filepath.Ext(d.Name())
+		if ext != ".strings" && ext != ".stringsdict" {
+			return nil
+		}
+		keys, err := stringTableKeys(path)
+		if err != nil {
+			return nil
+		}
+		tableName := bundle + "/" + strings.TrimSuffix(d.Name(), ext)
+		collector.AddKeys(locale, tableName, keys, firstParty)
+		return nil
+	})
+
+	collector.AddSize(locale, relPath, size, firstParty)
+}
+
+// collectStringCatalog splits an .xcstrings file between its locales in proportion to
+// the size of their translations, and adds the keys each locale translates
+func collectStringCatalog(collector *localization.Collector, path, relPath, baseLocale string, firstParty bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var catalog stringCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return
+	}
+
+	sourceLocale := localization.Normalize(catalog.SourceLanguage)
+	if sourceLocale == "" {
+		sourceLocale = baseLocale
+	}
+
+	translationBytes := make(map[string]int64)
+	keys := make(map[string][]string)
+	var total int64
+	for key, entry := range catalog.Strings {
+		// Keys without translations are shown as is, in the source language
+		if len(entry.Localizations) == 0 {
+			keys[sourceLocale] = append(keys[sourceLocale], key)
+		}
+		for name, translation := range entry.Localizations {
+			locale := localization.Normalize(name)
+			if locale == "" {
+				continue
+			}
+			translationBytes[locale] += int64(len(translation))
+			total += int64(len(translation))
+			keys[locale] = append(keys[locale], key)
+		}
+	}
+
+	tableName := filepath.ToSlash(filepath.Dir(relPath)) + "/" + strings.TrimSuffix(filepath.Base(relPath), ".xcstrings")
+	for locale, localeKeys := range keys {
+		collector.AddKeys(locale, tableName, localeKeys, firstParty)
+	}
+	for locale, n := range translationBytes {
+		collector.AddSize(locale, relPath, int64(len(data))*n/total, firstParty)
+	}
+}
+
+// stringTableKeys returns the keys of a .strings or .stringsdict file. Both are property
+// lists: text "key" = "value"; pairs in source form, and binary property lists once compiled.
+func stringTableKeys(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var table map[string]interface{}
+	if _, err := plist.Unmarshal(data, &table); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// developmentRegion returns the CFBundleDevelopmentRegion of an app as a locale, "en" if unset
+func developmentRegion(appPath string) string {
+	data, err := os.ReadFile(filepath.Join(appPath, "Info.plist"))
+	if err == nil {
+		var info struct {
+			DevelopmentRegion string `plist:"CFBundleDevelopmentRegion"`
+		}
+		if _, err := plist.Unmarshal(data, &info); err == nil {
+			if locale := localization.Normalize(info.DevelopmentRegion); locale != "" {
+				return locale
+			}
+		}
+	}
+	return "en"
+}
+
+// lprojLocale returns the locale of an .lproj directory name; Base.lproj counts
+// towards the development region
+func lprojLocale(name, baseLocale string) string {
+	name = strings.TrimSuffix(name, ".lproj")
+	if name == baseLocalization {
+		return baseLocale
+	}
+	return localization.Normalize(name)
+}
+
+// isThirdPartyPath reports whether a path belongs to an embedded framework or a
+// resource bundle rather than the app or one of its extensions
+func isThirdPartyPath(relPath string) bool {
+	for _, part := range strings.Split(relPath, "/") {
+		if part == "Frameworks" || strings.HasSuffix(part, ".bundle") {
+			return true
+		}
+	}
+	return false
+}
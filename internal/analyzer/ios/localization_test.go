@@ -0,0 +1,88 @@
+package ios
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"howett.net/plist"
+)
+
+// writeTestFile writes a file of an app bundle, creating its directories
+func writeTestFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+}
+
+// localeInfo returns the inventory entry of a locale
+func localeInfo(t *testing.T, info *types.LocalizationInfo, locale string) types.LocaleInfo {
+	t.Helper()
+	for _, l := range info.Locales {
+		if l.Locale == locale {
+			return l
+		}
+	}
+	require.Failf(t, "locale not found", "%s not in %+v", locale, info.Locales)
+	return types.LocaleInfo{}
+}
+
+func TestAnalyzeLocalization(t *testing.T) {
+	app := filepath.Join(t.TempDir(), "Example.app")
+	info, err := plist.Marshal(map[string]interface{}{"CFBundleDevelopmentRegion": "en"}, plist.XMLFormat)
+	require.NoError(t, err)
+	writeTestFile(t, filepath.Join(app, "Info.plist"), info)
+
+	// Text .strings in the app, a compiled binary one in the extension
+	writeTestFile(t, filepath.Join(app, "en.lproj", "Localizable.strings"), []byte("\"title\" = \"Title\";\n\"done\" = \"Done\";\n"))
+	writeTestFile(t, filepath.Join(app, "de.lproj", "Localizable.strings"), []byte("\"title\" = \"Titel\";\n"))
+	writeTestFile(t, filepath.Join(app, "Base.lproj", "Main.storyboardc", "Info.plist"), make([]byte, 50))
+	widget, err := plist.Marshal(map[string]string{"widget": "Widget"}, plist.BinaryFormat)
+	require.NoError(t, err)
+	writeTestFile(t, filepath.Join(app, "PlugIns", "Widget.appex", "pt_BR.lproj", "Localizable.strings"), widget)
+
+	// A framework that ships more languages than the app
+	writeTestFile(t, filepath.Join(app, "Frameworks", "Kit.framework", "en.lproj", "Kit.strings"), []byte("\"ok\" = \"OK\";"))
+	writeTestFile(t, filepath.Join(app, "Frameworks", "Kit.framework", "de.lproj", "Kit.strings"), []byte("\"ok\" = \"OK\";"))
+	writeTestFile(t, filepath.Join(app, "Frameworks", "Kit.framework", "ja.lproj", "Kit.strings"), []byte("\"ok\" = \"OK\";"))
+
+	// A string catalog with a German translation
+	writeTestFile(t, filepath.Join(app, "Settings.xcstrings"), []byte(`{"sourceLanguage": "en", "strings": {
+		"Save": {"localizations": {"en": {"stringUnit": {"value": "Save"}}, "de": {"stringUnit": {"value": "Sichern"}}}},
+		"Cancel": {}
+	}}`))
+
+	result := AnalyzeLocalization(app)
+	require.NotNil(t, result)
+	assert.Equal(t, "en", result.BaseLocale)
+	assert.Len(t, result.Locales, 4)
+
+	en := localeInfo(t, result, "en")
+	assert.True(t, en.IsBase)
+	assert.Contains(t, en.Sources, "Base.lproj")
+	assert.Equal(t, 5, en.Keys) // title, done, ok, Save and the untranslated Cancel
+
+	de := localeInfo(t, result, "de")
+	assert.False(t, de.ThirdPartyOnly)
+	assert.Equal(t, 3, de.Keys)
+	// Missing: done from Localizable and Cancel from Settings
+	assert.Equal(t, 2, de.MissingKeys)
+	assert.Equal(t, []string{"Frameworks/Kit.framework/de.lproj", "Settings.xcstrings", "de.lproj"}, de.Sources)
+
+	ja := localeInfo(t, result, "ja")
+	assert.True(t, ja.ThirdPartyOnly)
+	assert.Zero(t, ja.MissingKeys)
+
+	pt := localeInfo(t, result, "pt-BR")
+	assert.Equal(t, []string{"PlugIns/Widget.appex/pt_BR.lproj"}, pt.Sources)
+	assert.Equal(t, 1, pt.Keys)
+}
+
+func TestAnalyzeLocalization_NotLocalized(t *testing.T) {
+	app := filepath.Join(t.TempDir(), "Example.app")
+	writeTestFile(t, filepath.Join(app, "Example"), make([]byte, 10))
+	assert.Nil(t, AnalyzeLocalization(app))
+}
@@ -0,0 +1,169 @@
+package ios
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Dependency managers of locked dependencies
+const (
+	SourceSwiftPM   = "spm"
+	SourceCocoaPods = "cocoapods"
+	SourceCarthage  = "carthage"
+)
+
+// LockedDependency is a dependency pinned by a Package.resolved, Podfile.lock or
+// Cartfile.resolved file
+type LockedDependency struct {
+	Name    string
+	Source  string // SourceSwiftPM, SourceCocoaPods or SourceCarthage
+	Version string // Version, or branch or revision when not pinned to a version
+}
+
+// LoadLockfile reads the dependencies of a Package.resolved, Podfile.lock or
+// Cartfile.resolved file, recognized by its name
+func LoadLockfile(filename string) ([]LockedDependency, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	var dependencies []LockedDependency
+	switch filepath.Base(filename) {
+	case "Package.resolved":
+		dependencies, err = parsePackageResolved(data)
+	case "Podfile.lock":
+		dependencies, err = parsePodfileLock(data)
+	case "Cartfile.resolved":
+		dependencies, err = parseCartfileResolved(data)
+	default:
+		return nil, fmt.Errorf("unsupported lockfile %s: expected Package.resolved, Podfile.lock or Cartfile.resolved", filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid lockfile %s: %w", filename, err)
+	}
+	return dependencies, nil
+}
+
+// packageResolvedPin is a pin of a Package.resolved file: version 1 names the package,
+// versions 2 and 3 only have its identity and location
+type packageResolvedPin struct {
+	Package       string `json:"package"`
+	RepositoryURL string `json:"repositoryURL"`
+	Identity      string `json:"identity"`
+	Location      string `json:"location"`
+	State         struct {
+		Version  string `json:"version"`
+		Branch   string `json:"branch"`
+		Revision string `json:"revision"`
+	} `json:"state"`
+}
+
+// parsePackageResolved reads the pins of a Package.resolved file of any version
+func parsePackageResolved(data []byte) ([]LockedDependency, error) {
+	var resolved struct {
+		Pins   []packageResolvedPin `json:"pins"`
+		Object struct {
+			Pins []packageResolvedPin `json:"pins"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal(data, &resolved); err != nil {
+		return nil, err
+	}
+
+	var dependencies []LockedDependency
+	for _, pin := range append(resolved.Object.Pins, resolved.Pins...) {
+		name := pin.Package
+		if name == "" {
+			name = repositoryName(pin.Location)
+		}
+		if name == "" {
+			name = pin.Identity
+		}
+		version := pin.State.Version
+		if version == "" {
+			version = pin.State.Branch
+		}
+		if version == "" && len(pin.State.Revision) >= 7 {
+			version = pin.State.Revision[:7]
+		}
+		dependencies = append(dependencies, LockedDependency{Name: name, Source: SourceSwiftPM, Version: version})
+	}
+	return dependencies, nil
+}
+
+// podfileLockPod matches a pod of the PODS section, e.g. `  - Alamofire (5.8.0)` or
+// `  - "Firebase/Core (10.18.0)":` for subspecs with dependencies
+var podfileLockPod = regexp.MustCompile(`^  - "?([^ "]+) \(([^)]+)\)"?:?$`)
+
+// parsePodfileLock reads the pods of a Podfile.lock, merging subspecs into their pod
+func parsePodfileLock(data []byte) ([]LockedDependency, error) {
+	var dependencies []LockedDependency
+	seen := make(map[string]bool)
+	inPods := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, " ") {
+			inPods = line == "PODS:"
+			continue
+		}
+		if !inPods {
+			continue
+		}
+		match := podfileLockPod.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		name := strings.SplitN(match[1], "/", 2)[0]
+		if !seen[name] {
+			seen[name] = true
+			dependencies = append(dependencies, LockedDependency{Name: name, Source: SourceCocoaPods, Version: match[2]})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(dependencies) == 0 {
+		return nil, fmt.Errorf("no pods found")
+	}
+	return dependencies, nil
+}
+
+// parseCartfileResolved reads the `<origin> "<location>" "<version>"` lines of a Cartfile.resolved
+func parseCartfileResolved(data []byte) ([]LockedDependency, error) {
+	var dependencies []LockedDependency
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected origin, location and version, got %q", lineNumber, line)
+		}
+		dependencies = append(dependencies, LockedDependency{
+			Name:    repositoryName(strings.Trim(fields[1], `"`)),
+			Source:  SourceCarthage,
+			Version: strings.Trim(fields[2], `"`),
+		})
+	}
+	return dependencies, scanner.Err()
+}
+
+// repositoryName returns the last path component of a repository location without
+// its extension, e.g. Alamofire for https://github.com/Alamofire/Alamofire.git
+func repositoryName(location string) string {
+	name := location[strings.LastIndex(location, "/")+1:]
+	return strings.TrimSuffix(strings.TrimSuffix(name, ".git"), ".json")
+}
@@ -0,0 +1,104 @@
+package ios
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLockfile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadLockfile_PackageResolved(t *testing.T) {
+	v2 := `{
+  "pins" : [
+    {
+      "identity" : "alamofire",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/Alamofire/Alamofire.git",
+      "state" : { "revision" : "f455c2975872ccd2d9c81594c658af65716e9b9a", "version" : "5.9.1" }
+    },
+    {
+      "identity" : "swift-collections",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/apple/swift-collections",
+      "state" : { "branch" : "main", "revision" : "94cf62b3ba8d4bed62680a282d4c25f9c63c2efb" }
+    }
+  ],
+  "version" : 2
+}`
+	deps, err := LoadLockfile(writeLockfile(t, "Package.resolved", v2))
+	require.NoError(t, err)
+	assert.Equal(t, []LockedDependency{
+		{Name: "Alamofire", Source: SourceSwiftPM, Version: "5.9.1"},
+		{Name: "swift-collections", Source: SourceSwiftPM, Version: "main"},
+	}, deps)
+
+	v1 := `{"object": {"pins": [{"package": "Kingfisher", "repositoryURL": "https://github.com/onevcat/Kingfisher.git",
+		"state": {"branch": null, "revision": "3ec0ab0bca4feb56e8b33e289c9496e89059dd08", "version": null}}]}, "version": 1}`
+	deps, err = LoadLockfile(writeLockfile(t, "Package.resolved", v1))
+	require.NoError(t, err)
+	assert.Equal(t, []LockedDependency{{Name: "Kingfisher", Source: SourceSwiftPM, Version: "3ec0ab0"}}, deps)
+}
+
+func TestLoadLockfile_PodfileLock(t *testing.T) {
+	lock := `PODS:
+  - Alamofire (5.8.0)
+  - "Firebase/Core (10.18.0)":
+    - FirebaseAnalytics (~> 10.18.0)
+  - Firebase/CoreOnly (10.18.0):
+    - FirebaseCore (= 10.18.0)
+  - FirebaseCore (10.18.0)
+
+DEPENDENCIES:
+  - Alamofire (~> 5.8)
+  - Firebase/Core
+
+SPEC CHECKSUMS:
+  Alamofire: 3ca42e259043ee0dc5c0cdd76c4bc568b8e42af7
+
+COCOAPODS: 1.14.3
+`
+	deps, err := LoadLockfile(writeLockfile(t, "Podfile.lock", lock))
+	require.NoError(t, err)
+	assert.Equal(t, []LockedDependency{
+		{Name: "Alamofire", Source: SourceCocoaPods, Version: "5.8.0"},
+		{Name: "Firebase", Source: SourceCocoaPods, Version: "10.18.0"},
+		{Name: "FirebaseCore", Source: SourceCocoaPods, Version: "10.18.0"},
+	}, deps)
+}
+
+func TestLoadLockfile_CartfileResolved(t *testing.T) {
+	lock := `github "Alamofire/Alamofire" "5.8.0"
+git "https://example.com/acme/AcmeKit.git" "1a2b3c4"
+binary "https://example.com/Sentry.json" "8.17.1"
+`
+	deps, err := LoadLockfile(writeLockfile(t, "Cartfile.resolved", lock))
+	require.NoError(t, err)
+	assert.Equal(t, []LockedDependency{
+		{Name: "Alamofire", Source: SourceCarthage, Version: "5.8.0"},
+		{Name: "AcmeKit", Source: SourceCarthage, Version: "1a2b3c4"},
+		{Name: "Sentry", Source: SourceCarthage, Version: "8.17.1"},
+	}, deps)
+}
+
+func TestLoadLockfile_Errors(t *testing.T) {
+	_, err := LoadLockfile(writeLockfile(t, "Gemfile.lock", ""))
+	assert.ErrorContains(t, err, "unsupported lockfile")
+
+	_, err = LoadLockfile(writeLockfile(t, "Podfile.lock", "COCOAPODS: 1.14.3\n"))
+	assert.ErrorContains(t, err, "no pods found")
+
+	_, err = LoadLockfile(writeLockfile(t, "Cartfile.resolved", `github "Alamofire/Alamofire"`))
+	assert.ErrorContains(t, err, "line 1")
+
+	_, err = LoadLockfile(filepath.Join(t.TempDir(), "Package.resolved"))
+	assert.ErrorContains(t, err, "failed to read lockfile")
+}
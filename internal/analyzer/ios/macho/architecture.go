@@ -4,6 +4,7 @@ import (
 	"debug/macho"
 	"encoding/binary"
 	"os"
+	"strings"
 )
 
 // Mach-O magic numbers
@@ -78,3 +79,84 @@ func IsFatBinary(path string) bool {
 
 	return magic == MagicFat64 || magic == MagicFat32
 }
+
+// GetArchitectureName converts a CPU type and subtype to an architecture name,
+// distinguishing arm64e from arm64
+func GetArchitectureName(cpu macho.Cpu, subCpu uint32) string {
+	// The high byte of the subtype holds capability bits
+	if cpu == macho.CpuArm64 && subCpu&0x00ffffff == cpuSubtypeArm64E {
+		return "arm64e"
+	}
+	return GetCPUTypeName(cpu)
+}
+
+// Load commands that declare the target platform
+const (
+	loadCmdBuildVersion      = 0x32
+	loadCmdVersionMinMacOS   = 0x24
+	loadCmdVersionMinIOS     = 0x25
+	loadCmdVersionMinTvOS    = 0x2f
+	loadCmdVersionMinWatchOS = 0x30
+)
+
+// cpuSubtypeArm64E is the CPU subtype of arm64e slices
+const cpuSubtypeArm64E = 2
+
+// buildPlatforms maps LC_BUILD_VERSION platforms to names
+var buildPlatforms = map[uint32]string{
+	1:  "macos",
+	2:  "ios",
+	3:  "tvos",
+	4:  "watchos",
+	5:  "bridgeos",
+	6:  "maccatalyst",
+	7:  "ios-simulator",
+	8:  "tvos-simulator",
+	9:  "watchos-simulator",
+	10: "driverkit",
+	11: "visionos",
+	12: "visionos-simulator",
+}
+
+// GetPlatform returns the platform a Mach-O file was built for, from LC_BUILD_VERSION
+// or the older LC_VERSION_MIN_* commands. Older commands do not distinguish simulators,
+// so Intel slices built for iOS, tvOS or watchOS are reported as simulator slices.
+// Returns "" when the binary declares no platform.
+func GetPlatform(file *macho.File) string {
+	for _, load := range file.Loads {
+		raw := load.Raw()
+		if len(raw) < 12 {
+			continue
+		}
+
+		var platform string
+		switch file.ByteOrder.Uint32(raw) {
+		case loadCmdBuildVersion:
+			return buildPlatforms[file.ByteOrder.Uint32(raw[8:])]
+		case loadCmdVersionMinMacOS:
+			return "macos"
+		case loadCmdVersionMinIOS:
+			platform = "ios"
+		case loadCmdVersionMinTvOS:
+			platform = "tvos"
+		case loadCmdVersionMinWatchOS:
+			platform = "watchos"
+		default:
+			continue
+		}
+		if file.Cpu == macho.CpuAmd64 || file.Cpu == macho.Cpu386 {
+			platform += "-simulator"
+		}
+		return platform
+	}
+	return ""
+}
+
+// IsSimulatorSlice reports whether a slice can only run in a simulator. Slices without
+// a declared platform are judged by their architecture.
+func IsSimulatorSlice(architecture, platform string) bool {
+	if platform == "" {
+		return architecture == "x86_64" || architecture == "i386"
+	}
+	return strings.HasSuffix(platform, "-simulator")
+}
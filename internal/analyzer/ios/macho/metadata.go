@@ -0,0 +1,517 @@
+package macho
+
+import (
+	"bytes"
+	"debug/macho"
+	"encoding/binary"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// reflectionSections are the Swift sections that -disable-reflection-metadata drops
+var reflectionSections = map[string]bool{
+	"__swift5_fieldmd": true,
+	"__swift5_reflstr": true,
+	"__swift5_assocty": true,
+	"__swift5_builtin": true,
+	"__swift5_capture": true,
+}
+
+// Swift context descriptor kinds (the low 5 bits of the descriptor flags)
+const (
+	contextKindModule = 0
+	contextKindClass  = 16
+	contextKindStruct = 17
+	contextKindEnum   = 18
+)
+
+// maxLargestTypes is the number of Swift types listed in RuntimeMetadata.LargestTypes
+const maxLargestTypes = 10
+
+// maxContextDepth bounds parent chain walks in malformed binaries
+const maxContextDepth = 16
+
+// ParseRuntimeMetadata counts the Swift types, protocols and conformances and the
+// Objective-C classes, categories, protocols and selectors of a binary, and measures
+// their metadata sections. Swift metadata is attributed to modules through the type
+// context descriptors; Objective-C classes without a Swift module are attributed to
+// binaryName. Returns nil when the binary has no Swift or Objective-C metadata.
+func ParseRuntimeMetadata(file *macho.File, binaryName string) *types.RuntimeMetadata {
+	metadata := &types.RuntimeMetadata{}
+	found := false
+	for _, section := range file.Sections {
+		switch {
+		case strings.HasPrefix(section.Name, "__swift5_"):
+			metadata.SwiftMetadataSize += int64(section.Size)
+			if reflectionSections[section.Name] {
+				metadata.ReflectionSize += int64(section.Size)
+			}
+		case strings.HasPrefix(section.Name, "__objc_"):
+			metadata.ObjCMetadataSize += int64(section.Size)
+		default:
+			continue
+		}
+		found = true
+	}
+	if !found {
+		return nil
+	}
+
+	img := newImage(file)
+	modules := make(map[string]*types.RuntimeModule)
+	module := func(name string) *types.RuntimeModule {
+		if modules[name] == nil {
+			modules[name] = &types.RuntimeModule{Name: name}
+		}
+		return modules[name]
+	}
+
+	// Swift types, with their field descriptors as reflection cost
+	var swiftTypes []types.RuntimeType
+	for _, entry := range img.relativeList("__swift5_types") {
+		metadata.SwiftTypes++
+		descriptor, ok := img.typeDescriptor(entry)
+		if !ok {
+			continue
+		}
+		t := img.swiftType(descriptor)
+		if t.Module == "" {
+			continue
+		}
+		m := module(t.Module)
+		m.SwiftTypes++
+		m.ReflectionSize += t.ReflectionSize
+		swiftTypes = append(swiftTypes, t)
+	}
+
+	for _, entry := range img.relativeList("__swift5_protos") {
+		metadata.SwiftProtocols++
+		descriptor, ok := img.indirectable(entry)
+		if !ok {
+			continue
+		}
+		if name := img.moduleName(descriptor); name != "" {
+			module(name).SwiftProtocols++
+		}
+	}
+
+	// Conformances are attributed to the module of the conforming type
+	for _, entry := range img.relativeList("__swift5_proto") {
+		metadata.SwiftConformances++
+		conformance, ok := img.relative(entry)
+		if !ok {
+			continue
+		}
+		flags, ok := img.uint32(conformance + 12)
+		if !ok {
+			continue
+		}
+		typeRef, ok := img.relative(conformance + 4)
+		if !ok {
+			continue
+		}
+		switch (flags >> 3) & 7 {
+		case 0: // Direct type descriptor
+		case 1: // Indirect type descriptor
+			if typeRef, ok = img.pointer(typeRef); !ok {
+				continue
+			}
+		default: // Objective-C class
+			continue
+		}
+		if name := img.moduleName(typeRef); name != "" {
+			module(name).SwiftConformances++
+		}
+	}
+
+	// Objective-C classes, by the module of their Swift mangled name
+	if img.is64 {
+		for _, class := range img.pointerList("__objc_classlist") {
+			metadata.ObjCClasses++
+			name := binaryName
+			if className := img.objcClassName(class); className != "" {
+				if swiftModule := swiftClassModule(className); swiftModule != "" {
+					name = swiftModule
+				}
+			}
+			module(name).ObjCClasses++
+		}
+	} else if section := img.section("__objc_classlist"); section != nil {
+		metadata.ObjCClasses = int(section.Size / 4)
+	}
+
+	metadata.ObjCCategories = img.listLength("__objc_catlist")
+	metadata.ObjCProtocols = img.listLength("__objc_protolist")
+	metadata.ObjCSelectors = img.stringCount("__objc_methname")
+
+	for _, m := range modules {
+		metadata.Modules = append(metadata.Modules, *m)
+	}
+	sort.Slice(metadata.Modules, func(i, j int) bool {
+		a, b := metadata.Modules[i], metadata.Modules[j]
+		if a.ReflectionSize != b.ReflectionSize {
+			return a.ReflectionSize > b.ReflectionSize
+		}
+		return a.Name < b.Name
+	})
+
+	sort.SliceStable(swiftTypes, func(i, j int) bool {
+		return swiftTypes[i].ReflectionSize > swiftTypes[j].ReflectionSize
+	})
+	for _, t := range swiftTypes {
+		if len(metadata.LargestTypes) == maxLargestTypes || t.ReflectionSize == 0 {
+			break
+		}
+		metadata.LargestTypes = append(metadata.LargestTypes, t)
+	}
+
+	return metadata
+}
+
+// swiftClassModule returns the module of a Swift class from its Objective-C runtime
+// name, e.g. MyKit for _TtC5MyKit5Cache. Returns "" for Objective-C classes.
+func swiftClassModule(name string) string {
+	if !strings.HasPrefix(name, "_Tt") {
+		return ""
+	}
+	rest := strings.TrimLeft(name[3:], "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	// "s" is the standard library module without a length prefix
+	if strings.HasPrefix(rest, "s") {
+		return "Swift"
+	}
+	digits := 0
+	for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+		digits++
+	}
+	length, err := strconv.Atoi(rest[:digits])
+	if err != nil || length <= 0 || digits+length > len(rest) {
+		return ""
+	}
+	return rest[digits : digits+length]
+}
+
+// image reads the mapped memory of a Mach-O file by virtual address. Only 64-bit
+// pointers are decoded; relative pointers work for both word sizes.
+type image struct {
+	file  *macho.File
+	order binary.ByteOrder
+	is64  bool
+	base  uint64                    // Address of the __TEXT segment, for chained fixup offsets
+	data  map[*macho.Section][]byte // Section contents, loaded on first read
+}
+
+func newImage(file *macho.File) *image {
+	img := &image{
+		file:  file,
+		order: file.ByteOrder,
+		is64:  file.Magic == macho.Magic64,
+		data:  make(map[*macho.Section][]byte),
+	}
+	if seg := file.Segment("__TEXT"); seg != nil {
+		img.base = seg.Addr
+	}
+	return img
+}
+
+// section returns the first section with the given name in any segment
+func (m *image) section(name string) *macho.Section {
+	for _, section := range m.file.Sections {
+		if section.Name == name {
+			return section
+		}
+	}
+	return nil
+}
+
+// read returns n bytes at a virtual address from the section that contains it
+func (m *image) read(addr uint64, n int) ([]byte, bool) {
+	section, data := m.sectionAt(addr)
+	if section == nil {
+		return nil, false
+	}
+	offset := addr - section.Addr
+	if offset+uint64(n) > uint64(len(data)) {
+		return nil, false
+	}
+	return data[offset : offset+uint64(n)], true
+}
+
+// sectionAt returns the section that contains a virtual address, with its contents
+func (m *image) sectionAt(addr uint64) (*macho.Section, []byte) {
+	for _, section := range m.file.Sections {
+		if addr < section.Addr || addr >= section.Addr+section.Size {
+			continue
+		}
+		// Zero-fill sections have no file content
+		if section.Offset == 0 {
+			return nil, nil
+		}
+		data, ok := m.data[section]
+		if !ok {
+			data, _ = section.Data()
+			m.data[section] = data
+		}
+		return section, data
+	}
+	return nil, nil
+}
+
+func (m *image) uint32(addr uint64) (uint32, bool) {
+	buf, ok := m.read(addr, 4)
+	if !ok {
+		return 0, false
+	}
+	return m.order.Uint32(buf), true
+}
+
+// relative resolves a 32-bit relative pointer stored at addr
+func (m *image) relative(addr uint64) (uint64, bool) {
+	value, ok := m.uint32(addr)
+	if !ok || value == 0 {
+		return 0, false
+	}
+	return addr + uint64(int64(int32(value))), true
+}
+
+// indirectable resolves a relative pointer whose low bit marks a pointer to the target
+func (m *image) indirectable(addr uint64) (uint64, bool) {
+	value, ok := m.uint32(addr)
+	if !ok || value == 0 {
+		return 0, false
+	}
+	target := addr + uint64(int64(int32(value&^1)))
+	if value&1 == 0 {
+		return target, true
+	}
+	return m.pointer(target)
+}
+
+// typeDescriptor resolves a __swift5_types entry, whose low two bits are the type
+// reference kind. Objective-C class references are not resolved.
+func (m *image) typeDescriptor(addr uint64) (uint64, bool) {
+	value, ok := m.uint32(addr)
+	if !ok || value == 0 {
+		return 0, false
+	}
+	target := addr + uint64(int64(int32(value&^3)))
+	switch value & 3 {
+	case 0:
+		return target, true
+	case 1:
+		return m.pointer(target)
+	}
+	return 0, false
+}
+
+// pointer reads a 64-bit pointer at addr. Chained fixups store either the target
+// address or its offset from the image base in the low bits; binds to other images
+// are not resolved.
+func (m *image) pointer(addr uint64) (uint64, bool) {
+	if !m.is64 {
+		return 0, false
+	}
+	buf, ok := m.read(addr, 8)
+	if !ok {
+		return 0, false
+	}
+	value := m.order.Uint64(buf)
+	if value == 0 {
+		return 0, false
+	}
+
+	var target uint64
+	switch {
+	case value>>63 == 1 && (value>>62)&1 == 0:
+		// arm64e authenticated rebase: 32-bit offset from the image base
+		target = m.base + value&0xffffffff
+	case value>>63 == 1:
+		return 0, false // Bind
+	default:
+		target = value & 0xfffffffff
+	}
+	if _, ok := m.read(target, 1); ok {
+		return target, true
+	}
+	if _, ok := m.read(m.base+target, 1); ok {
+		return m.base + target, true
+	}
+	return 0, false
+}
+
+// cString reads a NUL-terminated string at addr
+func (m *image) cString(addr uint64) string {
+	section, data := m.sectionAt(addr)
+	if section == nil || addr-section.Addr >= uint64(len(data)) {
+		return ""
+	}
+	str := data[addr-section.Addr:]
+	if end := bytes.IndexByte(str, 0); end >= 0 {
+		str = str[:end]
+	}
+	return string(str)
+}
+
+// relativeList returns the addresses of the 32-bit entries of a Swift record section
+func (m *image) relativeList(name string) []uint64 {
+	section := m.section(name)
+	if section == nil {
+		return nil
+	}
+	entries := make([]uint64, 0, section.Size/4)
+	for offset := uint64(0); offset+4 <= section.Size; offset += 4 {
+		entries = append(entries, section.Addr+offset)
+	}
+	return entries
+}
+
+// pointerList returns the targets of a list of 64-bit pointers, e.g. __objc_classlist.
+// Unresolved pointers are returned as 0.
+func (m *image) pointerList(name string) []uint64 {
+	section := m.section(name)
+	if section == nil {
+		return nil
+	}
+	targets := make([]uint64, 0, section.Size/8)
+	for offset := uint64(0); offset+8 <= section.Size; offset += 8 {
+		target, _ := m.pointer(section.Addr + offset)
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// listLength returns the number of pointers in a list section
+func (m *image) listLength(name string) int {
+	section := m.section(name)
+	if section == nil {
+		return 0
+	}
+	if m.is64 {
+		return int(section.Size / 8)
+	}
+	return int(section.Size / 4)
+}
+
+// stringCount returns the number of NUL-terminated strings in a section
+func (m *image) stringCount(name string) int {
+	section := m.section(name)
+	if section == nil {
+		return 0
+	}
+	_, data := m.sectionAt(section.Addr)
+	count := 0
+	for _, part := range strings.Split(string(data), "\x00") {
+		if part != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// moduleName walks the parent chain of a context descriptor up to its module
+func (m *image) moduleName(descriptor uint64) string {
+	for depth := 0; depth < maxContextDepth; depth++ {
+		flags, ok := m.uint32(descriptor)
+		if !ok {
+			return ""
+		}
+		if flags&0x1f == contextKindModule {
+			if name, ok := m.relative(descriptor + 8); ok {
+				return m.cString(name)
+			}
+			return ""
+		}
+		if descriptor, ok = m.indirectable(descriptor + 4); !ok {
+			return ""
+		}
+	}
+	return ""
+}
+
+// swiftType reads the name, module and field descriptor of a Swift type descriptor
+func (m *image) swiftType(descriptor uint64) types.RuntimeType {
+	var t types.RuntimeType
+	flags, ok := m.uint32(descriptor)
+	if !ok {
+		return t
+	}
+	switch flags & 0x1f {
+	case contextKindClass:
+		t.Kind = "class"
+	case contextKindStruct:
+		t.Kind = "struct"
+	case contextKindEnum:
+		t.Kind = "enum"
+	default:
+		return t
+	}
+
+	// Qualify nested types with the names of their enclosing types
+	var names []string
+	context := descriptor
+	for depth := 0; depth < maxContextDepth; depth++ {
+		contextFlags, ok := m.uint32(context)
+		if !ok {
+			break
+		}
+		kind := contextFlags & 0x1f
+		if kind == contextKindModule {
+			if name, ok := m.relative(context + 8); ok {
+				t.Module = m.cString(name)
+			}
+			break
+		}
+		if kind >= contextKindClass && kind <= contextKindEnum {
+			if name, ok := m.relative(context + 8); ok {
+				names = append([]string{m.cString(name)}, names...)
+			}
+		}
+		if context, ok = m.indirectable(context + 4); !ok {
+			break
+		}
+	}
+	t.Name = strings.Join(names, ".")
+
+	// Field descriptor: mangled type name, superclass, kind, record size, field count
+	fields, ok := m.relative(descriptor + 16)
+	if !ok {
+		return t
+	}
+	header, ok := m.read(fields, 16)
+	if !ok {
+		return t
+	}
+	recordSize := uint64(m.order.Uint16(header[10:]))
+	numFields := uint64(m.order.Uint32(header[12:]))
+	if recordSize < 12 || numFields > 0xffff {
+		return t
+	}
+	t.Fields = int(numFields)
+	t.ReflectionSize = int64(16 + numFields*recordSize)
+	for i := uint64(0); i < numFields; i++ {
+		if name, ok := m.relative(fields + 16 + i*recordSize + 8); ok {
+			t.ReflectionSize += int64(len(m.cString(name)) + 1)
+		}
+	}
+	return t
+}
+
+// objcClassName reads the name of an Objective-C class from its class_ro_t
+func (m *image) objcClassName(class uint64) string {
+	if class == 0 {
+		return ""
+	}
+	// class_t: isa, superclass, cache, vtable, data (low bits are Swift flags)
+	data, ok := m.pointer(class + 32)
+	if !ok {
+		return ""
+	}
+	// class_ro_t: flags, instanceStart, instanceSize, reserved, ivarLayout, name
+	name, ok := m.pointer(data&^7 + 24)
+	if !ok {
+		return ""
+	}
+	return m.cString(name)
+}
@@ -0,0 +1,268 @@
+package macho
+
+import (
+	"bytes"
+	"debug/macho"
+	"encoding/binary"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testImageBase = 0x100000000
+
+// testSection is a section of a synthetic arm64 Mach-O image
+type testSection struct {
+	seg, name string
+	addr      uint64
+	offset    uint32
+	data      []byte
+}
+
+// testImage lays out sections at fixed addresses so that tests can write pointers
+// between them before the image is serialized
+type testImage struct {
+	sections []*testSection
+	cpu      macho.Cpu
+	platform uint32     // LC_BUILD_VERSION platform, none when 0
+	loads    [][]uint32 // Further load commands, written as is
+}
+
+func newTestImage(specs ...testSection) *testImage {
+	img := &testImage{cpu: macho.CpuArm64}
+	offset := uint32(4096)
+	for _, spec := range specs {
+		section := &testSection{
+			seg:    spec.seg,
+			name:   spec.name,
+			addr:   testImageBase + uint64(offset),
+			offset: offset,
+			data:   make([]byte, len(spec.data)),
+		}
+		copy(section.data, spec.data)
+		img.sections = append(img.sections, section)
+		offset += uint32(len(spec.data)+7) &^ 7
+	}
+	return img
+}
+
+func (img *testImage) sec(name string) *testSection {
+	for _, section := range img.sections {
+		if section.name == name {
+			return section
+		}
+	}
+	panic("unknown test section " + name)
+}
+
+// addr returns the address of an offset in a section
+func (img *testImage) addr(name string, offset int) uint64 {
+	return img.sec(name).addr + uint64(offset)
+}
+
+func (img *testImage) putUint32(name string, offset int, value uint32) {
+	binary.LittleEndian.PutUint32(img.sec(name).data[offset:], value)
+}
+
+func (img *testImage) putUint64(name string, offset int, value uint64) {
+	binary.LittleEndian.PutUint64(img.sec(name).data[offset:], value)
+}
+
+// putRelative writes a 32-bit relative pointer to target, with optional low bits
+func (img *testImage) putRelative(name string, offset int, target uint64, bits uint32) {
+	img.putUint32(name, offset, uint32(int32(int64(target)-int64(img.addr(name, offset))))|bits)
+}
+
+// file parses the serialized image
+func (img *testImage) file(t *testing.T) *macho.File {
+	t.Helper()
+	file, err := macho.NewFile(bytes.NewReader(img.bytes()))
+	require.NoError(t, err)
+	return file
+}
+
+// bytes serializes the image with one LC_SEGMENT_64 per segment; __TEXT starts at the
+// beginning of the file like in linked binaries
+func (img *testImage) bytes() []byte {
+	var segments []string
+	bySegment := make(map[string][]*testSection)
+	for _, section := range img.sections {
+		if bySegment[section.seg] == nil {
+			segments = append(segments, section.seg)
+		}
+		bySegment[section.seg] = append(bySegment[section.seg], section)
+	}
+
+	var cmds bytes.Buffer
+	for _, seg := range segments {
+		sections := bySegment[seg]
+		first, last := sections[0], sections[len(sections)-1]
+		addr, fileOff := first.addr, uint64(first.offset)
+		if seg == "__TEXT" {
+			addr, fileOff = testImageBase, 0
+		}
+		size := last.addr + uint64(len(last.data)) - addr
+
+		binary.Write(&cmds, binary.LittleEndian, []uint32{0x19, uint32(72 + 80*len(sections))})
+		cmds.Write(fixedName(seg))
+		binary.Write(&cmds, binary.LittleEndian, []uint64{addr, size, fileOff, size})
+		binary.Write(&cmds, binary.LittleEndian, []uint32{5, 5, uint32(len(sections)), 0})
+		for _, section := range sections {
+			cmds.Write(fixedName(section.name))
+			cmds.Write(fixedName(seg))
+			binary.Write(&cmds, binary.LittleEndian, []uint64{section.addr, uint64(len(section.data))})
+			binary.Write(&cmds, binary.LittleEndian, []uint32{section.offset, 3, 0, 0, 0, 0, 0, 0})
+		}
+	}
+
+	ncmds := len(segments)
+	if img.platform != 0 {
+		// LC_BUILD_VERSION: platform, minos, sdk, no tools
+		binary.Write(&cmds, binary.LittleEndian, []uint32{loadCmdBuildVersion, 24, img.platform, 0x100000, 0x110000, 0})
+		ncmds++
+	}
+	for _, load := range img.loads {
+		binary.Write(&cmds, binary.LittleEndian, load)
+		ncmds++
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, []uint32{
+		macho.Magic64, uint32(img.cpu), 0, uint32(macho.TypeExec),
+		uint32(ncmds), uint32(cmds.Len()), 0, 0,
+	})
+	buf.Write(cmds.Bytes())
+	for _, section := range img.sections {
+		buf.Write(make([]byte, int(section.offset)-buf.Len()))
+		buf.Write(section.data)
+	}
+	return buf.Bytes()
+}
+
+func fixedName(name string) []byte {
+	buf := make([]byte, 16)
+	copy(buf, name)
+	return buf
+}
+
+// swiftTestImage builds a binary with a Swift module MyKit declaring struct Point with
+// two fields, a nested class Point.Cache with one field, protocol Drawable and a
+// conformance of Point to it, next to a plain Objective-C class
+func swiftTestImage(t *testing.T) *macho.File {
+	img := newTestImage(
+		testSection{seg: "__TEXT", name: "__text", data: make([]byte, 64)},
+		testSection{seg: "__TEXT", name: "__const", data: make([]byte, 96)},
+		testSection{seg: "__TEXT", name: "__cstring", data: []byte("MyKit\x00Point\x00Cache\x00Drawable\x00")},
+		testSection{seg: "__TEXT", name: "__swift5_types", data: make([]byte, 8)},
+		testSection{seg: "__TEXT", name: "__swift5_protos", data: make([]byte, 4)},
+		testSection{seg: "__TEXT", name: "__swift5_proto", data: make([]byte, 8)},
+		testSection{seg: "__TEXT", name: "__swift5_fieldmd", data: make([]byte, 80)},
+		testSection{seg: "__TEXT", name: "__swift5_reflstr", data: []byte("x\x00y\x00storage\x00\x00\x00\x00\x00")},
+		testSection{seg: "__TEXT", name: "__objc_methname", data: []byte("init\x00drawRect:\x00layoutSubviews\x00\x00\x00")},
+		testSection{seg: "__TEXT", name: "__objc_classname", data: []byte("_TtCV5MyKit5Point5Cache\x00ABCLegacyView\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00")},
+		testSection{seg: "__DATA_CONST", name: "__objc_classlist", data: make([]byte, 16)},
+		testSection{seg: "__DATA_CONST", name: "__objc_catlist", data: make([]byte, 8)},
+		testSection{seg: "__DATA_CONST", name: "__objc_protolist", data: make([]byte, 8)},
+		testSection{seg: "__DATA", name: "__objc_data", data: make([]byte, 80)},
+		testSection{seg: "__DATA", name: "__objc_const", data: make([]byte, 96)},
+	)
+
+	// Context descriptors: flags, parent, name, access function, fields
+	img.putRelative("__const", 8, img.addr("__cstring", 0), 0) // module MyKit
+	img.putUint32("__const", 12, contextKindStruct)
+	img.putRelative("__const", 16, img.addr("__const", 0), 0)
+	img.putRelative("__const", 20, img.addr("__cstring", 6), 0)
+	img.putRelative("__const", 28, img.addr("__swift5_fieldmd", 0), 0)
+	img.putUint32("__const", 32, contextKindClass|0x80) // High flags are ignored
+	img.putRelative("__const", 36, img.addr("__const", 12), 0)
+	img.putRelative("__const", 40, img.addr("__cstring", 12), 0)
+	img.putRelative("__const", 48, img.addr("__swift5_fieldmd", 40), 0)
+	img.putUint32("__const", 52, 3) // protocol Drawable
+	img.putRelative("__const", 56, img.addr("__const", 0), 0)
+	img.putRelative("__const", 60, img.addr("__cstring", 18), 0)
+
+	// Conformances: protocol, type reference, witness table, flags
+	img.putRelative("__const", 64, img.addr("__const", 52), 0)
+	img.putRelative("__const", 68, img.addr("__const", 12), 0)
+	img.putRelative("__const", 80, img.addr("__const", 52), 0)
+	img.putRelative("__const", 84, img.addr("__objc_classname", 24), 0)
+	img.putUint32("__const", 92, 2<<3) // Objective-C class name reference
+
+	img.putRelative("__swift5_types", 0, img.addr("__const", 12), 0)
+	img.putRelative("__swift5_types", 4, img.addr("__const", 32), 0)
+	img.putRelative("__swift5_protos", 0, img.addr("__const", 52), 0)
+	img.putRelative("__swift5_proto", 0, img.addr("__const", 64), 0)
+	img.putRelative("__swift5_proto", 4, img.addr("__const", 80), 0)
+
+	// Field descriptors with 12-byte records: flags, mangled type name, field name
+	img.putUint32("__swift5_fieldmd", 8, 12<<16)
+	img.putUint32("__swift5_fieldmd", 12, 2)
+	img.putRelative("__swift5_fieldmd", 24, img.addr("__swift5_reflstr", 0), 0)
+	img.putRelative("__swift5_fieldmd", 36, img.addr("__swift5_reflstr", 2), 0)
+	img.putUint32("__swift5_fieldmd", 48, 12<<16)
+	img.putUint32("__swift5_fieldmd", 52, 1)
+	img.putRelative("__swift5_fieldmd", 64, img.addr("__swift5_reflstr", 4), 0)
+
+	// Objective-C classes: a plain pointer and a chained fixup offset with a next field
+	img.putUint64("__objc_classlist", 0, img.addr("__objc_data", 0))
+	img.putUint64("__objc_classlist", 8, 3<<51|(img.addr("__objc_data", 40)-testImageBase))
+	img.putUint64("__objc_data", 32, img.addr("__objc_const", 0)|1) // Swift class flag
+	img.putUint64("__objc_data", 72, img.addr("__objc_const", 48))
+	img.putUint64("__objc_const", 24, img.addr("__objc_classname", 0))
+	img.putUint64("__objc_const", 72, img.addr("__objc_classname", 24))
+
+	return img.file(t)
+}
+
+func TestParseRuntimeMetadata(t *testing.T) {
+	metadata := ParseRuntimeMetadata(swiftTestImage(t), "TestBinary")
+	require.NotNil(t, metadata)
+
+	assert.Equal(t, 2, metadata.SwiftTypes)
+	assert.Equal(t, 1, metadata.SwiftProtocols)
+	assert.Equal(t, 2, metadata.SwiftConformances)
+	assert.Equal(t, 2, metadata.ObjCClasses)
+	assert.Equal(t, 1, metadata.ObjCCategories)
+	assert.Equal(t, 1, metadata.ObjCProtocols)
+	assert.Equal(t, 3, metadata.ObjCSelectors)
+	assert.Equal(t, int64(8+4+8+80+16), metadata.SwiftMetadataSize)
+	assert.Equal(t, int64(80+16), metadata.ReflectionSize)
+	assert.Equal(t, int64(32+48+16+8+8+80+96), metadata.ObjCMetadataSize)
+
+	assert.Equal(t, []types.RuntimeModule{
+		{Name: "MyKit", SwiftTypes: 2, SwiftProtocols: 1, SwiftConformances: 1, ObjCClasses: 1, ReflectionSize: 44 + 36},
+		{Name: "TestBinary", ObjCClasses: 1},
+	}, metadata.Modules)
+
+	assert.Equal(t, []types.RuntimeType{
+		{Name: "Point", Module: "MyKit", Kind: "struct", Fields: 2, ReflectionSize: 16 + 2*12 + 2 + 2},
+		{Name: "Point.Cache", Module: "MyKit", Kind: "class", Fields: 1, ReflectionSize: 16 + 12 + 8},
+	}, metadata.LargestTypes)
+}
+
+func TestParseRuntimeMetadata_NoMetadata(t *testing.T) {
+	img := newTestImage(testSection{seg: "__TEXT", name: "__text", data: make([]byte, 64)})
+	assert.Nil(t, ParseRuntimeMetadata(img.file(t), "Plain"))
+}
+
+func TestSwiftClassModule(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"_TtC5MyKit5Cache", "MyKit"},
+		{"_TtCV5MyKit5Point5Cache", "MyKit"},
+		{"_TtCs12_SwiftObject", "Swift"},
+		{"ABCLegacyView", ""},
+		{"_TtC", ""},
+		{"_TtC99Short", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, swiftClassModule(tt.name))
+		})
+	}
+}
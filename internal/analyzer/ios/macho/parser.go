@@ -6,24 +6,106 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
 )
 
-// ParseMachO parses a Mach-O binary and extracts metadata
+// ParseMachO parses a Mach-O binary and extracts metadata. Every slice of a fat
+// binary is parsed; the top-level fields describe the first device slice.
 func ParseMachO(path string) (*types.BinaryInfo, error) {
+	if fatFile, err := macho.OpenFat(path); err == nil {
+		defer fatFile.Close()
+		return parseFatMachO(fatFile, path)
+	}
+
 	file, err := macho.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open Mach-O file: %w", err)
 	}
 	defer file.Close()
 
+	info := parseSlice(file)
+
+	// Estimate debug symbol size if present
+	if info.HasDebugSymbols {
+		info.DebugSymbolsSize = estimateSymbolTableSize(file, path)
+	}
+
+	// Count Swift and Objective-C runtime metadata and string literals, unless they are encrypted
+	if !info.IsEncrypted {
+		info.RuntimeMetadata = ParseRuntimeMetadata(file, filepath.Base(path))
+		info.Strings = ParseStrings(file)
+	}
+
+	return info, nil
+}
+
+// parseFatMachO parses every slice of a fat binary
+func parseFatMachO(fatFile *macho.FatFile, path string) (*types.BinaryInfo, error) {
+	if len(fatFile.Arches) == 0 {
+		return nil, fmt.Errorf("fat binary has no slices")
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	primary := primarySlice(fatFile)
+	info := parseSlice(fatFile.Arches[primary].File)
+	info.Architectures = make([]string, 0, len(fatFile.Arches))
+	info.BitcodeSize = 0
+	info.CodeSignatureSize = 0
+
+	simulator := make([]bool, len(fatFile.Arches))
+	for i, arch := range fatFile.Arches {
+		slice := parseSlice(arch.File)
+		simulator[i] = IsSimulatorSlice(slice.Architecture, slice.Platform)
+
+		info.Architectures = append(info.Architectures, slice.Architecture)
+		info.HasDebugSymbols = info.HasDebugSymbols || slice.HasDebugSymbols
+		info.IsEncrypted = info.IsEncrypted || slice.IsEncrypted
+		// Simulator slices are counted whole in SimulatorSliceSavings
+		if !simulator[i] {
+			info.BitcodeSize += slice.BitcodeSize
+		}
+		info.CodeSignatureSize += slice.CodeSignatureSize
+		info.Slices = append(info.Slices, types.BinarySlice{
+			Architecture:    slice.Architecture,
+			Platform:        slice.Platform,
+			IsSimulator:     simulator[i],
+			Size:            int64(arch.Size),
+			CodeSize:        slice.CodeSize,
+			DataSize:        slice.DataSize,
+			LinkedLibraries: slice.LinkedLibraries,
+			HasDebugSymbols: slice.HasDebugSymbols,
+		})
+	}
+	info.SimulatorSliceSavings = thinningSavings(stat.Size(), fatFile.Arches, simulator)
+
+	// strip handles all slices of a fat binary at once
+	if info.HasDebugSymbols {
+		info.DebugSymbolsSize = estimateSymbolTableSize(fatFile.Arches[primary].File, path)
+	}
+
+	if !info.IsEncrypted {
+		info.RuntimeMetadata = ParseRuntimeMetadata(fatFile.Arches[primary].File, filepath.Base(path))
+		info.Strings = ParseStrings(fatFile.Arches[primary].File)
+	}
+
+	return info, nil
+}
+
+// parseSlice extracts the metadata of a single architecture
+func parseSlice(file *macho.File) *types.BinaryInfo {
+	architecture := GetArchitectureName(file.Cpu, file.SubCpu)
 	info := &types.BinaryInfo{
-		Architecture:    GetCPUTypeName(file.Cpu),
-		Architectures:   []string{GetCPUTypeName(file.Cpu)},
+		Architecture:    architecture,
+		Architectures:   []string{architecture},
 		Type:            getBinaryType(file.Type),
 		LinkedLibraries: make([]string, 0),
 		RPaths:          make([]string, 0),
+		Platform:        GetPlatform(file),
 	}
 
 	// Extract segment sizes
@@ -44,12 +126,96 @@ func ParseMachO(path string) (*types.BinaryInfo, error) {
 	// Check for debug symbols
 	info.HasDebugSymbols = hasDebugSymbols(file)
 
-	// Estimate debug symbol size if present
-	if info.HasDebugSymbols {
-		info.DebugSymbolsSize = estimateSymbolTableSize(file, path)
+	info.BitcodeSize = getBitcodeSize(file)
+	info.IsEncrypted = isEncrypted(file)
+	_, signatureSize := getCodeSignature(file)
+	info.CodeSignatureSize = int64(signatureSize)
+
+	return info
+}
+
+// Load commands for encryption and code signature
+const (
+	loadCmdCodeSignature    = 0x1d
+	loadCmdEncryptionInfo   = 0x21
+	loadCmdEncryptionInfo64 = 0x2c
+)
+
+// getBitcodeSize returns the size of the __LLVM segment that holds embedded bitcode.
+// Bitcode markers (-fembed-bitcode-marker) have no content and count as no bitcode.
+func getBitcodeSize(file *macho.File) int64 {
+	seg := file.Segment("__LLVM")
+	if seg == nil {
+		return 0
 	}
 
-	return info, nil
+	var content uint64
+	for _, section := range file.Sections {
+		if section.Seg == "__LLVM" {
+			content += section.Size
+		}
+	}
+	if content <= 1 {
+		return 0
+	}
+	return int64(seg.Filesz)
+}
+
+// isEncrypted reports whether LC_ENCRYPTION_INFO(_64) marks the binary as encrypted
+func isEncrypted(file *macho.File) bool {
+	for _, load := range file.Loads {
+		raw := load.Raw()
+		cmd := file.ByteOrder.Uint32(raw)
+		// cmd, cmdsize, cryptoff, cryptsize, cryptid
+		if (cmd == loadCmdEncryptionInfo || cmd == loadCmdEncryptionInfo64) && len(raw) >= 20 {
+			return file.ByteOrder.Uint32(raw[16:]) != 0
+		}
+	}
+	return false
+}
+
+// primarySlice returns the index of the first device slice of a fat binary, or 0
+// when all slices are simulator slices
+func primarySlice(fatFile *macho.FatFile) int {
+	for i, arch := range fatFile.Arches {
+		if !IsSimulatorSlice(GetArchitectureName(arch.Cpu, arch.SubCpu), GetPlatform(arch.File)) {
+			return i
+		}
+	}
+	return 0
+}
+
+// thinningSavings returns the bytes that lipo saves by removing the marked slices of a
+// fat binary. A single remaining slice is written as a thin binary; several remaining
+// slices keep a fat header and their alignment. Returns 0 when nothing or everything
+// would be removed.
+func thinningSavings(fileSize int64, arches []macho.FatArch, remove []bool) int64 {
+	var kept []macho.FatArch
+	for i, arch := range arches {
+		if !remove[i] {
+			kept = append(kept, arch)
+		}
+	}
+	if len(kept) == 0 || len(kept) == len(arches) {
+		return 0
+	}
+
+	thinnedSize := int64(kept[0].Size)
+	if len(kept) > 1 {
+		// fat_header followed by one fat_arch per slice
+		offset := int64(8 + 20*len(kept))
+		for _, arch := range kept {
+			align := int64(1) << arch.Align
+			offset = (offset + align - 1) / align * align
+			offset += int64(arch.Size)
+		}
+		thinnedSize = offset
+	}
+
+	if savings := fileSize - thinnedSize; savings > 0 {
+		return savings
+	}
+	return 0
 }
 
 // GetArchitectures returns all architectures in binary (handles fat binaries)
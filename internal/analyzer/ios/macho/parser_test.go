@@ -1,10 +1,14 @@
 package macho
 
 import (
+	"bytes"
+	"debug/macho"
+	"encoding/binary"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -167,3 +171,177 @@ func TestGetSegmentSizes(t *testing.T) {
 	// Code segment should typically be larger than data segment for executables
 	t.Logf("Code size: %d bytes, Data size: %d bytes", codeSize, dataSize)
 }
+
+// writeFatBinary writes a fat binary with the given slices, 4 KB aligned, and returns
+// its path and the slice sizes
+func writeFatBinary(t *testing.T, slices ...*testImage) (string, []int64) {
+	t.Helper()
+
+	const align = 12
+	header := make([]byte, 8+20*len(slices))
+	binary.BigEndian.PutUint32(header, MagicFat32)
+	binary.BigEndian.PutUint32(header[4:], uint32(len(slices)))
+
+	var body bytes.Buffer
+	var sizes []int64
+	offset := 1 << align
+	for i, slice := range slices {
+		data := slice.bytes()
+		entry := header[8+20*i:]
+		binary.BigEndian.PutUint32(entry, uint32(slice.cpu))
+		binary.BigEndian.PutUint32(entry[4:], 0)
+		binary.BigEndian.PutUint32(entry[8:], uint32(offset))
+		binary.BigEndian.PutUint32(entry[12:], uint32(len(data)))
+		binary.BigEndian.PutUint32(entry[16:], align)
+
+		body.Write(make([]byte, offset-len(header)-body.Len()))
+		body.Write(data)
+		sizes = append(sizes, int64(len(data)))
+		offset = (len(header) + body.Len() + 1<<align - 1) &^ (1<<align - 1)
+	}
+
+	path := filepath.Join(t.TempDir(), "Fat")
+	require.NoError(t, os.WriteFile(path, append(header, body.Bytes()...), 0o644))
+	return path, sizes
+}
+
+// fatTestSlices returns an iOS simulator slice followed by an iOS device slice
+func fatTestSlices() []*testImage {
+	simulator := newTestImage(testSection{seg: "__TEXT", name: "__text", data: make([]byte, 3000)})
+	simulator.cpu = macho.CpuAmd64
+	simulator.platform = 7 // iOS simulator
+	device := newTestImage(testSection{seg: "__TEXT", name: "__text", data: make([]byte, 1000)})
+	device.platform = 2 // iOS
+	return []*testImage{simulator, device}
+}
+
+func TestParseMachO_FatBinary(t *testing.T) {
+	path, sizes := writeFatBinary(t, fatTestSlices()...)
+	stat, err := os.Stat(path)
+	require.NoError(t, err)
+
+	info, err := ParseMachO(path)
+	require.NoError(t, err)
+
+	// The top-level fields describe the device slice
+	assert.Equal(t, "arm64", info.Architecture)
+	assert.Equal(t, "ios", info.Platform)
+	assert.Equal(t, []string{"x86_64", "arm64"}, info.Architectures)
+	require.Len(t, info.Slices, 2)
+	assert.Equal(t, "x86_64", info.Slices[0].Architecture)
+	assert.Equal(t, "ios-simulator", info.Slices[0].Platform)
+	assert.True(t, info.Slices[0].IsSimulator)
+	assert.Equal(t, sizes[0], info.Slices[0].Size)
+	assert.False(t, info.Slices[1].IsSimulator)
+	assert.Equal(t, sizes[1], info.Slices[1].Size)
+
+	// Thinning to the device slice leaves a thin binary of exactly that slice
+	assert.Equal(t, stat.Size()-sizes[1], info.SimulatorSliceSavings)
+}
+
+func TestParseSegments_FatBinary(t *testing.T) {
+	path, sizes := writeFatBinary(t, fatTestSlices()...)
+	stat, err := os.Stat(path)
+	require.NoError(t, err)
+
+	segments, err := ParseSegments(path)
+	require.NoError(t, err)
+	assert.True(t, segments.IsFat)
+	assert.Equal(t, "arm64", segments.Architecture)
+	require.Len(t, segments.Slices, 2)
+	assert.True(t, segments.Slices[0].IsSimulator)
+
+	children := ExpandSegmentsAsChildren(segments, "Frameworks/Fat.framework/Fat")
+	require.Len(t, children, 3)
+
+	var total int64
+	bySlice := make(map[string]*types.FileNode)
+	for _, child := range children {
+		total += child.Size
+		bySlice[child.Name] = child
+		assert.True(t, child.IsVirtual)
+		assert.Equal(t, "Frameworks/Fat.framework/Fat", child.SourceFile)
+	}
+	assert.Equal(t, stat.Size(), total, "slices and fat header should add up to the file size")
+	require.Contains(t, bySlice, "fat_header")
+	require.Contains(t, bySlice, "x86_64")
+	require.Contains(t, bySlice, "arm64")
+	assert.Equal(t, sizes[0], bySlice["x86_64"].Size)
+	assert.Equal(t, sizes[1], bySlice["arm64"].Size)
+	require.NotEmpty(t, bySlice["arm64"].Children)
+	assert.Equal(t, "Frameworks/Fat.framework/Fat/arm64/__TEXT", bySlice["arm64"].Children[0].Path)
+}
+
+func TestThinningSavings(t *testing.T) {
+	arches := []macho.FatArch{
+		{FatArchHeader: macho.FatArchHeader{Offset: 16384, Size: 10000, Align: 14}},
+		{FatArchHeader: macho.FatArchHeader{Offset: 32768, Size: 20000, Align: 14}},
+		{FatArchHeader: macho.FatArchHeader{Offset: 65536, Size: 5000, Align: 14}},
+	}
+	fileSize := int64(65536 + 5000)
+
+	// Removing the middle slice keeps a fat header: 16384 + 10000 -> 32768 + 5000
+	assert.Equal(t, fileSize-(32768+5000), thinningSavings(fileSize, arches, []bool{false, true, false}))
+	// A single remaining slice becomes a thin binary
+	assert.Equal(t, fileSize-20000, thinningSavings(fileSize, arches, []bool{true, false, true}))
+	assert.Equal(t, int64(0), thinningSavings(fileSize, arches, []bool{false, false, false}))
+	assert.Equal(t, int64(0), thinningSavings(fileSize, arches, []bool{true, true, true}))
+}
+
+func TestIsSimulatorSlice(t *testing.T) {
+	tests := []struct {
+		architecture string
+		platform     string
+		want         bool
+	}{
+		{"arm64", "ios", false},
+		{"arm64", "ios-simulator", true},
+		{"x86_64", "ios-simulator", true},
+		{"x86_64", "macos", false},
+		{"x86_64", "", true},
+		{"arm64", "", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsSimulatorSlice(tt.architecture, tt.platform), "%s %s", tt.architecture, tt.platform)
+	}
+}
+
+// payloadTestImage returns an image with bitcode, a code signature and optional encryption
+func payloadTestImage(bitcode int, cryptid uint32) *testImage {
+	img := newTestImage(
+		testSection{seg: "__TEXT", name: "__text", data: make([]byte, 1000)},
+		testSection{seg: "__LLVM", name: "__bundle", data: make([]byte, bitcode)},
+	)
+	img.loads = [][]uint32{
+		{loadCmdEncryptionInfo64, 24, 0x4000, 0x1000, cryptid, 0},
+		{loadCmdCodeSignature, 16, 0x8000, 2048},
+	}
+	return img
+}
+
+func TestParseSlice_BitcodeEncryptionSignature(t *testing.T) {
+	info := parseSlice(payloadTestImage(4096, 1).file(t))
+	assert.Equal(t, int64(4096), info.BitcodeSize)
+	assert.True(t, info.IsEncrypted)
+	assert.Equal(t, int64(2048), info.CodeSignatureSize)
+
+	// A bitcode marker (-fembed-bitcode-marker) is not bitcode, and cryptid 0 is not encrypted
+	info = parseSlice(payloadTestImage(1, 0).file(t))
+	assert.Zero(t, info.BitcodeSize)
+	assert.False(t, info.IsEncrypted)
+}
+
+func TestParseMachO_FatBinaryPayload(t *testing.T) {
+	device := payloadTestImage(4096, 1)
+	simulator := payloadTestImage(1024, 0)
+	simulator.cpu = macho.CpuAmd64
+	path, _ := writeFatBinary(t, simulator, device)
+
+	info, err := ParseMachO(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4096), info.BitcodeSize, "bitcode of the simulator slice is part of the thinning savings")
+	assert.Equal(t, int64(2*2048), info.CodeSignatureSize)
+	assert.True(t, info.IsEncrypted)
+	assert.Nil(t, info.RuntimeMetadata, "metadata of encrypted binaries is not parsed")
+}
@@ -2,6 +2,7 @@ package macho
 
 import (
 	"debug/macho"
+	"os"
 	"path/filepath"
 	"sort"
 
@@ -23,20 +24,29 @@ type SegmentInfo struct {
 }
 
 // MachOSegments contains parsed segment/section information from a Mach-O binary.
+// For fat binaries, the top-level fields describe the first device slice and Slices
+// holds every slice.
 type MachOSegments struct {
-	Path         string        `json:"path"`
-	Architecture string        `json:"architecture"`
-	IsFat        bool          `json:"is_fat"`
-	Segments     []SegmentInfo `json:"segments"`
+	Path         string          `json:"path"`
+	Architecture string          `json:"architecture"`
+	Platform     string          `json:"platform,omitempty"`
+	IsSimulator  bool            `json:"is_simulator,omitempty"`
+	IsFat        bool            `json:"is_fat"`
+	Size         int64           `json:"size"` // Bytes in the file: the file size, or the slice size for slices
+	Segments     []SegmentInfo   `json:"segments"`
+	Slices       []MachOSegments `json:"slices,omitempty"`
 }
 
 // ParseSegments extracts segment and section information from a Mach-O binary.
-// For fat binaries, it parses the first architecture only.
+// For fat binaries, it parses every slice.
 func ParseSegments(path string) (*MachOSegments, error) {
 	result := &MachOSegments{
 		Path:     path,
 		Segments: make([]SegmentInfo, 0),
 	}
+	if stat, err := os.Stat(path); err == nil {
+		result.Size = stat.Size()
+	}
 
 	// Try to open as fat binary first
 	fatFile, err := macho.OpenFat(path)
@@ -44,11 +54,24 @@ func ParseSegments(path string) (*MachOSegments, error) {
 		defer fatFile.Close()
 		result.IsFat = true
 
-		if len(fatFile.Arches) > 0 {
-			// Use first architecture
-			arch := fatFile.Arches[0]
-			result.Architecture = GetCPUTypeName(arch.Cpu)
-			result.Segments = extractSegments(arch.File)
+		for _, arch := range fatFile.Arches {
+			architecture := GetArchitectureName(arch.Cpu, arch.SubCpu)
+			platform := GetPlatform(arch.File)
+			result.Slices = append(result.Slices, MachOSegments{
+				Path:         path,
+				Architecture: architecture,
+				Platform:     platform,
+				IsSimulator:  IsSimulatorSlice(architecture, platform),
+				Size:         int64(arch.Size),
+				Segments:     extractSegments(arch.File),
+			})
+		}
+		if len(result.Slices) > 0 {
+			primary := result.Slices[primarySlice(fatFile)]
+			result.Architecture = primary.Architecture
+			result.Platform = primary.Platform
+			result.IsSimulator = primary.IsSimulator
+			result.Segments = primary.Segments
 		}
 		return result, nil
 	}
@@ -61,7 +84,9 @@ func ParseSegments(path string) (*MachOSegments, error) {
 	defer file.Close()
 
 	result.IsFat = false
-	result.Architecture = GetCPUTypeName(file.Cpu)
+	result.Architecture = GetArchitectureName(file.Cpu, file.SubCpu)
+	result.Platform = GetPlatform(file)
+	result.IsSimulator = IsSimulatorSlice(result.Architecture, result.Platform)
 	result.Segments = extractSegments(file)
 
 	return result, nil
@@ -124,16 +149,66 @@ func extractSegments(file *macho.File) []SegmentInfo {
 }
 
 // ExpandSegmentsAsChildren creates virtual FileNode children representing
-// segments and their sections for a Mach-O binary.
+// segments and their sections for a Mach-O binary. Fat binaries get one child per
+// architecture slice, with the segments of that slice below it.
 func ExpandSegmentsAsChildren(segments *MachOSegments, binaryRelativePath string) []*types.FileNode {
-	if segments == nil || len(segments.Segments) == 0 {
+	if segments == nil {
 		return nil
 	}
+	if len(segments.Slices) > 1 {
+		return expandSlices(segments, binaryRelativePath)
+	}
+	if len(segments.Segments) == 0 {
+		return nil
+	}
+
+	return expandSegments(segments.Segments, binaryRelativePath, binaryRelativePath)
+}
+
+// expandSlices creates one virtual child per slice of a fat binary. The fat header and
+// the alignment padding between slices are shown as fat_header.
+func expandSlices(segments *MachOSegments, binaryRelativePath string) []*types.FileNode {
+	children := make([]*types.FileNode, 0, len(segments.Slices)+1)
+
+	var slicesSize int64
+	for _, slice := range segments.Slices {
+		slicePath := filepath.Join(binaryRelativePath, slice.Architecture)
+		children = append(children, &types.FileNode{
+			Path:       slicePath,
+			Name:       slice.Architecture,
+			Size:       slice.Size,
+			IsDir:      true,
+			IsVirtual:  true,
+			SourceFile: binaryRelativePath,
+			Children:   expandSegments(slice.Segments, slicePath, binaryRelativePath),
+		})
+		slicesSize += slice.Size
+	}
+
+	if segments.Size > slicesSize {
+		children = append(children, &types.FileNode{
+			Path:       filepath.Join(binaryRelativePath, "fat_header"),
+			Name:       "fat_header",
+			Size:       segments.Size - slicesSize,
+			IsVirtual:  true,
+			SourceFile: binaryRelativePath,
+		})
+	}
+
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].Size > children[j].Size
+	})
+
+	return children
+}
 
-	children := make([]*types.FileNode, 0, len(segments.Segments))
+// expandSegments creates virtual FileNode children for segments and their sections
+// below parentPath
+func expandSegments(segments []SegmentInfo, parentPath, binaryRelativePath string) []*types.FileNode {
+	children := make([]*types.FileNode, 0, len(segments))
 
-	for _, seg := range segments.Segments {
-		segPath := filepath.Join(binaryRelativePath, seg.Name)
+	for _, seg := range segments {
+		segPath := filepath.Join(parentPath, seg.Name)
 
 		segNode := &types.FileNode{
 			Path:       segPath,
@@ -0,0 +1,109 @@
+package macho
+
+import (
+	"debug/macho"
+	"encoding/binary"
+	"fmt"
+	"os"
+
+	"howett.net/plist"
+)
+
+// Code signature blobs (big-endian, see xnu's cs_blobs.h)
+const (
+	csMagicEmbeddedSignature    = 0xfade0cc0
+	csMagicEmbeddedEntitlements = 0xfade7171
+	csSlotEntitlements          = 5
+)
+
+// getCodeSignature returns the file offset and size of the LC_CODE_SIGNATURE data
+func getCodeSignature(file *macho.File) (offset, size uint32) {
+	for _, load := range file.Loads {
+		raw := load.Raw()
+		// cmd, cmdsize, dataoff, datasize
+		if file.ByteOrder.Uint32(raw) == loadCmdCodeSignature && len(raw) >= 16 {
+			return file.ByteOrder.Uint32(raw[8:]), file.ByteOrder.Uint32(raw[12:])
+		}
+	}
+	return 0, 0
+}
+
+// ParseEntitlements extracts the entitlements from the code signature of a binary.
+// Fat binaries are read from their first device slice. Returns nil for unsigned
+// binaries and signatures without entitlements.
+func ParseEntitlements(path string) (map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	var file *macho.File
+	var base, limit int64
+	if fatFile, err := macho.NewFatFile(f); err == nil {
+		arch := fatFile.Arches[primarySlice(fatFile)]
+		file = arch.File
+		base = int64(arch.Offset)
+		limit = int64(arch.Size)
+	} else {
+		file, err = macho.NewFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Mach-O: %w", err)
+		}
+		stat, err := f.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat file: %w", err)
+		}
+		limit = stat.Size()
+	}
+
+	offset, size := getCodeSignature(file)
+	if size == 0 {
+		return nil, nil
+	}
+	// The load command is not trusted to size the allocation
+	if int64(offset)+int64(size) > limit {
+		return nil, fmt.Errorf("code signature runs past the end of the binary")
+	}
+
+	signature := make([]byte, size)
+	if _, err := f.ReadAt(signature, base+int64(offset)); err != nil {
+		return nil, fmt.Errorf("failed to read code signature: %w", err)
+	}
+	return parseEntitlementsBlob(signature)
+}
+
+// parseEntitlementsBlob finds the XML entitlements blob in an embedded signature
+// super blob: magic, length, count, then count (type, offset) index entries
+func parseEntitlementsBlob(signature []byte) (map[string]interface{}, error) {
+	if len(signature) < 12 || binary.BigEndian.Uint32(signature) != csMagicEmbeddedSignature {
+		return nil, fmt.Errorf("invalid code signature")
+	}
+
+	count := binary.BigEndian.Uint32(signature[8:])
+	for i := uint32(0); i < count; i++ {
+		entry := 12 + int(i)*8
+		if entry+8 > len(signature) {
+			break
+		}
+		if binary.BigEndian.Uint32(signature[entry:]) != csSlotEntitlements {
+			continue
+		}
+
+		offset := int(binary.BigEndian.Uint32(signature[entry+4:]))
+		if offset+8 > len(signature) || binary.BigEndian.Uint32(signature[offset:]) != csMagicEmbeddedEntitlements {
+			return nil, fmt.Errorf("invalid entitlements blob")
+		}
+		length := int(binary.BigEndian.Uint32(signature[offset+4:]))
+		if length < 8 || offset+length > len(signature) {
+			return nil, fmt.Errorf("invalid entitlements blob")
+		}
+
+		var entitlements map[string]interface{}
+		if _, err := plist.Unmarshal(signature[offset+8:offset+length], &entitlements); err != nil {
+			return nil, fmt.Errorf("failed to parse entitlements: %w", err)
+		}
+		return entitlements, nil
+	}
+	return nil, nil
+}
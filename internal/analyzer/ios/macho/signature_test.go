@@ -0,0 +1,108 @@
+package macho
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEntitlements = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>application-identifier</key>
+	<string>ABCDE12345.com.example.app</string>
+	<key>get-task-allow</key>
+	<false/>
+</dict>
+</plist>`
+
+// testSignature builds an embedded signature super blob with a code directory
+// placeholder and an entitlements blob
+func testSignature(entitlements string) []byte {
+	const headerSize = 12 + 2*8
+	codeDirectory := []byte{0xfa, 0xde, 0x0c, 0x02, 0, 0, 0, 8}
+
+	blob := make([]byte, 8+len(entitlements))
+	binary.BigEndian.PutUint32(blob, csMagicEmbeddedEntitlements)
+	binary.BigEndian.PutUint32(blob[4:], uint32(len(blob)))
+	copy(blob[8:], entitlements)
+
+	signature := make([]byte, headerSize)
+	binary.BigEndian.PutUint32(signature, csMagicEmbeddedSignature)
+	binary.BigEndian.PutUint32(signature[4:], uint32(headerSize+len(codeDirectory)+len(blob)))
+	binary.BigEndian.PutUint32(signature[8:], 2)
+	binary.BigEndian.PutUint32(signature[12:], 0) // CSSLOT_CODEDIRECTORY
+	binary.BigEndian.PutUint32(signature[16:], headerSize)
+	binary.BigEndian.PutUint32(signature[20:], csSlotEntitlements)
+	binary.BigEndian.PutUint32(signature[24:], uint32(headerSize+len(codeDirectory)))
+	signature = append(signature, codeDirectory...)
+	return append(signature, blob...)
+}
+
+func TestParseEntitlements(t *testing.T) {
+	signature := testSignature(testEntitlements)
+	img := newTestImage(
+		testSection{seg: "__TEXT", name: "__text", data: make([]byte, 64)},
+		testSection{seg: "__LINKEDIT", name: "__signature", data: signature},
+	)
+	img.loads = [][]uint32{{loadCmdCodeSignature, 16, img.sec("__signature").offset, uint32(len(signature))}}
+
+	path := filepath.Join(t.TempDir(), "App")
+	require.NoError(t, os.WriteFile(path, img.bytes(), 0o644))
+
+	entitlements, err := ParseEntitlements(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"application-identifier": "ABCDE12345.com.example.app",
+		"get-task-allow":         false,
+	}, entitlements)
+
+	// Fat binaries are read from the first device slice, not the unsigned simulator slice
+	fatPath, _ := writeFatBinary(t, fatTestSlices()[0], img)
+	entitlements, err = ParseEntitlements(fatPath)
+	require.NoError(t, err)
+	assert.Equal(t, "ABCDE12345.com.example.app", entitlements["application-identifier"])
+}
+
+func TestParseEntitlements_SignaturePastEnd(t *testing.T) {
+	img := newTestImage(testSection{seg: "__TEXT", name: "__text", data: make([]byte, 64)})
+	img.loads = [][]uint32{{loadCmdCodeSignature, 16, 0x1000, 0xffffffff}}
+	path := filepath.Join(t.TempDir(), "App")
+	require.NoError(t, os.WriteFile(path, img.bytes(), 0o644))
+
+	_, err := ParseEntitlements(path)
+	assert.ErrorContains(t, err, "past the end")
+}
+
+func TestParseEntitlements_Unsigned(t *testing.T) {
+	img := newTestImage(testSection{seg: "__TEXT", name: "__text", data: make([]byte, 64)})
+	path := filepath.Join(t.TempDir(), "App")
+	require.NoError(t, os.WriteFile(path, img.bytes(), 0o644))
+
+	entitlements, err := ParseEntitlements(path)
+	require.NoError(t, err)
+	assert.Nil(t, entitlements)
+}
+
+func TestParseEntitlementsBlob_Invalid(t *testing.T) {
+	_, err := parseEntitlementsBlob([]byte("not a signature"))
+	assert.Error(t, err)
+
+	// Entitlements blob that runs past the end of the signature
+	signature := testSignature(testEntitlements)
+	binary.BigEndian.PutUint32(signature[len(signature)-len(testEntitlements)-4:], 1<<20)
+	_, err = parseEntitlementsBlob(signature)
+	assert.Error(t, err)
+
+	// Signatures without entitlements, indexing only the code directory
+	signature = testSignature(testEntitlements)
+	binary.BigEndian.PutUint32(signature[8:], 1)
+	entitlements, err := parseEntitlementsBlob(signature)
+	assert.NoError(t, err)
+	assert.Nil(t, entitlements)
+}
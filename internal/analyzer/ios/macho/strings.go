@@ -0,0 +1,214 @@
+package macho
+
+import (
+	"bytes"
+	"debug/macho"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// stringSections are the sections of string literals, by section name
+var stringSections = []string{"__cstring", "__oslogstring", "__objc_methname", "__swift5_reflstr"}
+
+// Heuristic string categories, checked in this order
+const (
+	StringCategoryBuildPath = "build_path"
+	StringCategoryURL       = "url"
+	StringCategorySQL       = "sql"
+	StringCategoryJSON      = "json"
+	StringCategoryLogFormat = "log_format"
+)
+
+// maxLargestStrings is the number of literals listed in StringLiterals.Largest
+const maxLargestStrings = 10
+
+// maxBuildPaths is the number of build machine paths listed in StringLiterals.BuildPaths
+const maxBuildPaths = 10
+
+// maxStringValue is the number of characters of a literal kept in a report
+const maxStringValue = 100
+
+// buildPathPrefixes are directories that only exist on the machine that built a binary
+var buildPathPrefixes = []string{
+	"/Users/",
+	"/Volumes/",
+	"/home/",
+	"/private/var/folders/",
+	"/var/folders/",
+	"/tmp/",
+	"/builds/",
+	"/bitrise/",
+	"/Applications/Xcode",
+}
+
+var (
+	urlPattern       = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s]+`)
+	sqlPattern       = regexp.MustCompile(`(?i)^\s*(SELECT|INSERT|UPDATE|DELETE|CREATE|DROP|ALTER|PRAGMA|REPLACE)\s`)
+	formatPattern    = regexp.MustCompile(`%(\{[a-z, ]+\})?(\d+\$)?[-+ #0]*\d*(\.\d+)?(hh|h|ll|l|q|z|t|j|L)?[@dDiuUxXoOfFeEgGcCsSp]`)
+	formatTextFilter = regexp.MustCompile(`[a-zA-Z]{2,}`)
+)
+
+// ParseStrings sums the string literals of a binary by section and heuristic category,
+// and lists the largest ones and the paths of the build machine. Returns nil when the
+// binary has no string sections.
+func ParseStrings(file *macho.File) *types.StringLiterals {
+	result := &types.StringLiterals{}
+	categories := make(map[string]*types.StringCategory)
+	buildPaths := make(map[string]bool)
+
+	for _, name := range stringSections {
+		strs := sectionStrings(file, name)
+		if strs == nil {
+			continue
+		}
+		section := types.StringSection{Name: name}
+		for _, s := range strs {
+			size := int64(len(s) + 1)
+			section.Count++
+			section.Size += size
+			result.Largest = append(result.Largest, types.StringLiteral{Value: s, Section: name, Size: size})
+
+			// Method and field names are identifiers
+			if name != "__cstring" && name != "__oslogstring" {
+				continue
+			}
+			category := stringCategory(s)
+			if category == "" {
+				continue
+			}
+			if categories[category] == nil {
+				categories[category] = &types.StringCategory{Name: category}
+			}
+			categories[category].Count++
+			categories[category].Size += size
+			if category == StringCategoryBuildPath {
+				buildPaths[s] = true
+			}
+		}
+		result.Count += section.Count
+		result.TotalSize += section.Size
+		result.Sections = append(result.Sections, section)
+	}
+	if len(result.Sections) == 0 {
+		return nil
+	}
+
+	for _, category := range categories {
+		result.Categories = append(result.Categories, *category)
+	}
+	sort.Slice(result.Categories, func(i, j int) bool {
+		if result.Categories[i].Size != result.Categories[j].Size {
+			return result.Categories[i].Size > result.Categories[j].Size
+		}
+		return result.Categories[i].Name < result.Categories[j].Name
+	})
+
+	sort.SliceStable(result.Largest, func(i, j int) bool { return result.Largest[i].Size > result.Largest[j].Size })
+	if len(result.Largest) > maxLargestStrings {
+		result.Largest = result.Largest[:maxLargestStrings]
+	}
+	for i := range result.Largest {
+		result.Largest[i].Value = TruncateString(result.Largest[i].Value)
+	}
+
+	for path := range buildPaths {
+		result.BuildPaths = append(result.BuildPaths, path)
+	}
+	sort.Strings(result.BuildPaths)
+	if len(result.BuildPaths) > maxBuildPaths {
+		result.BuildPaths = result.BuildPaths[:maxBuildPaths]
+	}
+
+	return result
+}
+
+// CStrings returns the unique literals in __TEXT,__cstring of a binary, the first device
+// slice of a fat binary
+func CStrings(path string) ([]string, error) {
+	if fatFile, err := macho.OpenFat(path); err == nil {
+		defer fatFile.Close()
+		return uniqueStrings(sectionStrings(fatFile.Arches[primarySlice(fatFile)].File, "__cstring")), nil
+	}
+
+	file, err := macho.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Mach-O file: %w", err)
+	}
+	defer file.Close()
+
+	return uniqueStrings(sectionStrings(file, "__cstring")), nil
+}
+
+// sectionStrings splits a __TEXT section of NUL-terminated strings, skipping empty ones.
+// Returns nil when the section does not exist.
+func sectionStrings(file *macho.File, name string) []string {
+	section := file.Section(name)
+	if section == nil || section.Seg != "__TEXT" || section.Offset == 0 {
+		return nil
+	}
+	data, err := section.Data()
+	if err != nil {
+		return nil
+	}
+
+	strs := make([]string, 0)
+	for _, part := range bytes.Split(data, []byte{0}) {
+		if len(part) > 0 {
+			strs = append(strs, string(part))
+		}
+	}
+	return strs
+}
+
+// stringCategory returns the heuristic category of a C string literal, or ""
+func stringCategory(s string) string {
+	for _, prefix := range buildPathPrefixes {
+		if strings.HasPrefix(s, prefix) && !strings.ContainsAny(s, "%\n") {
+			return StringCategoryBuildPath
+		}
+	}
+	if strings.Contains(s, "/DerivedData/") {
+		return StringCategoryBuildPath
+	}
+	if urlPattern.MatchString(s) {
+		return StringCategoryURL
+	}
+	if sqlPattern.MatchString(s) {
+		return StringCategorySQL
+	}
+	trimmed := strings.TrimSpace(s)
+	if (strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}") ||
+		strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]")) && strings.Contains(trimmed, `":`) {
+		return StringCategoryJSON
+	}
+	if formatPattern.MatchString(s) && formatTextFilter.MatchString(formatPattern.ReplaceAllString(s, "")) {
+		return StringCategoryLogFormat
+	}
+	return ""
+}
+
+// TruncateString shortens a literal to maxStringValue characters for a report
+func TruncateString(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxStringValue {
+		return s
+	}
+	return string(runes[:maxStringValue]) + "…"
+}
+
+// uniqueStrings removes repeated strings, keeping the first occurrence
+func uniqueStrings(strs []string) []string {
+	seen := make(map[string]bool, len(strs))
+	unique := make([]string, 0, len(strs))
+	for _, s := range strs {
+		if !seen[s] {
+			seen[s] = true
+			unique = append(unique, s)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,101 @@
+package macho
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// cstringSection joins strings into a section of NUL-terminated strings
+func cstringSection(seg, name string, strs ...string) testSection {
+	return testSection{seg: seg, name: name, data: []byte(strings.Join(strs, "\x00") + "\x00")}
+}
+
+func TestParseStrings(t *testing.T) {
+	longLiteral := strings.Repeat("SELECT * FROM events ", 10)
+	img := newTestImage(
+		cstringSection("__TEXT", "__cstring",
+			"https://api.example.com/v1/events",
+			"/Users/builder/work/App/Sources/Network/Client.swift",
+			"/Users/builder/work/App/Sources/Network/Client.swift",
+			longLiteral,
+			`{"event": "launch"}`,
+			"Request failed with status %d",
+			"Done",
+		),
+		cstringSection("__TEXT", "__oslogstring", "Loaded %{public}@ in %.2f seconds"),
+		cstringSection("__TEXT", "__objc_methname", "viewDidLoad", "initWithFrame:"),
+		cstringSection("__DATA", "__cstring", "/Users/other/ignored.swift"),
+	)
+
+	got := ParseStrings(img.file(t))
+	require.NotNil(t, got)
+
+	assert.Equal(t, []types.StringSection{
+		{Name: "__cstring", Count: 7, Size: 34 + 53 + 53 + 211 + 20 + 30 + 5},
+		{Name: "__oslogstring", Count: 1, Size: 34},
+		{Name: "__objc_methname", Count: 2, Size: 12 + 15},
+	}, got.Sections)
+	assert.Equal(t, 10, got.Count)
+	assert.Equal(t, int64(34+53+53+211+20+30+5+34+12+15), got.TotalSize)
+
+	assert.Equal(t, []types.StringCategory{
+		{Name: StringCategorySQL, Count: 1, Size: 211},
+		{Name: StringCategoryBuildPath, Count: 2, Size: 106},
+		{Name: StringCategoryLogFormat, Count: 2, Size: 64},
+		{Name: StringCategoryURL, Count: 1, Size: 34},
+		{Name: StringCategoryJSON, Count: 1, Size: 20},
+	}, got.Categories)
+	assert.Equal(t, []string{"/Users/builder/work/App/Sources/Network/Client.swift"}, got.BuildPaths)
+
+	require.Len(t, got.Largest, maxLargestStrings)
+	assert.Equal(t, int64(211), got.Largest[0].Size)
+	assert.Equal(t, "__cstring", got.Largest[0].Section)
+	assert.Equal(t, []rune(longLiteral)[:maxStringValue], []rune(strings.TrimSuffix(got.Largest[0].Value, "…")))
+}
+
+func TestParseStrings_NoSections(t *testing.T) {
+	img := newTestImage(testSection{seg: "__TEXT", name: "__text", data: make([]byte, 16)})
+	assert.Nil(t, ParseStrings(img.file(t)))
+}
+
+func TestCStrings(t *testing.T) {
+	img := newTestImage(cstringSection("__TEXT", "__cstring", "first", "second", "first"))
+	path := filepath.Join(t.TempDir(), "App")
+	require.NoError(t, os.WriteFile(path, img.bytes(), 0644))
+
+	got, err := CStrings(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, got)
+}
+
+func TestStringCategory(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"/Users/jenkins/workspace/App/Sources/main.swift", StringCategoryBuildPath},
+		{"/Volumes/Build/DerivedData/App/Build/Intermediates.noindex/App.build", StringCategoryBuildPath},
+		{"Assertion failed in /home/runner/work/lib/src/core.c", ""},
+		{"/Users/%@/Documents", StringCategoryLogFormat},
+		{"myapp://open?item=1", StringCategoryURL},
+		{"select id from users where id = ?", StringCategorySQL},
+		{`[{"id": 1}]`, StringCategoryJSON},
+		{"User %@ logged in", StringCategoryLogFormat},
+		{"%d", ""},
+		{"100%", ""},
+		{"Settings", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			assert.Equal(t, tt.want, stringCategory(tt.s))
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package macho
+
+import (
+	"debug/macho"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Symbol table type bits
+const (
+	symbolTypeStab = 0xe0 // N_STAB: debugging entry
+	symbolTypeMask = 0x0e // N_TYPE
+	symbolTypeSect = 0x0e // N_SECT: defined in a section
+	symbolTypeExt  = 0x01 // N_EXT: external symbol
+)
+
+// CodeSymbol is a function symbol in __TEXT,__text
+type CodeSymbol struct {
+	Name     string
+	Size     int64 // Bytes up to the next symbol, or to the end of the section
+	External bool
+}
+
+// SwiftModuleCodeSizes sums the code in __TEXT,__text per Swift module of a binary,
+// the first device slice of a fat binary. Returns nil for stripped binaries.
+func SwiftModuleCodeSizes(path string) (map[string]int64, error) {
+	if fatFile, err := macho.OpenFat(path); err == nil {
+		defer fatFile.Close()
+		return swiftModuleCodeSizes(fatFile.Arches[primarySlice(fatFile)].File), nil
+	}
+
+	file, err := macho.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Mach-O file: %w", err)
+	}
+	defer file.Close()
+
+	return swiftModuleCodeSizes(file), nil
+}
+
+// swiftModuleCodeSizes attributes every function symbol in __TEXT,__text to the Swift
+// module in its mangled name. A function extends to the next symbol, the last one to
+// the end of the section. Code of other symbols (Objective-C, C, the standard library)
+// is not attributed.
+func swiftModuleCodeSizes(file *macho.File) map[string]int64 {
+	textIndex, text := textSection(file)
+	if text == nil {
+		return nil
+	}
+
+	// Module of each function address; aliases keep the first Swift module
+	modules := make(map[uint64]string)
+	for _, sym := range file.Symtab.Syms {
+		if sym.Type&symbolTypeStab != 0 || sym.Type&symbolTypeMask != symbolTypeSect || int(sym.Sect) != textIndex+1 {
+			continue
+		}
+		if sym.Value < text.Addr || sym.Value >= text.Addr+text.Size {
+			continue
+		}
+		if modules[sym.Value] == "" {
+			modules[sym.Value] = SwiftSymbolModule(sym.Name)
+		}
+	}
+	if len(modules) == 0 {
+		return nil
+	}
+
+	addresses := make([]uint64, 0, len(modules))
+	for addr := range modules {
+		addresses = append(addresses, addr)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i] < addresses[j] })
+
+	sizes := make(map[string]int64)
+	for i, addr := range addresses {
+		module := modules[addr]
+		if module == "" {
+			continue
+		}
+		end := text.Addr + text.Size
+		if i+1 < len(addresses) {
+			end = addresses[i+1]
+		}
+		sizes[module] += int64(end - addr)
+	}
+	return sizes
+}
+
+// CodeSymbols lists the function symbols in __TEXT,__text of a binary, the first device
+// slice of a fat binary. Returns nil for stripped binaries.
+func CodeSymbols(path string) ([]CodeSymbol, error) {
+	if fatFile, err := macho.OpenFat(path); err == nil {
+		defer fatFile.Close()
+		return codeSymbols(fatFile.Arches[primarySlice(fatFile)].File), nil
+	}
+
+	file, err := macho.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Mach-O file: %w", err)
+	}
+	defer file.Close()
+
+	return codeSymbols(file), nil
+}
+
+// codeSymbols lists the symbols defined in __TEXT,__text by address. A function extends
+// to the next symbol, the last one to the end of the section; aliases keep the first name.
+func codeSymbols(file *macho.File) []CodeSymbol {
+	textIndex, text := textSection(file)
+	if text == nil {
+		return nil
+	}
+
+	byAddress := make(map[uint64]macho.Symbol)
+	for _, sym := range file.Symtab.Syms {
+		if sym.Type&symbolTypeStab != 0 || sym.Type&symbolTypeMask != symbolTypeSect || int(sym.Sect) != textIndex+1 {
+			continue
+		}
+		if sym.Value < text.Addr || sym.Value >= text.Addr+text.Size || sym.Name == "" {
+			continue
+		}
+		if _, ok := byAddress[sym.Value]; !ok {
+			byAddress[sym.Value] = sym
+		}
+	}
+	if len(byAddress) == 0 {
+		return nil
+	}
+
+	addresses := make([]uint64, 0, len(byAddress))
+	for addr := range byAddress {
+		addresses = append(addresses, addr)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i] < addresses[j] })
+
+	symbols := make([]CodeSymbol, len(addresses))
+	for i, addr := range addresses {
+		end := text.Addr + text.Size
+		if i+1 < len(addresses) {
+			end = addresses[i+1]
+		}
+		sym := byAddress[addr]
+		symbols[i] = CodeSymbol{Name: sym.Name, Size: int64(end - addr), External: sym.Type&symbolTypeExt != 0}
+	}
+	return symbols
+}
+
+// textSection returns the index and header of the __TEXT,__text section of a binary with
+// a symbol table, or nil
+func textSection(file *macho.File) (int, *macho.Section) {
+	if file.Symtab == nil {
+		return -1, nil
+	}
+	for i, section := range file.Sections {
+		if section.Seg == "__TEXT" && section.Name == "__text" {
+			return i, section
+		}
+	}
+	return -1, nil
+}
+
+// SwiftSymbolModule returns the module of a mangled Swift symbol, e.g. Alamofire for
+// _$s9Alamofire7SessionC7requestyyF. Returns "" for other symbols and for symbols whose
+// context is a standard substitution, such as the standard library or imported C types.
+func SwiftSymbolModule(name string) string {
+	name = strings.TrimPrefix(name, "_")
+	var rest string
+	for _, prefix := range []string{"$s", "$S", "_T0"} {
+		if strings.HasPrefix(name, prefix) {
+			rest = name[len(prefix):]
+			break
+		}
+	}
+
+	digits := 0
+	for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+		digits++
+	}
+	length, err := strconv.Atoi(rest[:digits])
+	if err != nil || length <= 0 || digits+length > len(rest) {
+		return ""
+	}
+	return rest[digits : digits+length]
+}
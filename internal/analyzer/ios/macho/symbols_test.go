@@ -0,0 +1,125 @@
+package macho
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSymbol is an nlist_64 entry of a synthetic symbol table
+type testSymbol struct {
+	name   string
+	typ    uint8
+	sect   uint8
+	offset int // Offset in __text
+}
+
+// symbolTestImage builds a binary with a 64-byte __TEXT,__text section and a symbol
+// table in __LINKEDIT
+func symbolTestImage(symbols ...testSymbol) []byte {
+	var strtab bytes.Buffer
+	strtab.WriteByte(0)
+	offsets := make([]uint32, len(symbols))
+	for i, sym := range symbols {
+		offsets[i] = uint32(strtab.Len())
+		strtab.WriteString(sym.name)
+		strtab.WriteByte(0)
+	}
+	symtabSize := 16 * len(symbols)
+	linkedit := make([]byte, symtabSize+strtab.Len())
+	copy(linkedit[symtabSize:], strtab.Bytes())
+
+	img := newTestImage(
+		testSection{seg: "__TEXT", name: "__text", data: make([]byte, 64)},
+		testSection{seg: "__LINKEDIT", name: "__symbols", data: linkedit},
+	)
+	text, symtab := img.sec("__text"), img.sec("__symbols")
+	for i, sym := range symbols {
+		entry := symtab.data[16*i:]
+		binary.LittleEndian.PutUint32(entry, offsets[i])
+		entry[4] = sym.typ
+		entry[5] = sym.sect
+		binary.LittleEndian.PutUint64(entry[8:], text.addr+uint64(sym.offset))
+	}
+
+	// LC_SYMTAB: symbol table offset and count, string table offset and size
+	img.loads = append(img.loads, []uint32{0x2, 24, symtab.offset, uint32(len(symbols)),
+		symtab.offset + uint32(symtabSize), uint32(strtab.Len())})
+	return img.bytes()
+}
+
+func TestSwiftModuleCodeSizes(t *testing.T) {
+	data := symbolTestImage(
+		testSymbol{"_$s9Alamofire7SessionC7requestyyF", 0x0f, 1, 0},
+		testSymbol{"_$s9Alamofire7SessionC6cancelyyF", 0x0e, 1, 16},
+		testSymbol{"_helper_alias", 0x0e, 1, 16},
+		testSymbol{"_$s5MyApp4mainyyF", 0x0f, 1, 24},
+		testSymbol{"-[ABCLegacyView layoutSubviews]", 0x0e, 1, 40},
+		testSymbol{"_$s9Alamofire7SessionC7requestyyF", 0x24, 1, 8}, // Debugging entry
+		testSymbol{"_$s9Alamofire7SessionC5resetyyF", 0x01, 0, 0},   // Undefined
+	)
+	path := filepath.Join(t.TempDir(), "App")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	sizes, err := SwiftModuleCodeSizes(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"Alamofire": 24, "MyApp": 16}, sizes)
+}
+
+func TestSwiftModuleCodeSizes_Stripped(t *testing.T) {
+	img := newTestImage(testSection{seg: "__TEXT", name: "__text", data: make([]byte, 64)})
+	assert.Nil(t, swiftModuleCodeSizes(img.file(t)))
+}
+
+func TestCodeSymbols(t *testing.T) {
+	data := symbolTestImage(
+		testSymbol{"_$s9Alamofire7SessionC7requestyyF", 0x0f, 1, 0},
+		testSymbol{"_$s9Alamofire7SessionC6cancelyyF", 0x0e, 1, 16},
+		testSymbol{"_helper_alias", 0x0e, 1, 16},
+		testSymbol{"-[ABCLegacyView layoutSubviews]", 0x0e, 1, 40},
+		testSymbol{"_main", 0x24, 1, 24}, // Debugging entry
+		testSymbol{"_objc_msgSend", 0x01, 0, 0},
+	)
+	path := filepath.Join(t.TempDir(), "App")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	symbols, err := CodeSymbols(path)
+	require.NoError(t, err)
+	assert.Equal(t, []CodeSymbol{
+		{Name: "_$s9Alamofire7SessionC7requestyyF", Size: 16, External: true},
+		{Name: "_$s9Alamofire7SessionC6cancelyyF", Size: 24},
+		{Name: "-[ABCLegacyView layoutSubviews]", Size: 24},
+	}, symbols)
+}
+
+func TestCodeSymbols_Stripped(t *testing.T) {
+	img := newTestImage(testSection{seg: "__TEXT", name: "__text", data: make([]byte, 64)})
+	assert.Nil(t, codeSymbols(img.file(t)))
+}
+
+func TestSwiftSymbolModule(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"_$s9Alamofire7SessionC7requestyyF", "Alamofire"},
+		{"$s5MyApp4mainyyF", "MyApp"},
+		{"_$S7RxSwift10ObservableC", "RxSwift"},
+		{"__T05MyKit5CacheC", "MyKit"},
+		{"_$sSa6appendyyxnF", ""},
+		{"_$sSo8NSObjectC", ""},
+		{"-[ABCLegacyView layoutSubviews]", ""},
+		{"_$s99Short", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SwiftSymbolModule(tt.name))
+		})
+	}
+}
@@ -2,9 +2,12 @@ package ios
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/ios/assets"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/ios/macho"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
 )
@@ -63,3 +66,202 @@ func GenerateLargeAssetOptimizations(assetCatalogs []*assets.AssetCatalogInfo) [
 
 	return optimizations
 }
+
+// minReflectionMetadataSize is the reflection metadata size of a binary from which
+// disabling reflection metadata is suggested
+const minReflectionMetadataSize = 256 * 1024
+
+// GenerateReflectionMetadataOptimizations suggests building binaries with a large amount
+// of Swift reflection metadata without it
+func GenerateReflectionMetadataOptimizations(binaries map[string]*types.BinaryInfo) []types.Optimization {
+	var optimizations []types.Optimization
+
+	for path, binary := range binaries {
+		metadata := binary.RuntimeMetadata
+		if metadata == nil || metadata.ReflectionSize < minReflectionMetadataSize {
+			continue
+		}
+
+		description := fmt.Sprintf("Binary contains %s of Swift reflection metadata (field descriptors and names) for %d types",
+			util.FormatBytes(metadata.ReflectionSize), metadata.SwiftTypes)
+		if len(metadata.Modules) > 0 && metadata.Modules[0].ReflectionSize > 0 {
+			description += fmt.Sprintf(", most of it from %s (%s)",
+				metadata.Modules[0].Name, util.FormatBytes(metadata.Modules[0].ReflectionSize))
+		}
+
+		optimizations = append(optimizations, types.Optimization{
+			Category:    "swift-metadata",
+			Severity:    "low",
+			Title:       fmt.Sprintf("Swift reflection metadata in %s", filepath.Base(path)),
+			Description: description,
+			Impact:      metadata.ReflectionSize,
+			Files:       []string{path},
+			Action: "Build modules that do not rely on Mirror or reflection-based libraries with " +
+				"-disable-reflection-metadata (SWIFT_REFLECTION_METADATA_LEVEL = none), or keep it for " +
+				"the debugger only with -reflection-metadata-for-debugger-only",
+		})
+	}
+
+	// Sort by impact (largest first), then by binary path
+	sort.Slice(optimizations, func(i, j int) bool {
+		if optimizations[i].Impact != optimizations[j].Impact {
+			return optimizations[i].Impact > optimizations[j].Impact
+		}
+		return optimizations[i].Files[0] < optimizations[j].Files[0]
+	})
+
+	return optimizations
+}
+
+// GenerateBitcodeOptimizations suggests removing embedded bitcode, which the
+// App Store no longer accepts or uses since Xcode 14, from release builds. Builds
+// signed with a development profile are skipped; without a profile the build
+// configuration is unknown, which the description states.
+func GenerateBitcodeOptimizations(binaries map[string]*types.BinaryInfo, profile *types.ProvisioningProfile) []types.Optimization {
+	if profile != nil && profile.DistributionType == "development" {
+		return nil
+	}
+
+	var optimizations []types.Optimization
+
+	for path, binary := range binaries {
+		if binary.BitcodeSize <= 0 {
+			continue
+		}
+
+		name := filepath.Base(path)
+		severity := "high"
+		description := fmt.Sprintf("Binary contains %s of bitcode (__LLVM segment) that the App Store no longer uses", util.FormatBytes(binary.BitcodeSize))
+		if profile == nil {
+			severity = "medium"
+			description += ". The bundle has no provisioning profile, so this may not be a release build"
+		}
+		optimizations = append(optimizations, types.Optimization{
+			Category:    "bitcode",
+			Severity:    severity,
+			Title:       fmt.Sprintf("Embedded bitcode in %s", name),
+			Description: description,
+			Impact:      binary.BitcodeSize,
+			Files:       []string{path},
+			Action: fmt.Sprintf("Build with ENABLE_BITCODE = NO, or strip prebuilt binaries with "+
+				"'xcrun bitcode_strip -r %s -o %s'", name, name),
+		})
+	}
+
+	// Sort by impact (largest first), then by binary path
+	sort.Slice(optimizations, func(i, j int) bool {
+		if optimizations[i].Impact != optimizations[j].Impact {
+			return optimizations[i].Impact > optimizations[j].Impact
+		}
+		return optimizations[i].Files[0] < optimizations[j].Files[0]
+	})
+
+	return optimizations
+}
+
+// GenerateBuildPathOptimizations flags binaries that contain absolute paths of the build
+// machine, which leak the build environment and usually come from #file and __FILE__ in
+// assertions and logging
+func GenerateBuildPathOptimizations(binaries map[string]*types.BinaryInfo) []types.Optimization {
+	var optimizations []types.Optimization
+
+	for path, binary := range binaries {
+		if binary.Strings == nil || len(binary.Strings.BuildPaths) == 0 {
+			continue
+		}
+
+		var category types.StringCategory
+		for _, c := range binary.Strings.Categories {
+			if c.Name == macho.StringCategoryBuildPath {
+				category = c
+			}
+		}
+
+		name := filepath.Base(path)
+		optimizations = append(optimizations, types.Optimization{
+			Category: "build-paths",
+			Severity: "medium",
+			Title:    fmt.Sprintf("Build machine paths in %s", name),
+			Description: fmt.Sprintf("Binary contains %d absolute paths of the build machine (%s), e.g. %s. "+
+				"Shortening them saves little, so no savings are counted",
+				category.Count, util.FormatBytes(category.Size), binary.Strings.BuildPaths[0]),
+			Impact: 0, // A leak of the build environment rather than a size issue
+			Files:  []string{path},
+			Action: "Use #fileID instead of #file and #filePath in Swift (the default with Swift 6 or " +
+				"-enable-upcoming-feature ConciseMagicFile), and pass -fmacro-prefix-map=$(SRCROOT)=. " +
+				"to clang for __FILE__ in C and Objective-C",
+		})
+	}
+
+	// Sort by binary path
+	sort.Slice(optimizations, func(i, j int) bool {
+		return optimizations[i].Files[0] < optimizations[j].Files[0]
+	})
+
+	return optimizations
+}
+
+// EncryptionWarnings lists the binaries encrypted by the App Store, whose section
+// contents cannot be analyzed
+func EncryptionWarnings(binaries map[string]*types.BinaryInfo) []string {
+	var encrypted []string
+	for path, binary := range binaries {
+		if binary.IsEncrypted {
+			encrypted = append(encrypted, path)
+		}
+	}
+	if len(encrypted) == 0 {
+		return nil
+	}
+	sort.Strings(encrypted)
+
+	return []string{fmt.Sprintf("Encrypted binaries (App Store FairPlay): %s. Section sizes and Swift metadata "+
+		"of these binaries are unreliable; analyze an exported or development build instead.", strings.Join(encrypted, ", "))}
+}
+
+// GenerateSimulatorSliceOptimizations suggests removing the simulator slices of fat
+// binaries, which release builds cannot run
+func GenerateSimulatorSliceOptimizations(binaries map[string]*types.BinaryInfo) []types.Optimization {
+	var optimizations []types.Optimization
+
+	for path, binary := range binaries {
+		if binary.SimulatorSliceSavings <= 0 {
+			continue
+		}
+
+		var slices, removes []string
+		for _, slice := range binary.Slices {
+			if !slice.IsSimulator {
+				continue
+			}
+			name := slice.Architecture
+			if slice.Platform != "" {
+				name += " (" + slice.Platform + ")"
+			}
+			slices = append(slices, name)
+			removes = append(removes, "-remove "+slice.Architecture)
+		}
+
+		name := filepath.Base(path)
+		optimizations = append(optimizations, types.Optimization{
+			Category:    "architecture",
+			Severity:    "high",
+			Title:       fmt.Sprintf("Simulator slices in %s", name),
+			Description: fmt.Sprintf("Binary contains simulator slices that devices cannot run: %s", strings.Join(slices, ", ")),
+			Impact:      binary.SimulatorSliceSavings,
+			Files:       []string{path},
+			Action: fmt.Sprintf("Thin the binary with 'lipo %s %s -output %s', or embed the framework as an "+
+				"XCFramework so that only the device slice is copied", name, strings.Join(removes, " "), name),
+		})
+	}
+
+	// Sort by impact (largest first), then by binary path
+	sort.Slice(optimizations, func(i, j int) bool {
+		if optimizations[i].Impact != optimizations[j].Impact {
+			return optimizations[i].Impact > optimizations[j].Impact
+		}
+		return optimizations[i].Files[0] < optimizations[j].Files[0]
+	})
+
+	return optimizations
+}
@@ -0,0 +1,201 @@
+package ios
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func TestGenerateReflectionMetadataOptimizations(t *testing.T) {
+	binaries := map[string]*types.BinaryInfo{
+		"MyApp": {RuntimeMetadata: &types.RuntimeMetadata{
+			SwiftTypes:     1200,
+			ReflectionSize: 2 * 1024 * 1024,
+			Modules:        []types.RuntimeModule{{Name: "MyKit", ReflectionSize: 1536 * 1024}},
+		}},
+		"Frameworks/Small.framework/Small": {RuntimeMetadata: &types.RuntimeMetadata{SwiftTypes: 10, ReflectionSize: 4096}},
+		"Frameworks/Big.framework/Big":     {RuntimeMetadata: &types.RuntimeMetadata{SwiftTypes: 300, ReflectionSize: 512 * 1024}},
+		"Frameworks/ObjC.framework/ObjC":   {},
+	}
+
+	opts := GenerateReflectionMetadataOptimizations(binaries)
+	if len(opts) != 2 {
+		t.Fatalf("got %d optimizations, want 2", len(opts))
+	}
+
+	if opts[0].Files[0] != "MyApp" || opts[1].Files[0] != "Frameworks/Big.framework/Big" {
+		t.Errorf("optimizations not sorted by impact: %v, %v", opts[0].Files, opts[1].Files)
+	}
+	if opts[0].Category != "swift-metadata" || opts[0].Impact != 2*1024*1024 {
+		t.Errorf("optimization = %+v, want swift-metadata with the reflection size as impact", opts[0])
+	}
+	if !strings.Contains(opts[0].Description, "most of it from MyKit (1.5 MB)") {
+		t.Errorf("description %q does not name the largest module", opts[0].Description)
+	}
+	if !strings.Contains(opts[0].Action, "-disable-reflection-metadata") {
+		t.Errorf("action %q does not mention -disable-reflection-metadata", opts[0].Action)
+	}
+}
+
+func TestGenerateReflectionMetadataOptimizations_Order(t *testing.T) {
+	binaries := make(map[string]*types.BinaryInfo)
+	for _, path := range []string{"Frameworks/C.framework/C", "Frameworks/A.framework/A", "Frameworks/B.framework/B"} {
+		binaries[path] = &types.BinaryInfo{RuntimeMetadata: &types.RuntimeMetadata{SwiftTypes: 100, ReflectionSize: 512 * 1024}}
+	}
+
+	for run := 0; run < 5; run++ {
+		var files []string
+		for _, opt := range GenerateReflectionMetadataOptimizations(binaries) {
+			files = append(files, opt.Files[0])
+		}
+		if strings.Join(files, ",") != "Frameworks/A.framework/A,Frameworks/B.framework/B,Frameworks/C.framework/C" {
+			t.Fatalf("optimizations with equal impact not sorted by binary path: %v", files)
+		}
+	}
+}
+
+func TestGenerateSimulatorSliceOptimizations(t *testing.T) {
+	binaries := map[string]*types.BinaryInfo{
+		"Frameworks/Fat.framework/Fat": {
+			SimulatorSliceSavings: 3 * 1024 * 1024,
+			Slices: []types.BinarySlice{
+				{Architecture: "x86_64", Platform: "ios-simulator", IsSimulator: true},
+				{Architecture: "arm64", Platform: "ios"},
+			},
+		},
+		"MyApp": {Slices: []types.BinarySlice{{Architecture: "arm64"}}},
+	}
+
+	opts := GenerateSimulatorSliceOptimizations(binaries)
+	if len(opts) != 1 {
+		t.Fatalf("got %d optimizations, want 1", len(opts))
+	}
+
+	opt := opts[0]
+	if opt.Category != "architecture" || opt.Impact != 3*1024*1024 || opt.Files[0] != "Frameworks/Fat.framework/Fat" {
+		t.Errorf("optimization = %+v, want architecture with the thinning savings as impact", opt)
+	}
+	if !strings.Contains(opt.Description, "x86_64 (ios-simulator)") {
+		t.Errorf("description %q does not list the simulator slice", opt.Description)
+	}
+	if !strings.Contains(opt.Action, "lipo Fat -remove x86_64 -output Fat") {
+		t.Errorf("action %q does not contain the lipo command", opt.Action)
+	}
+}
+
+func TestGenerateSimulatorSliceOptimizations_Order(t *testing.T) {
+	binaries := make(map[string]*types.BinaryInfo)
+	for _, path := range []string{"Frameworks/C.framework/C", "Frameworks/A.framework/A", "Frameworks/B.framework/B"} {
+		binaries[path] = &types.BinaryInfo{
+			SimulatorSliceSavings: 1024 * 1024,
+			Slices:                []types.BinarySlice{{Architecture: "x86_64", IsSimulator: true}, {Architecture: "arm64"}},
+		}
+	}
+
+	for run := 0; run < 5; run++ {
+		var files []string
+		for _, opt := range GenerateSimulatorSliceOptimizations(binaries) {
+			files = append(files, opt.Files[0])
+		}
+		if strings.Join(files, ",") != "Frameworks/A.framework/A,Frameworks/B.framework/B,Frameworks/C.framework/C" {
+			t.Fatalf("optimizations with equal impact not sorted by binary path: %v", files)
+		}
+	}
+}
+
+func TestGenerateBitcodeOptimizations(t *testing.T) {
+	binaries := map[string]*types.BinaryInfo{
+		"Frameworks/Legacy.framework/Legacy": {BitcodeSize: 2 * 1024 * 1024},
+		"MyApp":                              {},
+	}
+
+	opts := GenerateBitcodeOptimizations(binaries, &types.ProvisioningProfile{DistributionType: "app-store"})
+	if len(opts) != 1 {
+		t.Fatalf("got %d optimizations, want 1", len(opts))
+	}
+
+	opt := opts[0]
+	if opt.Category != "bitcode" || opt.Severity != "high" || opt.Impact != 2*1024*1024 {
+		t.Errorf("optimization = %+v, want high severity bitcode with the bitcode size as impact", opt)
+	}
+	if !strings.Contains(opt.Action, "xcrun bitcode_strip -r Legacy -o Legacy") {
+		t.Errorf("action %q does not contain the bitcode_strip command", opt.Action)
+	}
+}
+
+func TestGenerateBitcodeOptimizations_BuildConfiguration(t *testing.T) {
+	binaries := map[string]*types.BinaryInfo{"MyApp": {BitcodeSize: 1024 * 1024}}
+
+	if opts := GenerateBitcodeOptimizations(binaries, &types.ProvisioningProfile{DistributionType: "development"}); len(opts) != 0 {
+		t.Errorf("got %+v, want no optimizations for development builds", opts)
+	}
+
+	// Without a profile the build may not be a release build
+	opts := GenerateBitcodeOptimizations(binaries, nil)
+	if len(opts) != 1 || opts[0].Severity != "medium" || !strings.Contains(opts[0].Description, "may not be a release build") {
+		t.Errorf("got %+v, want a medium optimization stating the unknown build configuration", opts)
+	}
+}
+
+func TestEncryptionWarnings(t *testing.T) {
+	if warnings := EncryptionWarnings(map[string]*types.BinaryInfo{"MyApp": {}}); warnings != nil {
+		t.Errorf("EncryptionWarnings() = %v, want none for unencrypted binaries", warnings)
+	}
+
+	warnings := EncryptionWarnings(map[string]*types.BinaryInfo{
+		"PlugIns/Widget.appex/Widget": {IsEncrypted: true},
+		"MyApp":                       {IsEncrypted: true},
+	})
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "MyApp, PlugIns/Widget.appex/Widget") {
+		t.Errorf("EncryptionWarnings() = %v, want one warning listing the encrypted binaries", warnings)
+	}
+}
+
+func TestGenerateBuildPathOptimizations(t *testing.T) {
+	binaries := map[string]*types.BinaryInfo{
+		"MyApp": {Strings: &types.StringLiterals{
+			Categories: []types.StringCategory{
+				{Name: "url", Count: 4, Size: 200},
+				{Name: "build_path", Count: 12, Size: 900},
+			},
+			BuildPaths: []string{"/Users/ci/src/App/AppDelegate.swift"},
+		}},
+		"Frameworks/Kit.framework/Kit":   {Strings: &types.StringLiterals{Categories: []types.StringCategory{{Name: "url", Count: 1, Size: 40}}}},
+		"Frameworks/ObjC.framework/ObjC": {},
+	}
+
+	opts := GenerateBuildPathOptimizations(binaries)
+	if len(opts) != 1 {
+		t.Fatalf("got %d optimizations, want 1", len(opts))
+	}
+
+	opt := opts[0]
+	if opt.Category != "build-paths" || opt.Impact != 0 || opt.Files[0] != "MyApp" {
+		t.Errorf("optimization = %+v, want build-paths for MyApp without savings", opt)
+	}
+	if !strings.Contains(opt.Description, "12 absolute paths") || !strings.Contains(opt.Description, "/Users/ci/src/App/AppDelegate.swift") {
+		t.Errorf("description %q does not count and name the paths", opt.Description)
+	}
+	if !strings.Contains(opt.Action, "#fileID") {
+		t.Errorf("action %q does not mention #fileID", opt.Action)
+	}
+}
+
+func TestGenerateBuildPathOptimizations_Order(t *testing.T) {
+	binaries := make(map[string]*types.BinaryInfo)
+	for _, path := range []string{"MyApp", "Frameworks/B.framework/B", "Frameworks/A.framework/A", "PlugIns/Widget.appex/Widget"} {
+		binaries[path] = &types.BinaryInfo{Strings: &types.StringLiterals{BuildPaths: []string{"/Users/ci/src/main.swift"}}}
+	}
+
+	for run := 0; run < 5; run++ {
+		opts := GenerateBuildPathOptimizations(binaries)
+		var files []string
+		for _, opt := range opts {
+			files = append(files, opt.Files[0])
+		}
+		if strings.Join(files, ",") != "Frameworks/A.framework/A,Frameworks/B.framework/B,MyApp,PlugIns/Widget.appex/Widget" {
+			t.Fatalf("optimizations not sorted by binary path: %v", files)
+		}
+	}
+}
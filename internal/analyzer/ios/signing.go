@@ -0,0 +1,230 @@
+package ios
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/ios/macho"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+	"howett.net/plist"
+)
+
+// expiryWarningPeriod is how long before expiry profiles and certificates are reported
+const expiryWarningPeriod = 30 * 24 * time.Hour
+
+// Directories that contain app extensions
+var extensionDirs = []string{"PlugIns", "Extensions"}
+
+// provisioningProfile holds the embedded.mobileprovision keys used by the report
+type provisioningProfile struct {
+	Name                  string                 `plist:"Name"`
+	UUID                  string                 `plist:"UUID"`
+	TeamIdentifier        []string               `plist:"TeamIdentifier"`
+	TeamName              string                 `plist:"TeamName"`
+	CreationDate          time.Time              `plist:"CreationDate"`
+	ExpirationDate        time.Time              `plist:"ExpirationDate"`
+	ProvisionedDevices    []string               `plist:"ProvisionedDevices"`
+	ProvisionsAllDevices  bool                   `plist:"ProvisionsAllDevices"`
+	DeveloperCertificates [][]byte               `plist:"DeveloperCertificates"`
+	Entitlements          map[string]interface{} `plist:"Entitlements"`
+}
+
+// ParseSigning reads the provisioning profiles and entitlements of an app bundle
+// and its app extensions. Returns nil when nothing in the bundle is signed.
+func ParseSigning(appPath string) *types.SigningInfo {
+	signing := parseBundleSigning(appPath)
+
+	for _, dir := range extensionDirs {
+		matches, _ := filepath.Glob(filepath.Join(appPath, dir, "*.appex"))
+		for _, extensionPath := range matches {
+			extension := parseBundleSigning(extensionPath)
+			if extension == nil {
+				continue
+			}
+			extension.Path = filepath.ToSlash(filepath.Join(dir, filepath.Base(extensionPath)))
+			if signing == nil {
+				signing = &types.SigningInfo{}
+			}
+			signing.Extensions = append(signing.Extensions, *extension)
+		}
+	}
+
+	return signing
+}
+
+// parseBundleSigning reads the provisioning profile and executable entitlements of one bundle
+func parseBundleSigning(bundlePath string) *types.SigningInfo {
+	signing := &types.SigningInfo{}
+
+	if profile, err := ParseProvisioningProfile(filepath.Join(bundlePath, "embedded.mobileprovision")); err == nil {
+		signing.Profile = profile
+	}
+	if entitlements, err := macho.ParseEntitlements(bundleExecutable(bundlePath)); err == nil {
+		signing.Entitlements = entitlements
+	}
+
+	if signing.Profile == nil && signing.Entitlements == nil {
+		return nil
+	}
+	return signing
+}
+
+// signingProfile returns the provisioning profile of the app itself, or nil
+func signingProfile(signing *types.SigningInfo) *types.ProvisioningProfile {
+	if signing == nil {
+		return nil
+	}
+	return signing.Profile
+}
+
+// bundleExecutable returns the path of the bundle executable from CFBundleExecutable,
+// falling back to the bundle name
+func bundleExecutable(bundlePath string) string {
+	name := strings.TrimSuffix(filepath.Base(bundlePath), filepath.Ext(bundlePath))
+
+	if data, err := os.ReadFile(filepath.Join(bundlePath, "Info.plist")); err == nil {
+		var info struct {
+			Executable string `plist:"CFBundleExecutable"`
+		}
+		if _, err := plist.Unmarshal(data, &info); err == nil && info.Executable != "" {
+			name = info.Executable
+		}
+	}
+
+	return filepath.Join(bundlePath, name)
+}
+
+// ParseProvisioningProfile parses an embedded.mobileprovision file
+func ParseProvisioningProfile(path string) (*types.ProvisioningProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provisioning profile: %w", err)
+	}
+
+	// The profile is a CMS signed message whose content is an XML plist; the
+	// signature is not verified, so the plist is taken from the message as is
+	start := bytes.Index(data, []byte("<?xml"))
+	end := bytes.LastIndex(data, []byte("</plist>"))
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no property list in provisioning profile")
+	}
+
+	var raw provisioningProfile
+	if _, err := plist.Unmarshal(data[start:end+len("</plist>")], &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse provisioning profile: %w", err)
+	}
+
+	profile := &types.ProvisioningProfile{
+		Name:               raw.Name,
+		UUID:               raw.UUID,
+		TeamName:           raw.TeamName,
+		DistributionType:   distributionType(&raw),
+		CreatedAt:          raw.CreationDate,
+		ExpiresAt:          raw.ExpirationDate,
+		ProvisionedDevices: len(raw.ProvisionedDevices),
+	}
+	if len(raw.TeamIdentifier) > 0 {
+		profile.TeamID = raw.TeamIdentifier[0]
+	}
+
+	for _, der := range raw.DeveloperCertificates {
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			continue
+		}
+		profile.Certificates = append(profile.Certificates, types.SigningCertificate{
+			Subject:   certificate.Subject.CommonName,
+			ExpiresAt: certificate.NotAfter,
+		})
+	}
+
+	return profile, nil
+}
+
+// distributionType classifies a profile the way Xcode does: enterprise profiles
+// provision all devices, development profiles allow debugging, ad-hoc profiles list
+// devices and App Store profiles have none
+func distributionType(profile *provisioningProfile) string {
+	switch {
+	case profile.ProvisionsAllDevices:
+		return "enterprise"
+	case len(profile.ProvisionedDevices) == 0:
+		return "app-store"
+	case profile.Entitlements["get-task-allow"] == true:
+		return "development"
+	default:
+		return "ad-hoc"
+	}
+}
+
+// SigningWarnings reports development profiles, and profiles and certificates that
+// have expired or expire within 30 days of now
+func SigningWarnings(signing *types.SigningInfo, now time.Time) []string {
+	if signing == nil {
+		return nil
+	}
+
+	bundles := append([]types.SigningInfo{*signing}, signing.Extensions...)
+
+	var warnings, development, expiring []string
+	seenCertificates := make(map[string]bool)
+	for _, bundle := range bundles {
+		if bundle.Profile == nil {
+			continue
+		}
+		label := bundle.Path
+		if label == "" {
+			label = "the app"
+		}
+
+		if bundle.Profile.DistributionType == "development" {
+			development = append(development, label)
+		}
+		if message := expiryMessage(bundle.Profile.ExpiresAt, now); message != "" {
+			expiring = append(expiring, fmt.Sprintf("Provisioning profile %q of %s %s", bundle.Profile.Name, label, message))
+		}
+
+		for _, certificate := range bundle.Profile.Certificates {
+			if seenCertificates[certificate.Subject] {
+				continue
+			}
+			seenCertificates[certificate.Subject] = true
+			if message := expiryMessage(certificate.ExpiresAt, now); message != "" {
+				expiring = append(expiring, fmt.Sprintf("Signing certificate %q %s", certificate.Subject, message))
+			}
+		}
+	}
+
+	if len(development) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Signed with a development provisioning profile (%s); "+
+			"it can only be installed on registered devices and not be submitted to the App Store", strings.Join(development, ", ")))
+	}
+	sort.Strings(expiring)
+	return append(warnings, expiring...)
+}
+
+// expiryMessage describes an expiry date that has passed or is within the warning period
+func expiryMessage(expiresAt, now time.Time) string {
+	if expiresAt.IsZero() {
+		return ""
+	}
+
+	remaining := expiresAt.Sub(now)
+	date := expiresAt.UTC().Format("2006-01-02")
+	switch {
+	case remaining <= 0:
+		return fmt.Sprintf("expired on %s", date)
+	case remaining <= expiryWarningPeriod:
+		days := int(math.Ceil(remaining.Hours() / 24))
+		return fmt.Sprintf("expires on %s (in %d days)", date, days)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,152 @@
+package ios
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"howett.net/plist"
+)
+
+// testCertificate creates a self-signed DER certificate
+func testCertificate(t *testing.T, commonName string, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return der
+}
+
+// writeTestProfile writes a provisioning profile with the plist wrapped in placeholder
+// CMS bytes, like the DER structure around the content of real profiles
+func writeTestProfile(t *testing.T, path string, profile provisioningProfile) {
+	t.Helper()
+	data, err := plist.MarshalIndent(profile, plist.XMLFormat, "\t")
+	require.NoError(t, err)
+
+	cms := append([]byte{0x30, 0x80, 0x06, 0x09, 0x2a, 0x86, 0x48}, data...)
+	cms = append(cms, 0xa0, 0x82, 0x03, 0x00, 0x30, 0x82)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, cms, 0o644))
+}
+
+func TestParseProvisioningProfile(t *testing.T) {
+	expires := time.Date(2027, 3, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "embedded.mobileprovision")
+	writeTestProfile(t, path, provisioningProfile{
+		Name:                  "Example Ad Hoc",
+		UUID:                  "0c1f7a2e-1111-2222-3333-444455556666",
+		TeamIdentifier:        []string{"ABCDE12345"},
+		TeamName:              "Example Inc",
+		CreationDate:          expires.AddDate(-1, 0, 0),
+		ExpirationDate:        expires,
+		ProvisionedDevices:    []string{"00008030-0001", "00008030-0002"},
+		DeveloperCertificates: [][]byte{testCertificate(t, "Apple Distribution: Example Inc (ABCDE12345)", expires)},
+		Entitlements:          map[string]interface{}{"get-task-allow": false},
+	})
+
+	profile, err := ParseProvisioningProfile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Example Ad Hoc", profile.Name)
+	assert.Equal(t, "ABCDE12345", profile.TeamID)
+	assert.Equal(t, "Example Inc", profile.TeamName)
+	assert.Equal(t, "ad-hoc", profile.DistributionType)
+	assert.Equal(t, 2, profile.ProvisionedDevices)
+	assert.True(t, expires.Equal(profile.ExpiresAt))
+	require.Len(t, profile.Certificates, 1)
+	assert.Equal(t, "Apple Distribution: Example Inc (ABCDE12345)", profile.Certificates[0].Subject)
+	assert.True(t, expires.Equal(profile.Certificates[0].ExpiresAt))
+
+	_, err = ParseProvisioningProfile(filepath.Join(t.TempDir(), "missing.mobileprovision"))
+	assert.Error(t, err)
+}
+
+func TestDistributionType(t *testing.T) {
+	devices := []string{"00008030-0001"}
+	tests := []struct {
+		name    string
+		profile provisioningProfile
+		want    string
+	}{
+		{"app store", provisioningProfile{}, "app-store"},
+		{"enterprise", provisioningProfile{ProvisionsAllDevices: true}, "enterprise"},
+		{"ad hoc", provisioningProfile{ProvisionedDevices: devices}, "ad-hoc"},
+		{"development", provisioningProfile{ProvisionedDevices: devices,
+			Entitlements: map[string]interface{}{"get-task-allow": true}}, "development"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, distributionType(&tt.profile))
+		})
+	}
+}
+
+func TestParseSigning(t *testing.T) {
+	app := filepath.Join(t.TempDir(), "Example.app")
+	expires := time.Now().AddDate(1, 0, 0)
+	writeTestProfile(t, filepath.Join(app, "embedded.mobileprovision"), provisioningProfile{
+		Name: "Example App Store", TeamIdentifier: []string{"ABCDE12345"}, ExpirationDate: expires,
+	})
+	writeTestProfile(t, filepath.Join(app, "PlugIns", "Widget.appex", "embedded.mobileprovision"), provisioningProfile{
+		Name: "Example Widget", TeamIdentifier: []string{"ABCDE12345"}, ExpirationDate: expires,
+	})
+	require.NoError(t, os.MkdirAll(filepath.Join(app, "PlugIns", "Unsigned.appex"), 0o755))
+
+	signing := ParseSigning(app)
+	require.NotNil(t, signing)
+	require.NotNil(t, signing.Profile)
+	assert.Equal(t, "Example App Store", signing.Profile.Name)
+	require.Len(t, signing.Extensions, 1)
+	assert.Equal(t, "PlugIns/Widget.appex", signing.Extensions[0].Path)
+	assert.Equal(t, "Example Widget", signing.Extensions[0].Profile.Name)
+
+	assert.Nil(t, ParseSigning(t.TempDir()))
+}
+
+func TestSigningWarnings(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	certificate := types.SigningCertificate{Subject: "Apple Development: Jane Doe (XYZ)", ExpiresAt: now.AddDate(0, 0, 10)}
+	signing := &types.SigningInfo{
+		Profile: &types.ProvisioningProfile{
+			Name: "Example Dev", DistributionType: "development", ExpiresAt: now.AddDate(1, 0, 0),
+			Certificates: []types.SigningCertificate{certificate},
+		},
+		Extensions: []types.SigningInfo{{
+			Path: "PlugIns/Widget.appex",
+			Profile: &types.ProvisioningProfile{
+				Name: "Widget Dev", DistributionType: "development", ExpiresAt: now.AddDate(0, 0, -1),
+				Certificates: []types.SigningCertificate{certificate},
+			},
+		}},
+	}
+
+	warnings := SigningWarnings(signing, now)
+	require.Len(t, warnings, 3)
+	assert.True(t, strings.HasPrefix(warnings[0], "Signed with a development provisioning profile (the app, PlugIns/Widget.appex)"))
+	assert.Equal(t, `Provisioning profile "Widget Dev" of PlugIns/Widget.appex expired on 2026-09-30`, warnings[1])
+	assert.Equal(t, `Signing certificate "Apple Development: Jane Doe (XYZ)" expires on 2026-10-11 (in 10 days)`, warnings[2])
+
+	assert.Empty(t, SigningWarnings(&types.SigningInfo{Profile: &types.ProvisioningProfile{
+		DistributionType: "app-store", ExpiresAt: now.AddDate(1, 0, 0),
+	}}, now))
+	assert.Nil(t, SigningWarnings(nil, now))
+}
@@ -0,0 +1,73 @@
+package ios
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/ios/macho"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// minDuplicateStringSize is the size, including the NUL terminator, from which a C string
+// compiled into several binaries is reported
+const minDuplicateStringSize = 32
+
+// maxDuplicateStrings is the number of strings listed in IOSDetails.DuplicateStrings
+const maxDuplicateStrings = 20
+
+// FindDuplicateStrings lists the C strings compiled into more than one binary of the app
+// bundle, most wasted bytes first. Encrypted binaries are skipped.
+func FindDuplicateStrings(appPath string, binaries map[string]*types.BinaryInfo) []types.DuplicateString {
+	stringsByBinary := make(map[string][]string)
+	for path, binary := range binaries {
+		if binary == nil || binary.IsEncrypted {
+			continue
+		}
+		if strs, err := macho.CStrings(filepath.Join(appPath, path)); err == nil {
+			stringsByBinary[path] = strs
+		}
+	}
+	return duplicateStrings(stringsByBinary)
+}
+
+// duplicateStrings finds the strings of at least minDuplicateStringSize bytes that more
+// than one binary contains. The strings of each binary must be unique.
+func duplicateStrings(stringsByBinary map[string][]string) []types.DuplicateString {
+	binariesByString := make(map[string][]string)
+	for binary, strs := range stringsByBinary {
+		for _, s := range strs {
+			if len(s)+1 >= minDuplicateStringSize {
+				binariesByString[s] = append(binariesByString[s], binary)
+			}
+		}
+	}
+
+	var duplicates []types.DuplicateString
+	for s, binaries := range binariesByString {
+		if len(binaries) < 2 {
+			continue
+		}
+		sort.Strings(binaries)
+		size := int64(len(s) + 1)
+		duplicates = append(duplicates, types.DuplicateString{
+			Value:      s,
+			Size:       size,
+			WastedSize: size * int64(len(binaries)-1),
+			Binaries:   binaries,
+		})
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].WastedSize != duplicates[j].WastedSize {
+			return duplicates[i].WastedSize > duplicates[j].WastedSize
+		}
+		return duplicates[i].Value < duplicates[j].Value
+	})
+	if len(duplicates) > maxDuplicateStrings {
+		duplicates = duplicates[:maxDuplicateStrings]
+	}
+	for i := range duplicates {
+		duplicates[i].Value = macho.TruncateString(duplicates[i].Value)
+	}
+	return duplicates
+}
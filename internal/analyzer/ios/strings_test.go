@@ -0,0 +1,36 @@
+package ios
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func TestDuplicateStrings(t *testing.T) {
+	shared := "https://analytics.example.com/v2/collect"
+	long := strings.Repeat("x", 150)
+	stringsByBinary := map[string][]string{
+		"MyApp":                        {shared, long, "short", "only in the app, but long enough"},
+		"PlugIns/Widget.appex/Widget":  {shared, long, "short"},
+		"Frameworks/Kit.framework/Kit": {shared},
+	}
+
+	got := duplicateStrings(stringsByBinary)
+	assert.Equal(t, []types.DuplicateString{
+		{
+			Value:      strings.Repeat("x", 100) + "…",
+			Size:       151,
+			WastedSize: 151,
+			Binaries:   []string{"MyApp", "PlugIns/Widget.appex/Widget"},
+		},
+		{
+			Value:      shared,
+			Size:       41,
+			WastedSize: 82,
+			Binaries:   []string{"Frameworks/Kit.framework/Kit", "MyApp", "PlugIns/Widget.appex/Widget"},
+		},
+	}, got)
+}
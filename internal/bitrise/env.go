@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // BuildMetadata contains Bitrise build information
@@ -47,6 +48,81 @@ func DetectBundlePath() (string, error) {
 	return "", fmt.Errorf("no bundle found in Bitrise environment variables (checked BITRISE_IPA_PATH, BITRISE_AAB_PATH, BITRISE_APK_PATH)")
 }
 
+// listSeparator separates paths in Bitrise *_PATH_LIST environment variables
+const listSeparator = "|"
+
+// artifactEnvVars lists the Bitrise variables checked in multi-artifact mode, in order.
+// List variables may hold several paths separated by listSeparator.
+var artifactEnvVars = []string{
+	"BITRISE_IPA_PATH",
+	"BITRISE_XCARCHIVE_PATH",
+	"BITRISE_APP_DIR_PATH",
+	"BITRISE_AAB_PATH",
+	"BITRISE_AAB_PATH_LIST",
+	"BITRISE_APK_PATH",
+	"BITRISE_APK_PATH_LIST",
+}
+
+// DetectBundlePaths returns every artifact found in Bitrise environment variables.
+// Unlike DetectBundlePath it doesn't stop at the first match, so builds producing
+// several flavors or both platforms get all of their artifacts analyzed.
+// XCArchives are resolved to the .app bundle they contain. Paths are de-duplicated.
+func DetectBundlePaths() ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+
+	for _, envVar := range artifactEnvVars {
+		for _, path := range strings.Split(os.Getenv(envVar), listSeparator) {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+
+			if envVar == "BITRISE_XCARCHIVE_PATH" {
+				appPath, err := findXCArchiveApp(path)
+				if err != nil {
+					continue
+				}
+				path = appPath
+			}
+
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+
+			key := filepath.Clean(path)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			paths = append(paths, path)
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no bundles found in Bitrise environment variables (checked %s)", strings.Join(artifactEnvVars, ", "))
+	}
+
+	return paths, nil
+}
+
+// findXCArchiveApp returns the .app bundle inside an XCArchive's Products/Applications directory
+func findXCArchiveApp(archivePath string) (string, error) {
+	applicationsDir := filepath.Join(archivePath, "Products", "Applications")
+	entries, err := os.ReadDir(applicationsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read XCArchive applications: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasSuffix(entry.Name(), ".app") {
+			return filepath.Join(applicationsDir, entry.Name()), nil
+		}
+	}
+
+	return "", fmt.Errorf("no .app bundle found in %s", archivePath)
+}
+
 // GetBuildMetadata returns Bitrise build information from environment variables
 func GetBuildMetadata() BuildMetadata {
 	return BuildMetadata{
@@ -344,3 +344,89 @@ func TestExportToDeployDir(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectBundlePaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	ipaPath := filepath.Join(tmpDir, "test.ipa")
+	aabPath := filepath.Join(tmpDir, "test.aab")
+	apkDebugPath := filepath.Join(tmpDir, "app-debug.apk")
+	apkReleasePath := filepath.Join(tmpDir, "app-release.apk")
+	archivePath := filepath.Join(tmpDir, "App.xcarchive")
+	archiveAppPath := filepath.Join(archivePath, "Products", "Applications", "App.app")
+
+	for _, path := range []string{ipaPath, aabPath, apkDebugPath, apkReleasePath} {
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(archiveAppPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		envVars   map[string]string
+		wantPaths []string
+		wantErr   bool
+	}{
+		{
+			name:    "no env vars set",
+			envVars: map[string]string{},
+			wantErr: true,
+		},
+		{
+			name: "all platforms and list variables",
+			envVars: map[string]string{
+				"BITRISE_IPA_PATH":       ipaPath,
+				"BITRISE_AAB_PATH":       aabPath,
+				"BITRISE_APK_PATH_LIST":  apkDebugPath + "|" + apkReleasePath,
+				"BITRISE_XCARCHIVE_PATH": archivePath,
+			},
+			wantPaths: []string{ipaPath, archiveAppPath, aabPath, apkDebugPath, apkReleasePath},
+		},
+		{
+			name: "single path repeated in list is de-duplicated",
+			envVars: map[string]string{
+				"BITRISE_APK_PATH":      apkReleasePath,
+				"BITRISE_APK_PATH_LIST": apkReleasePath + "|" + apkDebugPath,
+			},
+			wantPaths: []string{apkReleasePath, apkDebugPath},
+		},
+		{
+			name: "missing files are skipped",
+			envVars: map[string]string{
+				"BITRISE_AAB_PATH_LIST": "/nonexistent/a.aab|" + aabPath,
+			},
+			wantPaths: []string{aabPath},
+		},
+		{
+			name: "xcarchive without app is skipped",
+			envVars: map[string]string{
+				"BITRISE_XCARCHIVE_PATH": tmpDir,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Clearenv()
+			for k, v := range tt.envVars {
+				os.Setenv(k, v)
+			}
+
+			got, err := DetectBundlePaths()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DetectBundlePaths() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.wantPaths) {
+				t.Fatalf("DetectBundlePaths() = %v, want %v", got, tt.wantPaths)
+			}
+			for i := range got {
+				if got[i] != tt.wantPaths[i] {
+					t.Errorf("DetectBundlePaths()[%d] = %v, want %v", i, got[i], tt.wantPaths[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package bitrise
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// DefaultEnvPrefix is the default prefix for exported environment variable names
+const DefaultEnvPrefix = "BUNDLE_INSPECTOR"
+
+// envmanCommand is the envman executable used for exporting variables
+var envmanCommand = "envman"
+
+// KeyMetrics contains the report values exported to subsequent workflow steps
+type KeyMetrics struct {
+	Size                 int64
+	UncompressedSize     int64
+	TotalSavings         int64
+	ReportJSONPath       string
+	ReportHTMLPath       string
+	TopOptimizationTitle string
+}
+
+// EnvVar is a single environment variable to export
+type EnvVar struct {
+	Key   string
+	Value string
+}
+
+// NewKeyMetrics extracts key metrics from a report.
+// The top optimization is the one with the largest impact.
+func NewKeyMetrics(report *types.Report, jsonPath, htmlPath string) KeyMetrics {
+	metrics := KeyMetrics{
+		Size:             report.ArtifactInfo.Size,
+		UncompressedSize: report.ArtifactInfo.UncompressedSize,
+		TotalSavings:     report.TotalSavings,
+		ReportJSONPath:   jsonPath,
+		ReportHTMLPath:   htmlPath,
+	}
+
+	if len(report.Optimizations) > 0 {
+		opts := make([]types.Optimization, len(report.Optimizations))
+		copy(opts, report.Optimizations)
+		sort.SliceStable(opts, func(i, j int) bool {
+			return opts[i].Impact > opts[j].Impact
+		})
+		metrics.TopOptimizationTitle = opts[0].Title
+	}
+
+	return metrics
+}
+
+// EnvVars returns the metrics as environment variables using the given name prefix
+func (m KeyMetrics) EnvVars(prefix string) []EnvVar {
+	prefix = normalizeEnvPrefix(prefix)
+
+	return []EnvVar{
+		{Key: prefix + "_SIZE", Value: strconv.FormatInt(m.Size, 10)},
+		{Key: prefix + "_UNCOMPRESSED_SIZE", Value: strconv.FormatInt(m.UncompressedSize, 10)},
+		{Key: prefix + "_TOTAL_SAVINGS", Value: strconv.FormatInt(m.TotalSavings, 10)},
+		{Key: prefix + "_REPORT_JSON_PATH", Value: m.ReportJSONPath},
+		{Key: prefix + "_REPORT_HTML_PATH", Value: m.ReportHTMLPath},
+		{Key: prefix + "_TOP_OPTIMIZATION", Value: m.TopOptimizationTitle},
+	}
+}
+
+// ExportKeyMetrics exports all key metrics through envman
+// Returns the exported variables or error
+func ExportKeyMetrics(prefix string, metrics KeyMetrics) ([]EnvVar, error) {
+	vars := metrics.EnvVars(prefix)
+	for _, v := range vars {
+		if err := ExportEnvVar(v.Key, v.Value); err != nil {
+			return nil, err
+		}
+	}
+	return vars, nil
+}
+
+// ExportEnvVar exports a single environment variable through envman,
+// making it available to subsequent steps of the workflow
+func ExportEnvVar(key, value string) error {
+	if _, err := exec.LookPath(envmanCommand); err != nil {
+		return fmt.Errorf("envman not found: %w", err)
+	}
+
+	cmd := exec.Command(envmanCommand, "add", "--key", key, "--value", value)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to export %s: %w (%s)", key, err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// ArtifactEnvPrefix returns the name prefix of the variables of one artifact in
+// multi-artifact mode, e.g. BUNDLE_INSPECTOR_APP_RELEASE for app-release
+func ArtifactEnvPrefix(prefix, artifactName string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(artifactName))
+	return normalizeEnvPrefix(prefix) + "_" + strings.Trim(name, "_")
+}
+
+// normalizeEnvPrefix upper-cases the prefix and strips trailing underscores
+func normalizeEnvPrefix(prefix string) string {
+	prefix = strings.TrimRight(strings.ToUpper(strings.TrimSpace(prefix)), "_")
+	if prefix == "" {
+		return DefaultEnvPrefix
+	}
+	return prefix
+}
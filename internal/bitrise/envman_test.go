@@ -0,0 +1,156 @@
+package bitrise
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// installFakeEnvman replaces envman with a script that records its arguments
+func installFakeEnvman(t *testing.T) string {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "envman.log")
+	scriptPath := filepath.Join(tmpDir, "envman")
+	script := "#!/bin/sh\necho \"$@\" >> " + logPath + "\n"
+	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	original := envmanCommand
+	envmanCommand = scriptPath
+	t.Cleanup(func() { envmanCommand = original })
+
+	return logPath
+}
+
+func TestNewKeyMetrics(t *testing.T) {
+	report := &types.Report{
+		ArtifactInfo: types.ArtifactInfo{
+			Size:             1000,
+			UncompressedSize: 3000,
+		},
+		TotalSavings: 400,
+		Optimizations: []types.Optimization{
+			{Title: "Small", Impact: 100},
+			{Title: "Largest", Impact: 300},
+		},
+	}
+
+	metrics := NewKeyMetrics(report, "/deploy/report.json", "/out/report.html")
+
+	if metrics.Size != 1000 || metrics.UncompressedSize != 3000 || metrics.TotalSavings != 400 {
+		t.Errorf("unexpected sizes: %+v", metrics)
+	}
+	if metrics.TopOptimizationTitle != "Largest" {
+		t.Errorf("TopOptimizationTitle = %q, want %q", metrics.TopOptimizationTitle, "Largest")
+	}
+	if metrics.ReportJSONPath != "/deploy/report.json" || metrics.ReportHTMLPath != "/out/report.html" {
+		t.Errorf("unexpected report paths: %+v", metrics)
+	}
+
+	// Source report order must not change
+	if report.Optimizations[0].Title != "Small" {
+		t.Error("NewKeyMetrics reordered report optimizations")
+	}
+}
+
+func TestKeyMetrics_EnvVars(t *testing.T) {
+	metrics := KeyMetrics{Size: 10, UncompressedSize: 20, TotalSavings: 5, TopOptimizationTitle: "Strip"}
+
+	tests := []struct {
+		name       string
+		prefix     string
+		wantPrefix string
+	}{
+		{name: "default prefix", prefix: "", wantPrefix: "BUNDLE_INSPECTOR"},
+		{name: "custom prefix", prefix: "APP_SIZE", wantPrefix: "APP_SIZE"},
+		{name: "trailing underscore and lowercase", prefix: "my_app_", wantPrefix: "MY_APP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := metrics.EnvVars(tt.prefix)
+			got := make(map[string]string)
+			for _, v := range vars {
+				got[v.Key] = v.Value
+			}
+
+			want := map[string]string{
+				tt.wantPrefix + "_SIZE":              "10",
+				tt.wantPrefix + "_UNCOMPRESSED_SIZE": "20",
+				tt.wantPrefix + "_TOTAL_SAVINGS":     "5",
+				tt.wantPrefix + "_REPORT_JSON_PATH":  "",
+				tt.wantPrefix + "_REPORT_HTML_PATH":  "",
+				tt.wantPrefix + "_TOP_OPTIMIZATION":  "Strip",
+			}
+			if len(got) != len(want) {
+				t.Fatalf("got %d vars, want %d", len(got), len(want))
+			}
+			for k, v := range want {
+				if got[k] != v {
+					t.Errorf("%s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestArtifactEnvPrefix(t *testing.T) {
+	tests := []struct {
+		prefix, name, want string
+	}{
+		{"BUNDLE_INSPECTOR", "app-release", "BUNDLE_INSPECTOR_APP_RELEASE"},
+		{"app_", "MyApp.v2", "APP_MYAPP_V2"},
+		{"", "app-release-2", "BUNDLE_INSPECTOR_APP_RELEASE_2"},
+	}
+
+	for _, tt := range tests {
+		if got := ArtifactEnvPrefix(tt.prefix, tt.name); got != tt.want {
+			t.Errorf("ArtifactEnvPrefix(%q, %q) = %q, want %q", tt.prefix, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExportKeyMetrics(t *testing.T) {
+	logPath := installFakeEnvman(t)
+
+	metrics := KeyMetrics{Size: 42, ReportJSONPath: "/deploy/bundle-analysis.json", TopOptimizationTitle: "Remove 2 duplicate copies of files"}
+	vars, err := ExportKeyMetrics("BUNDLE_INSPECTOR", metrics)
+	if err != nil {
+		t.Fatalf("ExportKeyMetrics() error = %v", err)
+	}
+	if len(vars) != 6 {
+		t.Errorf("exported %d vars, want 6", len(vars))
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(data)
+
+	for _, want := range []string{
+		"add --key BUNDLE_INSPECTOR_SIZE --value 42",
+		"add --key BUNDLE_INSPECTOR_REPORT_JSON_PATH --value /deploy/bundle-analysis.json",
+		"add --key BUNDLE_INSPECTOR_TOP_OPTIMIZATION --value Remove 2 duplicate copies of files",
+	} {
+		if !strings.Contains(log, want) {
+			t.Errorf("envman log missing %q\n%s", want, log)
+		}
+	}
+}
+
+func TestExportEnvVar_EnvmanMissing(t *testing.T) {
+	original := envmanCommand
+	envmanCommand = filepath.Join(t.TempDir(), "does-not-exist")
+	defer func() { envmanCommand = original }()
+
+	if err := ExportEnvVar("KEY", "value"); err == nil {
+		t.Error("ExportEnvVar() expected error when envman is missing")
+	}
+}
@@ -0,0 +1,236 @@
+package detector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/ios/macho"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// minDuplicateCodeSize is the duplicated code size from which a symbol set is reported
+const minDuplicateCodeSize = 16 * 1024
+
+// maxExampleSymbols is the number of symbols named in the description of a symbol set
+const maxExampleSymbols = 3
+
+// ignoredSymbolPrefixes are compiler-generated helpers that every image defines under the
+// same name, such as outlined functions, block helpers and temporary labels
+var ignoredSymbolPrefixes = []string{
+	"_OUTLINED_FUNCTION_",
+	"___swift_",
+	"___copy_helper_block_",
+	"___destroy_helper_block_",
+	"___Block_byref_object_",
+	"ltmp",
+	"l_",
+	"L",
+}
+
+// SymbolDuplicateDetector implements the Detector interface.
+// This detector is iOS-only: it compares the function symbols of all Mach-O images in the
+// bundle to find code that is linked into several of them, typically a static library
+// linked into the app, its extensions and a framework.
+type SymbolDuplicateDetector struct{}
+
+// NewSymbolDuplicateDetector creates a new symbol-level duplicate code detector
+func NewSymbolDuplicateDetector() *SymbolDuplicateDetector {
+	return &SymbolDuplicateDetector{}
+}
+
+// Name returns the detector name
+func (d *SymbolDuplicateDetector) Name() string {
+	return "duplicate-code"
+}
+
+// Detect reads the function symbols of every Mach-O image and reports the sets of
+// symbols defined in more than one image
+func (d *SymbolDuplicateDetector) Detect(rootPath string) ([]types.Optimization, error) {
+	mapper := util.NewPathMapper(rootPath)
+	images := make(map[string][]macho.CodeSymbol)
+
+	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() || !macho.IsMachO(path) {
+			return err
+		}
+		symbols, err := macho.CodeSymbols(path)
+		if err != nil {
+			return nil // Unreadable images are analyzed elsewhere
+		}
+		if len(symbols) > 0 {
+			images[mapper.ToRelative(path)] = symbols
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, WrapError("duplicate-code", "reading symbol tables", err)
+	}
+
+	return FindDuplicateSymbols(images), nil
+}
+
+// duplicateSymbolSet is a group of symbols defined in the same images
+type duplicateSymbolSet struct {
+	images  []string
+	symbols []string
+	size    int64 // Code size of all copies but the largest
+}
+
+// FindDuplicateSymbols groups the symbols defined in more than one image by the images
+// that define them, and suggests linking each group only once. Each copy after the first
+// is duplicated code; a set is reported from minDuplicateCodeSize.
+func FindDuplicateSymbols(images map[string][]macho.CodeSymbol) []types.Optimization {
+	type definition struct {
+		image string
+		size  int64
+	}
+	definitions := make(map[string][]definition)
+	for image, symbols := range images {
+		seen := make(map[string]bool)
+		for _, sym := range symbols {
+			if seen[sym.Name] || isIgnoredSymbol(sym.Name) {
+				continue
+			}
+			seen[sym.Name] = true
+			definitions[sym.Name] = append(definitions[sym.Name], definition{image, sym.Size})
+		}
+	}
+
+	sets := make(map[string]*duplicateSymbolSet)
+	for name, defs := range definitions {
+		if len(defs) < 2 {
+			continue
+		}
+		sort.Slice(defs, func(i, j int) bool { return defs[i].image < defs[j].image })
+
+		imageNames := make([]string, len(defs))
+		var total, largest int64
+		for i, def := range defs {
+			imageNames[i] = def.image
+			total += def.size
+			if def.size > largest {
+				largest = def.size
+			}
+		}
+
+		key := strings.Join(imageNames, "\x00")
+		set := sets[key]
+		if set == nil {
+			set = &duplicateSymbolSet{images: imageNames}
+			sets[key] = set
+		}
+		set.symbols = append(set.symbols, name)
+		set.size += total - largest
+	}
+
+	var optimizations []types.Optimization
+	for _, set := range sets {
+		if set.size < minDuplicateCodeSize {
+			continue
+		}
+		sort.Strings(set.symbols)
+
+		examples := set.symbols
+		if len(examples) > maxExampleSymbols {
+			examples = examples[:maxExampleSymbols]
+		}
+
+		title := fmt.Sprintf("Code linked into %d images", len(set.images))
+		if library := symbolSetLibrary(set.symbols); library != "" {
+			title = fmt.Sprintf("%s linked into %d images", library, len(set.images))
+		}
+
+		optimizations = append(optimizations, types.Optimization{
+			Category: "duplicate-code",
+			Severity: duplicateCodeSeverity(set.size),
+			Title:    title,
+			Description: fmt.Sprintf("%d functions are defined in each of %s, duplicating %s of code, e.g. %s",
+				len(set.symbols), strings.Join(set.images, ", "), util.FormatBytes(set.size), strings.Join(examples, ", ")),
+			Impact: set.size,
+			Files:  set.images,
+			Action: "Link the static library into a single image: move it into a dynamic framework that the app " +
+				"and its extensions share, or remove it from the images that do not use it",
+		})
+	}
+
+	// Sort by impact (highest first)
+	sort.Slice(optimizations, func(i, j int) bool {
+		if optimizations[i].Impact != optimizations[j].Impact {
+			return optimizations[i].Impact > optimizations[j].Impact
+		}
+		return optimizations[i].Title < optimizations[j].Title
+	})
+
+	return optimizations
+}
+
+// isIgnoredSymbol reports whether a symbol is a compiler-generated helper, or a Swift
+// symbol in the context of the standard library or an imported type, which every image
+// using it defines
+func isIgnoredSymbol(name string) bool {
+	if name == "_main" {
+		return true
+	}
+	for _, prefix := range ignoredSymbolPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	trimmed := strings.TrimPrefix(name, "_")
+	if strings.HasPrefix(trimmed, "$s") || strings.HasPrefix(trimmed, "$S") {
+		return macho.SwiftSymbolModule(name) == ""
+	}
+	return false
+}
+
+// symbolSetLibrary names the library of a symbol set after the Swift module or
+// Objective-C class that more than half of its symbols belong to
+func symbolSetLibrary(symbols []string) string {
+	counts := make(map[string]int)
+	for _, name := range symbols {
+		if module := macho.SwiftSymbolModule(name); module != "" {
+			counts[module]++
+		} else if class := objcSymbolClass(name); class != "" {
+			counts[class]++
+		}
+	}
+
+	for name, count := range counts {
+		if count*2 > len(symbols) {
+			return name
+		}
+	}
+	return ""
+}
+
+// objcSymbolClass returns the class of an Objective-C method symbol, e.g. SDWebImageManager
+// for -[SDWebImageManager loadImageWithURL:] or -[NSString(SDAdditions) sd_md5]
+func objcSymbolClass(name string) string {
+	if !strings.HasPrefix(name, "-[") && !strings.HasPrefix(name, "+[") {
+		return ""
+	}
+	fields := strings.Fields(name[2:])
+	if len(fields) == 0 {
+		return ""
+	}
+	class := fields[0]
+	if i := strings.Index(class, "("); i >= 0 {
+		class = class[:i]
+	}
+	return class
+}
+
+// duplicateCodeSeverity rates duplicated code by its size
+func duplicateCodeSeverity(size int64) string {
+	if size >= 1024*1024 {
+		return "high"
+	}
+	if size >= 256*1024 {
+		return "medium"
+	}
+	return "low"
+}
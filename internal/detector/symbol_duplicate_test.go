@@ -0,0 +1,121 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/ios/macho"
+)
+
+func TestFindDuplicateSymbols(t *testing.T) {
+	kit := []macho.CodeSymbol{
+		{Name: "_$s7AcmeKit6ClientC4sendyyF", Size: 30000, External: true},
+		{Name: "_$s7AcmeKit6ClientC5closeyyF", Size: 10000},
+		{Name: "_$s7AcmeKit6ClientC5resetyyF", Size: 5000},
+		{Name: "_acme_checksum", Size: 2000},
+	}
+	images := map[string][]macho.CodeSymbol{
+		"Payload/App.app/App": append([]macho.CodeSymbol{
+			{Name: "_$s3App4mainyyF", Size: 500000},
+			{Name: "_main", Size: 100},
+			{Name: "_OUTLINED_FUNCTION_0", Size: 40000},
+			{Name: "_$sSa6appendyyxnF", Size: 40000},
+		}, kit...),
+		"Payload/App.app/PlugIns/Widget.appex/Widget": append([]macho.CodeSymbol{
+			{Name: "_main", Size: 100},
+			{Name: "_OUTLINED_FUNCTION_0", Size: 40000},
+			{Name: "_$sSa6appendyyxnF", Size: 40000},
+		}, kit...),
+		"Payload/App.app/PlugIns/Intents.appex/Intents": {
+			{Name: "_$s7AcmeKit6ClientC4sendyyF", Size: 30000},
+			{Name: "-[ACMELogger log:]", Size: 3000},
+		},
+		"Payload/App.app/Frameworks/Log.framework/Log": {
+			{Name: "-[ACMELogger log:]", Size: 3000},
+		},
+	}
+
+	opts := FindDuplicateSymbols(images)
+	if len(opts) != 2 {
+		t.Fatalf("got %d optimizations, want 2: %+v", len(opts), opts)
+	}
+
+	// AcmeKit.Client.send is in all three images: two duplicated copies
+	if opts[0].Title != "AcmeKit linked into 3 images" || opts[0].Impact != 60000 {
+		t.Errorf("first optimization = %q with impact %d, want AcmeKit in 3 images with 60000", opts[0].Title, opts[0].Impact)
+	}
+	wantImages := []string{
+		"Payload/App.app/App",
+		"Payload/App.app/PlugIns/Intents.appex/Intents",
+		"Payload/App.app/PlugIns/Widget.appex/Widget",
+	}
+	if !reflect.DeepEqual(opts[0].Files, wantImages) {
+		t.Errorf("files = %v, want %v", opts[0].Files, wantImages)
+	}
+
+	// Client.close, Client.reset and acme_checksum are only in the app and the widget
+	if opts[1].Title != "AcmeKit linked into 2 images" || opts[1].Impact != 17000 {
+		t.Errorf("second optimization = %q with impact %d, want AcmeKit in 2 images with 17000", opts[1].Title, opts[1].Impact)
+	}
+	if opts[1].Category != "duplicate-code" || !strings.Contains(opts[1].Description, "3 functions are defined in each of") {
+		t.Errorf("second optimization = %+v, want duplicate-code with the function count", opts[1])
+	}
+}
+
+func TestIsIgnoredSymbol(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"_main", true},
+		{"_OUTLINED_FUNCTION_12", true},
+		{"___swift_instantiateConcreteTypeFromMangledName", true},
+		{"___copy_helper_block_e8_32s", true},
+		{"ltmp3", true},
+		{"_$sSa6appendyyxnF", true},
+		{"_$s9Alamofire7SessionC7requestyyF", false},
+		{"-[SDImageCache storeImage:forKey:]", false},
+		{"_sqlite3_open", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isIgnoredSymbol(tt.name); got != tt.want {
+				t.Errorf("isIgnoredSymbol(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjcSymbolClass(t *testing.T) {
+	tests := map[string]string{
+		"-[SDWebImageManager loadImageWithURL:]": "SDWebImageManager",
+		"+[NSString(SDAdditions) sd_md5]":        "NSString",
+		"_sqlite3_open":                          "",
+		"-[":                                     "",
+	}
+
+	for name, want := range tests {
+		if got := objcSymbolClass(name); got != want {
+			t.Errorf("objcSymbolClass(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSymbolDuplicateDetector_NoMachO(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Info.plist"), []byte("<plist/>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := NewSymbolDuplicateDetector().Detect(dir)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if len(opts) != 0 {
+		t.Errorf("Detect() = %+v, want no optimizations", opts)
+	}
+}
@@ -0,0 +1,254 @@
+package detector
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// Alignments zipalign applies to stored entries: page alignment for native libraries
+// that are loaded straight from the APK, 4 bytes for everything else
+const (
+	nativeLibAlignment = 4096
+	storedAlignment    = 4
+)
+
+// minStoredSavings is the least a stored file must shrink by to be reported as compressible
+const minStoredSavings = 1024
+
+// compressedMediaExtensions are formats that are already compressed. aapt2 stores them
+// by default, since deflating them gains little and prevents memory-mapping.
+var compressedMediaExtensions = []string{
+	".jpg", ".jpeg", ".png", ".gif", ".webp", ".heic", ".heif", ".avif",
+	".wav", ".mp2", ".mp3", ".ogg", ".aac", ".m4a", ".amr", ".awb", ".wma", ".flac", ".opus",
+	".mpg", ".mpeg", ".mp4", ".m4v", ".3gp", ".3gpp", ".3g2", ".3gpp2", ".webm", ".mkv", ".wmv",
+	".mid", ".midi", ".smf", ".jet", ".rtttl", ".imy", ".xmf",
+	".zip", ".jar", ".gz", ".bz2", ".xz", ".br", ".zst", ".7z",
+}
+
+// zipStorageEntry is a ZIP entry flagged by the audit with its savings in bytes
+type zipStorageEntry struct {
+	path    string
+	savings int64
+}
+
+// ZipStorageDetector implements the Detector interface.
+// This detector is APK-only: it audits how each ZIP entry is stored, since the
+// compression method and alignment of an APK's entries decide what Android can
+// memory-map and what it has to decompress or extract at install time.
+type ZipStorageDetector struct {
+	artifactPath string
+	manifest     *types.ManifestInfo
+}
+
+// NewZipStorageDetector creates a ZIP storage detector for the APK at artifactPath.
+// manifest may be nil when the manifest could not be read.
+func NewZipStorageDetector(artifactPath string, manifest *types.ManifestInfo) *ZipStorageDetector {
+	return &ZipStorageDetector{
+		artifactPath: artifactPath,
+		manifest:     manifest,
+	}
+}
+
+// Name returns the detector name
+func (d *ZipStorageDetector) Name() string {
+	return "zip-storage"
+}
+
+// Detect audits the entries of the APK. rootPath, the extracted artifact, is not used:
+// compression methods and data offsets are only known from the archive itself.
+func (d *ZipStorageDetector) Detect(rootPath string) ([]types.Optimization, error) {
+	zr, err := zip.OpenReader(d.artifactPath)
+	if err != nil {
+		return nil, WrapError("zip-storage", "opening APK", err)
+	}
+	defer zr.Close()
+
+	return DetectZipStorage(&zr.Reader, d.manifest)
+}
+
+// DetectZipStorage flags DEFLATEd files that must be stored, stored files that are not
+// aligned as zipalign would, stored files that compress well, and already-compressed
+// media that is DEFLATEd. resources.arsc must be stored when targeting API 30 or higher
+// (or an unknown level), native libraries when extractNativeLibs is false.
+func DetectZipStorage(zr *zip.Reader, manifest *types.ManifestInfo) ([]types.Optimization, error) {
+	storeResourceTable := manifest == nil || manifest.TargetSDK == 0 || manifest.TargetSDK >= 30
+	storeNativeLibs := manifest != nil && manifest.ExtractNativeLibs != nil && !*manifest.ExtractNativeLibs
+
+	var mustStore, misaligned, compressible, deflatedMedia []zipStorageEntry
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		nativeLib := isNativeLibEntry(f.Name)
+		required := (f.Name == "resources.arsc" && storeResourceTable) || (nativeLib && storeNativeLibs)
+
+		switch f.Method {
+		case zip.Store:
+			alignment := int64(storedAlignment)
+			if nativeLib {
+				alignment = nativeLibAlignment
+			}
+			offset, err := f.DataOffset()
+			if err != nil {
+				return nil, WrapError("zip-storage", "reading data offset of "+f.Name, err)
+			}
+			if offset%alignment != 0 {
+				misaligned = append(misaligned, zipStorageEntry{path: f.Name})
+			}
+
+			if required || nativeLib || f.Name == "resources.arsc" || util.HasExtension(f.Name, compressedMediaExtensions...) {
+				continue
+			}
+			compressedSize, err := deflatedSize(f)
+			if err != nil {
+				return nil, WrapError("zip-storage", "compressing "+f.Name, err)
+			}
+			if savings := int64(f.UncompressedSize64) - compressedSize; savings >= minStoredSavings {
+				compressible = append(compressible, zipStorageEntry{path: f.Name, savings: savings})
+			}
+
+		case zip.Deflate:
+			switch {
+			case required:
+				mustStore = append(mustStore, zipStorageEntry{path: f.Name})
+			case util.HasExtension(f.Name, compressedMediaExtensions...) &&
+				f.CompressedSize64*100 >= f.UncompressedSize64*98:
+				// Less than 2% saved: storing costs (almost) nothing
+				savings := int64(f.CompressedSize64) - int64(f.UncompressedSize64)
+				if savings < 0 {
+					savings = 0
+				}
+				deflatedMedia = append(deflatedMedia, zipStorageEntry{path: f.Name, savings: savings})
+			}
+		}
+	}
+
+	var optimizations []types.Optimization
+	if len(mustStore) > 0 {
+		optimizations = append(optimizations, types.Optimization{
+			Category: "zip-storage",
+			Severity: "high",
+			Title:    fmt.Sprintf("Store %d files that Android expects uncompressed", len(mustStore)),
+			Description: "resources.arsc must be stored uncompressed for apps targeting Android 11 (API 30) or " +
+				"higher, and native libraries must be stored when extractNativeLibs is false. Compressed, they " +
+				"are decompressed into memory or fail to install instead of being memory-mapped from the APK.",
+			Files: zipStoragePaths(mustStore),
+			Action: "Keep resources.arsc out of custom compression steps, and package native libraries " +
+				"uncompressed with packaging.jniLibs.useLegacyPackaging = false",
+		})
+	}
+	if len(misaligned) > 0 {
+		optimizations = append(optimizations, types.Optimization{
+			Category: "zip-storage",
+			Severity: "high",
+			Title:    fmt.Sprintf("Align %d uncompressed files with zipalign", len(misaligned)),
+			Description: "Uncompressed native libraries must start on a 4 KB page boundary and other " +
+				"uncompressed files on a 4-byte boundary to be memory-mapped from the APK. " +
+				"Misaligned files are read into memory, and misaligned native libraries can fail to load.",
+			Files:  zipStoragePaths(misaligned),
+			Action: "Run zipalign -p -f 4 before signing with apksigner, or let the Android Gradle Plugin package the APK",
+		})
+	}
+	if len(compressible) > 0 {
+		savings := zipStorageSavings(compressible)
+		severity := "low"
+		if savings >= 100*1024 {
+			severity = "medium"
+		}
+		optimizations = append(optimizations, types.Optimization{
+			Category: "zip-storage",
+			Severity: severity,
+			Title:    fmt.Sprintf("Compress %d stored files", len(compressible)),
+			Description: fmt.Sprintf("%d files are stored uncompressed although DEFLATE shrinks them by %s, "+
+				"as measured by compressing each of them", len(compressible), util.FormatBytes(savings)),
+			Impact: savings,
+			Files:  zipStoragePaths(compressible),
+			Action: "Remove these extensions from androidResources.noCompress, or fix the packaging step that stores them",
+		})
+	}
+	if len(deflatedMedia) > 0 {
+		savings := zipStorageSavings(deflatedMedia)
+		description := fmt.Sprintf("%d media and archive files are DEFLATEd but shrink by less than 2%%. "+
+			"Stored, they can be memory-mapped and streamed without decompression", len(deflatedMedia))
+		if savings > 0 {
+			description += fmt.Sprintf(", and the APK shrinks by %s", util.FormatBytes(savings))
+		}
+		optimizations = append(optimizations, types.Optimization{
+			Category:    "zip-storage",
+			Severity:    "low",
+			Title:       fmt.Sprintf("Store %d already-compressed media files", len(deflatedMedia)),
+			Description: description + ".",
+			Impact:      savings,
+			Files:       zipStoragePaths(deflatedMedia),
+			Action:      "Add these extensions to androidResources.noCompress",
+		})
+	}
+
+	return optimizations, nil
+}
+
+// isNativeLibEntry reports whether a ZIP entry is a native library, e.g. lib/arm64-v8a/libapp.so
+func isNativeLibEntry(name string) bool {
+	return strings.HasPrefix(name, "lib/") && strings.HasSuffix(name, ".so")
+}
+
+// deflatedSize returns the size of a ZIP entry compressed with DEFLATE at the default level
+func deflatedSize(f *zip.File) (int64, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	counter := &countingWriter{}
+	fw, err := flate.NewWriter(counter, flate.DefaultCompression)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := io.Copy(fw, rc); err != nil {
+		return 0, err
+	}
+	if err := fw.Close(); err != nil {
+		return 0, err
+	}
+	return counter.n, nil
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int64
+}
+
+// Write implements io.Writer
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// zipStorageSavings sums the savings of flagged entries
+func zipStorageSavings(entries []zipStorageEntry) int64 {
+	var total int64
+	for _, e := range entries {
+		total += e.savings
+	}
+	return total
+}
+
+// zipStoragePaths returns the paths of flagged entries, largest savings first
+func zipStoragePaths(entries []zipStorageEntry) []string {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].savings > entries[j].savings
+	})
+	paths := make([]string, len(entries))
+	for i, e := range entries {
+		paths[i] = e.path
+	}
+	return paths
+}
@@ -0,0 +1,133 @@
+package detector
+
+import (
+	"archive/zip"
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// testZipEntry is a file of a test archive with its compression method
+type testZipEntry struct {
+	name   string
+	method uint16
+	data   []byte
+}
+
+// createTestZip writes the entries into an in-memory archive
+func createTestZip(t *testing.T, entries []testZipEntry) *zip.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: e.name, Method: e.method})
+		if err != nil {
+			t.Fatalf("CreateHeader() error = %v", err)
+		}
+		if _, err := w.Write(e.data); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	return zr
+}
+
+// findOptimization returns the optimization whose title starts with prefix, or nil
+func findOptimization(opts []types.Optimization, prefix string) *types.Optimization {
+	for i := range opts {
+		if strings.HasPrefix(opts[i].Title, prefix) {
+			return &opts[i]
+		}
+	}
+	return nil
+}
+
+func TestDetectZipStorage(t *testing.T) {
+	random := make([]byte, 8192)
+	rand.New(rand.NewSource(1)).Read(random)
+	json := []byte(strings.Repeat(`{"key": "value", "enabled": true}`, 300))
+
+	zr := createTestZip(t, []testZipEntry{
+		{"resources.arsc", zip.Deflate, make([]byte, 4096)},
+		{"lib/arm64-v8a/libapp.so", zip.Deflate, make([]byte, 4096)},
+		{"lib/x86_64/libapp.so", zip.Store, make([]byte, 4096)},
+		{"assets/config.json", zip.Store, json},
+		{"res/raw/intro.mp4", zip.Deflate, random},
+		{"res/raw/small.txt", zip.Store, []byte("tiny")},
+	})
+	disabled := false
+	opts, err := DetectZipStorage(zr, &types.ManifestInfo{TargetSDK: 34, ExtractNativeLibs: &disabled})
+	if err != nil {
+		t.Fatalf("DetectZipStorage() error = %v", err)
+	}
+	if len(opts) != 4 {
+		t.Fatalf("got %d optimizations, want 4: %+v", len(opts), opts)
+	}
+
+	if opt := findOptimization(opts, "Store 2 files that Android expects uncompressed"); opt == nil ||
+		opt.Severity != "high" || len(opt.Files) != 2 {
+		t.Errorf("must-store optimization = %+v", opt)
+	}
+
+	if opt := findOptimization(opts, "Align"); opt == nil || !containsString(opt.Files, "lib/x86_64/libapp.so") {
+		t.Errorf("alignment optimization = %+v, want the stored native library", opt)
+	}
+
+	var jsonSize, mediaCompressed int64
+	for _, f := range zr.File {
+		switch f.Name {
+		case "assets/config.json":
+			size, err := deflatedSize(f)
+			if err != nil {
+				t.Fatalf("deflatedSize() error = %v", err)
+			}
+			jsonSize = size
+		case "res/raw/intro.mp4":
+			mediaCompressed = int64(f.CompressedSize64)
+		}
+	}
+	if opt := findOptimization(opts, "Compress 1 stored files"); opt == nil ||
+		opt.Impact != int64(len(json))-jsonSize || opt.Files[0] != "assets/config.json" {
+		t.Errorf("compressible optimization = %+v, want %d bytes saved on config.json", opt, int64(len(json))-jsonSize)
+	}
+	if opt := findOptimization(opts, "Store 1 already-compressed media files"); opt == nil ||
+		opt.Impact != mediaCompressed-int64(len(random)) {
+		t.Errorf("media optimization = %+v, want %d bytes saved", opt, mediaCompressed-int64(len(random)))
+	}
+}
+
+func TestDetectZipStorage_LegacyTargets(t *testing.T) {
+	zr := createTestZip(t, []testZipEntry{
+		{"resources.arsc", zip.Deflate, make([]byte, 4096)},
+		{"lib/arm64-v8a/libapp.so", zip.Deflate, make([]byte, 4096)},
+	})
+
+	// Compressed resource tables are allowed below API 30, and libraries are extracted by default
+	opts, err := DetectZipStorage(zr, &types.ManifestInfo{TargetSDK: 29})
+	if err != nil {
+		t.Fatalf("DetectZipStorage() error = %v", err)
+	}
+	if len(opts) != 0 {
+		t.Errorf("got %+v, want no optimizations", opts)
+	}
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
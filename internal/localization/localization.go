@@ -0,0 +1,247 @@
+// Package localization inventories the locales of an artifact and finds the ones
+// outside of the languages an app supports
+package localization
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// DefaultLocale is the base locale of Android resources without a locale qualifier
+const DefaultLocale = "default"
+
+// legacyLanguageNames maps the English language names of old .lproj directories to language codes
+var legacyLanguageNames = map[string]string{
+	"english":  "en",
+	"french":   "fr",
+	"german":   "de",
+	"italian":  "it",
+	"japanese": "ja",
+	"spanish":  "es",
+	"dutch":    "nl",
+}
+
+// Collector accumulates localized content by locale while an artifact is walked
+type Collector struct {
+	locales map[string]*localeContent
+	tables  map[string]*table
+}
+
+// localeContent is the content collected for one locale
+type localeContent struct {
+	size       int64
+	sources    map[string]bool
+	firstParty bool // Whether any of the content belongs to the app rather than a framework or library
+}
+
+// table is a set of localized strings, e.g. Localizable.strings of one bundle or the
+// string resources of one Android module, with its keys by locale
+type table struct {
+	firstParty bool
+	keys       map[string]map[string]bool
+}
+
+// NewCollector creates an empty collector
+func NewCollector() *Collector {
+	return &Collector{
+		locales: make(map[string]*localeContent),
+		tables:  make(map[string]*table),
+	}
+}
+
+// AddSize attributes localized content from a source, such as an .lproj directory
+// or resource table, to a locale
+func (c *Collector) AddSize(locale, source string, size int64, firstParty bool) {
+	content := c.locales[locale]
+	if content == nil {
+		content = &localeContent{sources: make(map[string]bool)}
+		c.locales[locale] = content
+	}
+	content.size += size
+	if source != "" {
+		content.sources[source] = true
+	}
+	content.firstParty = content.firstParty || firstParty
+}
+
+// AddKeys records the string keys a locale translates in a table
+func (c *Collector) AddKeys(locale, tableName string, keys []string, firstParty bool) {
+	t := c.tables[tableName]
+	if t == nil {
+		t = &table{keys: make(map[string]map[string]bool)}
+		c.tables[tableName] = t
+	}
+	t.firstParty = t.firstParty || firstParty
+
+	localeKeys := t.keys[locale]
+	if localeKeys == nil {
+		localeKeys = make(map[string]bool, len(keys))
+		t.keys[locale] = localeKeys
+	}
+	for _, key := range keys {
+		localeKeys[key] = true
+	}
+}
+
+// Result returns the inventory relative to a base locale, or nil when nothing was collected.
+//
+// Missing keys are counted for the locales the app itself ships: against every table of
+// the app, and against framework tables the locale has a translation for.
+func (c *Collector) Result(baseLocale string) *types.LocalizationInfo {
+	if len(c.locales) == 0 {
+		return nil
+	}
+
+	info := &types.LocalizationInfo{BaseLocale: baseLocale}
+	for locale, content := range c.locales {
+		entry := types.LocaleInfo{
+			Locale:         locale,
+			Size:           content.size,
+			IsBase:         locale == baseLocale,
+			ThirdPartyOnly: !content.firstParty,
+		}
+		for source := range content.sources {
+			entry.Sources = append(entry.Sources, source)
+		}
+		sort.Strings(entry.Sources)
+
+		for _, t := range c.tables {
+			keys, translated := t.keys[locale]
+			entry.Keys += len(keys)
+
+			base := t.keys[baseLocale]
+			if entry.IsBase || entry.ThirdPartyOnly || len(base) == 0 || (!translated && !t.firstParty) {
+				continue
+			}
+			for key := range base {
+				if !keys[key] {
+					entry.MissingKeys++
+				}
+			}
+		}
+
+		info.TotalSize += entry.Size
+		info.Locales = append(info.Locales, entry)
+	}
+
+	sort.Slice(info.Locales, func(i, j int) bool {
+		if info.Locales[i].Size != info.Locales[j].Size {
+			return info.Locales[i].Size > info.Locales[j].Size
+		}
+		return info.Locales[i].Locale < info.Locales[j].Locale
+	})
+	return info
+}
+
+// Normalize converts a locale name as found in .lproj directories, resource qualifiers
+// and resource tables to a BCP 47 tag, e.g. "pt_BR", "pt-rBR" and "b+pt+BR" to "pt-BR".
+// Returns "" for names that are not locales.
+func Normalize(name string) string {
+	if code, ok := legacyLanguageNames[strings.ToLower(name)]; ok {
+		return code
+	}
+
+	var parts []string
+	if strings.HasPrefix(name, "b+") {
+		parts = strings.Split(strings.TrimPrefix(name, "b+"), "+")
+	} else {
+		parts = strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' })
+	}
+	if len(parts) == 0 || len(parts[0]) < 2 || len(parts[0]) > 3 || !isLetters(parts[0]) {
+		return ""
+	}
+
+	tag := []string{strings.ToLower(parts[0])}
+	for _, part := range parts[1:] {
+		switch {
+		case len(part) == 3 && (part[0] == 'r' || part[0] == 'R') && isLetters(part[1:]):
+			tag = append(tag, strings.ToUpper(part[1:])) // Android region qualifier, e.g. rBR
+		case len(part) == 4 && isLetters(part):
+			tag = append(tag, strings.ToUpper(part[:1])+strings.ToLower(part[1:])) // Script, e.g. Hans
+		case len(part) == 2 && isLetters(part):
+			tag = append(tag, strings.ToUpper(part))
+		default:
+			tag = append(tag, part) // Numeric regions like 419 and variants
+		}
+	}
+	return strings.Join(tag, "-")
+}
+
+// Language returns the language of a BCP 47 tag, e.g. "pt" for "pt-BR"
+func Language(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+	return language
+}
+
+// Allowed reports whether a locale is covered by an allow-list. A language entry such
+// as "pt" covers all of its regional variants; "pt-BR" covers only itself.
+func Allowed(locale string, allowList []string) bool {
+	for _, allowed := range allowList {
+		allowed = Normalize(allowed)
+		if strings.EqualFold(allowed, locale) || (!strings.Contains(allowed, "-") && allowed == Language(locale)) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateAllowListOptimization suggests removing the locales outside of an allow-list
+// of supported languages. The base locale and Android default resources are always kept.
+func GenerateAllowListOptimization(info *types.LocalizationInfo, allowList []string) *types.Optimization {
+	if info == nil || len(allowList) == 0 {
+		return nil
+	}
+
+	var removable []types.LocaleInfo
+	var impact int64
+	for _, locale := range info.Locales {
+		if locale.IsBase || locale.Locale == DefaultLocale || Allowed(locale.Locale, allowList) {
+			continue
+		}
+		removable = append(removable, locale)
+		impact += locale.Size
+	}
+	if len(removable) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(removable))
+	var files []string
+	seen := make(map[string]bool)
+	for _, locale := range removable {
+		names = append(names, locale.Locale)
+		for _, source := range locale.Sources {
+			if !seen[source] {
+				seen[source] = true
+				files = append(files, source)
+			}
+		}
+	}
+	sort.Strings(files)
+
+	return &types.Optimization{
+		Category: "localization",
+		Severity: "medium",
+		Title:    fmt.Sprintf("Remove %d unsupported locales", len(removable)),
+		Description: fmt.Sprintf("%d of %d locales are not in the supported languages (%s): %s",
+			len(removable), len(info.Locales), strings.Join(allowList, ", "), strings.Join(names, ", ")),
+		Impact: impact,
+		Files:  files,
+		Action: "On Android, set android.defaultConfig.resourceConfigurations (localeFilters in AGP 8.7+) " +
+			"to the supported languages. On iOS, remove the unsupported .lproj directories in a build phase " +
+			"or drop them from the localizations of the project and its packages",
+	}
+}
+
+// isLetters reports whether s consists of ASCII letters only
+func isLetters(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,118 @@
+package localization
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"en":          "en",
+		"pt_BR":       "pt-BR",
+		"pt-rBR":      "pt-BR",
+		"b+sr+Latn":   "sr-Latn",
+		"zh-hans":     "zh-Hans",
+		"es-419":      "es-419",
+		"fil":         "fil",
+		"English":     "en",
+		"Base":        "",
+		"drawable":    "",
+		"":            "",
+		"zh_Hant_TW":  "zh-Hant-TW",
+		"b+es+419":    "es-419",
+		"values-fr":   "",
+		"de-DE-1996x": "de-DE-1996x",
+	}
+	for input, want := range tests {
+		if got := Normalize(input); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	allowList := []string{"en", "pt_BR"}
+	tests := map[string]bool{
+		"en":    true,
+		"en-GB": true,
+		"pt-BR": true,
+		"pt-PT": false,
+		"pt":    false,
+		"de":    false,
+	}
+	for locale, want := range tests {
+		if got := Allowed(locale, allowList); got != want {
+			t.Errorf("Allowed(%q) = %v, want %v", locale, got, want)
+		}
+	}
+}
+
+func TestCollector_Result(t *testing.T) {
+	c := NewCollector()
+	c.AddSize("en", "en.lproj", 100, true)
+	c.AddKeys("en", "Localizable", []string{"title", "done"}, true)
+	c.AddSize("de", "de.lproj", 80, true)
+	c.AddKeys("de", "Localizable", []string{"title"}, true)
+	c.AddSize("en", "Frameworks/Kit.framework/en.lproj", 10, false)
+	c.AddKeys("en", "Kit", []string{"ok", "cancel"}, false)
+	c.AddSize("de", "Frameworks/Kit.framework/de.lproj", 10, false)
+	c.AddKeys("de", "Kit", []string{"ok", "cancel"}, false)
+	c.AddSize("ja", "Frameworks/Kit.framework/ja.lproj", 40, false)
+	c.AddKeys("ja", "Kit", []string{"ok"}, false)
+
+	info := c.Result("en")
+	if info.BaseLocale != "en" || info.TotalSize != 240 || len(info.Locales) != 3 {
+		t.Fatalf("Result() = %+v, want 3 locales totalling 240 bytes", info)
+	}
+
+	en, de, ja := info.Locales[0], info.Locales[1], info.Locales[2]
+	if en.Locale != "en" || !en.IsBase || en.Keys != 4 || en.MissingKeys != 0 {
+		t.Errorf("en = %+v, want the base locale with 4 keys", en)
+	}
+	if de.Locale != "de" || de.ThirdPartyOnly || de.MissingKeys != 1 || len(de.Sources) != 2 {
+		t.Errorf("de = %+v, want an app locale missing 1 key in 2 sources", de)
+	}
+	if ja.Locale != "ja" || !ja.ThirdPartyOnly || ja.MissingKeys != 0 {
+		t.Errorf("ja = %+v, want a third-party only locale without missing keys", ja)
+	}
+
+	if NewCollector().Result("en") != nil {
+		t.Error("Result() of an empty collector should be nil")
+	}
+}
+
+func TestGenerateAllowListOptimization(t *testing.T) {
+	info := &types.LocalizationInfo{
+		BaseLocale: "en",
+		Locales: []types.LocaleInfo{
+			{Locale: "en", Size: 500, IsBase: true, Sources: []string{"en.lproj"}},
+			{Locale: "de", Size: 400, Sources: []string{"de.lproj"}},
+			{Locale: "ja", Size: 300, ThirdPartyOnly: true, Sources: []string{"Frameworks/Kit.framework/ja.lproj"}},
+			{Locale: "ko", Size: 200, Sources: []string{"resources.arsc"}},
+			{Locale: "af", Size: 100, Sources: []string{"resources.arsc"}},
+		},
+	}
+
+	opt := GenerateAllowListOptimization(info, []string{"de"})
+	if opt == nil {
+		t.Fatal("GenerateAllowListOptimization() = nil")
+	}
+	if opt.Category != "localization" || opt.Impact != 600 {
+		t.Errorf("optimization = %+v, want localization with ja, ko and af as impact", opt)
+	}
+	if !strings.Contains(opt.Description, "3 of 5 locales") || !strings.Contains(opt.Description, "ja, ko, af") {
+		t.Errorf("description %q does not list the removable locales", opt.Description)
+	}
+	if len(opt.Files) != 2 {
+		t.Errorf("files = %v, want each source once", opt.Files)
+	}
+
+	if GenerateAllowListOptimization(info, nil) != nil {
+		t.Error("no optimization expected without an allow-list")
+	}
+	if GenerateAllowListOptimization(info, []string{"de", "ja", "ko", "af"}) != nil {
+		t.Error("no optimization expected when every locale is allowed")
+	}
+}
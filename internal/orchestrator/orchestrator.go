@@ -9,9 +9,14 @@ import (
 	"strings"
 
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/android"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/ios"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/analyzer/ios/assets"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/detector"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/localization"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/logger"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/ownership"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/sdk"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
 )
@@ -21,6 +26,21 @@ type Orchestrator struct {
 	IncludeDuplicates     bool
 	FilterSmallDuplicates bool
 	Logger                logger.Logger
+
+	// DeviceSpecs override the default device matrix for AAB split size estimation
+	DeviceSpecs []types.DeviceSpec
+
+	// Owners attributes sizes to teams when set
+	Owners *ownership.Rules
+
+	// SupportedLocales is the allow-list of locales; others are suggested for removal
+	SupportedLocales []string
+
+	// LockedDependencies from iOS lockfiles name and version the dependencies of iOS apps
+	LockedDependencies []ios.LockedDependency
+
+	// SDKSignatures override the built-in signatures for Android library attribution
+	SDKSignatures *sdk.Database
 }
 
 // New creates a new orchestrator with default settings
@@ -40,6 +60,16 @@ func (o *Orchestrator) RunAnalysis(ctx context.Context, artifactPath string) (*t
 		return nil, fmt.Errorf("failed to create analyzer: %w", err)
 	}
 
+	if aab, ok := a.(*android.AABAnalyzer); ok && len(o.DeviceSpecs) > 0 {
+		aab.DeviceSpecs = o.DeviceSpecs
+	}
+	switch ia := a.(type) {
+	case *ios.IPAAnalyzer:
+		ia.LockedDependencies = o.LockedDependencies
+	case *ios.AppAnalyzer:
+		ia.LockedDependencies = o.LockedDependencies
+	}
+
 	// Perform initial analysis
 	report, err := a.Analyze(ctx, artifactPath)
 	if err != nil {
@@ -57,16 +87,78 @@ func (o *Orchestrator) RunAnalysis(ctx context.Context, artifactPath string) (*t
 		}
 	}
 
+	// Audit the APK's ZIP entries, which does not need the extracted artifact
+	o.runArchiveDetectors(report)
+
+	if opt := localization.GenerateAllowListOptimization(report.Localization, o.SupportedLocales); opt != nil {
+		report.Optimizations = append(report.Optimizations, *opt)
+	}
+
 	// Generate optimization recommendations
 	report.Optimizations = o.generateOptimizations(report, platform)
 	report.TotalSavings = calculateTotalSavings(report)
 
+	if report.Android != nil {
+		o.attributeSDKs(report)
+	}
+
+	if o.Owners != nil {
+		report.Ownership = ownership.Attribute(report, o.Owners)
+	}
+
 	// Add Git/CI metadata if available
 	o.enrichWithCIMetadata(report)
 
+	report.SchemaVersion = types.SchemaVersion
+
 	return report, nil
 }
 
+// attributeSDKs sums the sizes of known libraries and adds their total to the size breakdown
+func (o *Orchestrator) attributeSDKs(report *types.Report) {
+	signatures := o.SDKSignatures
+	if signatures == nil {
+		signatures = sdk.Default()
+	}
+
+	report.Android.SDKs = sdk.Attribute(report.FileTree, signatures)
+	if total := sdk.TotalSize(report.Android.SDKs); total > 0 {
+		if report.SizeBreakdown.ByCategory == nil {
+			report.SizeBreakdown.ByCategory = make(map[string]int64)
+		}
+		report.SizeBreakdown.ByCategory[sdk.Category] = total
+	}
+}
+
+// ArtifactResult holds the outcome of analyzing one artifact in multi-artifact mode
+type ArtifactResult struct {
+	Path   string
+	Report *types.Report
+	Err    error
+}
+
+// RunMultiAnalysis analyzes every artifact in order. A failing artifact is recorded
+// in its result and does not stop the analysis of the remaining ones.
+func (o *Orchestrator) RunMultiAnalysis(ctx context.Context, artifactPaths []string) []ArtifactResult {
+	results := make([]ArtifactResult, 0, len(artifactPaths))
+
+	for i, artifactPath := range artifactPaths {
+		if err := ctx.Err(); err != nil {
+			results = append(results, ArtifactResult{Path: artifactPath, Err: err})
+			continue
+		}
+
+		o.Logger.Info("Analyzing artifact %d/%d: %s", i+1, len(artifactPaths), artifactPath)
+		report, err := o.RunAnalysis(ctx, artifactPath)
+		if err != nil {
+			o.Logger.Warn("analysis of %s failed: %v", artifactPath, err)
+		}
+		results = append(results, ArtifactResult{Path: artifactPath, Report: report, Err: err})
+	}
+
+	return results
+}
+
 // runDetectors executes duplicate detection and additional optimization detectors
 func (o *Orchestrator) runDetectors(report *types.Report, artifactPath string, platform detector.Platform) error {
 
@@ -143,6 +235,7 @@ func (o *Orchestrator) runAdditionalDetectors(report *types.Report, extractPath
 		detectors = append(detectors, detector.NewUnnecessaryFilesDetector())
 		detectors = append(detectors, detector.NewLooseImagesDetector())
 		detectors = append(detectors, detector.NewSmallFilesDetector())
+		detectors = append(detectors, detector.NewSymbolDuplicateDetector())
 	}
 
 	for _, d := range detectors {
@@ -155,6 +248,26 @@ func (o *Orchestrator) runAdditionalDetectors(report *types.Report, extractPath
 	}
 }
 
+// runArchiveDetectors runs the detectors that read the artifact archive itself
+func (o *Orchestrator) runArchiveDetectors(report *types.Report) {
+	// Compression methods and alignment only matter for installable APKs
+	if report.ArtifactInfo.Type != types.ArtifactTypeAPK {
+		return
+	}
+
+	var manifest *types.ManifestInfo
+	if report.Android != nil {
+		manifest = report.Android.Manifest
+	}
+	d := detector.NewZipStorageDetector(report.ArtifactInfo.Path, manifest)
+	opts, err := d.Detect(report.ArtifactInfo.Path)
+	if err != nil {
+		o.Logger.Warn("%s detector failed: %v", d.Name(), err)
+		return
+	}
+	report.Optimizations = append(report.Optimizations, opts...)
+}
+
 // detectPlatform maps artifact type to detector platform
 func (o *Orchestrator) detectPlatform(artifactType types.ArtifactType) detector.Platform {
 	if o.isIOSArtifact(artifactType) {
@@ -307,79 +420,64 @@ func calculateTotalSavings(report *types.Report) int64 {
 
 // enrichWithCIMetadata adds Git/CI metadata from environment variables
 func (o *Orchestrator) enrichWithCIMetadata(report *types.Report) {
-	if report.Metadata == nil {
-		report.Metadata = make(map[string]interface{})
-	}
+	ci := &types.CIInfo{}
 
 	// Try Bitrise environment variables first
 	if branch := os.Getenv("BITRISE_GIT_BRANCH"); branch != "" {
-		report.Metadata["git_branch"] = branch
+		ci.Branch = branch
 	} else if branch := os.Getenv("GIT_BRANCH"); branch != "" {
-		report.Metadata["git_branch"] = branch
+		ci.Branch = branch
 	}
 
-	// Use commit_hash key to match markdown formatter expectations
 	// Try Bitrise environment variable first (GIT_CLONE_COMMIT_HASH)
 	if commit := os.Getenv("GIT_CLONE_COMMIT_HASH"); commit != "" {
-		report.Metadata["commit_hash"] = commit
+		ci.CommitHash = commit
 	} else if commit := os.Getenv("BITRISE_GIT_COMMIT"); commit != "" {
-		report.Metadata["commit_hash"] = commit
+		ci.CommitHash = commit
 	} else if commit := os.Getenv("GIT_COMMIT"); commit != "" {
-		report.Metadata["commit_hash"] = commit
+		ci.CommitHash = commit
 	}
-}
 
-// detectAssetDuplicates extracts asset catalogs from report metadata and detects duplicates.
-func (o *Orchestrator) detectAssetDuplicates(report *types.Report) []types.DuplicateSet {
-	if report.Metadata == nil {
-		return nil
+	if ci.Branch != "" || ci.CommitHash != "" {
+		report.CI = ci
 	}
+}
 
-	// Extract asset catalogs from metadata
-	catalogsInterface, ok := report.Metadata["asset_catalogs"]
-	if !ok {
+// detectAssetDuplicates converts the asset catalogs of an iOS report and detects duplicates.
+func (o *Orchestrator) detectAssetDuplicates(report *types.Report) []types.DuplicateSet {
+	if report.IOS == nil {
 		return nil
 	}
 
 	// Convert to internal asset catalog type
 	var internalCatalogs []*assets.AssetCatalogInfo
-
-	switch catalogs := catalogsInterface.(type) {
-	case []*types.AssetCatalogInfo:
-		// Convert types.AssetCatalogInfo to assets.AssetCatalogInfo
-		for _, tc := range catalogs {
-			if tc == nil {
-				continue
-			}
-			ic := &assets.AssetCatalogInfo{
-				Path:       tc.Path,
-				TotalSize:  tc.TotalSize,
-				AssetCount: tc.AssetCount,
-				ByType:     tc.ByType,
-				ByScale:    tc.ByScale,
-			}
-			// Convert assets
-			for _, ta := range tc.Assets {
-				ic.Assets = append(ic.Assets, assets.AssetInfo{
-					Name:          ta.Name,
-					RenditionName: ta.RenditionName,
-					Type:          ta.Type,
-					Scale:         ta.Scale,
-					Size:          ta.Size,
-					Idiom:         ta.Idiom,
-					Compression:   ta.Compression,
-					PixelWidth:    ta.PixelWidth,
-					PixelHeight:   ta.PixelHeight,
-					SHA1Digest:    ta.SHA1Digest,
-				})
-			}
-			internalCatalogs = append(internalCatalogs, ic)
+	for _, tc := range report.IOS.AssetCatalogs {
+		if tc == nil {
+			continue
 		}
-	case []*assets.AssetCatalogInfo:
-		internalCatalogs = catalogs
-	default:
-		o.Logger.Warn("asset_catalogs metadata has unexpected type: %T", catalogsInterface)
-		return nil
+		ic := &assets.AssetCatalogInfo{
+			Path:       tc.Path,
+			TotalSize:  tc.TotalSize,
+			AssetCount: tc.AssetCount,
+			ByType:     tc.ByType,
+			ByScale:    tc.ByScale,
+		}
+		// Convert assets
+		for _, ta := range tc.Assets {
+			ic.Assets = append(ic.Assets, assets.AssetInfo{
+				Name:          ta.Name,
+				RenditionName: ta.RenditionName,
+				Type:          ta.Type,
+				Scale:         ta.Scale,
+				Size:          ta.Size,
+				Idiom:         ta.Idiom,
+				Compression:   ta.Compression,
+				PixelWidth:    ta.PixelWidth,
+				PixelHeight:   ta.PixelHeight,
+				SHA1Digest:    ta.SHA1Digest,
+			})
+		}
+		internalCatalogs = append(internalCatalogs, ic)
 	}
 
 	if len(internalCatalogs) == 0 {
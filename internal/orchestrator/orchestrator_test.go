@@ -1,11 +1,14 @@
 package orchestrator
 
 import (
+	"archive/zip"
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/detector"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/logger"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
 )
 
@@ -412,3 +415,129 @@ func TestDetectPlatform(t *testing.T) {
 		})
 	}
 }
+
+func TestRunMultiAnalysis(t *testing.T) {
+	tmpDir := t.TempDir()
+	apkPath := filepath.Join(tmpDir, "app-release.apk")
+	createTestZip(t, apkPath, map[string]int{
+		"AndroidManifest.xml": 100,
+		"res/raw/data.bin":    2000,
+	})
+
+	orch := New()
+	orch.Logger = logger.NewSilentLogger()
+
+	results := orch.RunMultiAnalysis(context.Background(), []string{
+		apkPath,
+		filepath.Join(tmpDir, "missing.ipa"),
+	})
+
+	if len(results) != 2 {
+		t.Fatalf("RunMultiAnalysis() returned %d results, want 2", len(results))
+	}
+
+	if results[0].Err != nil {
+		t.Fatalf("first artifact failed: %v", results[0].Err)
+	}
+	if results[0].Report == nil || results[0].Report.ArtifactInfo.Type != types.ArtifactTypeAPK {
+		t.Errorf("first result has unexpected report: %+v", results[0].Report)
+	}
+
+	if results[1].Err == nil {
+		t.Error("expected error for missing artifact")
+	}
+	if results[1].Path != filepath.Join(tmpDir, "missing.ipa") {
+		t.Errorf("second result path = %s", results[1].Path)
+	}
+}
+
+func TestRunMultiAnalysis_CancelledContext(t *testing.T) {
+	orch := New()
+	orch.Logger = logger.NewSilentLogger()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := orch.RunMultiAnalysis(ctx, []string{"a.apk", "b.apk"})
+	for _, r := range results {
+		if r.Err == nil {
+			t.Errorf("expected context error for %s", r.Path)
+		}
+	}
+}
+
+func TestRunAnalysis_ZipStorageWithoutDuplicates(t *testing.T) {
+	apkPath := filepath.Join(t.TempDir(), "app-release.apk")
+	createTestZip(t, apkPath, map[string]int{
+		"AndroidManifest.xml": 100,
+		"resources.arsc":      2000,
+	})
+
+	orch := New()
+	orch.Logger = logger.NewSilentLogger()
+	orch.IncludeDuplicates = false
+
+	report, err := orch.RunAnalysis(context.Background(), apkPath)
+	if err != nil {
+		t.Fatalf("RunAnalysis() error = %v", err)
+	}
+
+	// The DEFLATEd resources.arsc is flagged although duplicate detection is disabled
+	found := false
+	for _, opt := range report.Optimizations {
+		if opt.Category == "zip-storage" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("optimizations = %+v, want a zip-storage audit", report.Optimizations)
+	}
+}
+
+// createTestZip writes a ZIP archive with files of the given sizes
+func createTestZip(t *testing.T, path string, files map[string]int) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, size := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(make([]byte, size)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAttributeSDKs(t *testing.T) {
+	report := &types.Report{
+		Android: &types.AndroidDetails{},
+		FileTree: []*types.FileNode{
+			{Path: "lib", Name: "lib", IsDir: true, Children: []*types.FileNode{
+				{Path: "lib/arm64-v8a", Name: "arm64-v8a", IsDir: true, Children: []*types.FileNode{
+					{Path: "lib/arm64-v8a/libflutter.so", Name: "libflutter.so", Size: 9000},
+					{Path: "lib/arm64-v8a/libapp.so", Name: "libapp.so", Size: 4000},
+				}},
+			}},
+		},
+	}
+
+	New().attributeSDKs(report)
+
+	if len(report.Android.SDKs) != 1 || report.Android.SDKs[0].Name != "Flutter" {
+		t.Fatalf("SDKs = %+v, want Flutter only", report.Android.SDKs)
+	}
+	if got := report.SizeBreakdown.ByCategory["sdk"]; got != 9000 {
+		t.Errorf("ByCategory[sdk] = %d, want 9000", got)
+	}
+}
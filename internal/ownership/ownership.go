@@ -0,0 +1,241 @@
+// Package ownership attributes artifact contents to teams with a CODEOWNERS-style file
+package ownership
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// Unowned is the owner of files that match no rule
+const Unowned = "(unowned)"
+
+// Rule maps a path pattern to an owner
+type Rule struct {
+	Pattern string
+	Owner   string
+
+	segments []string
+	anchored bool // Pattern starts with "/" and only matches from the artifact root
+	dirOnly  bool // Pattern ends with "/" and only matches directories
+}
+
+// Rules is an ordered list of ownership rules. As in CODEOWNERS, the last matching rule wins.
+type Rules struct {
+	Rules []Rule
+}
+
+// Load reads an ownership file
+func Load(filename string) (*Rules, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ownership file: %w", err)
+	}
+	defer f.Close()
+
+	rules, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ownership file %s: %w", filename, err)
+	}
+	return rules, nil
+}
+
+// Parse reads ownership rules, one "<pattern> <owner>" pair per line.
+// Empty lines and lines starting with # are ignored.
+//
+// Patterns are paths within the artifact, e.g. Frameworks/Payments*.framework/**,
+// res/raw/onboarding_* or com/acme/checkout/** for DEX classes. * and ? match within
+// a path segment and ** matches any number of segments. A pattern matches at any depth,
+// so res/raw/* also matches base/res/raw/* of an app bundle, unless it starts with /.
+// A pattern that matches a directory also matches everything in it.
+func Parse(r io.Reader) (*Rules, error) {
+	rules := &Rules{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected a pattern and one owner, got %q", lineNumber, line)
+		}
+		rule, err := newRule(fields[0], fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		rules.Rules = append(rules.Rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// newRule parses the pattern of a rule
+func newRule(pattern, owner string) (Rule, error) {
+	rule := Rule{Pattern: pattern, Owner: owner}
+
+	trimmed := pattern
+	if strings.HasPrefix(trimmed, "/") {
+		rule.anchored = true
+		trimmed = strings.TrimPrefix(trimmed, "/")
+	}
+	if strings.HasSuffix(trimmed, "/") {
+		rule.dirOnly = true
+		trimmed = strings.TrimSuffix(trimmed, "/")
+	}
+	if trimmed == "" {
+		return Rule{}, fmt.Errorf("empty pattern %q", pattern)
+	}
+
+	rule.segments = strings.Split(trimmed, "/")
+	for _, segment := range rule.segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return Rule{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return rule, nil
+}
+
+// Owner returns the owner of a path, or Unowned if no rule matches it
+func (r *Rules) Owner(filePath string) string {
+	segments := strings.Split(strings.Trim(filePath, "/"), "/")
+	for i := len(r.Rules) - 1; i >= 0; i-- {
+		if r.Rules[i].matches(segments) {
+			return r.Rules[i].Owner
+		}
+	}
+	return Unowned
+}
+
+// matches reports whether the rule matches a path or one of its parent directories
+func (rule *Rule) matches(segments []string) bool {
+	starts := len(segments)
+	if rule.anchored {
+		starts = 1
+	}
+	for start := 0; start < starts; start++ {
+		for end := start + 1; end <= len(segments); end++ {
+			if rule.dirOnly && end == len(segments) {
+				break
+			}
+			if matchSegments(rule.segments, segments[start:end]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchSegments matches pattern segments against path segments, with ** matching
+// any number of path segments
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+// ipaPayloadPrefix matches the prefix of paths reported by detectors for IPAs,
+// which are relative to the archive root instead of the app bundle
+var ipaPayloadPrefix = regexp.MustCompile(`^Payload/[^/]+\.app/`)
+
+// Attribute sums the file tree sizes, optimization impact and duplicate waste of a report
+// per owner, largest owner first. Optimization impact and duplicate waste are split evenly
+// between the files involved.
+func Attribute(report *types.Report, rules *Rules) []types.OwnerSize {
+	byOwner := make(map[string]*types.OwnerSize)
+	get := func(owner string) *types.OwnerSize {
+		if byOwner[owner] == nil {
+			byOwner[owner] = &types.OwnerSize{Owner: owner}
+		}
+		return byOwner[owner]
+	}
+
+	var walk func(nodes []*types.FileNode)
+	walk = func(nodes []*types.FileNode) {
+		for _, node := range nodes {
+			if node == nil {
+				continue
+			}
+			if len(node.Children) > 0 {
+				walk(node.Children)
+				continue
+			}
+			if node.IsDir {
+				continue
+			}
+			owner := get(rules.Owner(node.Path))
+			owner.Size += node.Size
+			owner.FileCount++
+		}
+	}
+	walk(report.FileTree)
+
+	for _, opt := range report.Optimizations {
+		for owner, share := range splitByOwner(opt.Impact, opt.Files, rules) {
+			get(owner).PotentialSavings += share
+			get(owner).Optimizations++
+		}
+	}
+
+	for _, dup := range report.Duplicates {
+		for owner, share := range splitByOwner(dup.WastedSize, dup.Files, rules) {
+			get(owner).DuplicateWaste += share
+		}
+	}
+
+	result := make([]types.OwnerSize, 0, len(byOwner))
+	for _, owner := range byOwner {
+		result = append(result, *owner)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Size != result[j].Size {
+			return result[i].Size > result[j].Size
+		}
+		return result[i].Owner < result[j].Owner
+	})
+	return result
+}
+
+// splitByOwner splits an amount evenly between the owners of the given files.
+// The remainder of the division goes to the owner of the first file, and an
+// amount without files is unowned.
+func splitByOwner(amount int64, files []string, rules *Rules) map[string]int64 {
+	if len(files) == 0 {
+		return map[string]int64{Unowned: amount}
+	}
+
+	shares := make(map[string]int64)
+	share := amount / int64(len(files))
+	for _, file := range files {
+		shares[rules.Owner(ipaPayloadPrefix.ReplaceAllString(file, ""))] += share
+	}
+	shares[rules.Owner(ipaPayloadPrefix.ReplaceAllString(files[0], ""))] += amount - share*int64(len(files))
+	return shares
+}
@@ -0,0 +1,130 @@
+package ownership
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+const testOwners = `
+# Payments owns its frameworks
+Frameworks/Payments*.framework/**  payments
+
+res/raw/onboarding_*               onboarding
+com/acme/checkout/**               checkout
+/Assets.car                        design
+PlugIns/                           extensions
+*.lproj                            localization
+Frameworks/PaymentsUI.framework    design
+`
+
+func mustParse(t *testing.T, input string) *Rules {
+	t.Helper()
+	rules, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	return rules
+}
+
+func TestRules_Owner(t *testing.T) {
+	rules := mustParse(t, testOwners)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"Frameworks/PaymentsCore.framework/PaymentsCore", "payments"},
+		{"Frameworks/PaymentsCore.framework/Info.plist", "payments"},
+		{"Frameworks/Analytics.framework/Analytics", Unowned},
+		{"res/raw/onboarding_intro.mp4", "onboarding"},
+		{"base/res/raw/onboarding_intro.mp4", "onboarding"},
+		{"base/res/raw/splash.mp4", Unowned},
+		{"Dex/com/acme/checkout/CartActivity.class", "checkout"},
+		{"Dex/com/acme/checkout/ui/Button.class", "checkout"},
+		{"Dex/com/acme/home/HomeActivity.class", Unowned},
+		{"Assets.car", "design"},
+		{"Assets.car/AppIcon", "design"},
+		{"Frameworks/Foo.framework/Assets.car", Unowned},
+		{"PlugIns/Widget.appex/Widget", "extensions"},
+		{"PlugIns", Unowned},
+		{"en.lproj/Localizable.strings", "localization"},
+		{"Frameworks/Foo.framework/de.lproj/Foo.strings", "localization"},
+		// The later rule wins over Frameworks/Payments*.framework/**
+		{"Frameworks/PaymentsUI.framework/PaymentsUI", "design"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := rules.Owner(tt.path); got != tt.want {
+				t.Errorf("Owner(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"missing owner", "Frameworks/**", "line 1: expected a pattern and one owner"},
+		{"several owners", "\n# comment\nFrameworks/** payments checkout", "line 3: expected a pattern and one owner"},
+		{"invalid pattern", "Frameworks/[a payments", "invalid pattern"},
+		{"empty pattern", "/ payments", "empty pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	rules := mustParse(t, testOwners)
+	report := &types.Report{
+		ArtifactInfo: types.ArtifactInfo{Type: types.ArtifactTypeIPA},
+		FileTree: []*types.FileNode{
+			{Name: "Frameworks", Path: "Frameworks", IsDir: true, Children: []*types.FileNode{
+				{Name: "PaymentsCore.framework", Path: "Frameworks/PaymentsCore.framework", IsDir: true, Children: []*types.FileNode{
+					{Name: "PaymentsCore", Path: "Frameworks/PaymentsCore.framework/PaymentsCore", Size: 1000},
+					{Name: "Info.plist", Path: "Frameworks/PaymentsCore.framework/Info.plist", Size: 10},
+				}},
+			}},
+			{Name: "Assets.car", Path: "Assets.car", Size: 500, Children: []*types.FileNode{
+				{Name: "AppIcon", Path: "Assets.car/AppIcon", Size: 300, IsVirtual: true},
+				{Name: "Logo", Path: "Assets.car/Logo", Size: 200, IsVirtual: true},
+			}},
+			{Name: "MyApp", Path: "MyApp", Size: 2000},
+			{Name: "Empty", Path: "Empty", IsDir: true},
+		},
+		Optimizations: []types.Optimization{
+			// Detectors report IPA paths relative to the archive root
+			{Title: "Duplicate", Impact: 101, Files: []string{
+				"Payload/MyApp.app/Frameworks/PaymentsCore.framework/Info.plist",
+				"Payload/MyApp.app/Assets.car",
+			}},
+			{Title: "Strip symbols", Impact: 40},
+		},
+		Duplicates: []types.DuplicateSet{
+			{Files: []string{"Frameworks/PaymentsCore.framework/logo.png", "Frameworks/Other.framework/logo.png"}, WastedSize: 60},
+		},
+	}
+
+	got := Attribute(report, rules)
+	want := []types.OwnerSize{
+		{Owner: Unowned, Size: 2000, FileCount: 1, Optimizations: 1, PotentialSavings: 40, DuplicateWaste: 30},
+		{Owner: "payments", Size: 1010, FileCount: 2, Optimizations: 1, PotentialSavings: 51, DuplicateWaste: 30},
+		{Owner: "design", Size: 500, FileCount: 2, Optimizations: 1, PotentialSavings: 50},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Attribute() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// bitbucketClient comments on Bitbucket Cloud pull requests
+type bitbucketClient struct {
+	api  *apiClient
+	repo string // workspace/repo_slug
+	id   string
+}
+
+type bitbucketContent struct {
+	Raw string `json:"raw"`
+}
+
+type bitbucketComment struct {
+	ID      int64            `json:"id"`
+	Content bitbucketContent `json:"content"`
+	Deleted bool             `json:"deleted"`
+}
+
+type bitbucketCommentPage struct {
+	Values []bitbucketComment `json:"values"`
+	Next   string             `json:"next"`
+}
+
+type bitbucketCommentRequest struct {
+	Content bitbucketContent `json:"content"`
+}
+
+func (c *bitbucketClient) ListComments(ctx context.Context) ([]Comment, error) {
+	var comments []Comment
+
+	// Bitbucket returns the absolute URL of the next page
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments?pagelen=100", c.repo, c.id)
+	for page := 1; page <= maxPages && path != ""; page++ {
+		var batch bitbucketCommentPage
+		if err := c.api.do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, comment := range batch.Values {
+			if comment.Deleted {
+				continue
+			}
+			comments = append(comments, Comment{ID: comment.ID, Body: comment.Content.Raw})
+		}
+		path = batch.Next
+	}
+	return comments, nil
+}
+
+func (c *bitbucketClient) CreateComment(ctx context.Context, body string) error {
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments", c.repo, c.id)
+	return c.api.do(ctx, http.MethodPost, path, bitbucketCommentRequest{Content: bitbucketContent{Raw: body}}, nil)
+}
+
+func (c *bitbucketClient) UpdateComment(ctx context.Context, id int64, body string) error {
+	path := fmt.Sprintf("/repositories/%s/pullrequests/%s/comments/%d", c.repo, c.id, id)
+	return c.api.do(ctx, http.MethodPut, path, bitbucketCommentRequest{Content: bitbucketContent{Raw: body}}, nil)
+}
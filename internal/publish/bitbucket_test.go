@@ -0,0 +1,95 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBitbucketClient_Publish(t *testing.T) {
+	const commentsPath = "/repositories/workspace/app/pullrequests/3/comments"
+	var serverURL string
+	var created bitbucketCommentRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == commentsPath && r.URL.Query().Get("page") == "":
+			// First page links to the second through an absolute URL
+			json.NewEncoder(w).Encode(bitbucketCommentPage{
+				Values: []bitbucketComment{{ID: 1, Content: bitbucketContent{Raw: "Nice"}}},
+				Next:   serverURL + commentsPath + "?page=2",
+			})
+		case r.Method == http.MethodGet && r.URL.Path == commentsPath && r.URL.Query().Get("page") == "2":
+			json.NewEncoder(w).Encode(bitbucketCommentPage{
+				Values: []bitbucketComment{{ID: 2, Content: bitbucketContent{Raw: CommentMarker + "\nold"}, Deleted: true}},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == commentsPath:
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	client, err := NewClient(Config{
+		Provider:    ProviderBitbucket,
+		BaseURL:     server.URL,
+		Repository:  "workspace/app",
+		PullRequest: "3",
+		Token:       "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	// The only marked comment is deleted, so a new one is created
+	result, err := Publish(context.Background(), client, "report")
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if result != ResultCreated {
+		t.Errorf("Publish() = %s, want created", result)
+	}
+	if created.Content.Raw != CommentMarker+"\nreport" {
+		t.Errorf("created body = %q", created.Content.Raw)
+	}
+}
+
+func TestBitbucketClient_ForeignNextPage(t *testing.T) {
+	foreign := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request sent to a foreign host with Authorization %q", r.Header.Get("Authorization"))
+	}))
+	defer foreign.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(bitbucketCommentPage{Next: foreign.URL + "/comments?page=2"})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{
+		Provider:    ProviderBitbucket,
+		BaseURL:     server.URL,
+		Repository:  "workspace/app",
+		PullRequest: "3",
+		Token:       "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	_, err = client.ListComments(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "refusing to follow") {
+		t.Errorf("ListComments() error = %v, want a refused next page", err)
+	}
+}
@@ -0,0 +1,49 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// gitHubClient comments on GitHub pull requests through the issues API
+type gitHubClient struct {
+	api    *apiClient
+	repo   string
+	number string
+}
+
+type gitHubComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+func (c *gitHubClient) ListComments(ctx context.Context) ([]Comment, error) {
+	const perPage = 100
+
+	var comments []Comment
+	for page := 1; page <= maxPages; page++ {
+		var batch []gitHubComment
+		path := fmt.Sprintf("/repos/%s/issues/%s/comments?per_page=%d&page=%d", c.repo, c.number, perPage, page)
+		if err := c.api.do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, comment := range batch {
+			comments = append(comments, Comment{ID: comment.ID, Body: comment.Body})
+		}
+		if len(batch) < perPage {
+			break
+		}
+	}
+	return comments, nil
+}
+
+func (c *gitHubClient) CreateComment(ctx context.Context, body string) error {
+	path := fmt.Sprintf("/repos/%s/issues/%s/comments", c.repo, c.number)
+	return c.api.do(ctx, http.MethodPost, path, map[string]string{"body": body}, nil)
+}
+
+func (c *gitHubClient) UpdateComment(ctx context.Context, id int64, body string) error {
+	path := fmt.Sprintf("/repos/%s/issues/comments/%d", c.repo, id)
+	return c.api.do(ctx, http.MethodPatch, path, map[string]string{"body": body}, nil)
+}
@@ -0,0 +1,137 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeGitHub is a minimal stand-in for the GitHub issue comments API
+type fakeGitHub struct {
+	mu       sync.Mutex
+	comments []gitHubComment
+	nextID   int64
+	requests []string
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+		return
+	}
+
+	const listPath = "/repos/acme/app/issues/42/comments"
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == listPath:
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		start := (page - 1) * perPage
+		end := start + perPage
+		if start > len(f.comments) {
+			start = len(f.comments)
+		}
+		if end > len(f.comments) {
+			end = len(f.comments)
+		}
+		json.NewEncoder(w).Encode(f.comments[start:end])
+	case r.Method == http.MethodPost && r.URL.Path == listPath:
+		var req gitHubComment
+		json.NewDecoder(r.Body).Decode(&req)
+		f.nextID++
+		f.comments = append(f.comments, gitHubComment{ID: f.nextID, Body: req.Body})
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/repos/acme/app/issues/comments/"):
+		id, _ := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/repos/acme/app/issues/comments/"), 10, 64)
+		var req gitHubComment
+		json.NewDecoder(r.Body).Decode(&req)
+		for i := range f.comments {
+			if f.comments[i].ID == id {
+				f.comments[i].Body = req.Body
+				w.Write([]byte(`{}`))
+				return
+			}
+		}
+		http.NotFound(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestGitHubClient_Publish(t *testing.T) {
+	fake := &fakeGitHub{nextID: 1000}
+	// Fill more than one page of unrelated comments
+	for i := 0; i < 120; i++ {
+		fake.comments = append(fake.comments, gitHubComment{ID: int64(i + 1), Body: fmt.Sprintf("comment %d", i)})
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := NewClient(Config{
+		Provider:    ProviderGitHub,
+		BaseURL:     server.URL,
+		Repository:  "acme/app",
+		PullRequest: "42",
+		Token:       "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	ctx := context.Background()
+	for i, want := range []Result{ResultCreated, ResultUnchanged} {
+		result, err := Publish(ctx, client, "## Bitrise Report")
+		if err != nil {
+			t.Fatalf("Publish() #%d error = %v", i, err)
+		}
+		if result != want {
+			t.Errorf("Publish() #%d = %s, want %s", i, result, want)
+		}
+	}
+
+	result, err := Publish(ctx, client, "## Bitrise Report v2")
+	if err != nil || result != ResultUpdated {
+		t.Fatalf("Publish() = %s, %v, want updated", result, err)
+	}
+
+	var marked int
+	for _, c := range fake.comments {
+		if strings.Contains(c.Body, CommentMarker) {
+			marked++
+			if !strings.HasSuffix(c.Body, "v2") {
+				t.Errorf("comment not updated: %q", c.Body)
+			}
+		}
+	}
+	if marked != 1 {
+		t.Errorf("found %d marked comments, want exactly 1", marked)
+	}
+}
+
+func TestGitHubClient_APIError(t *testing.T) {
+	server := httptest.NewServer(&fakeGitHub{})
+	defer server.Close()
+
+	client, _ := NewClient(Config{
+		Provider:    ProviderGitHub,
+		BaseURL:     server.URL,
+		Repository:  "acme/app",
+		PullRequest: "42",
+		Token:       "wrong",
+	})
+
+	_, err := Publish(context.Background(), client, "body")
+	if err == nil || !strings.Contains(err.Error(), "401") || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Errorf("Publish() error = %v, want 401 with response body", err)
+	}
+}
@@ -0,0 +1,61 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// gitLabClient comments on GitLab merge requests through the notes API
+type gitLabClient struct {
+	api     *apiClient
+	project string // Full project path, may include subgroups
+	iid     string
+}
+
+type gitLabNote struct {
+	ID     int64  `json:"id"`
+	Body   string `json:"body"`
+	System bool   `json:"system"`
+}
+
+// projectID returns the URL-encoded project path used as project ID
+func (c *gitLabClient) projectID() string {
+	return strings.ReplaceAll(url.PathEscape(c.project), "/", "%2F")
+}
+
+func (c *gitLabClient) ListComments(ctx context.Context) ([]Comment, error) {
+	const perPage = 100
+
+	var comments []Comment
+	for page := 1; page <= maxPages; page++ {
+		var batch []gitLabNote
+		path := fmt.Sprintf("/projects/%s/merge_requests/%s/notes?per_page=%d&page=%d", c.projectID(), c.iid, perPage, page)
+		if err := c.api.do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, note := range batch {
+			// System notes are generated by GitLab (e.g. "added 1 commit")
+			if note.System {
+				continue
+			}
+			comments = append(comments, Comment{ID: note.ID, Body: note.Body})
+		}
+		if len(batch) < perPage {
+			break
+		}
+	}
+	return comments, nil
+}
+
+func (c *gitLabClient) CreateComment(ctx context.Context, body string) error {
+	path := fmt.Sprintf("/projects/%s/merge_requests/%s/notes", c.projectID(), c.iid)
+	return c.api.do(ctx, http.MethodPost, path, map[string]string{"body": body}, nil)
+}
+
+func (c *gitLabClient) UpdateComment(ctx context.Context, id int64, body string) error {
+	path := fmt.Sprintf("/projects/%s/merge_requests/%s/notes/%d", c.projectID(), c.iid, id)
+	return c.api.do(ctx, http.MethodPut, path, map[string]string{"body": body}, nil)
+}
@@ -0,0 +1,60 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGitLabClient_Publish(t *testing.T) {
+	const notesPath = "/projects/group%2Fsub%2Fapp/merge_requests/7/notes"
+	notes := []gitLabNote{
+		{ID: 1, Body: "added 1 commit", System: true},
+		{ID: 2, Body: CommentMarker + "\nold"},
+	}
+	var updatedBody string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.EscapedPath() == notesPath:
+			json.NewEncoder(w).Encode(notes)
+		case r.Method == http.MethodPut && r.URL.EscapedPath() == notesPath+"/2":
+			var req map[string]string
+			json.NewDecoder(r.Body).Decode(&req)
+			updatedBody = req["body"]
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{
+		Provider:    ProviderGitLab,
+		BaseURL:     server.URL + "/",
+		Repository:  "group/sub/app",
+		PullRequest: "7",
+		Token:       "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	result, err := Publish(context.Background(), client, "new")
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if result != ResultUpdated {
+		t.Errorf("Publish() = %s, want updated", result)
+	}
+	if updatedBody != CommentMarker+"\nnew" {
+		t.Errorf("updated body = %q", updatedBody)
+	}
+}
@@ -0,0 +1,93 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxPages bounds pagination when listing comments
+const maxPages = 50
+
+// maxErrorBody is the number of response body bytes included in API errors
+const maxErrorBody = 512
+
+// apiClient performs authenticated JSON requests against a provider API
+type apiClient struct {
+	baseURL    string
+	httpClient *http.Client
+	headers    map[string]string
+}
+
+// do sends a request and decodes the JSON response into out (if not nil).
+// path is relative to the base URL unless it is an absolute URL, which must have the
+// scheme and host of the base URL so that the credentials are not sent elsewhere.
+func (c *apiClient) do(ctx context.Context, method, path string, in interface{}, out interface{}) error {
+	reqURL := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		reqURL = c.baseURL + path
+	} else if err := c.checkSameOrigin(path); err != nil {
+		return err
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, reqURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("%s %s returned %s: %s", method, reqURL, resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// checkSameOrigin returns an error unless an absolute URL, such as a pagination link
+// returned by the API, has the scheme and host of the base URL
+func (c *apiClient) checkSameOrigin(rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL %q: %w", c.baseURL, err)
+	}
+	if !strings.EqualFold(target.Scheme, base.Scheme) || !strings.EqualFold(target.Host, base.Host) {
+		return fmt.Errorf("refusing to follow %s: it is not on %s://%s", rawURL, base.Scheme, base.Host)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+// Package publish posts analysis summaries as pull request comments on
+// GitHub, GitLab and Bitbucket Cloud.
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CommentMarker is a hidden HTML comment identifying the bundle inspector comment.
+// It lets repeated runs update the existing comment instead of adding new ones.
+const CommentMarker = "<!-- bitrise-bundle-inspector -->"
+
+// Provider identifies a git hosting service
+type Provider string
+
+const (
+	ProviderGitHub    Provider = "github"
+	ProviderGitLab    Provider = "gitlab"
+	ProviderBitbucket Provider = "bitbucket"
+)
+
+// TokenEnvVar returns the environment variable holding the provider's API token by default
+func (p Provider) TokenEnvVar() string {
+	switch p {
+	case ProviderGitHub:
+		return "GITHUB_TOKEN"
+	case ProviderGitLab:
+		return "GITLAB_TOKEN"
+	case ProviderBitbucket:
+		return "BITBUCKET_TOKEN"
+	default:
+		return ""
+	}
+}
+
+// Comment is a pull request comment
+type Comment struct {
+	ID   int64
+	Body string
+}
+
+// Client reads and writes comments of a single pull request
+type Client interface {
+	// ListComments returns all comments of the pull request.
+	ListComments(ctx context.Context) ([]Comment, error)
+
+	// CreateComment adds a new comment to the pull request.
+	CreateComment(ctx context.Context, body string) error
+
+	// UpdateComment replaces the body of an existing comment.
+	UpdateComment(ctx context.Context, id int64, body string) error
+}
+
+// Config describes the pull request to comment on
+type Config struct {
+	Provider    Provider
+	BaseURL     string // API base URL, e.g. https://api.github.com
+	Repository  string // Repository path, e.g. owner/repo
+	PullRequest string // Pull request number (merge request IID on GitLab)
+	Token       string
+	HTTPClient  *http.Client
+}
+
+// Result describes what Publish did
+type Result string
+
+const (
+	ResultCreated   Result = "created"
+	ResultUpdated   Result = "updated"
+	ResultUnchanged Result = "unchanged"
+)
+
+// NewClient creates the API client for the configured provider
+func NewClient(cfg Config) (Client, error) {
+	if cfg.Repository == "" {
+		return nil, fmt.Errorf("repository not set")
+	}
+	if cfg.PullRequest == "" {
+		return nil, fmt.Errorf("pull request number not set")
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("API token not set")
+	}
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		var err error
+		if baseURL, err = DefaultBaseURL(cfg.Provider, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	api := &apiClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+	}
+
+	switch cfg.Provider {
+	case ProviderGitHub:
+		api.headers = map[string]string{
+			"Authorization": "Bearer " + cfg.Token,
+			"Accept":        "application/vnd.github+json",
+		}
+		return &gitHubClient{api: api, repo: cfg.Repository, number: cfg.PullRequest}, nil
+	case ProviderGitLab:
+		api.headers = map[string]string{"PRIVATE-TOKEN": cfg.Token}
+		return &gitLabClient{api: api, project: cfg.Repository, iid: cfg.PullRequest}, nil
+	case ProviderBitbucket:
+		api.headers = map[string]string{"Authorization": "Bearer " + cfg.Token}
+		return &bitbucketClient{api: api, repo: cfg.Repository, id: cfg.PullRequest}, nil
+	default:
+		return nil, fmt.Errorf("unsupported provider: %q (supported: github, gitlab, bitbucket)", cfg.Provider)
+	}
+}
+
+// Publish creates or updates the single bundle inspector comment on the pull request.
+// The comment is found through CommentMarker, which is prepended to body.
+func Publish(ctx context.Context, client Client, body string) (Result, error) {
+	body = CommentMarker + "\n" + body
+
+	comments, err := client.ListComments(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	for _, comment := range comments {
+		if !strings.Contains(comment.Body, CommentMarker) {
+			continue
+		}
+		if strings.TrimSpace(comment.Body) == strings.TrimSpace(body) {
+			return ResultUnchanged, nil
+		}
+		if err := client.UpdateComment(ctx, comment.ID, body); err != nil {
+			return "", fmt.Errorf("failed to update comment %d: %w", comment.ID, err)
+		}
+		return ResultUpdated, nil
+	}
+
+	if err := client.CreateComment(ctx, body); err != nil {
+		return "", fmt.Errorf("failed to create comment: %w", err)
+	}
+	return ResultCreated, nil
+}
+
+// ParseRepositoryURL extracts the host and repository path from a git remote URL.
+// Supports https://host/owner/repo(.git), ssh://git@host/owner/repo and git@host:owner/repo.
+func ParseRepositoryURL(rawURL string) (host string, repoPath string, err error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", "", fmt.Errorf("repository URL is empty")
+	}
+
+	if strings.Contains(rawURL, "://") {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid repository URL: %w", err)
+		}
+		host, repoPath = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax: git@host:owner/repo.git
+		remote := rawURL
+		if at := strings.Index(remote, "@"); at >= 0 {
+			remote = remote[at+1:]
+		}
+		parts := strings.SplitN(remote, ":", 2)
+		if len(parts) != 2 {
+			return "", "", fmt.Errorf("invalid repository URL: %s", rawURL)
+		}
+		host, repoPath = parts[0], parts[1]
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if host == "" || !strings.Contains(repoPath, "/") {
+		return "", "", fmt.Errorf("invalid repository URL: %s", rawURL)
+	}
+
+	return host, repoPath, nil
+}
+
+// DetectProvider guesses the hosting provider from a repository host name
+func DetectProvider(host string) (Provider, error) {
+	host = strings.ToLower(host)
+	switch {
+	case strings.Contains(host, "github"):
+		return ProviderGitHub, nil
+	case strings.Contains(host, "gitlab"):
+		return ProviderGitLab, nil
+	case strings.Contains(host, "bitbucket"):
+		return ProviderBitbucket, nil
+	default:
+		return "", fmt.Errorf("cannot detect provider from host %q, set it explicitly", host)
+	}
+}
+
+// DefaultBaseURL returns the API base URL of a provider.
+// Self-hosted GitHub Enterprise and GitLab hosts get their standard API paths.
+func DefaultBaseURL(provider Provider, host string) (string, error) {
+	switch provider {
+	case ProviderGitHub:
+		if host == "" || host == "github.com" {
+			return "https://api.github.com", nil
+		}
+		return "https://" + host + "/api/v3", nil
+	case ProviderGitLab:
+		if host == "" {
+			host = "gitlab.com"
+		}
+		return "https://" + host + "/api/v4", nil
+	case ProviderBitbucket:
+		if host == "" || host == "bitbucket.org" {
+			return "https://api.bitbucket.org/2.0", nil
+		}
+		return "", fmt.Errorf("only Bitbucket Cloud is supported by default, set the base URL for %s", host)
+	default:
+		return "", fmt.Errorf("unsupported provider: %q (supported: github, gitlab, bitbucket)", provider)
+	}
+}
@@ -0,0 +1,166 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeClient is an in-memory Client
+type fakeClient struct {
+	comments []Comment
+	created  []string
+	updated  map[int64]string
+	listErr  error
+}
+
+func (c *fakeClient) ListComments(ctx context.Context) ([]Comment, error) {
+	return c.comments, c.listErr
+}
+
+func (c *fakeClient) CreateComment(ctx context.Context, body string) error {
+	c.created = append(c.created, body)
+	return nil
+}
+
+func (c *fakeClient) UpdateComment(ctx context.Context, id int64, body string) error {
+	if c.updated == nil {
+		c.updated = make(map[int64]string)
+	}
+	c.updated[id] = body
+	return nil
+}
+
+func TestPublish(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates comment when none has the marker", func(t *testing.T) {
+		client := &fakeClient{comments: []Comment{{ID: 1, Body: "LGTM"}}}
+		result, err := Publish(ctx, client, "report")
+		if err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		if result != ResultCreated || len(client.created) != 1 {
+			t.Fatalf("result = %s, created = %d", result, len(client.created))
+		}
+		if !strings.HasPrefix(client.created[0], CommentMarker) {
+			t.Error("created comment is missing the marker")
+		}
+	})
+
+	t.Run("updates existing marked comment", func(t *testing.T) {
+		client := &fakeClient{comments: []Comment{
+			{ID: 1, Body: "LGTM"},
+			{ID: 2, Body: CommentMarker + "\nold report"},
+		}}
+		result, err := Publish(ctx, client, "new report")
+		if err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		if result != ResultUpdated || len(client.created) != 0 {
+			t.Fatalf("result = %s, created = %d", result, len(client.created))
+		}
+		if client.updated[2] != CommentMarker+"\nnew report" {
+			t.Errorf("updated body = %q", client.updated[2])
+		}
+	})
+
+	t.Run("skips identical comment", func(t *testing.T) {
+		client := &fakeClient{comments: []Comment{{ID: 2, Body: CommentMarker + "\nreport\n"}}}
+		result, err := Publish(ctx, client, "report")
+		if err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		if result != ResultUnchanged || len(client.updated) != 0 {
+			t.Errorf("result = %s, updated = %d", result, len(client.updated))
+		}
+	})
+
+	t.Run("propagates list errors", func(t *testing.T) {
+		client := &fakeClient{listErr: errors.New("boom")}
+		if _, err := Publish(ctx, client, "report"); err == nil {
+			t.Error("Publish() expected error")
+		}
+	})
+}
+
+func TestParseRepositoryURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		wantHost string
+		wantPath string
+		wantErr  bool
+	}{
+		{url: "https://github.com/bitrise-io/app.git", wantHost: "github.com", wantPath: "bitrise-io/app"},
+		{url: "git@github.com:bitrise-io/app.git", wantHost: "github.com", wantPath: "bitrise-io/app"},
+		{url: "ssh://git@gitlab.example.com:2222/group/sub/app.git", wantHost: "gitlab.example.com", wantPath: "group/sub/app"},
+		{url: "https://bitbucket.org/workspace/app/", wantHost: "bitbucket.org", wantPath: "workspace/app"},
+		{url: "", wantErr: true},
+		{url: "https://github.com/only-owner", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			host, path, err := ParseRepositoryURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRepositoryURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if host != tt.wantHost || path != tt.wantPath {
+				t.Errorf("ParseRepositoryURL() = (%q, %q), want (%q, %q)", host, path, tt.wantHost, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestDetectProviderAndBaseURL(t *testing.T) {
+	tests := []struct {
+		host         string
+		wantProvider Provider
+		wantBaseURL  string
+	}{
+		{host: "github.com", wantProvider: ProviderGitHub, wantBaseURL: "https://api.github.com"},
+		{host: "github.acme.com", wantProvider: ProviderGitHub, wantBaseURL: "https://github.acme.com/api/v3"},
+		{host: "gitlab.com", wantProvider: ProviderGitLab, wantBaseURL: "https://gitlab.com/api/v4"},
+		{host: "bitbucket.org", wantProvider: ProviderBitbucket, wantBaseURL: "https://api.bitbucket.org/2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			provider, err := DetectProvider(tt.host)
+			if err != nil || provider != tt.wantProvider {
+				t.Fatalf("DetectProvider() = %q, %v", provider, err)
+			}
+			baseURL, err := DefaultBaseURL(provider, tt.host)
+			if err != nil || baseURL != tt.wantBaseURL {
+				t.Errorf("DefaultBaseURL() = %q, %v, want %q", baseURL, err, tt.wantBaseURL)
+			}
+		})
+	}
+
+	if _, err := DetectProvider("git.example.com"); err == nil {
+		t.Error("DetectProvider() expected error for unknown host")
+	}
+}
+
+func TestNewClient_Validation(t *testing.T) {
+	valid := Config{Provider: ProviderGitHub, Repository: "o/r", PullRequest: "1", Token: "t"}
+	if _, err := NewClient(valid); err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	for name, mutate := range map[string]func(*Config){
+		"missing token":    func(c *Config) { c.Token = "" },
+		"missing pr":       func(c *Config) { c.PullRequest = "" },
+		"missing repo":     func(c *Config) { c.Repository = "" },
+		"unknown provider": func(c *Config) { c.Provider = "gitea" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := valid
+			mutate(&cfg)
+			if _, err := NewClient(cfg); err == nil {
+				t.Error("NewClient() expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// hasArchiveLayout reports whether a report has APK signing, META-INF/ or ZIP overhead details
+func hasArchiveLayout(report *types.Report) bool {
+	a := report.Android
+	return a != nil && (a.Signing != nil || len(a.MetaInf) > 0 || a.ZIPOverhead != nil)
+}
+
+// signingBlockSummary lists the entries of an APK Signing Block with their sizes
+func signingBlockSummary(signing *types.APKSigningInfo) string {
+	parts := make([]string, 0, len(signing.Blocks))
+	for _, b := range signing.Blocks {
+		parts = append(parts, fmt.Sprintf("%s %s", b.Name, util.FormatBytes(b.Size)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// signingSchemes formats the signature schemes of an APK, e.g. "v1, v2, v3"
+func signingSchemes(signing *types.APKSigningInfo) string {
+	if len(signing.Schemes) == 0 {
+		return "none"
+	}
+	return strings.Join(signing.Schemes, ", ")
+}
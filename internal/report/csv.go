@@ -0,0 +1,183 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// CSVColumns are the file tree columns of the CSV export, in their default order
+var CSVColumns = []string{
+	"path", "name", "size", "parent", "depth", "is_dir", "is_virtual",
+	"source_file", "hash", "is_duplicate", "category", "extension",
+	"compressed_size", "compression_method",
+}
+
+// csvOptimizationColumns are the columns of the optimizations CSV
+var csvOptimizationColumns = []string{
+	"category", "severity", "title", "description", "impact", "action", "file_count", "files",
+}
+
+// CSVFormatter flattens the file tree of a report into one row per node, including
+// virtual nodes like DEX classes, Mach-O segments and asset renditions.
+// Rows are written while walking the tree, so large trees are not buffered.
+type CSVFormatter struct {
+	Columns []string // File tree columns, CSVColumns when empty
+	Comma   rune     // Field delimiter
+}
+
+// NewCSVFormatter creates a new comma-separated formatter
+func NewCSVFormatter() *CSVFormatter {
+	return &CSVFormatter{Comma: ','}
+}
+
+// NewTSVFormatter creates a new tab-separated formatter
+func NewTSVFormatter() *CSVFormatter {
+	return &CSVFormatter{Comma: '\t'}
+}
+
+// ParseCSVColumns parses a comma-separated list of file tree columns.
+// An empty list selects all columns.
+func ParseCSVColumns(columnsStr string) ([]string, error) {
+	valid := make(map[string]bool, len(CSVColumns))
+	for _, column := range CSVColumns {
+		valid[column] = true
+	}
+
+	var columns []string
+	for _, column := range strings.Split(columnsStr, ",") {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			continue
+		}
+		if !valid[column] {
+			return nil, fmt.Errorf("unsupported CSV column: %s (valid columns: %s)", column, strings.Join(CSVColumns, ", "))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// Format writes the flattened file tree to the writer, directories before their children
+func (f *CSVFormatter) Format(w io.Writer, report *types.Report) error {
+	columns := f.Columns
+	if len(columns) == 0 {
+		columns = CSVColumns
+	}
+
+	writer := f.newWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+
+	html := &HTMLFormatter{}
+	row := make([]string, len(columns))
+
+	var walk func(nodes []*types.FileNode, parent string, depth int) error
+	walk = func(nodes []*types.FileNode, parent string, depth int) error {
+		for _, node := range nodes {
+			if node == nil {
+				continue
+			}
+			for i, column := range columns {
+				row[i] = csvNodeValue(html, node, column, parent, depth)
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+			if err := walk(node.Children, node.Path, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(report.FileTree, "", 0); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// FormatOptimizations writes one row per optimization to the writer.
+// Files are joined with semicolons.
+func (f *CSVFormatter) FormatOptimizations(w io.Writer, report *types.Report) error {
+	writer := f.newWriter(w)
+	if err := writer.Write(csvOptimizationColumns); err != nil {
+		return err
+	}
+
+	for _, opt := range report.Optimizations {
+		if err := writer.Write([]string{
+			opt.Category,
+			opt.Severity,
+			opt.Title,
+			opt.Description,
+			strconv.FormatInt(opt.Impact, 10),
+			opt.Action,
+			strconv.Itoa(len(opt.Files)),
+			strings.Join(opt.Files, ";"),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// newWriter creates a CSV writer with the formatter's delimiter
+func (f *CSVFormatter) newWriter(w io.Writer) *csv.Writer {
+	writer := csv.NewWriter(w)
+	if f.Comma != 0 {
+		writer.Comma = f.Comma
+	}
+	return writer
+}
+
+// csvNodeValue returns the value of a file tree column for a node
+func csvNodeValue(html *HTMLFormatter, node *types.FileNode, column, parent string, depth int) string {
+	switch column {
+	case "path":
+		return node.Path
+	case "name":
+		return node.Name
+	case "size":
+		return strconv.FormatInt(node.Size, 10)
+	case "parent":
+		return parent
+	case "depth":
+		return strconv.Itoa(depth)
+	case "is_dir":
+		return strconv.FormatBool(node.IsDir)
+	case "is_virtual":
+		return strconv.FormatBool(node.IsVirtual)
+	case "source_file":
+		return node.SourceFile
+	case "hash":
+		return node.Hash
+	case "is_duplicate":
+		return strconv.FormatBool(node.IsDuplicate)
+	case "category":
+		// Virtual DEX classes have no file extension of their own
+		if node.IsVirtual && (node.Path == "Dex" || strings.HasPrefix(node.Path, "Dex/")) {
+			return "dex"
+		}
+		return html.getFileType(node.Name, node.Path)
+	case "extension":
+		if node.IsDir {
+			return ""
+		}
+		return strings.ToLower(filepath.Ext(node.Name))
+	case "compressed_size":
+		return strconv.FormatInt(node.CompressedSize, 10)
+	case "compression_method":
+		return node.CompressionMethod
+	}
+	return ""
+}
@@ -0,0 +1,153 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func csvTestReport() *types.Report {
+	return &types.Report{
+		FileTree: []*types.FileNode{
+			{Name: "Dex", Path: "Dex", IsDir: true, IsVirtual: true, Size: 300, Children: []*types.FileNode{
+				{Name: "com", Path: "Dex/com", IsDir: true, IsVirtual: true, Size: 300, Children: []*types.FileNode{
+					{Name: "Main.class", Path: "Dex/com/Main.class", IsVirtual: true, Size: 300, SourceFile: "classes.dex"},
+				}},
+			}},
+			{Name: "res", Path: "res", IsDir: true, Size: 20, Children: []*types.FileNode{
+				{Name: "Logo, dark.PNG", Path: "res/Logo, dark.PNG", Size: 20, Hash: "abc", IsDuplicate: true,
+					CompressedSize: 18, CompressionMethod: "deflate"},
+			}},
+		},
+		Optimizations: []types.Optimization{
+			{Category: "images", Severity: "high", Title: "Convert to WebP", Description: "Large \"PNG\" images",
+				Impact: 1024, Action: "Convert", Files: []string{"res/a.png", "res/b.png"}},
+		},
+	}
+}
+
+func readCSV(t *testing.T, data string, comma rune) [][]string {
+	t.Helper()
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.Comma = comma
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read CSV output: %v\n%s", err, data)
+	}
+	return records
+}
+
+func TestCSVFormatter_Format(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewCSVFormatter().Format(&buf, csvTestReport()); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+
+	got := readCSV(t, buf.String(), ',')
+	want := [][]string{
+		CSVColumns,
+		{"Dex", "Dex", "300", "", "0", "true", "true", "", "", "false", "dex", "", "0", ""},
+		{"Dex/com", "com", "300", "Dex", "1", "true", "true", "", "", "false", "dex", "", "0", ""},
+		{"Dex/com/Main.class", "Main.class", "300", "Dex/com", "2", "false", "true", "classes.dex", "", "false", "dex", ".class", "0", ""},
+		{"res", "res", "20", "", "0", "true", "false", "", "", "false", "other", "", "0", ""},
+		{"res/Logo, dark.PNG", "Logo, dark.PNG", "20", "res", "1", "false", "false", "", "abc", "true", "image", ".png", "18", "deflate"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Format() rows =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestCSVFormatter_Format_TSVColumns(t *testing.T) {
+	formatter := NewTSVFormatter()
+	formatter.Columns = []string{"size", "path"}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, csvTestReport()); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "size\tpath" {
+		t.Errorf("header = %q, want %q", lines[0], "size\tpath")
+	}
+	if lines[len(lines)-1] != "20\tres/Logo, dark.PNG" {
+		t.Errorf("last row = %q, want %q", lines[len(lines)-1], "20\tres/Logo, dark.PNG")
+	}
+}
+
+func TestCSVFormatter_FormatOptimizations(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewCSVFormatter().FormatOptimizations(&buf, csvTestReport()); err != nil {
+		t.Fatalf("FormatOptimizations() failed: %v", err)
+	}
+
+	got := readCSV(t, buf.String(), ',')
+	want := [][]string{
+		csvOptimizationColumns,
+		{"images", "high", "Convert to WebP", "Large \"PNG\" images", "1024", "Convert", "2", "res/a.png;res/b.png"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FormatOptimizations() rows =\n%v\nwant\n%v", got, want)
+	}
+}
+
+// countingWriter counts the writes it receives
+type countingWriter struct {
+	writes int
+	bytes  int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	w.bytes += len(p)
+	return len(p), nil
+}
+
+func TestCSVFormatter_Format_Streams(t *testing.T) {
+	const nodeCount = 10000
+	dir := &types.FileNode{Name: "res", Path: "res", IsDir: true}
+	for i := 0; i < nodeCount; i++ {
+		name := fmt.Sprintf("file_%05d.png", i)
+		dir.Children = append(dir.Children, &types.FileNode{Name: name, Path: "res/" + name, Size: int64(i)})
+	}
+
+	w := &countingWriter{}
+	if err := NewCSVFormatter().Format(w, &types.Report{FileTree: []*types.FileNode{dir}}); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+
+	// Rows are flushed in buffer-sized chunks while walking, not in one write at the end
+	if w.writes < 10 {
+		t.Errorf("Format() made %d writes for %d bytes, want the output streamed", w.writes, w.bytes)
+	}
+}
+
+func TestParseCSVColumns(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"path,size", []string{"path", "size"}, false},
+		{" size , path ,", []string{"size", "path"}, false},
+		{"path,bogus", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCSVColumns(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCSVColumns(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCSVColumns(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package report
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -20,6 +24,18 @@ type HTMLFormatter struct {
 	IncludeTreemap bool
 	IncludeCharts  bool
 	Theme          string // "light" or "dark"
+
+	// Offline inlines all styles and scripts so the report works without network access.
+	// Charts are drawn by a built-in SVG renderer instead of ECharts from a CDN.
+	Offline bool
+	// CSPNonce is added to every inline script and style, along with a matching
+	// Content-Security-Policy meta tag. Requires Offline.
+	CSPNonce string
+	// CSPHashes adds a Content-Security-Policy meta tag that allows the inline scripts
+	// and styles by their SHA-256 hashes. Requires Offline.
+	CSPHashes bool
+	// SizeView selects the node sizes of the treemap and file breakdown, install by default.
+	SizeView SizeView
 }
 
 // NewHTMLFormatter creates a new HTML formatter
@@ -29,16 +45,78 @@ func NewHTMLFormatter() *HTMLFormatter {
 		IncludeTreemap: true,
 		IncludeCharts:  true,
 		Theme:          "light",
+		Offline:        true,
 	}
 }
 
+// validNonce matches base64 and base64url nonce values
+var validNonce = regexp.MustCompile(`^[A-Za-z0-9+/_=-]+$`)
+
+// inlineBlocks matches the content of inline script and style elements
+var inlineBlocks = regexp.MustCompile(`(?s)<(script|style)(?: nonce="[^"]*")?>(.*?)</(?:script|style)>`)
+
 // Format writes the report in HTML format to the writer
 func (f *HTMLFormatter) Format(w io.Writer, report *types.Report) error {
+	if (f.CSPNonce != "" || f.CSPHashes) && !f.Offline {
+		return fmt.Errorf("content security policy options require an offline HTML report")
+	}
+
 	// Prepare data for JavaScript
 	data := f.prepareTemplateData(report)
 
-	// Execute template
-	return f.executeTemplate(w, data)
+	return executeWithCSP(w, f.CSPNonce, f.CSPHashes, func(w io.Writer, policy string) error {
+		data.ContentSecurityPolicy = policy
+		return f.executeTemplate(w, data)
+	})
+}
+
+// executeWithCSP renders a page whose inline scripts and styles carry the nonce, if any.
+// With a nonce or hashes, execute receives the Content-Security-Policy to embed; for hashes
+// the page is rendered once without a policy to hash its inline blocks.
+func executeWithCSP(w io.Writer, nonce string, hashes bool, execute func(w io.Writer, policy string) error) error {
+	if nonce != "" && !validNonce.MatchString(nonce) {
+		return fmt.Errorf("invalid CSP nonce %q: must be base64 encoded", nonce)
+	}
+	if nonce == "" && !hashes {
+		return execute(w, "")
+	}
+
+	var scriptSources, styleSources []string
+	if nonce != "" {
+		scriptSources = append(scriptSources, "'nonce-"+nonce+"'")
+		styleSources = append(styleSources, "'nonce-"+nonce+"'")
+	}
+	if hashes {
+		var buf bytes.Buffer
+		if err := execute(&buf, ""); err != nil {
+			return err
+		}
+		for _, match := range inlineBlocks.FindAllSubmatch(buf.Bytes(), -1) {
+			sum := sha256.Sum256(match[2])
+			source := "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+			if string(match[1]) == "script" {
+				scriptSources = append(scriptSources, source)
+			} else {
+				styleSources = append(styleSources, source)
+			}
+		}
+	}
+
+	return execute(w, contentSecurityPolicy(scriptSources, styleSources))
+}
+
+// contentSecurityPolicy builds a policy that only allows the given inline scripts and
+// styles, plus data: URIs for the embedded app icon
+func contentSecurityPolicy(scriptSources, styleSources []string) string {
+	return strings.Join([]string{
+		"default-src 'none'",
+		"script-src " + strings.Join(scriptSources, " "),
+		"style-src " + strings.Join(styleSources, " "),
+		"img-src data:",
+		"font-src data:",
+		"base-uri 'none'",
+		"form-action 'none'",
+	}, "; ")
 }
 
 // templateData holds all data needed for the HTML template
@@ -62,6 +140,43 @@ type templateData struct {
 	DataJSON           template.JS
 	NodeCount          int
 	PerformanceWarning bool
+	Owners             []ownerRow
+	Warnings           []string
+	SizeView           SizeView
+
+	Offline               bool
+	OfflineCSS            template.CSS
+	ChartRenderer         template.JS
+	TreeScript            template.JS
+	Nonce                 string
+	ContentSecurityPolicy string
+}
+
+// ownerRow is one row of the size by owner table
+type ownerRow struct {
+	Owner            string
+	Size             string
+	Share            string
+	FileCount        int
+	PotentialSavings string
+	DuplicateWaste   string
+}
+
+// prepareOwnerRows formats the sizes by owner for the template
+func (f *HTMLFormatter) prepareOwnerRows(owners []types.OwnerSize) []ownerRow {
+	owned := ownedSize(owners)
+	rows := make([]ownerRow, 0, len(owners))
+	for _, o := range owners {
+		rows = append(rows, ownerRow{
+			Owner:            o.Owner,
+			Size:             util.FormatBytes(o.Size),
+			Share:            util.FormatPercentage(o.Size, owned),
+			FileCount:        o.FileCount,
+			PotentialSavings: util.FormatBytes(o.PotentialSavings),
+			DuplicateWaste:   util.FormatBytes(o.DuplicateWaste),
+		})
+	}
+	return rows
 }
 
 // prepareTemplateData converts the report into template-ready data
@@ -130,15 +245,10 @@ func (f *HTMLFormatter) prepareTemplateData(report *types.Report) templateData {
 		if v, ok := report.Metadata["platform"].(string); ok {
 			platform = v
 		}
-		if v, ok := report.Metadata["git_branch"].(string); ok {
-			branch = v
-		}
-		// Check both commit_hash (new) and git_commit (legacy) for backwards compatibility
-		if v, ok := report.Metadata["commit_hash"].(string); ok {
-			commitSHA = v
-		} else if v, ok := report.Metadata["git_commit"].(string); ok {
-			commitSHA = v
-		}
+	}
+	if report.CI != nil {
+		branch = report.CI.Branch
+		commitSHA = report.CI.CommitHash
 	}
 
 	// Determine platform from artifact type if not set
@@ -171,6 +281,14 @@ func (f *HTMLFormatter) prepareTemplateData(report *types.Report) templateData {
 		DataJSON:           template.JS(dataJSON),
 		NodeCount:          nodeCount,
 		PerformanceWarning: performanceWarning,
+		Owners:             f.prepareOwnerRows(report.Ownership),
+		Warnings:           report.Warnings,
+		SizeView:           f.SizeView.resolve(report.FileTree),
+		Offline:            f.Offline,
+		OfflineCSS:         template.CSS(htmlOfflineCSS),
+		ChartRenderer:      template.JS(htmlChartRenderer),
+		TreeScript:         template.JS(htmlTreeScript),
+		Nonce:              f.CSPNonce,
 	}
 }
 
@@ -242,17 +360,19 @@ func (f *HTMLFormatter) prepareTreemapData(nodes []*types.FileNode) interface{}
 		}
 	}
 
+	view := f.SizeView.resolve(nodes)
+
 	// If single root, use it; otherwise create a wrapper root
 	if len(nodes) == 1 {
-		return f.convertNodeToMap(nodes[0], 0)
+		return f.convertNodeToMap(nodes[0], 0, view)
 	}
 
 	// Multiple roots - create wrapper
 	var totalSize int64
 	children := make([]interface{}, 0, len(nodes))
 	for _, node := range nodes {
-		totalSize += node.Size
-		children = append(children, f.convertNodeToMap(node, 0))
+		totalSize += view.nodeSize(node)
+		children = append(children, f.convertNodeToMap(node, 0, view))
 	}
 
 	return map[string]interface{}{
@@ -264,10 +384,10 @@ func (f *HTMLFormatter) prepareTreemapData(nodes []*types.FileNode) interface{}
 }
 
 // convertNodeToMap recursively converts a FileNode to a map for ECharts
-func (f *HTMLFormatter) convertNodeToMap(node *types.FileNode, depth int) map[string]interface{} {
+func (f *HTMLFormatter) convertNodeToMap(node *types.FileNode, depth int, view SizeView) map[string]interface{} {
 	result := map[string]interface{}{
 		"name":  node.Name,
-		"value": node.Size,
+		"value": view.nodeSize(node),
 		"path":  node.Path,
 	}
 
@@ -281,7 +401,7 @@ func (f *HTMLFormatter) convertNodeToMap(node *types.FileNode, depth int) map[st
 		children := make([]interface{}, 0, len(node.Children))
 
 		for _, child := range node.Children {
-			children = append(children, f.convertNodeToMap(child, depth+1))
+			children = append(children, f.convertNodeToMap(child, depth+1, view))
 		}
 
 		if len(children) > 0 {
@@ -0,0 +1,432 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// maxDiffDeltas caps the file deltas embedded in the diff page
+const maxDiffDeltas = 500
+
+// titleCountPattern matches the counts in optimization titles, e.g. the 3 of
+// "Compress 3 stored files"
+var titleCountPattern = regexp.MustCompile(`\b\d+\b`)
+
+// Diff node statuses
+const (
+	diffAdded     = "added"
+	diffRemoved   = "removed"
+	diffChanged   = "changed"
+	diffUnchanged = "unchanged"
+)
+
+// HTMLDiffFormatter formats the difference between a baseline and a head report as a
+// standalone HTML page. Like offline reports, the page has no external dependencies.
+type HTMLDiffFormatter struct {
+	Title    string
+	baseline *types.Report
+
+	// CSPNonce is added to every inline script and style, along with a matching
+	// Content-Security-Policy meta tag
+	CSPNonce string
+	// CSPHashes adds a Content-Security-Policy meta tag that allows the inline scripts
+	// and styles by their SHA-256 hashes
+	CSPHashes bool
+}
+
+// NewHTMLDiffFormatter creates a new HTML diff formatter comparing against baseline
+func NewHTMLDiffFormatter(baseline *types.Report) *HTMLDiffFormatter {
+	return &HTMLDiffFormatter{
+		Title:    "Bundle Comparison Report",
+		baseline: baseline,
+	}
+}
+
+// diffNode is a file tree node holding both baseline and head sizes.
+// Value is the larger of the two, so removed files stay visible in the treemap.
+type diffNode struct {
+	Name     string      `json:"name"`
+	Path     string      `json:"path,omitempty"`
+	Value    int64       `json:"value"`
+	BaseSize int64       `json:"baseSize"`
+	HeadSize int64       `json:"headSize"`
+	Delta    int64       `json:"delta"`
+	Status   string      `json:"status"`
+	FileType string      `json:"fileType,omitempty"`
+	Children []*diffNode `json:"children,omitempty"`
+}
+
+// diffCategory holds the baseline and head size of a size breakdown category
+type diffCategory struct {
+	Name string `json:"name"`
+	Base int64  `json:"base"`
+	Head int64  `json:"head"`
+}
+
+// diffJSData is embedded in the diff page for its charts and tables
+type diffJSData struct {
+	Tree       *diffNode      `json:"tree"`
+	Deltas     []*diffNode    `json:"deltas"`
+	Categories []diffCategory `json:"categories"`
+}
+
+// diffTemplateData holds all data needed for the HTML diff template
+type diffTemplateData struct {
+	Title        string
+	AppName      string
+	BaseLabel    string
+	HeadLabel    string
+	Sizes        []diffSizeRow
+	Owners       []diffSizeRow
+	Manifest     []manifestChange
+	NewOpts      []optimizationData
+	ResolvedOpts []optimizationData
+	Timestamp    string
+	DataJSON     template.JS
+
+	ChartRenderer         template.JS
+	TreeScript            template.JS
+	DiffCSS               template.CSS
+	Nonce                 string
+	ContentSecurityPolicy string
+}
+
+// diffSizeRow is one row of the size summary table
+type diffSizeRow struct {
+	Name     string
+	Base     string
+	Head     string
+	Delta    string
+	Increase bool
+	Decrease bool
+}
+
+// Format writes the comparison of head against the baseline in HTML format to the writer
+func (f *HTMLDiffFormatter) Format(w io.Writer, head *types.Report) error {
+	if f.baseline == nil {
+		return fmt.Errorf("a baseline report is required")
+	}
+
+	data, err := f.prepareTemplateData(head)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("diff").Funcs(template.FuncMap{
+		"formatBytes": util.FormatBytes,
+	}).Parse(htmlDiffTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return executeWithCSP(w, f.CSPNonce, f.CSPHashes, func(w io.Writer, policy string) error {
+		data.ContentSecurityPolicy = policy
+		return tmpl.Execute(w, data)
+	})
+}
+
+// prepareTemplateData converts both reports into template-ready data
+func (f *HTMLDiffFormatter) prepareTemplateData(head *types.Report) (diffTemplateData, error) {
+	base := f.baseline
+
+	tree := buildDiffTree(base.FileTree, head.FileTree)
+	jsData := diffJSData{
+		Tree:       tree,
+		Deltas:     collectDiffDeltas(tree),
+		Categories: diffCategories(&base.SizeBreakdown, &head.SizeBreakdown),
+	}
+	jsonBytes, err := json.Marshal(jsData)
+	if err != nil {
+		return diffTemplateData{}, fmt.Errorf("failed to encode diff data: %w", err)
+	}
+
+	newOpts, resolvedOpts := diffOptimizations(base.Optimizations, head.Optimizations)
+	formatter := &HTMLFormatter{}
+
+	data := diffTemplateData{
+		Title:     f.Title,
+		BaseLabel: reportLabel(base),
+		HeadLabel: reportLabel(head),
+		Sizes: []diffSizeRow{
+			newDiffSizeRow("Download Size", base.ArtifactInfo.Size, head.ArtifactInfo.Size),
+			newDiffSizeRow("Install Size", calculateUncompressedSize(&base.SizeBreakdown), calculateUncompressedSize(&head.SizeBreakdown)),
+			newDiffSizeRow("Potential Savings", base.TotalSavings, head.TotalSavings),
+		},
+		NewOpts:       formatter.prepareOptimizationData(newOpts),
+		ResolvedOpts:  formatter.prepareOptimizationData(resolvedOpts),
+		Timestamp:     head.ArtifactInfo.AnalyzedAt.Format(time.RFC3339),
+		DataJSON:      template.JS(jsonBytes),
+		ChartRenderer: template.JS(htmlChartRenderer),
+		TreeScript:    template.JS(htmlTreeScript),
+		DiffCSS:       template.CSS(htmlDiffCSS),
+		Nonce:         f.CSPNonce,
+	}
+	data.Manifest = diffManifests(base, head)
+	for _, c := range diffOwners(base, head) {
+		data.Owners = append(data.Owners, newDiffSizeRow(c.owner, c.baseline, c.current))
+	}
+	data.AppName = head.ArtifactInfo.AppName
+	if data.AppName == "" && head.Metadata != nil {
+		data.AppName = firstMetadataString(head.Metadata, "app_name")
+	}
+
+	return data, nil
+}
+
+// reportLabel names a report by version and commit, falling back to the artifact file name
+func reportLabel(report *types.Report) string {
+	label := report.ArtifactInfo.Version
+	sha := ciCommitHash(report)
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+	switch {
+	case label == "":
+		label = sha
+	case sha != "":
+		label += " (" + sha + ")"
+	}
+	if label == "" {
+		label = filepath.Base(report.ArtifactInfo.Path)
+	}
+	return label
+}
+
+// firstMetadataString returns the first non-empty string metadata value of the given keys
+func firstMetadataString(metadata map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := metadata[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// newDiffSizeRow formats a baseline and head size with their difference
+func newDiffSizeRow(name string, base, head int64) diffSizeRow {
+	return diffSizeRow{
+		Name:     name,
+		Base:     util.FormatBytes(base),
+		Head:     util.FormatBytes(head),
+		Delta:    formatSizeDelta(head - base),
+		Increase: head > base,
+		Decrease: head < base,
+	}
+}
+
+// buildDiffTree merges the baseline and head file trees into a single tree by path
+func buildDiffTree(base, head []*types.FileNode) *diffNode {
+	root := &diffNode{Name: "root", Path: "/"}
+	root.Children = mergeDiffChildren(base, head)
+	for _, child := range root.Children {
+		root.Value += child.Value
+		root.BaseSize += child.BaseSize
+		root.HeadSize += child.HeadSize
+	}
+	root.Delta = root.HeadSize - root.BaseSize
+	root.Status = diffStatus(root)
+	return root
+}
+
+// mergeDiffChildren pairs sibling nodes by name, keeping head order with removed nodes last
+func mergeDiffChildren(base, head []*types.FileNode) []*diffNode {
+	baseByName := make(map[string]*types.FileNode, len(base))
+	for _, node := range base {
+		baseByName[node.Name] = node
+	}
+
+	result := make([]*diffNode, 0, len(head))
+	seen := make(map[string]bool, len(head))
+	for _, node := range head {
+		seen[node.Name] = true
+		result = append(result, mergeDiffNode(baseByName[node.Name], node))
+	}
+	for _, node := range base {
+		if !seen[node.Name] {
+			result = append(result, mergeDiffNode(node, nil))
+		}
+	}
+
+	return result
+}
+
+// mergeDiffNode merges a baseline and head node, either of which may be nil
+func mergeDiffNode(base, head *types.FileNode) *diffNode {
+	node := head
+	if node == nil {
+		node = base
+	}
+
+	result := &diffNode{Name: node.Name, Path: node.Path}
+
+	var baseChildren, headChildren []*types.FileNode
+	if base != nil {
+		baseChildren = base.Children
+	}
+	if head != nil {
+		headChildren = head.Children
+	}
+
+	if len(baseChildren) > 0 || len(headChildren) > 0 {
+		result.Children = mergeDiffChildren(baseChildren, headChildren)
+		for _, child := range result.Children {
+			result.Value += child.Value
+			result.BaseSize += child.BaseSize
+			result.HeadSize += child.HeadSize
+		}
+	} else {
+		if base != nil {
+			result.BaseSize = base.Size
+		}
+		if head != nil {
+			result.HeadSize = head.Size
+		}
+		result.Value = max(result.BaseSize, result.HeadSize)
+		if !node.IsDir {
+			result.FileType = (&HTMLFormatter{}).getFileType(node.Name, node.Path)
+		}
+	}
+
+	result.Delta = result.HeadSize - result.BaseSize
+	switch {
+	case base == nil:
+		result.Status = diffAdded
+	case head == nil:
+		result.Status = diffRemoved
+	default:
+		result.Status = diffStatus(result)
+	}
+
+	return result
+}
+
+// diffStatus reports whether a node present in both trees changed
+func diffStatus(node *diffNode) string {
+	if node.Delta != 0 {
+		return diffChanged
+	}
+	for _, child := range node.Children {
+		if child.Status != diffUnchanged {
+			return diffChanged
+		}
+	}
+	return diffUnchanged
+}
+
+// collectDiffDeltas returns the changed leaf files ordered by the size of their change
+func collectDiffDeltas(root *diffNode) []*diffNode {
+	var deltas []*diffNode
+	var walk func(node *diffNode)
+	walk = func(node *diffNode) {
+		if len(node.Children) == 0 {
+			if node.Status != diffUnchanged && node != root {
+				deltas = append(deltas, &diffNode{
+					Name:     node.Name,
+					Path:     node.Path,
+					BaseSize: node.BaseSize,
+					HeadSize: node.HeadSize,
+					Delta:    node.Delta,
+					Status:   node.Status,
+					FileType: node.FileType,
+				})
+			}
+			return
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	sort.SliceStable(deltas, func(i, j int) bool {
+		a, b := absInt64(deltas[i].Delta), absInt64(deltas[j].Delta)
+		if a != b {
+			return a > b
+		}
+		return deltas[i].Path < deltas[j].Path
+	})
+	if len(deltas) > maxDiffDeltas {
+		deltas = deltas[:maxDiffDeltas]
+	}
+
+	return deltas
+}
+
+// diffCategories pairs the baseline and head size breakdown, skipping empty categories
+func diffCategories(base, head *types.SizeBreakdown) []diffCategory {
+	baseSizes := categorySizes(base)
+	var result []diffCategory
+	for i, cat := range categorySizes(head) {
+		if cat.size == 0 && baseSizes[i].size == 0 {
+			continue
+		}
+		result = append(result, diffCategory{Name: cat.name, Base: baseSizes[i].size, Head: cat.size})
+	}
+	return result
+}
+
+// diffOptimizations returns head optimizations missing from the baseline and baseline
+// optimizations no longer reported in head. Optimizations match by category and title
+// without the counts in it, so "Compress 3 stored files" matches "Compress 4 stored
+// files". Among several candidates, one sharing a file is preferred.
+func diffOptimizations(base, head []types.Optimization) (added, resolved []types.Optimization) {
+	key := func(opt types.Optimization) string {
+		return opt.Category + "\x00" + titleCountPattern.ReplaceAllString(opt.Title, "#")
+	}
+
+	candidates := make(map[string][]int)
+	for i, opt := range base {
+		candidates[key(opt)] = append(candidates[key(opt)], i)
+	}
+
+	matched := make([]bool, len(base))
+	for _, opt := range head {
+		k := key(opt)
+		match := -1
+		for n, i := range candidates[k] {
+			if sharesFile(base[i], opt) {
+				match = n
+				break
+			}
+			if match < 0 {
+				match = n
+			}
+		}
+		if match < 0 {
+			added = append(added, opt)
+			continue
+		}
+		matched[candidates[k][match]] = true
+		candidates[k] = append(candidates[k][:match], candidates[k][match+1:]...)
+	}
+	for i, opt := range base {
+		if !matched[i] {
+			resolved = append(resolved, opt)
+		}
+	}
+
+	return added, resolved
+}
+
+// sharesFile reports whether two optimizations name a common file
+func sharesFile(a, b types.Optimization) bool {
+	files := make(map[string]bool, len(a.Files))
+	for _, file := range a.Files {
+		files[file] = true
+	}
+	for _, file := range b.Files {
+		if files[file] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,393 @@
+package report
+
+// htmlDiffCSS styles the dependency-free diff page
+const htmlDiffCSS = `
+        body { font-family: system-ui, -apple-system, sans-serif; margin: 0; background: #f8fafc; color: #0f172a; }
+        main { max-width: 80rem; margin: 0 auto; padding: 1.5rem; }
+        h1 { font-size: 1.75rem; margin: 0 0 0.25rem; }
+        h2 { font-size: 1.25rem; margin: 2rem 0 0.75rem; }
+        .subtitle { color: #64748b; margin: 0 0 1rem; }
+        .panel { background: #fff; border: 1px solid #e2e8f0; border-radius: 0.5rem; padding: 1rem; }
+        table { width: 100%; border-collapse: collapse; background: #fff; border: 1px solid #e2e8f0; border-radius: 0.5rem; }
+        th, td { padding: 0.5rem 0.75rem; border-bottom: 1px solid #e2e8f0; text-align: left; font-size: 0.875rem; }
+        th { background: #f1f5f9; font-weight: 600; }
+        th[data-action] { cursor: pointer; user-select: none; }
+        td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+        td.path { word-break: break-all; font-family: ui-monospace, monospace; font-size: 0.8125rem; }
+        .increase { color: #dc2626; font-weight: 600; }
+        .decrease { color: #16a34a; font-weight: 600; }
+        .status { display: inline-block; padding: 0 0.375rem; border-radius: 0.25rem; font-size: 0.75rem; background: #f1f5f9; }
+        .status-added { background: #fee2e2; color: #991b1b; }
+        .status-removed { background: #dcfce7; color: #166534; }
+        .status-changed { background: #fef3c7; color: #92400e; }
+        .chart { width: 100%; height: 32rem; }
+        .chart-small { width: 100%; height: 20rem; }
+        .legend { display: flex; gap: 1rem; font-size: 0.8125rem; color: #475569; margin-bottom: 0.5rem; }
+        .swatch { display: inline-block; width: 0.75rem; height: 0.75rem; border-radius: 0.125rem; margin-right: 0.25rem; vertical-align: middle; }
+        .swatch-grow { background: #dc2626; }
+        .swatch-shrink { background: #16a34a; }
+        .swatch-unchanged { background: #cbd5e1; }
+        input[type=search] { width: 100%; box-sizing: border-box; padding: 0.5rem 0.75rem; border: 1px solid #cbd5e1; border-radius: 0.375rem; font-size: 0.875rem; margin-bottom: 0.75rem; }
+        .hint { color: #64748b; font-size: 0.75rem; margin: -0.5rem 0 0.75rem; }
+        .opt { border-left: 4px solid #e2e8f0; padding: 0.5rem 0.75rem; margin-bottom: 0.5rem; background: #fff; }
+        .opt-new { border-color: #dc2626; }
+        .opt-resolved { border-color: #16a34a; }
+        .opt-title { font-weight: 600; }
+        .opt-meta { color: #64748b; font-size: 0.8125rem; }
+        .empty { color: #64748b; font-style: italic; }
+        footer { text-align: center; color: #64748b; font-size: 0.875rem; padding: 1.5rem; }
+`
+
+// htmlDiffTemplate compares two reports: size summary, a treemap colored by growth,
+// the largest file deltas, category sizes and optimization changes
+const htmlDiffTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    {{if .ContentSecurityPolicy}}<meta http-equiv="Content-Security-Policy" content="{{.ContentSecurityPolicy}}">{{end}}
+    <title>{{.Title}}</title>
+    <style{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>{{.DiffCSS}}</style>
+</head>
+<body>
+<main>
+    <h1>{{.Title}}</h1>
+    <p class="subtitle">{{if .AppName}}{{.AppName}}: {{end}}{{.BaseLabel}} → {{.HeadLabel}}</p>
+
+    <table>
+        <thead>
+            <tr>
+                <th>Metric</th>
+                <th class="num">Base</th>
+                <th class="num">Head</th>
+                <th class="num">Change</th>
+            </tr>
+        </thead>
+        <tbody>
+        {{range .Sizes}}
+            <tr>
+                <td>{{.Name}}</td>
+                <td class="num">{{.Base}}</td>
+                <td class="num">{{.Head}}</td>
+                <td class="num{{if .Increase}} increase{{else if .Decrease}} decrease{{end}}">{{.Delta}}</td>
+            </tr>
+        {{end}}
+        </tbody>
+    </table>
+
+    <h2>File Tree Changes</h2>
+    <div class="panel">
+        <input type="search" id="search-input" placeholder="Search files (e.g. .png, Frameworks/, ` + "`" + `MyModule` + "`" + `)">
+        <p class="hint">Matches names, paths and file types. Use a / to match paths only, or backticks for an exact directory.</p>
+        <div class="legend">
+            <span><span class="swatch swatch-grow"></span>Grew or added</span>
+            <span><span class="swatch swatch-shrink"></span>Shrank or removed</span>
+            <span><span class="swatch swatch-unchanged"></span>Unchanged</span>
+        </div>
+        <div id="treemap" class="chart"></div>
+    </div>
+
+    <h2>Largest File Changes</h2>
+    <table>
+        <thead>
+            <tr>
+                <th data-action="sort-deltas" data-column="path">File</th>
+                <th data-action="sort-deltas" data-column="status">Status</th>
+                <th class="num" data-action="sort-deltas" data-column="baseSize">Base</th>
+                <th class="num" data-action="sort-deltas" data-column="headSize">Head</th>
+                <th class="num" data-action="sort-deltas" data-column="delta">Change</th>
+            </tr>
+        </thead>
+        <tbody id="deltas-body"></tbody>
+    </table>
+
+    <h2>Size by Category</h2>
+    <div class="panel">
+        <div id="category-chart" class="chart-small"></div>
+    </div>
+
+    {{if .Owners}}
+    <h2>Size by Owner</h2>
+    <table>
+        <thead>
+            <tr>
+                <th>Owner</th>
+                <th class="num">Base</th>
+                <th class="num">Head</th>
+                <th class="num">Change</th>
+            </tr>
+        </thead>
+        <tbody>
+        {{range .Owners}}
+            <tr>
+                <td>{{.Name}}</td>
+                <td class="num">{{.Base}}</td>
+                <td class="num">{{.Head}}</td>
+                <td class="num{{if .Increase}} increase{{else if .Decrease}} decrease{{end}}">{{.Delta}}</td>
+            </tr>
+        {{end}}
+        </tbody>
+    </table>
+    {{end}}
+
+    {{if .Manifest}}
+    <h2>Manifest Changes</h2>
+    <table>
+        <thead>
+            <tr>
+                <th>Change</th>
+                <th>Entry</th>
+                <th>Base</th>
+                <th>Head</th>
+            </tr>
+        </thead>
+        <tbody>
+        {{range .Manifest}}
+            <tr>
+                <td><span class="status status-{{.Status}}">{{.Status}}</span> {{.Kind}}</td>
+                <td class="path">{{.Name}}</td>
+                <td>{{.Baseline}}</td>
+                <td>{{.Current}}</td>
+            </tr>
+        {{end}}
+        </tbody>
+    </table>
+    {{end}}
+
+    <h2>New Optimizations</h2>
+    {{range .NewOpts}}
+    <div class="opt opt-new">
+        <div class="opt-title">{{.Title}}</div>
+        <div class="opt-meta">{{.Category}} · {{.Severity}} · {{formatBytes .Impact}}</div>
+    </div>
+    {{else}}
+    <p class="empty">No new optimizations</p>
+    {{end}}
+
+    <h2>Resolved Optimizations</h2>
+    {{range .ResolvedOpts}}
+    <div class="opt opt-resolved">
+        <div class="opt-title">{{.Title}}</div>
+        <div class="opt-meta">{{.Category}} · {{.Severity}} · {{formatBytes .Impact}}</div>
+    </div>
+    {{else}}
+    <p class="empty">No resolved optimizations</p>
+    {{end}}
+</main>
+<footer>Generated by Bitrise Bundle Inspector · {{.Timestamp}}</footer>
+
+<script{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>{{.ChartRenderer}}</script>
+<script{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>
+{{.TreeScript}}
+        const DiffData = {{.DataJSON}};
+
+        function formatBytes(bytes) {
+            const abs = Math.abs(bytes);
+            if (abs < 1024) return bytes + ' B';
+            const units = ['KB', 'MB', 'GB'];
+            let value = abs / 1024;
+            let unit = 0;
+            while (value >= 1024 && unit < units.length - 1) {
+                value /= 1024;
+                unit++;
+            }
+            return (bytes < 0 ? '-' : '') + value.toFixed(1) + ' ' + units[unit];
+        }
+
+        function formatDelta(delta) {
+            if (delta === 0) return 'no change';
+            return (delta > 0 ? '+' : '') + formatBytes(delta);
+        }
+
+        function escapeHTML(value) {
+            return String(value).replace(/[&<>"']/g, c => ({ '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;' }[c]));
+        }
+
+        // Recompute directory sizes after filtering, TreeUtils.filter only sums values
+        function aggregateDiff(node) {
+            if (!node.children || node.children.length === 0) {
+                return;
+            }
+            let base = 0;
+            let head = 0;
+            node.children.forEach(child => {
+                aggregateDiff(child);
+                base += child.baseSize || 0;
+                head += child.headSize || 0;
+            });
+            node.baseSize = base;
+            node.headSize = head;
+            node.delta = head - base;
+        }
+
+        // Color nodes red for growth and green for shrinkage, stronger for larger relative changes
+        function colorDiff(node) {
+            let strength = 0;
+            if (node.status === 'added' || node.status === 'removed') {
+                strength = 1;
+            } else if (node.delta && node.baseSize > 0) {
+                strength = Math.min(1, 0.25 + Math.abs(node.delta) / node.baseSize);
+            }
+            const neutral = [203, 213, 225];
+            const target = node.delta > 0 || node.status === 'added' ? [220, 38, 38] : [22, 163, 74];
+            const rgb = neutral.map((c, i) => Math.round(c + (target[i] - c) * strength));
+            node.itemStyle = { color: 'rgb(' + rgb.join(',') + ')' };
+            node.label = { color: strength > 0.5 ? '#fff' : '#0f172a' };
+            (node.children || []).forEach(colorDiff);
+        }
+
+        function leafPaths(node, paths) {
+            if (!node.children || node.children.length === 0) {
+                if (node.path) paths.add(node.path);
+            } else {
+                node.children.forEach(child => leafPaths(child, paths));
+            }
+            return paths;
+        }
+
+        const treemapChart = echarts.init(document.getElementById('treemap'));
+        const categoryChart = echarts.init(document.getElementById('category-chart'));
+
+        function renderTreemap(tree) {
+            aggregateDiff(tree);
+            colorDiff(tree);
+            treemapChart.setOption({
+                tooltip: {
+                    formatter: function (params) {
+                        const d = params.data || {};
+                        return '<strong>' + escapeHTML(d.path || d.name) + '</strong><br>' +
+                            escapeHTML(formatBytes(d.baseSize || 0)) + ' → ' + escapeHTML(formatBytes(d.headSize || 0)) +
+                            ' (' + escapeHTML(formatDelta(d.delta || 0)) + ')';
+                    }
+                },
+                series: [{
+                    type: 'treemap',
+                    data: [tree],
+                    itemStyle: { borderColor: '#fff' },
+                    breadcrumb: { itemStyle: { color: '#475569' } }
+                }]
+            });
+        }
+
+        // Deltas table, sorted by the size of the change until a column is clicked
+        const deltasState = { rows: DiffData.deltas || [], column: null, ascending: false };
+
+        function renderDeltas() {
+            const rows = deltasState.rows.slice();
+            const column = deltasState.column;
+            if (column) {
+                rows.sort((a, b) => {
+                    const x = a[column];
+                    const y = b[column];
+                    const order = typeof x === 'string' ? x.localeCompare(y) : x - y;
+                    return deltasState.ascending ? order : -order;
+                });
+            }
+
+            const body = document.getElementById('deltas-body');
+            body.textContent = '';
+            if (rows.length === 0) {
+                const tr = body.insertRow();
+                const td = tr.insertCell();
+                td.colSpan = 5;
+                td.className = 'empty';
+                td.textContent = 'No file changes';
+                return;
+            }
+            rows.forEach(row => {
+                const tr = body.insertRow();
+                const cells = [
+                    [row.path, 'path'],
+                    [row.status, ''],
+                    [formatBytes(row.baseSize), 'num'],
+                    [formatBytes(row.headSize), 'num'],
+                    [formatDelta(row.delta), 'num ' + (row.delta > 0 ? 'increase' : row.delta < 0 ? 'decrease' : '')]
+                ];
+                cells.forEach(([text, className], i) => {
+                    const td = tr.insertCell();
+                    td.className = className;
+                    if (i === 1) {
+                        const badge = document.createElement('span');
+                        badge.className = 'status status-' + text;
+                        badge.textContent = text;
+                        td.appendChild(badge);
+                    } else {
+                        td.textContent = text;
+                    }
+                });
+            });
+        }
+
+        document.addEventListener('click', function (event) {
+            const target = event.target.closest('[data-action="sort-deltas"]');
+            if (!target) return;
+            const column = target.getAttribute('data-column');
+            deltasState.ascending = deltasState.column === column ? !deltasState.ascending : column === 'path';
+            deltasState.column = column;
+            renderDeltas();
+        });
+
+        function renderCategories() {
+            const categories = DiffData.categories || [];
+            categoryChart.setOption({
+                tooltip: {
+                    formatter: function (params) {
+                        const c = categories.find(cat => cat.name === params.name) || { base: 0, head: 0 };
+                        return '<strong>' + escapeHTML(params.name) + '</strong><br>' +
+                            escapeHTML(formatBytes(c.base)) + ' → ' + escapeHTML(formatBytes(c.head)) +
+                            ' (' + escapeHTML(formatDelta(c.head - c.base)) + ')';
+                    }
+                },
+                legend: {},
+                xAxis: { type: 'value', axisLabel: { formatter: formatBytes } },
+                yAxis: { type: 'category', data: categories.map(c => c.name) },
+                series: [
+                    { name: 'Base', type: 'bar', data: categories.map(c => c.base), itemStyle: { color: '#94a3b8' } },
+                    { name: 'Head', type: 'bar', data: categories.map(c => c.head), itemStyle: { color: '#9247C2' } }
+                ]
+            });
+        }
+
+        // Search filters the treemap and the deltas table with the same syntax as the report page
+        let searchTimeout = null;
+        document.getElementById('search-input').addEventListener('input', function (e) {
+            const query = e.target.value.trim();
+            if (searchTimeout) {
+                clearTimeout(searchTimeout);
+            }
+            searchTimeout = setTimeout(function () {
+                const parsed = parseSearchQuery(query);
+                if (parsed.mode === 'empty') {
+                    deltasState.rows = DiffData.deltas || [];
+                    renderTreemap(TreeUtils.deepCopy(DiffData.tree));
+                    renderDeltas();
+                    return;
+                }
+
+                const filtered = filterTreeByQuery(TreeUtils.deepCopy(DiffData.tree), parsed.mode, parsed.query);
+                if (!filtered || (filtered.children && filtered.children.length === 0)) {
+                    deltasState.rows = [];
+                    renderTreemap({ name: 'No results', value: 0, baseSize: 0, headSize: 0, delta: 0, children: [] });
+                    renderDeltas();
+                    return;
+                }
+
+                const paths = leafPaths(filtered, new Set());
+                deltasState.rows = (DiffData.deltas || []).filter(row => paths.has(row.path));
+                renderTreemap(filtered);
+                renderDeltas();
+            }, 300);
+        });
+
+        window.addEventListener('resize', function () {
+            treemapChart.resize();
+            categoryChart.resize();
+        });
+
+        renderTreemap(TreeUtils.deepCopy(DiffData.tree));
+        renderDeltas();
+        renderCategories();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,249 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func diffTestTrees() (base, head []*types.FileNode) {
+	base = []*types.FileNode{
+		{Name: "Frameworks", Path: "Frameworks", IsDir: true, Children: []*types.FileNode{
+			{Name: "A.framework", Path: "Frameworks/A.framework", Size: 1000},
+			{Name: "Old.framework", Path: "Frameworks/Old.framework", Size: 300},
+		}},
+		{Name: "Info.plist", Path: "Info.plist", Size: 50},
+	}
+	head = []*types.FileNode{
+		{Name: "Frameworks", Path: "Frameworks", IsDir: true, Children: []*types.FileNode{
+			{Name: "A.framework", Path: "Frameworks/A.framework", Size: 1500},
+			{Name: "New.framework", Path: "Frameworks/New.framework", Size: 200},
+		}},
+		{Name: "Info.plist", Path: "Info.plist", Size: 50},
+	}
+	return base, head
+}
+
+func TestBuildDiffTree(t *testing.T) {
+	tree := buildDiffTree(diffTestTrees())
+
+	if tree.BaseSize != 1350 || tree.HeadSize != 1750 || tree.Delta != 400 {
+		t.Errorf("root sizes = %d/%d/%d", tree.BaseSize, tree.HeadSize, tree.Delta)
+	}
+	if tree.Status != diffChanged {
+		t.Errorf("root status = %q", tree.Status)
+	}
+
+	frameworks := tree.Children[0]
+	want := map[string]struct {
+		status string
+		value  int64
+		delta  int64
+	}{
+		"A.framework":   {diffChanged, 1500, 500},
+		"New.framework": {diffAdded, 200, 200},
+		"Old.framework": {diffRemoved, 300, -300},
+	}
+	if len(frameworks.Children) != len(want) {
+		t.Fatalf("got %d framework nodes, want %d", len(frameworks.Children), len(want))
+	}
+	for _, child := range frameworks.Children {
+		w := want[child.Name]
+		if child.Status != w.status || child.Value != w.value || child.Delta != w.delta {
+			t.Errorf("%s = %+v, want %+v", child.Name, child, w)
+		}
+	}
+	if frameworks.Children[2].Name != "Old.framework" {
+		t.Errorf("removed nodes should come last, got %s", frameworks.Children[2].Name)
+	}
+	if frameworks.Value != 2000 {
+		t.Errorf("directory value = %d, want sum of children", frameworks.Value)
+	}
+
+	if plist := tree.Children[1]; plist.Status != diffUnchanged {
+		t.Errorf("Info.plist status = %q", plist.Status)
+	}
+}
+
+func TestCollectDiffDeltas(t *testing.T) {
+	deltas := collectDiffDeltas(buildDiffTree(diffTestTrees()))
+
+	var paths []string
+	for _, d := range deltas {
+		paths = append(paths, d.Path)
+		if d.Children != nil {
+			t.Errorf("%s: deltas should not carry children", d.Path)
+		}
+	}
+	want := "Frameworks/A.framework,Frameworks/Old.framework,Frameworks/New.framework"
+	if strings.Join(paths, ",") != want {
+		t.Errorf("deltas = %v, want %s", paths, want)
+	}
+}
+
+func TestDiffOptimizations(t *testing.T) {
+	base := []types.Optimization{
+		{Category: "images", Title: "Convert a.png to WebP"},
+		{Category: "duplicates", Title: "Remove duplicate files"},
+	}
+	head := []types.Optimization{
+		{Category: "images", Title: "Convert a.png to WebP"},
+		{Category: "strip-symbols", Title: "Strip debug symbols"},
+	}
+
+	added, resolved := diffOptimizations(base, head)
+	if len(added) != 1 || added[0].Title != "Strip debug symbols" {
+		t.Errorf("added = %+v", added)
+	}
+	if len(resolved) != 1 || resolved[0].Title != "Remove duplicate files" {
+		t.Errorf("resolved = %+v", resolved)
+	}
+}
+
+func TestDiffOptimizations_CountsInTitles(t *testing.T) {
+	base := []types.Optimization{
+		{Category: "zip-storage", Title: "Compress 3 stored files", Files: []string{"a.json", "b.json", "c.json"}},
+		{Category: "duplicates", Title: "Remove 1 duplicate copies of files", Files: []string{"x.png", "y/x.png"}},
+		{Category: "duplicates", Title: "Remove 2 duplicate copies of files", Files: []string{"z.png", "y/z.png", "w/z.png"}},
+	}
+	head := []types.Optimization{
+		{Category: "zip-storage", Title: "Compress 4 stored files", Files: []string{"a.json", "b.json", "c.json", "d.json"}},
+		{Category: "duplicates", Title: "Remove 1 duplicate copies of files", Files: []string{"z.png", "y/z.png"}},
+	}
+
+	// The x.png duplicates are resolved, the z.png ones only lost a copy
+	added, resolved := diffOptimizations(base, head)
+	if len(added) != 0 {
+		t.Errorf("added = %+v, want none", added)
+	}
+	if len(resolved) != 1 || resolved[0].Files[0] != "x.png" {
+		t.Errorf("resolved = %+v, want the x.png duplicates", resolved)
+	}
+}
+
+func TestHTMLDiffFormatter_Format(t *testing.T) {
+	baseTree, headTree := diffTestTrees()
+
+	base := newMinimalHTMLReport()
+	base.FileTree = baseTree
+	base.ArtifactInfo.Version = "1.0.0"
+	base.SizeBreakdown.Frameworks = 1300
+	base.Optimizations = []types.Optimization{{Category: "duplicates", Title: "Remove duplicate files", Impact: 2048}}
+
+	head := newMinimalHTMLReport()
+	head.FileTree = headTree
+	head.ArtifactInfo.Version = "1.1.0"
+	head.ArtifactInfo.Size = 1500
+	head.SizeBreakdown.Frameworks = 1700
+	head.Optimizations = []types.Optimization{{Category: "images", Title: "Convert <b>icon</b> to WebP", Impact: 1024}}
+
+	var buf bytes.Buffer
+	if err := NewHTMLDiffFormatter(base).Format(&buf, head); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"1.0.0 → 1.1.0",
+		"&#43;500 B",
+		`"path":"Frameworks/New.framework"`,
+		`"status":"removed"`,
+		`{"name":"Frameworks","base":1300,"head":1700}`,
+		"Convert &lt;b&gt;icon&lt;/b&gt; to WebP",
+		"Remove duplicate files",
+		"filterTreeByQuery",
+		"window.echarts",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	for _, remote := range []string{"https://", "<link "} {
+		if strings.Contains(output, remote) {
+			t.Errorf("output references %q", remote)
+		}
+	}
+}
+
+func TestHTMLDiffFormatter_CSPNonce(t *testing.T) {
+	formatter := NewHTMLDiffFormatter(newMinimalHTMLReport())
+	formatter.CSPNonce = "dGVzdA=="
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, newMinimalHTMLReport()); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	if got := strings.Count(output, `nonce="dGVzdA=="`); got != 3 {
+		t.Errorf("got %d nonce attributes, want 3", got)
+	}
+	if !strings.Contains(output, "script-src &#39;nonce-dGVzdA==&#39;") {
+		t.Error("output missing Content-Security-Policy with the nonce")
+	}
+	if strings.Contains(output, ` style="`) {
+		t.Error("inline style attributes are blocked by the Content-Security-Policy")
+	}
+}
+
+func TestHTMLDiffFormatter_NoBaseline(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewHTMLDiffFormatter(nil).Format(&buf, newMinimalHTMLReport()); err == nil {
+		t.Error("Format() expected error without a baseline")
+	}
+}
+
+func TestHTMLDiffFormatter_Owners(t *testing.T) {
+	base := newMinimalHTMLReport()
+	base.Ownership = []types.OwnerSize{{Owner: "payments", Size: 1000}}
+	head := newMinimalHTMLReport()
+	head.Ownership = []types.OwnerSize{{Owner: "payments", Size: 1200}, {Owner: "checkout", Size: 300}}
+
+	var buf bytes.Buffer
+	if err := NewHTMLDiffFormatter(base).Format(&buf, head); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, "<h2>Size by Owner</h2>") {
+		t.Fatal("output missing owner section")
+	}
+	for _, want := range []string{"<td>checkout</td>", "&#43;300 B", "<td>payments</td>", "&#43;200 B"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Index(output, "<td>checkout</td>") > strings.Index(output, "<td>payments</td>") {
+		t.Error("expected largest change first")
+	}
+
+	buf.Reset()
+	if err := NewHTMLDiffFormatter(newMinimalHTMLReport()).Format(&buf, head); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "Size by Owner") {
+		t.Error("owner section shown without baseline ownership")
+	}
+}
+
+func TestHTMLDiffFormatter_ManifestChanges(t *testing.T) {
+	base := newMinimalHTMLReport()
+	base.Android = &types.AndroidDetails{Manifest: &types.ManifestInfo{}}
+	head := newMinimalHTMLReport()
+	head.Android = &types.AndroidDetails{Manifest: &types.ManifestInfo{Permissions: []string{"android.permission.CAMERA"}}}
+
+	var buf bytes.Buffer
+	if err := NewHTMLDiffFormatter(base).Format(&buf, head); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, "<h2>Manifest Changes</h2>") {
+		t.Fatal("output missing manifest section")
+	}
+	if !strings.Contains(output, `<span class="status status-added">added</span> Permission`) || !strings.Contains(output, "android.permission.CAMERA") {
+		t.Error("output missing added permission")
+	}
+}
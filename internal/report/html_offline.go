@@ -0,0 +1,717 @@
+package report
+
+// htmlOfflineCSS replaces the Tailwind CDN in offline reports: a preflight reset followed by
+// the utility classes htmlTemplate uses, precompiled with the template's theme colors.
+// Add a rule here when the template starts using a new utility class.
+const htmlOfflineCSS = `
+/* Preflight */
+*,::before,::after{box-sizing:border-box;border-width:0;border-style:solid;border-color:hsl(var(--border))}
+html{line-height:1.5;-webkit-text-size-adjust:100%;tab-size:4;font-family:system-ui,-apple-system,'Segoe UI',Roboto,sans-serif}
+body{margin:0;line-height:inherit}
+hr{height:0;color:inherit;border-top-width:1px}
+h1,h2,h3,h4,h5,h6{font-size:inherit;font-weight:inherit}
+a{color:inherit;text-decoration:inherit}
+b,strong{font-weight:bolder}
+code,kbd,samp,pre{font-family:ui-monospace,SFMono-Regular,Menlo,Consolas,monospace;font-size:1em}
+small{font-size:80%}
+table{text-indent:0;border-color:inherit;border-collapse:collapse}
+button,input,optgroup,select,textarea{font-family:inherit;font-size:100%;font-weight:inherit;line-height:inherit;color:inherit;margin:0;padding:0}
+button,select{text-transform:none}
+button,[type='button'],[type='reset'],[type='submit']{-webkit-appearance:button;background-color:transparent;background-image:none}
+[type='search']{-webkit-appearance:textfield;outline-offset:-2px}
+summary{display:list-item}
+blockquote,dl,dd,h1,h2,h3,h4,h5,h6,hr,figure,p,pre{margin:0}
+ol,ul,menu{list-style:none;margin:0;padding:0}
+input::placeholder,textarea::placeholder{opacity:1;color:#9ca3af}
+button,[role="button"]{cursor:pointer}
+:disabled{cursor:default}
+img,svg,video,canvas,audio,iframe,embed,object{display:block;vertical-align:middle}
+img,video{max-width:100%;height:auto}
+[hidden]{display:none}
+
+/* Utilities */
+.pointer-events-none{pointer-events:none}
+.absolute{position:absolute}
+.relative{position:relative}
+.sticky{position:sticky}
+.left-3{left:0.75rem}
+.top-0{top:0px}
+.top-1\/2{top:50%}
+.z-50{z-index:50}
+.mx-auto{margin-left:auto;margin-right:auto}
+.mt-1{margin-top:0.25rem}
+.mt-1\.5{margin-top:0.375rem}
+.mt-4{margin-top:1rem}
+.mt-6{margin-top:1.5rem}
+.mb-1{margin-bottom:0.25rem}
+.mb-1\.5{margin-bottom:0.375rem}
+.mb-2{margin-bottom:0.5rem}
+.mb-2\.5{margin-bottom:0.625rem}
+.mb-4{margin-bottom:1rem}
+.mb-6{margin-bottom:1.5rem}
+.ml-1{margin-left:0.25rem}
+.block{display:block}
+.inline-block{display:inline-block}
+.flex{display:flex}
+.inline-flex{display:inline-flex}
+.grid{display:grid}
+.hidden{display:none}
+.h-10{height:2.5rem}
+.h-12{height:3rem}
+.h-16{height:4rem}
+.h-3{height:0.75rem}
+.h-3\.5{height:0.875rem}
+.h-4{height:1rem}
+.h-4\.5{height:1.125rem}
+.h-5{height:1.25rem}
+.h-6{height:1.5rem}
+.h-8{height:2rem}
+.h-9{height:2.25rem}
+.max-w-2xl{max-width:42rem}
+.max-w-7xl{max-width:80rem}
+.min-w-0{min-width:0px}
+.w-1\/4{width:25%}
+.w-12{width:3rem}
+.w-16{width:4rem}
+.w-3{width:0.75rem}
+.w-3\.5{width:0.875rem}
+.w-32{width:8rem}
+.w-4{width:1rem}
+.w-4\.5{width:1.125rem}
+.w-6{width:1.5rem}
+.w-8{width:2rem}
+.w-9{width:2.25rem}
+.w-full{width:100%}
+.flex-1{flex:1 1 0%}
+.flex-shrink-0{flex-shrink:0}
+.flex-grow{flex-grow:1}
+.-translate-y-1\/2{--tw-translate-y:-50%;transform:translate(var(--tw-translate-x,0),var(--tw-translate-y,0)) rotate(var(--tw-rotate,0)) scale(var(--tw-scale-x,1),var(--tw-scale-y,1))}
+.transform{transform:translate(var(--tw-translate-x,0),var(--tw-translate-y,0)) rotate(var(--tw-rotate,0)) scale(var(--tw-scale-x,1),var(--tw-scale-y,1))}
+.cursor-help{cursor:help}
+.cursor-pointer{cursor:pointer}
+.select-none{-webkit-user-select:none;user-select:none}
+.scroll-m-20{scroll-margin:5rem}
+.grid-cols-1{grid-template-columns:repeat(1,minmax(0,1fr))}
+.grid-cols-2{grid-template-columns:repeat(2,minmax(0,1fr))}
+.flex-col{flex-direction:column}
+.flex-wrap{flex-wrap:wrap}
+.items-center{align-items:center}
+.items-start{align-items:flex-start}
+.justify-between{justify-content:space-between}
+.justify-center{justify-content:center}
+.justify-end{justify-content:flex-end}
+.gap-0\.5{gap:0.125rem}
+.gap-1{gap:0.25rem}
+.gap-1\.5{gap:0.375rem}
+.gap-2{gap:0.5rem}
+.gap-3{gap:0.75rem}
+.gap-4{gap:1rem}
+.gap-6{gap:1.5rem}
+.gap-px{gap:1px}
+.space-y-0\.5>:not([hidden])~:not([hidden]){margin-top:0.125rem}
+.space-y-1>:not([hidden])~:not([hidden]){margin-top:0.25rem}
+.space-y-3>:not([hidden])~:not([hidden]){margin-top:0.75rem}
+.space-y-4>:not([hidden])~:not([hidden]){margin-top:1rem}
+.space-y-6>:not([hidden])~:not([hidden]){margin-top:1.5rem}
+.divide-y>:not([hidden])~:not([hidden]){border-top-width:1px;border-bottom-width:0px}
+.divide-border{border-color:hsl(var(--border))}
+.overflow-hidden{overflow:hidden}
+.truncate{overflow:hidden;text-overflow:ellipsis;white-space:nowrap}
+.whitespace-nowrap{white-space:nowrap}
+.break-all{word-break:break-all}
+.rounded{border-radius:0.25rem}
+.rounded-full{border-radius:9999px}
+.rounded-lg{border-radius:var(--radius)}
+.rounded-md{border-radius:calc(var(--radius) - 2px)}
+.rounded-sm{border-radius:calc(var(--radius) - 4px)}
+.rounded-xl{border-radius:0.75rem}
+.rounded-t-lg{border-top-left-radius:var(--radius);border-top-right-radius:var(--radius)}
+.rounded-b-lg{border-bottom-right-radius:var(--radius);border-bottom-left-radius:var(--radius)}
+.border{border-width:1px}
+.border-t{border-top-width:1px}
+.border-b{border-bottom-width:1px}
+.border-border{border-color:hsl(var(--border))}
+.border-amber-500\/50{border-color:rgb(245 158 11 / 0.5)}
+.border-input{border-color:hsl(var(--input))}
+.border-muted-foreground\/30{border-color:hsl(var(--muted-foreground) / 0.3)}
+.bg-amber-500\/10{background-color:rgb(245 158 11 / 0.1)}
+.bg-background{background-color:hsl(var(--background))}
+.bg-background\/95{background-color:hsl(var(--background) / 0.95)}
+.bg-border{background-color:hsl(var(--border))}
+.bg-card{background-color:hsl(var(--card))}
+.bg-muted{background-color:hsl(var(--muted))}
+.bg-muted\/20{background-color:hsl(var(--muted) / 0.2)}
+.bg-muted\/30{background-color:hsl(var(--muted) / 0.3)}
+.bg-muted\/50{background-color:hsl(var(--muted) / 0.5)}
+.bg-primary{background-color:rgb(146 71 194)}
+.bg-success\/10{background-color:rgb(52 199 89 / 0.1)}
+@supports (backdrop-filter:var(--tw)){.supports-\[backdrop-filter\]\:bg-background\/60{background-color:hsl(var(--background) / 0.6)}}
+.bg-gradient-to-r{background-image:linear-gradient(to right,var(--tw-gradient-stops))}
+.from-primary\/5{--tw-gradient-from:rgb(146 71 194 / 0.05);--tw-gradient-to:transparent;--tw-gradient-stops:var(--tw-gradient-from),var(--tw-gradient-to)}
+.to-primary\/10{--tw-gradient-to:rgb(146 71 194 / 0.1)}
+.p-1{padding:0.25rem}
+.p-4{padding:1rem}
+.p-6{padding:1.5rem}
+.px-1\.5{padding-left:0.375rem;padding-right:0.375rem}
+.px-2{padding-left:0.5rem;padding-right:0.5rem}
+.px-3{padding-left:0.75rem;padding-right:0.75rem}
+.px-4{padding-left:1rem;padding-right:1rem}
+.px-5{padding-left:1.25rem;padding-right:1.25rem}
+.px-6{padding-left:1.5rem;padding-right:1.5rem}
+.py-0\.5{padding-top:0.125rem;padding-bottom:0.125rem}
+.py-1{padding-top:0.25rem;padding-bottom:0.25rem}
+.py-1\.5{padding-top:0.375rem;padding-bottom:0.375rem}
+.py-10{padding-top:2.5rem;padding-bottom:2.5rem}
+.py-2{padding-top:0.5rem;padding-bottom:0.5rem}
+.py-4{padding-top:1rem;padding-bottom:1rem}
+.py-5{padding-top:1.25rem;padding-bottom:1.25rem}
+.py-6{padding-top:1.5rem;padding-bottom:1.5rem}
+.py-8{padding-top:2rem;padding-bottom:2rem}
+.pt-4{padding-top:1rem}
+.pl-10{padding-left:2.5rem}
+.text-left{text-align:left}
+.text-center{text-align:center}
+.text-right{text-align:right}
+.align-middle{vertical-align:middle}
+.align-top{vertical-align:top}
+.font-sans{font-family:Lato,system-ui,-apple-system,'Segoe UI',Roboto,sans-serif}
+.font-mono{font-family:'IBM Plex Mono',ui-monospace,SFMono-Regular,Menlo,Consolas,monospace}
+.text-2xl{font-size:1.5rem;line-height:2rem}
+.text-3xl{font-size:1.875rem;line-height:2.25rem}
+.text-lg{font-size:1.125rem;line-height:1.75rem}
+.text-sm{font-size:0.875rem;line-height:1.25rem}
+.text-xl{font-size:1.25rem;line-height:1.75rem}
+.text-xs{font-size:0.75rem;line-height:1rem}
+.text-\[10px\]{font-size:10px}
+.font-bold{font-weight:700}
+.font-medium{font-weight:500}
+.font-semibold{font-weight:600}
+.uppercase{text-transform:uppercase}
+.leading-none{line-height:1}
+.leading-normal{line-height:1.5}
+.leading-relaxed{line-height:1.625}
+.tracking-tight{letter-spacing:-0.025em}
+.tracking-wide{letter-spacing:0.025em}
+.text-amber-700{color:rgb(180 83 9)}
+.text-card-foreground{color:hsl(var(--card-foreground))}
+.text-foreground{color:hsl(var(--foreground))}
+.text-green-600{color:rgb(22 163 74)}
+.text-muted-foreground{color:hsl(var(--muted-foreground))}
+.text-primary{color:rgb(146 71 194)}
+.text-primary-foreground{color:rgb(255 255 255)}
+.text-success{color:rgb(52 199 89)}
+.antialiased{-webkit-font-smoothing:antialiased;-moz-osx-font-smoothing:grayscale}
+.opacity-0{opacity:0}
+.shadow{box-shadow:0 1px 3px 0 rgb(0 0 0 / 0.1),0 1px 2px -1px rgb(0 0 0 / 0.1)}
+.shadow-md{box-shadow:0 4px 6px -1px rgb(0 0 0 / 0.1),0 2px 4px -2px rgb(0 0 0 / 0.1)}
+.shadow-sm{box-shadow:0 1px 2px 0 rgb(0 0 0 / 0.05)}
+.ring-2{box-shadow:0 0 0 var(--tw-ring-offset-width,0px) var(--tw-ring-offset-color,#fff),0 0 0 calc(2px + var(--tw-ring-offset-width,0px)) var(--tw-ring-color,rgb(59 130 246 / 0.5))}
+.ring-background{--tw-ring-color:hsl(var(--background))}
+.ring-offset-background{--tw-ring-offset-color:hsl(var(--background))}
+.backdrop-blur{-webkit-backdrop-filter:blur(8px);backdrop-filter:blur(8px)}
+.transition-all{transition-property:all;transition-timing-function:cubic-bezier(0.4,0,0.2,1);transition-duration:150ms}
+.transition-colors{transition-property:color,background-color,border-color,text-decoration-color,fill,stroke;transition-timing-function:cubic-bezier(0.4,0,0.2,1);transition-duration:150ms}
+.transition-transform{transition-property:transform;transition-timing-function:cubic-bezier(0.4,0,0.2,1);transition-duration:150ms}
+.duration-200{transition-duration:200ms}
+.duration-300{transition-duration:300ms}
+.disabled\:pointer-events-none:disabled{pointer-events:none}
+.hover\:-translate-y-0\.5:hover{--tw-translate-y:-0.125rem;transform:translate(var(--tw-translate-x,0),var(--tw-translate-y,0)) rotate(var(--tw-rotate,0)) scale(var(--tw-scale-x,1),var(--tw-scale-y,1))}
+.group:hover .group-hover\:scale-110{--tw-scale-x:1.1;--tw-scale-y:1.1;transform:translate(var(--tw-translate-x,0),var(--tw-translate-y,0)) rotate(var(--tw-rotate,0)) scale(var(--tw-scale-x,1),var(--tw-scale-y,1))}
+.disabled\:cursor-not-allowed:disabled{cursor:not-allowed}
+.file\:border-0::file-selector-button{border-width:0px}
+.file\:bg-transparent::file-selector-button{background-color:transparent}
+.hover\:bg-accent:hover{background-color:hsl(var(--accent))}
+.hover\:bg-muted:hover{background-color:hsl(var(--muted))}
+.hover\:bg-muted\/50:hover{background-color:hsl(var(--muted) / 0.5)}
+.file\:text-sm::file-selector-button{font-size:0.875rem;line-height:1.25rem}
+.file\:font-medium::file-selector-button{font-weight:500}
+.hover\:text-accent-foreground:hover{color:hsl(var(--accent-foreground))}
+.hover\:text-foreground:hover{color:hsl(var(--foreground))}
+.placeholder\:text-muted-foreground::placeholder{color:hsl(var(--muted-foreground))}
+.hover\:underline:hover{text-decoration-line:underline}
+.disabled\:opacity-50:disabled{opacity:0.5}
+.group:hover .group-hover\:opacity-50{opacity:0.5}
+.hover\:shadow-md:hover{box-shadow:0 4px 6px -1px rgb(0 0 0 / 0.1),0 2px 4px -2px rgb(0 0 0 / 0.1)}
+.focus-visible\:outline-none:focus-visible{outline:2px solid transparent;outline-offset:2px}
+.focus-visible\:ring-2:focus-visible{box-shadow:0 0 0 var(--tw-ring-offset-width,0px) var(--tw-ring-offset-color,#fff),0 0 0 calc(2px + var(--tw-ring-offset-width,0px)) var(--tw-ring-color,rgb(59 130 246 / 0.5))}
+.focus-visible\:ring-offset-2:focus-visible{--tw-ring-offset-width:2px}
+.focus-visible\:ring-primary:focus-visible{--tw-ring-color:rgb(146 71 194)}
+.focus-visible\:ring-ring:focus-visible{--tw-ring-color:hsl(var(--ring))}
+.dark .dark\:text-amber-400{color:rgb(251 191 36)}
+.dark .dark\:text-green-500{color:rgb(34 197 94)}
+@media (min-width:640px){.sm\:inline{display:inline}.sm\:inline-flex{display:inline-flex}}
+@media (min-width:768px){.md\:grid-cols-2{grid-template-columns:repeat(2,minmax(0,1fr))}.md\:grid-cols-4{grid-template-columns:repeat(4,minmax(0,1fr))}}
+@media (min-width:1024px){.lg\:grid-cols-2{grid-template-columns:repeat(2,minmax(0,1fr))}}
+`
+
+// htmlChartRenderer is a lightweight SVG renderer for offline reports. It implements the
+// subset of the ECharts API the template uses (init, setOption, resize, dispose and
+// graphic.LinearGradient) for treemap, pie and horizontal, optionally grouped, bar series.
+const htmlChartRenderer = `
+(function () {
+    if (window.echarts) {
+        return;
+    }
+
+    const SVG_NS = 'http://www.w3.org/2000/svg';
+    const PALETTE = ['#5470c6', '#91cc75', '#fac858', '#ee6666', '#73c0de', '#3ba272', '#fc8452', '#9a60b4', '#ea7ccc'];
+    const TREEMAP_HEADER = 18;
+    const TREEMAP_MAX_DEPTH = 3;
+    const BREADCRUMB_HEIGHT = 28;
+
+    let gradientCount = 0;
+
+    function createSVG(tag, attrs, parent) {
+        const el = document.createElementNS(SVG_NS, tag);
+        for (const key in attrs) {
+            el.setAttribute(key, attrs[key]);
+        }
+        if (parent) {
+            parent.appendChild(el);
+        }
+        return el;
+    }
+
+    function createText(content, attrs, parent) {
+        const el = createSVG('text', attrs, parent);
+        el.textContent = content;
+        return el;
+    }
+
+    function LinearGradient(x, y, x2, y2, colorStops) {
+        this.x = x;
+        this.y = y;
+        this.x2 = x2;
+        this.y2 = y2;
+        this.colorStops = colorStops;
+    }
+
+    // Resolve a color, adding a <linearGradient> definition for gradients
+    function resolveColor(color, defs) {
+        if (!(color instanceof LinearGradient)) {
+            return color;
+        }
+        const id = 'chart-gradient-' + (++gradientCount);
+        const gradient = createSVG('linearGradient', { id: id, x1: color.x, y1: color.y, x2: color.x2, y2: color.y2 }, defs);
+        color.colorStops.forEach(stop => createSVG('stop', { offset: stop.offset, 'stop-color': stop.color }, gradient));
+        return 'url(#' + id + ')';
+    }
+
+    function parsePercent(value, fallback) {
+        const parsed = parseFloat(value);
+        return isNaN(parsed) ? fallback : parsed / 100;
+    }
+
+    // Shorten a label to fit the given width, assuming an average glyph width
+    function fitText(label, width, fontSize) {
+        label = String(label == null ? '' : label);
+        const maxChars = Math.floor(width / (fontSize * 0.6));
+        if (maxChars < 3) {
+            return '';
+        }
+        return label.length > maxChars ? label.slice(0, maxChars - 1) + '…' : label;
+    }
+
+    // Apply an ECharts formatter: a function or a template with {b} name, {c} value and {d} percent
+    function applyFormatter(formatter, params) {
+        if (typeof formatter === 'function') {
+            return formatter(params);
+        }
+        if (typeof formatter === 'string') {
+            return formatter.replace('{b}', params.name).replace('{c}', params.value).replace('{d}', params.percent);
+        }
+        return params.name;
+    }
+
+    function nodeValue(node) {
+        if (node.value) {
+            return node.value;
+        }
+        return (node.children || []).reduce((sum, child) => sum + nodeValue(child), 0);
+    }
+
+    // Squarified treemap layout: returns a rectangle for every item with a positive value
+    function squarify(nodes, x, y, width, height) {
+        const items = nodes
+            .map(node => ({ node: node, value: nodeValue(node) }))
+            .filter(item => item.value > 0)
+            .sort((a, b) => b.value - a.value);
+        const total = items.reduce((sum, item) => sum + item.value, 0);
+        const cells = [];
+        if (total === 0 || width <= 0 || height <= 0) {
+            return cells;
+        }
+
+        const scale = (width * height) / total;
+        const worst = (row, side) => {
+            const areas = row.map(item => item.value * scale);
+            const sum = areas.reduce((a, b) => a + b, 0);
+            const max = Math.max.apply(null, areas);
+            const min = Math.min.apply(null, areas);
+            return Math.max((side * side * max) / (sum * sum), (sum * sum) / (side * side * min));
+        };
+
+        let rect = { x: x, y: y, w: width, h: height };
+        let index = 0;
+        while (index < items.length) {
+            const side = Math.min(rect.w, rect.h);
+            const row = [items[index++]];
+            while (index < items.length && worst(row.concat([items[index]]), side) <= worst(row, side)) {
+                row.push(items[index++]);
+            }
+
+            const rowArea = row.reduce((sum, item) => sum + item.value * scale, 0);
+            if (rect.w >= rect.h) {
+                const columnWidth = rowArea / rect.h;
+                let offset = rect.y;
+                row.forEach(item => {
+                    const h = (item.value * scale) / columnWidth;
+                    cells.push({ node: item.node, value: item.value, x: rect.x, y: offset, w: columnWidth, h: h });
+                    offset += h;
+                });
+                rect = { x: rect.x + columnWidth, y: rect.y, w: rect.w - columnWidth, h: rect.h };
+            } else {
+                const rowHeight = rowArea / rect.w;
+                let offset = rect.x;
+                row.forEach(item => {
+                    const w = (item.value * scale) / rowHeight;
+                    cells.push({ node: item.node, value: item.value, x: offset, y: rect.y, w: w, h: rowHeight });
+                    offset += w;
+                });
+                rect = { x: rect.x, y: rect.y + rowHeight, w: rect.w, h: rect.h - rowHeight };
+            }
+        }
+        return cells;
+    }
+
+    // Donut segment path between two angles
+    function arcPath(cx, cy, inner, outer, start, end) {
+        const point = (r, a) => (cx + r * Math.cos(a)).toFixed(2) + ' ' + (cy + r * Math.sin(a)).toFixed(2);
+        const large = end - start > Math.PI ? 1 : 0;
+        return 'M' + point(outer, start) +
+            ' A' + outer + ' ' + outer + ' 0 ' + large + ' 1 ' + point(outer, end) +
+            ' L' + point(inner, end) +
+            ' A' + inner + ' ' + inner + ' 0 ' + large + ' 0 ' + point(inner, start) + ' Z';
+    }
+
+    function Chart(container) {
+        this.container = container;
+        this.option = null;
+        this.items = [];
+        this.treemapRoot = null;
+        this.treemapPath = [];
+    }
+
+    Chart.prototype.setOption = function (option) {
+        this.option = option;
+        this.render();
+    };
+
+    Chart.prototype.resize = function () {
+        this.render();
+    };
+
+    Chart.prototype.dispose = function () {
+        this.option = null;
+        this.container.textContent = '';
+    };
+
+    Chart.prototype.render = function () {
+        const width = this.container.clientWidth;
+        const height = this.container.clientHeight;
+        this.container.textContent = '';
+        this.items = [];
+        if (!this.option || !this.option.series || width === 0 || height === 0) {
+            return;
+        }
+
+        this.container.style.position = 'relative';
+        this.svg = createSVG('svg', { width: width, height: height, viewBox: '0 0 ' + width + ' ' + height, 'font-family': 'system-ui, sans-serif' }, this.container);
+        this.defs = createSVG('defs', {}, this.svg);
+        this.tooltip = this.createTooltip();
+
+        const series = this.option.series[0];
+        if (series.type === 'treemap') {
+            this.renderTreemap(series, width, height);
+        } else if (series.type === 'pie') {
+            this.renderPie(series, width, height);
+        } else if (series.type === 'bar') {
+            this.renderBar(series, width, height);
+        }
+
+        this.svg.addEventListener('mousemove', event => this.handleMouseMove(event));
+        this.svg.addEventListener('mouseleave', () => { this.tooltip.style.display = 'none'; });
+        this.svg.addEventListener('click', event => this.handleClick(event));
+    };
+
+    Chart.prototype.createTooltip = function () {
+        const config = this.option.tooltip || {};
+        const tooltip = document.createElement('div');
+        Object.assign(tooltip.style, {
+            display: 'none',
+            position: 'absolute',
+            zIndex: '10',
+            pointerEvents: 'none',
+            maxWidth: '360px',
+            padding: '6px 10px',
+            borderRadius: '4px',
+            fontSize: '12px',
+            lineHeight: '1.5',
+            wordBreak: 'break-all',
+            background: config.backgroundColor || '#fff',
+            border: '1px solid ' + (config.borderColor || '#ccc'),
+            color: (config.textStyle && config.textStyle.color) || '#333',
+            boxShadow: '0 2px 8px rgba(0, 0, 0, 0.15)'
+        });
+        this.container.appendChild(tooltip);
+        return tooltip;
+    };
+
+    // Register the parameters of a hoverable shape and return its index
+    Chart.prototype.addItem = function (params) {
+        return this.items.push(params) - 1;
+    };
+
+    Chart.prototype.itemFromEvent = function (event) {
+        const el = event.target.closest ? event.target.closest('[data-item]') : null;
+        return el ? this.items[parseInt(el.getAttribute('data-item'), 10)] : null;
+    };
+
+    Chart.prototype.handleMouseMove = function (event) {
+        const item = this.itemFromEvent(event);
+        const config = this.option.tooltip || {};
+        if (!item || !config.formatter) {
+            this.tooltip.style.display = 'none';
+            return;
+        }
+
+        const content = applyFormatter(config.formatter, config.trigger === 'axis' ? [item] : item);
+        if (typeof config.formatter === 'function') {
+            // Formatters escape report data themselves, as with ECharts
+            this.tooltip.innerHTML = content;
+        } else {
+            this.tooltip.textContent = content;
+        }
+
+        const bounds = this.container.getBoundingClientRect();
+        this.tooltip.style.display = 'block';
+        let left = event.clientX - bounds.left + 12;
+        let top = event.clientY - bounds.top + 12;
+        if (left + this.tooltip.offsetWidth > bounds.width) {
+            left = Math.max(0, event.clientX - bounds.left - this.tooltip.offsetWidth - 12);
+        }
+        if (top + this.tooltip.offsetHeight > bounds.height) {
+            top = Math.max(0, event.clientY - bounds.top - this.tooltip.offsetHeight - 12);
+        }
+        this.tooltip.style.left = left + 'px';
+        this.tooltip.style.top = top + 'px';
+    };
+
+    Chart.prototype.handleClick = function (event) {
+        const crumb = event.target.closest ? event.target.closest('[data-crumb]') : null;
+        if (crumb) {
+            this.treemapPath = this.treemapPath.slice(0, parseInt(crumb.getAttribute('data-crumb'), 10) + 1);
+            this.render();
+            return;
+        }
+
+        // Drill into a treemap directory
+        const item = this.itemFromEvent(event);
+        if (item && item.path && item.data.children && item.data.children.length > 0) {
+            this.treemapPath = item.path;
+            this.render();
+        }
+    };
+
+    Chart.prototype.renderTreemap = function (series, width, height) {
+        const root = series.data[0];
+        if (this.treemapRoot !== root) {
+            this.treemapRoot = root;
+            this.treemapPath = [root];
+        }
+
+        // Breadcrumb for the drill-down path
+        const crumbColor = (series.breadcrumb && series.breadcrumb.itemStyle && series.breadcrumb.itemStyle.color) || 'rgba(80,80,80,0.9)';
+        let x = 5;
+        this.treemapPath.forEach((node, index) => {
+            const label = fitText(node.name, 184, 12) || '…';
+            const w = label.length * 7 + 16;
+            const crumb = createSVG('g', { 'data-crumb': index, cursor: 'pointer' }, this.svg);
+            createSVG('rect', { x: x, y: 2, width: w, height: BREADCRUMB_HEIGHT - 6, rx: 4, fill: crumbColor }, crumb);
+            createText(label, { x: x + 8, y: 17, fill: '#fff', 'font-size': 12 }, crumb);
+            x += w + 4;
+        });
+
+        const current = this.treemapPath[this.treemapPath.length - 1];
+        const borderColor = (series.itemStyle && series.itemStyle.borderColor) || '#fff';
+        this.layoutTreemap(current, this.treemapPath, 0, BREADCRUMB_HEIGHT, width, height - BREADCRUMB_HEIGHT, 0, borderColor);
+    };
+
+    Chart.prototype.layoutTreemap = function (node, ancestors, x, y, width, height, depth, borderColor) {
+        squarify(node.children || [], x, y, width, height).forEach(cell => {
+            if (cell.w < 1 || cell.h < 1) {
+                return;
+            }
+
+            const child = cell.node;
+            const path = ancestors.concat([child]);
+            const index = this.addItem({
+                name: child.name,
+                value: cell.value,
+                data: child,
+                path: path,
+                treePathInfo: path.map(n => ({ name: n.name, value: nodeValue(n) }))
+            });
+
+            const color = (child.itemStyle && child.itemStyle.color) || '#98989d';
+            const textColor = (child.label && child.label.color) || '#fff';
+            const hasChildren = child.children && child.children.length > 0;
+            const group = createSVG('g', { 'data-item': index, cursor: hasChildren ? 'pointer' : 'default' }, this.svg);
+            createSVG('rect', { x: cell.x, y: cell.y, width: cell.w, height: cell.h, fill: color, stroke: borderColor, 'stroke-width': 1 }, group);
+
+            if (hasChildren && depth < TREEMAP_MAX_DEPTH && cell.w > 40 && cell.h > TREEMAP_HEADER + 20) {
+                createText(fitText(child.name, cell.w - 8, 11), { x: cell.x + 4, y: cell.y + 13, fill: textColor, 'font-size': 11, 'font-weight': 'bold' }, group);
+                this.layoutTreemap(child, path, cell.x + 2, cell.y + TREEMAP_HEADER, cell.w - 4, cell.h - TREEMAP_HEADER - 2, depth + 1, borderColor);
+            } else if (cell.w > 30 && cell.h > 16) {
+                createText(fitText(child.name, cell.w - 8, 11), { x: cell.x + 4, y: cell.y + 14, fill: textColor, 'font-size': 11 }, group);
+            }
+        });
+    };
+
+    Chart.prototype.renderPie = function (series, width, height) {
+        const data = (series.data || []).filter(d => d.value > 0);
+        const total = data.reduce((sum, d) => sum + d.value, 0);
+        if (total === 0) {
+            return;
+        }
+
+        const cx = width / 2;
+        const cy = height / 2;
+        const radius = Math.min(width, height) / 2;
+        const radii = series.radius || ['0%', '75%'];
+        const inner = radius * parsePercent(radii[0], 0);
+        const outer = radius * parsePercent(radii[1], 0.75);
+        const itemStyle = series.itemStyle || {};
+        const label = series.label || {};
+        const fontSize = label.fontSize || 11;
+
+        let angle = -Math.PI / 2;
+        data.forEach((d, i) => {
+            // A full circle cannot be drawn as a single arc
+            const sweep = Math.min((d.value / total) * Math.PI * 2, Math.PI * 2 - 0.0001);
+            const params = { name: d.name, value: d.value, percent: ((d.value / total) * 100).toFixed(2), data: d };
+            const group = createSVG('g', { 'data-item': this.addItem(params) }, this.svg);
+            createSVG('path', {
+                d: arcPath(cx, cy, inner, outer, angle, angle + sweep),
+                fill: (d.itemStyle && d.itemStyle.color) || PALETTE[i % PALETTE.length],
+                stroke: itemStyle.borderColor || '#fff',
+                'stroke-width': itemStyle.borderWidth || 1
+            }, group);
+
+            // Label slices large enough to avoid overlapping neighbours
+            if (label.show !== false && sweep > 0.2) {
+                const mid = angle + sweep / 2;
+                const lx = cx + Math.cos(mid) * (outer + 12);
+                const ly = cy + Math.sin(mid) * (outer + 12);
+                const text = createText('', {
+                    x: lx,
+                    y: ly,
+                    fill: label.color || '#333',
+                    'font-size': fontSize,
+                    'text-anchor': Math.cos(mid) >= 0 ? 'start' : 'end'
+                }, group);
+                String(applyFormatter(label.formatter, params)).split('\n').forEach((line, n) => {
+                    const span = createSVG('tspan', { x: lx, dy: n === 0 ? 0 : fontSize + 2 }, text);
+                    span.textContent = line;
+                });
+            }
+            angle += sweep;
+        });
+    };
+
+    Chart.prototype.renderBar = function (series, width, height) {
+        const xAxis = this.option.xAxis || {};
+        const yAxis = this.option.yAxis || {};
+        const categories = yAxis.data || [];
+        const bars = this.option.series.filter(s => s.type === 'bar');
+        if (categories.length === 0) {
+            return;
+        }
+
+        const axisLabel = xAxis.axisLabel || {};
+        const textColor = axisLabel.color || '#333';
+        const gridColor = (xAxis.splitLine && xAxis.splitLine.lineStyle && xAxis.splitLine.lineStyle.color) || '#e5e5e7';
+        const fontSize = axisLabel.fontSize || 10;
+        const longest = categories.reduce((max, c) => Math.max(max, String(c).length), 0);
+        const left = Math.min(longest * fontSize * 0.6 + 12, width * 0.3);
+        const right = width - 70;
+        const top = this.option.legend && bars.length > 1 ? 28 : 8;
+        const bottom = height - 22;
+        const max = bars.reduce((m, s) => Math.max.apply(null, [m].concat(s.data || [])), 1);
+
+        // Legend for grouped bars
+        if (top > 8) {
+            let x = left;
+            bars.forEach((s, n) => {
+                createSVG('rect', { x: x, y: 6, width: 12, height: 12, rx: 2, fill: resolveColor((s.itemStyle && s.itemStyle.color) || PALETTE[n % PALETTE.length], this.defs) }, this.svg);
+                createText(s.name || '', { x: x + 16, y: 16, fill: textColor, 'font-size': 12 }, this.svg);
+                x += String(s.name || '').length * 7 + 36;
+            });
+        }
+
+        // Value axis with grid lines
+        const ticks = 4;
+        for (let i = 0; i <= ticks; i++) {
+            const x = left + ((right - left) * i) / ticks;
+            createSVG('line', { x1: x, y1: top, x2: x, y2: bottom, stroke: gridColor }, this.svg);
+            const value = (max * i) / ticks;
+            const tick = typeof axisLabel.formatter === 'function' ? axisLabel.formatter(value) : value;
+            createText(tick, { x: x, y: bottom + 14, fill: textColor, 'font-size': fontSize, 'text-anchor': 'middle' }, this.svg);
+        }
+
+        // Category axis lists the first category at the bottom, as ECharts does
+        const band = (bottom - top) / categories.length;
+        const barHeight = Math.max(1, (band * 0.6) / bars.length);
+        const fills = bars.map((s, n) => resolveColor((s.itemStyle && s.itemStyle.color) || PALETTE[n % PALETTE.length], this.defs));
+        categories.forEach((category, i) => {
+            const y = bottom - (i + 1) * band;
+            const params = { name: category, value: (bars[0].data || [])[i] || 0, seriesName: bars[0].name };
+            const group = createSVG('g', { 'data-item': this.addItem(params) }, this.svg);
+
+            createSVG('rect', { x: left, y: y, width: right - left, height: band, fill: 'transparent' }, group);
+            createText(fitText(category, left - 8, fontSize), {
+                x: left - 6,
+                y: y + band / 2 + fontSize / 3,
+                fill: textColor,
+                'font-size': fontSize,
+                'text-anchor': 'end'
+            }, group);
+
+            bars.forEach((s, n) => {
+                const value = (s.data || [])[i] || 0;
+                const barWidth = ((right - left) * value) / max;
+                const barY = y + band * 0.2 + n * barHeight;
+                const label = s.label || {};
+                createSVG('rect', { x: left, y: barY, width: Math.max(0, barWidth), height: barHeight, fill: fills[n] }, group);
+                if (label.show) {
+                    createText(applyFormatter(label.formatter, { name: category, value: value, seriesName: s.name }), {
+                        x: left + barWidth + 4,
+                        y: barY + barHeight / 2 + fontSize / 3,
+                        fill: label.color || textColor,
+                        'font-size': label.fontSize || fontSize
+                    }, group);
+                }
+            });
+        });
+    };
+
+    window.echarts = {
+        init: function (container) {
+            return new Chart(container);
+        },
+        graphic: {
+            LinearGradient: LinearGradient
+        }
+    };
+})();
+`
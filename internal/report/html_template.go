@@ -6,8 +6,10 @@ const htmlTemplate = `<!DOCTYPE html>
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>{{.Title}}</title>
-    <link rel="preconnect" href="https://fonts.googleapis.com">
+    {{if .ContentSecurityPolicy}}<meta http-equiv="Content-Security-Policy" content="{{.ContentSecurityPolicy}}">
+    {{end}}<title>{{.Title}}</title>
+    {{if .Offline}}<style{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>{{.OfflineCSS}}</style>
+    {{else}}<link rel="preconnect" href="https://fonts.googleapis.com">
     <link rel="preconnect" href="https://fonts.gstatic.com" crossorigin>
     <link href="https://fonts.googleapis.com/css2?family=Lato:wght@300;400;600;700;900&family=IBM+Plex+Mono:wght@300;400;500;600;700&display=swap" rel="stylesheet">
     <script src="https://cdn.tailwindcss.com"></script>
@@ -61,7 +63,7 @@ const htmlTemplate = `<!DOCTYPE html>
             }
         }
     </script>
-    <style>
+    {{end}}<style{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>
         :root {
             /* shadcn/ui style variables - Light mode */
             --background: 210 40% 98%;
@@ -285,6 +287,16 @@ const htmlTemplate = `<!DOCTYPE html>
             height: 1rem;
             border-radius: 0.25rem;
         }
+
+        /* Chart tooltip content */
+        .chart-tooltip-muted {
+            color: var(--color-muted);
+        }
+
+        .chart-tooltip-duplicate {
+            color: var(--color-duplicate);
+            font-weight: bold;
+        }
     </style>
 </head>
 <body class="font-sans antialiased bg-background text-foreground">
@@ -297,8 +309,8 @@ const htmlTemplate = `<!DOCTYPE html>
                     <svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 240 240" class="h-8 w-8" aria-label="Bitrise">
                         <defs>
                             <linearGradient id="bitrise-gradient" x1="0" y1="0" x2="1" y2="1">
-                                <stop offset="0" style="stop-color:#9247C2;stop-opacity:1" />
-                                <stop offset="1" style="stop-color:#0dd3c5;stop-opacity:1" />
+                                <stop offset="0" stop-color="#9247C2" stop-opacity="1" />
+                                <stop offset="1" stop-color="#0dd3c5" stop-opacity="1" />
                             </linearGradient>
                         </defs>
                         <rect x="20" y="20" width="200" height="200" rx="40" fill="url(#bitrise-gradient)"/>
@@ -433,6 +445,13 @@ const htmlTemplate = `<!DOCTYPE html>
             </div>
         </div>
 
+        {{if .Warnings}}
+        <!-- Analysis Warnings -->
+        <div role="alert" class="mt-6 rounded-lg border border-amber-500/50 bg-amber-500/10 px-4 py-2 text-sm text-amber-700 dark:text-amber-400 space-y-1">
+            {{range .Warnings}}<p>⚠️ {{.}}</p>{{end}}
+        </div>
+        {{end}}
+
             <!-- Tabs -->
             <div class="space-y-4">
             <div class="inline-flex h-10 items-center justify-center rounded-md bg-muted p-1 text-muted-foreground">
@@ -449,7 +468,7 @@ const htmlTemplate = `<!DOCTYPE html>
             <section id="app-analyzer-panel" class="tab-panel active" aria-labelledby="treemap-heading">
                 <div class="rounded-lg border bg-card text-card-foreground shadow-sm p-6">
                     <h2 id="treemap-heading" class="scroll-m-20 text-2xl font-semibold tracking-tight">Bundle Treemap</h2>
-                    <p class="text-sm text-muted-foreground leading-relaxed mt-1.5 mb-4">Click to drill down into folders. Use mouse wheel to zoom. Use breadcrumb to navigate back.</p>
+                    <p class="text-sm text-muted-foreground leading-relaxed mt-1.5 mb-4">{{if eq .SizeView "download"}}Sizes are compressed download sizes. {{end}}Click to drill down into folders. Use mouse wheel to zoom. Use breadcrumb to navigate back.</p>
                     <div class="mb-4">
                         <label for="search-input" class="sr-only">Search files</label>
                         <div class="relative">
@@ -479,12 +498,43 @@ const htmlTemplate = `<!DOCTYPE html>
                         <div id="extension-chart" class="chart" role="img" aria-label="Top file extensions bar chart"></div>
                     </div>
                 </div>
+                {{if .Owners}}
+                <div class="rounded-lg border bg-card text-card-foreground shadow-sm p-6 mt-6">
+                    <h2 id="owners-heading" class="scroll-m-20 text-xl font-semibold tracking-tight mb-6">Size by Owner</h2>
+                    <div class="rounded-md border overflow-hidden">
+                        <table class="w-full text-sm" aria-labelledby="owners-heading">
+                            <thead class="bg-muted/50">
+                                <tr class="border-b border-border">
+                                    <th class="h-10 px-4 align-middle font-medium text-muted-foreground text-left">Owner</th>
+                                    <th class="h-10 px-4 align-middle font-medium text-muted-foreground text-right">Size</th>
+                                    <th class="h-10 px-4 align-middle font-medium text-muted-foreground text-right">Share</th>
+                                    <th class="h-10 px-4 align-middle font-medium text-muted-foreground text-right">Files</th>
+                                    <th class="h-10 px-4 align-middle font-medium text-muted-foreground text-right">Potential Savings</th>
+                                    <th class="h-10 px-4 align-middle font-medium text-muted-foreground text-right">Duplicate Waste</th>
+                                </tr>
+                            </thead>
+                            <tbody class="divide-y divide-border">
+                                {{range .Owners}}
+                                <tr class="hover:bg-muted/50 transition-colors">
+                                    <td class="p-4 align-top text-left">{{.Owner}}</td>
+                                    <td class="p-4 align-top text-right">{{.Size}}</td>
+                                    <td class="p-4 align-top text-right">{{.Share}}</td>
+                                    <td class="p-4 align-top text-right">{{.FileCount}}</td>
+                                    <td class="p-4 align-top text-right">{{.PotentialSavings}}</td>
+                                    <td class="p-4 align-top text-right">{{.DuplicateWaste}}</td>
+                                </tr>
+                                {{end}}
+                            </tbody>
+                        </table>
+                    </div>
+                </div>
+                {{end}}
             </section>
 
             <section id="files-panel" class="tab-panel" aria-labelledby="files-heading">
                 <div class="rounded-lg border bg-card text-card-foreground shadow-sm p-6 hover:-translate-y-0.5 transition-transform duration-300">
                     <h2 id="files-heading" class="scroll-m-20 text-2xl font-semibold tracking-tight mb-2">File Breakdown</h2>
-                    <p class="text-sm text-muted-foreground leading-relaxed mb-4">All files in the bundle sorted by size</p>
+                    <p class="text-sm text-muted-foreground leading-relaxed mb-4">All files in the bundle sorted by {{if eq .SizeView "download"}}download {{end}}size</p>
 
                     <!-- Search Bar -->
                     <div class="mb-4">
@@ -541,8 +591,9 @@ const htmlTemplate = `<!DOCTYPE html>
         </div>
     </footer>
 
-    <script src="https://cdn.jsdelivr.net/npm/echarts@5/dist/echarts.min.js"></script>
-    <script>
+    {{if .Offline}}<script{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>{{.ChartRenderer}}</script>
+    {{else}}<script src="https://cdn.jsdelivr.net/npm/echarts@5/dist/echarts.min.js"></script>
+    {{end}}<script{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>
         const reportData = {{.DataJSON}};
 
         // ============================================================
@@ -759,96 +810,7 @@ const htmlTemplate = `<!DOCTYPE html>
         let originalCategories = null;
         let originalExtensions = null;
 
-        // ============================================================
-        // TreeUtils - Tree traversal and manipulation utilities
-        // ============================================================
-        const TreeUtils = {
-            // Deep copy a tree node
-            deepCopy(node) {
-                if (!node) return null;
-                const copy = {
-                    name: node.name,
-                    value: node.value
-                };
-                if (node.path) copy.path = node.path;
-                if (node.fileType) copy.fileType = node.fileType;
-                if (node.itemStyle) copy.itemStyle = JSON.parse(JSON.stringify(node.itemStyle));
-                if (node.isDuplicate) copy.isDuplicate = node.isDuplicate;
-                if (node.children && node.children.length > 0) {
-                    copy.children = node.children.map(child => TreeUtils.deepCopy(child));
-                }
-                return copy;
-            },
-
-            // Traverse tree with visitor function
-            traverse(node, visitor, depth = 0) {
-                if (!node) return;
-                visitor(node, depth);
-                if (node.children) {
-                    node.children.forEach(child => TreeUtils.traverse(child, visitor, depth + 1));
-                }
-            },
-
-            // Collect nodes matching a predicate
-            collect(node, predicate) {
-                const results = [];
-                TreeUtils.traverse(node, (n) => {
-                    if (predicate(n)) results.push(n);
-                });
-                return results;
-            },
-
-            // Filter tree, keeping only matching nodes and their parents
-            filter(node, predicate) {
-                if (!node) return null;
-
-                const matches = predicate(node);
-                const isLeaf = !node.children || node.children.length === 0;
-
-                if (isLeaf) {
-                    return matches ? TreeUtils.deepCopy(node) : null;
-                }
-
-                const filteredChildren = node.children
-                    .map(child => TreeUtils.filter(child, predicate))
-                    .filter(child => child !== null);
-
-                if (filteredChildren.length > 0 || matches) {
-                    const copy = TreeUtils.deepCopy(node);
-                    if (filteredChildren.length > 0) {
-                        copy.children = filteredChildren;
-                        copy.value = filteredChildren.reduce((sum, child) => sum + child.value, 0);
-                    }
-                    return copy;
-                }
-
-                return null;
-            },
-
-            // Find a node by name (exact match)
-            findByName(node, targetName) {
-                if (!node) return null;
-                if (node.name.toLowerCase() === targetName.toLowerCase()) {
-                    return TreeUtils.deepCopy(node);
-                }
-                if (node.children) {
-                    for (const child of node.children) {
-                        const found = TreeUtils.findByName(child, targetName);
-                        if (found) return found;
-                    }
-                }
-                return null;
-            },
-
-            // Aggregate values using a custom aggregator function
-            aggregate(node, aggregator, initialValue = 0) {
-                let result = initialValue;
-                TreeUtils.traverse(node, (n) => {
-                    result = aggregator(result, n);
-                });
-                return result;
-            }
-        };
+        {{.TreeScript}}
 
         // ============================================================
         // ChartFactory - Unified chart management
@@ -1320,7 +1282,7 @@ const htmlTemplate = `<!DOCTYPE html>
                             if (metadata.source_dex) {
                                 result += 'Source: ' + SafeHTML.escapeText(metadata.source_dex) + '<br/>';
                             }
-                            result += '<br/><small style="color: var(--color-muted);">Private size only</small>';
+                            result += '<br/><small class="chart-tooltip-muted">Private size only</small>';
                         }
 
                         // Unmapped DEX node metadata
@@ -1329,11 +1291,11 @@ const htmlTemplate = `<!DOCTYPE html>
                             if (metadata.description) {
                                 result += SafeHTML.escapeText(metadata.description) + '<br/>';
                             }
-                            result += '<br/><small style="color: var(--color-muted);">Cannot be attributed to specific classes</small>';
+                            result += '<br/><small class="chart-tooltip-muted">Cannot be attributed to specific classes</small>';
                         }
 
                         if (isDuplicate) {
-                            result += '<br/><span style="color: var(--color-duplicate); font-weight: bold;">⚠ Duplicate file</span>';
+                            result += '<br/><span class="chart-tooltip-duplicate">⚠ Duplicate file</span>';
                         }
 
                         return result;
@@ -2023,7 +1985,7 @@ const htmlTemplate = `<!DOCTYPE html>
 
                 if (col.sortable !== false) {
                     html += '<th class="h-10 px-4 align-middle font-medium text-muted-foreground' + widthClass + '">';
-                    html += '<button onclick="handleDataTableSort(\'' + tableId + '\', \'' + col.key + '\')" class="group inline-flex items-center gap-1 hover:text-foreground transition-colors ' + alignClass + ' w-full">';
+                    html += '<button data-action="sort-table" data-table="' + tableId + '" data-column="' + col.key + '" class="group inline-flex items-center gap-1 hover:text-foreground transition-colors ' + alignClass + ' w-full">';
                     html += col.label + sortIcon;
                     html += '</button>';
                     html += '</th>';
@@ -2063,7 +2025,7 @@ const htmlTemplate = `<!DOCTYPE html>
 
                 // Previous button
                 const prevDisabled = currentPage === 0;
-                html += '<button onclick="changeDataTablePage(\'' + tableId + '\', ' + (currentPage - 1) + ')" ' + (prevDisabled ? 'disabled' : '') + ' class="inline-flex items-center justify-center rounded-md text-sm font-medium h-8 w-8 border border-input bg-background hover:bg-accent hover:text-accent-foreground disabled:pointer-events-none disabled:opacity-50">';
+                html += '<button data-action="change-page" data-table="' + tableId + '" data-page="' + (currentPage - 1) + '" ' + (prevDisabled ? 'disabled' : '') + ' class="inline-flex items-center justify-center rounded-md text-sm font-medium h-8 w-8 border border-input bg-background hover:bg-accent hover:text-accent-foreground disabled:pointer-events-none disabled:opacity-50">';
                 html += '<svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24"><path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M15 19l-7-7 7-7"/></svg>';
                 html += '</button>';
 
@@ -2080,14 +2042,14 @@ const htmlTemplate = `<!DOCTYPE html>
                     const pageClass = isCurrentPage
                         ? 'bg-primary text-primary-foreground'
                         : 'border border-input bg-background hover:bg-accent hover:text-accent-foreground';
-                    html += '<button onclick="changeDataTablePage(\'' + tableId + '\', ' + i + ')" class="inline-flex items-center justify-center rounded-md text-sm font-medium h-8 w-8 ' + pageClass + '">';
+                    html += '<button data-action="change-page" data-table="' + tableId + '" data-page="' + i + '" class="inline-flex items-center justify-center rounded-md text-sm font-medium h-8 w-8 ' + pageClass + '">';
                     html += (i + 1);
                     html += '</button>';
                 }
 
                 // Next button
                 const nextDisabled = currentPage >= totalPages - 1;
-                html += '<button onclick="changeDataTablePage(\'' + tableId + '\', ' + (currentPage + 1) + ')" ' + (nextDisabled ? 'disabled' : '') + ' class="inline-flex items-center justify-center rounded-md text-sm font-medium h-8 w-8 border border-input bg-background hover:bg-accent hover:text-accent-foreground disabled:pointer-events-none disabled:opacity-50">';
+                html += '<button data-action="change-page" data-table="' + tableId + '" data-page="' + (currentPage + 1) + '" ' + (nextDisabled ? 'disabled' : '') + ' class="inline-flex items-center justify-center rounded-md text-sm font-medium h-8 w-8 border border-input bg-background hover:bg-accent hover:text-accent-foreground disabled:pointer-events-none disabled:opacity-50">';
                 html += '<svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24"><path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M9 5l7 7-7 7"/></svg>';
                 html += '</button>';
 
@@ -2555,6 +2517,14 @@ const htmlTemplate = `<!DOCTYPE html>
                     case 'toggle-all-insights':
                         toggleAllInsights();
                         break;
+
+                    case 'sort-table':
+                        handleDataTableSort(target.getAttribute('data-table'), target.getAttribute('data-column'));
+                        break;
+
+                    case 'change-page':
+                        changeDataTablePage(target.getAttribute('data-table'), parseInt(target.getAttribute('data-page'), 10));
+                        break;
                 }
             });
         }
@@ -2607,72 +2577,9 @@ const htmlTemplate = `<!DOCTYPE html>
             }
         });
 
-        // Parse search query to detect special syntax
-        function parseSearchQuery(query) {
-            if (!query) {
-                return { mode: 'empty', query: '' };
-            }
-
-            // Check for backtick syntax: ` + "`" + `moduleName` + "`" + `
-            const backtickMatch = query.match(/` + "`" + `([^` + "`" + `]+)` + "`" + `/);
-            if (backtickMatch) {
-                return { mode: 'backtick', query: backtickMatch[1].toLowerCase() };
-            }
-
-            // Check for path-specific syntax: contains /
-            if (query.includes('/')) {
-                return { mode: 'path', query: query.toLowerCase() };
-            }
-
-            // Default: basic search
-            return { mode: 'basic', query: query.toLowerCase() };
-        }
-
         // Legacy alias for deepCopy
         const deepCopyNode = TreeUtils.deepCopy;
 
-        // Filter tree based on search query
-        function filterTreeByQuery(tree, searchMode, query) {
-            if (!tree || searchMode === 'empty') {
-                return tree;
-            }
-
-            // Backtick mode: find exact node match by name, return it with all children
-            if (searchMode === 'backtick') {
-                const foundNode = TreeUtils.findByName(tree, query);
-                if (foundNode) {
-                    // Wrap in a root node to maintain structure
-                    return {
-                        name: tree.name,
-                        value: foundNode.value,
-                        children: [foundNode]
-                    };
-                }
-                return null;
-            }
-
-            // Path-specific mode: only include nodes whose path matches
-            if (searchMode === 'path') {
-                return TreeUtils.filter(tree, node => {
-                    const nodePath = (node.path || '').toLowerCase();
-                    const nodeName = node.name.toLowerCase();
-                    return nodePath.includes(query) || nodeName.includes(query);
-                });
-            }
-
-            // Basic mode: match against name, path, fileType
-            if (searchMode === 'basic') {
-                return TreeUtils.filter(tree, node => {
-                    const nodeName = node.name.toLowerCase();
-                    const nodePath = (node.path || '').toLowerCase();
-                    const nodeType = (node.fileType || '').toLowerCase();
-                    return nodeName.includes(query) || nodePath.includes(query) || nodeType.includes(query);
-                });
-            }
-
-            return tree;
-        }
-
         // Calculate categories and extensions from filtered tree
         function calculateStatsFromTree(tree) {
             const categoryMap = {};
@@ -2801,3 +2708,157 @@ const htmlTemplate = `<!DOCTYPE html>
 </body>
 </html>
 `
+
+// htmlTreeScript holds the tree utilities and search shared by the report and diff pages
+const htmlTreeScript = `
+        // ============================================================
+        // TreeUtils - Tree traversal and manipulation utilities
+        // ============================================================
+        const TreeUtils = {
+            // Deep copy a tree node
+            deepCopy(node) {
+                if (!node) return null;
+                // Copy every data field, e.g. the sizes and deltas of diff report nodes
+                const copy = Object.assign({}, node);
+                delete copy.children;
+                if (node.itemStyle) copy.itemStyle = JSON.parse(JSON.stringify(node.itemStyle));
+                if (node.label) copy.label = Object.assign({}, node.label);
+                if (node.children && node.children.length > 0) {
+                    copy.children = node.children.map(child => TreeUtils.deepCopy(child));
+                }
+                return copy;
+            },
+
+            // Traverse tree with visitor function
+            traverse(node, visitor, depth = 0) {
+                if (!node) return;
+                visitor(node, depth);
+                if (node.children) {
+                    node.children.forEach(child => TreeUtils.traverse(child, visitor, depth + 1));
+                }
+            },
+
+            // Collect nodes matching a predicate
+            collect(node, predicate) {
+                const results = [];
+                TreeUtils.traverse(node, (n) => {
+                    if (predicate(n)) results.push(n);
+                });
+                return results;
+            },
+
+            // Filter tree, keeping only matching nodes and their parents
+            filter(node, predicate) {
+                if (!node) return null;
+
+                const matches = predicate(node);
+                const isLeaf = !node.children || node.children.length === 0;
+
+                if (isLeaf) {
+                    return matches ? TreeUtils.deepCopy(node) : null;
+                }
+
+                const filteredChildren = node.children
+                    .map(child => TreeUtils.filter(child, predicate))
+                    .filter(child => child !== null);
+
+                if (filteredChildren.length > 0 || matches) {
+                    const copy = TreeUtils.deepCopy(node);
+                    if (filteredChildren.length > 0) {
+                        copy.children = filteredChildren;
+                        copy.value = filteredChildren.reduce((sum, child) => sum + child.value, 0);
+                    }
+                    return copy;
+                }
+
+                return null;
+            },
+
+            // Find a node by name (exact match)
+            findByName(node, targetName) {
+                if (!node) return null;
+                if (node.name.toLowerCase() === targetName.toLowerCase()) {
+                    return TreeUtils.deepCopy(node);
+                }
+                if (node.children) {
+                    for (const child of node.children) {
+                        const found = TreeUtils.findByName(child, targetName);
+                        if (found) return found;
+                    }
+                }
+                return null;
+            },
+
+            // Aggregate values using a custom aggregator function
+            aggregate(node, aggregator, initialValue = 0) {
+                let result = initialValue;
+                TreeUtils.traverse(node, (n) => {
+                    result = aggregator(result, n);
+                });
+                return result;
+            }
+        };
+
+        // Parse search query to detect special syntax
+        function parseSearchQuery(query) {
+            if (!query) {
+                return { mode: 'empty', query: '' };
+            }
+
+            // Check for backtick syntax: ` + "`" + `moduleName` + "`" + `
+            const backtickMatch = query.match(/` + "`" + `([^` + "`" + `]+)` + "`" + `/);
+            if (backtickMatch) {
+                return { mode: 'backtick', query: backtickMatch[1].toLowerCase() };
+            }
+
+            // Check for path-specific syntax: contains /
+            if (query.includes('/')) {
+                return { mode: 'path', query: query.toLowerCase() };
+            }
+
+            // Default: basic search
+            return { mode: 'basic', query: query.toLowerCase() };
+        }
+
+        // Filter tree based on search query
+        function filterTreeByQuery(tree, searchMode, query) {
+            if (!tree || searchMode === 'empty') {
+                return tree;
+            }
+
+            // Backtick mode: find exact node match by name, return it with all children
+            if (searchMode === 'backtick') {
+                const foundNode = TreeUtils.findByName(tree, query);
+                if (foundNode) {
+                    // Wrap in a root node to maintain structure
+                    return {
+                        name: tree.name,
+                        value: foundNode.value,
+                        children: [foundNode]
+                    };
+                }
+                return null;
+            }
+
+            // Path-specific mode: only include nodes whose path matches
+            if (searchMode === 'path') {
+                return TreeUtils.filter(tree, node => {
+                    const nodePath = (node.path || '').toLowerCase();
+                    const nodeName = node.name.toLowerCase();
+                    return nodePath.includes(query) || nodeName.includes(query);
+                });
+            }
+
+            // Basic mode: match against name, path, fileType
+            if (searchMode === 'basic') {
+                return TreeUtils.filter(tree, node => {
+                    const nodeName = node.name.toLowerCase();
+                    const nodePath = (node.path || '').toLowerCase();
+                    const nodeType = (node.fileType || '').toLowerCase();
+                    return nodeName.includes(query) || nodePath.includes(query) || nodeType.includes(query);
+                });
+            }
+
+            return tree;
+        }
+`
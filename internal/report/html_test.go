@@ -2,6 +2,10 @@ package report
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"html"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -679,3 +683,170 @@ func TestAccessibilityFeatures(t *testing.T) {
 		t.Error("Output missing aria-labelledby attributes")
 	}
 }
+
+func newMinimalHTMLReport() *types.Report {
+	return &types.Report{
+		ArtifactInfo: types.ArtifactInfo{
+			Path:       "/path/to/test.ipa",
+			Type:       types.ArtifactTypeIPA,
+			Size:       1000,
+			AnalyzedAt: time.Now(),
+		},
+		FileTree: []*types.FileNode{},
+	}
+}
+
+func TestHTMLFormatter_OfflineByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewHTMLFormatter().Format(&buf, newMinimalHTMLReport()); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	for _, remote := range []string{"cdn.jsdelivr.net", "cdn.tailwindcss.com", "fonts.googleapis.com", "<link "} {
+		if strings.Contains(output, remote) {
+			t.Errorf("offline output references %q", remote)
+		}
+	}
+	if !strings.Contains(output, "/* Preflight */") {
+		t.Error("offline output missing inline styles")
+	}
+	if !strings.Contains(output, "window.echarts = {") {
+		t.Error("offline output missing built-in chart renderer")
+	}
+	if strings.Contains(output, "Content-Security-Policy") {
+		t.Error("output has a Content-Security-Policy without CSP options")
+	}
+}
+
+func TestHTMLFormatter_Warnings(t *testing.T) {
+	report := newMinimalHTMLReport()
+	report.Warnings = []string{"Encrypted binaries (App Store FairPlay): TestApp."}
+
+	var buf bytes.Buffer
+	if err := NewHTMLFormatter().Format(&buf, report); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Encrypted binaries (App Store FairPlay): TestApp.") {
+		t.Error("output missing the analysis warning")
+	}
+
+	buf.Reset()
+	if err := NewHTMLFormatter().Format(&buf, newMinimalHTMLReport()); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "Analysis Warnings") {
+		t.Error("output has a warnings card without warnings")
+	}
+}
+
+func TestHTMLFormatter_Online(t *testing.T) {
+	formatter := NewHTMLFormatter()
+	formatter.Offline = false
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, newMinimalHTMLReport()); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, "https://cdn.jsdelivr.net/npm/echarts@5/dist/echarts.min.js") {
+		t.Error("online output missing ECharts CDN script")
+	}
+	if strings.Contains(output, "window.echarts = {") {
+		t.Error("online output should not embed the built-in chart renderer")
+	}
+}
+
+func TestHTMLFormatter_CSPNonce(t *testing.T) {
+	formatter := NewHTMLFormatter()
+	formatter.CSPNonce = "r4nd0m+Nonce="
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, newMinimalHTMLReport()); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, "script-src &#39;nonce-r4nd0m&#43;Nonce=&#39;") {
+		t.Errorf("output missing nonce policy:\n%s", output[:600])
+	}
+
+	blocks := inlineBlocks.FindAllStringSubmatch(output, -1)
+	if len(blocks) != 4 {
+		t.Fatalf("found %d inline blocks, want 4", len(blocks))
+	}
+	for _, tag := range regexp.MustCompile(`<(script|style)[^>]*>`).FindAllString(output, -1) {
+		if !strings.Contains(tag, `nonce="r4nd0m&#43;Nonce="`) {
+			t.Errorf("inline element without nonce: %s", tag)
+		}
+	}
+}
+
+func TestHTMLFormatter_CSPHashes(t *testing.T) {
+	formatter := NewHTMLFormatter()
+	formatter.CSPHashes = true
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, newMinimalHTMLReport()); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	policy := regexp.MustCompile(`http-equiv="Content-Security-Policy" content="([^"]*)"`).FindStringSubmatch(output)
+	if policy == nil {
+		t.Fatal("output missing Content-Security-Policy meta tag")
+	}
+
+	for _, block := range inlineBlocks.FindAllStringSubmatch(output, -1) {
+		sum := sha256.Sum256([]byte(block[2]))
+		hash := "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+		if !strings.Contains(html.UnescapeString(policy[1]), hash) {
+			t.Errorf("policy missing hash of inline %s", block[1])
+		}
+	}
+	if strings.Contains(output, "onclick=") {
+		t.Error("inline event handlers are blocked by the policy")
+	}
+}
+
+func TestHTMLFormatter_CSPErrors(t *testing.T) {
+	online := NewHTMLFormatter()
+	online.Offline = false
+	online.CSPHashes = true
+	if err := online.Format(&bytes.Buffer{}, newMinimalHTMLReport()); err == nil {
+		t.Error("expected error for CSP options on an online report")
+	}
+
+	invalid := NewHTMLFormatter()
+	invalid.CSPNonce = `abc" onload="x`
+	if err := invalid.Format(&bytes.Buffer{}, newMinimalHTMLReport()); err == nil {
+		t.Error("expected error for invalid nonce")
+	}
+}
+
+// TestHTMLOfflineCSS_CoversTemplateClasses guards against utility classes that only
+// the Tailwind CDN would style
+func TestHTMLOfflineCSS_CoversTemplateClasses(t *testing.T) {
+	// Classes used as JavaScript hooks or styled by the template's own stylesheet
+	hooks := map[string]bool{
+		"active": true, "dark": true, "group": true, "chart": true, "tab-button": true, "tab-panel": true,
+		"insight-card": true, "insight-files": true, "insight-files-content": true, "insights-badge": true,
+		"expand-indicator": true, "legend-item": true, "legend-color": true, "sr-only": true,
+		"tooltip-trigger": true, "tooltip-content": true, "tooltip-bottom": true,
+		"chart-tooltip-muted": true, "chart-tooltip-duplicate": true,
+	}
+	escaper := strings.NewReplacer(":", `\:`, "/", `\/`, ".", `\.`, "[", `\[`, "]", `\]`)
+
+	classAttr := regexp.MustCompile(`class="([^"']*)`)
+	for _, match := range classAttr.FindAllStringSubmatch(htmlTemplate, -1) {
+		for _, class := range strings.Fields(match[1]) {
+			if hooks[class] || strings.Contains(class, "{{") {
+				continue
+			}
+			if !strings.Contains(htmlOfflineCSS, "."+escaper.Replace(class)) {
+				t.Errorf("htmlOfflineCSS has no rule for class %q", class)
+			}
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// IndexEntry describes one artifact of a multi-artifact run
+type IndexEntry struct {
+	Name        string            // Artifact file name
+	Report      *types.Report     // Nil when the analysis failed
+	Error       string            // Analysis error, if any
+	ReportFiles map[string]string // Output format -> report file path
+}
+
+// indexFormatOrder is the order in which per-artifact report links are listed. The
+// optimizations written next to a csv or tsv report are listed as <format>-optimizations.
+var indexFormatOrder = []string{"html", "markdown", "json", "text", "csv", "csv-optimizations", "tsv", "tsv-optimizations"}
+
+// MarkdownIndexFormatter formats a side-by-side summary of several reports as markdown
+type MarkdownIndexFormatter struct{}
+
+// NewMarkdownIndexFormatter creates a new markdown index formatter
+func NewMarkdownIndexFormatter() *MarkdownIndexFormatter {
+	return &MarkdownIndexFormatter{}
+}
+
+// Format writes the index in markdown format to the writer
+func (f *MarkdownIndexFormatter) Format(w io.Writer, entries []IndexEntry) error {
+	if _, err := fmt.Fprintf(w, "## Bitrise Report (%d artifacts)\n\n", len(entries)); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "| Artifact | Platform | Version | Install Size | Download Size | Potential Savings | Top Optimization | Reports |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|----------|----------|---------|-------------:|--------------:|------------------:|------------------|---------|\n"); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		links := make([]string, 0, len(entry.ReportFiles))
+		for _, format := range indexFormatOrder {
+			if path, ok := entry.ReportFiles[format]; ok {
+				links = append(links, fmt.Sprintf("[%s](%s)", format, path))
+			}
+		}
+		linksDisplay := "-"
+		if len(links) > 0 {
+			linksDisplay = strings.Join(links, ", ")
+		}
+
+		if entry.Report == nil {
+			if _, err := fmt.Fprintf(w, "| %s | - | - | - | - | - | ❌ %s | %s |\n",
+				entry.Name, entry.Error, linksDisplay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		summary := summarizeForIndex(entry.Report)
+		topOpt := summary.topOptimization
+		if topOpt == "" {
+			topOpt = "-"
+		}
+		version := summary.version
+		if version == "" {
+			version = "-"
+		}
+
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			entry.Name, summary.platform, version,
+			util.FormatBytes(summary.installSize), util.FormatBytes(summary.downloadSize),
+			util.FormatBytes(entry.Report.TotalSavings), topOpt, linksDisplay); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "\n"); err != nil {
+		return err
+	}
+
+	return f.writeCategoryComparison(w, entries)
+}
+
+// writeCategoryComparison writes size categories with one column per artifact
+func (f *MarkdownIndexFormatter) writeCategoryComparison(w io.Writer, entries []IndexEntry) error {
+	comparison := buildCategoryComparison(entries)
+	if len(comparison.categories) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>📊 Size Breakdown by Category</strong></summary>\n\n"); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "| Category | %s |\n", strings.Join(comparison.names, " | ")); err != nil {
+		return err
+	}
+	separator := "|----------|" + strings.Repeat("-----:|", len(comparison.names))
+	if _, err := fmt.Fprintf(w, "%s\n", separator); err != nil {
+		return err
+	}
+
+	for _, category := range comparison.categories {
+		cells := make([]string, len(comparison.names))
+		for i, size := range comparison.sizes[category] {
+			cells[i] = "-"
+			if size > 0 {
+				cells[i] = util.FormatBytes(size)
+			}
+		}
+		if _, err := fmt.Fprintf(w, "| %s | %s |\n", category, strings.Join(cells, " | ")); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "\n</details>\n\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// HTMLIndexFormatter formats a side-by-side summary of several reports as a standalone HTML page
+type HTMLIndexFormatter struct {
+	Title string
+}
+
+// NewHTMLIndexFormatter creates a new HTML index formatter
+func NewHTMLIndexFormatter() *HTMLIndexFormatter {
+	return &HTMLIndexFormatter{
+		Title: "Bundle Analysis Index",
+	}
+}
+
+// indexTemplateData holds all data needed for the HTML index template
+type indexTemplateData struct {
+	Title      string
+	Rows       []indexRow
+	Names      []string
+	Categories []indexCategoryRow
+}
+
+type indexRow struct {
+	Name              string
+	Failed            bool
+	Error             string
+	Platform          string
+	Version           string
+	InstallSize       string
+	DownloadSize      string
+	TotalSavings      string
+	TopOptimization   string
+	Links             []indexLink
+	SavingsPercentage string
+}
+
+type indexLink struct {
+	Format string
+	Path   string
+}
+
+type indexCategoryRow struct {
+	Name  string
+	Sizes []string
+}
+
+// Format writes the index in HTML format to the writer
+func (f *HTMLIndexFormatter) Format(w io.Writer, entries []IndexEntry) error {
+	data := indexTemplateData{Title: f.Title}
+
+	for _, entry := range entries {
+		row := indexRow{Name: entry.Name}
+		for _, format := range indexFormatOrder {
+			if path, ok := entry.ReportFiles[format]; ok {
+				row.Links = append(row.Links, indexLink{Format: format, Path: path})
+			}
+		}
+
+		if entry.Report == nil {
+			row.Failed = true
+			row.Error = entry.Error
+			data.Rows = append(data.Rows, row)
+			continue
+		}
+
+		summary := summarizeForIndex(entry.Report)
+		row.Platform = summary.platform
+		row.Version = summary.version
+		row.InstallSize = util.FormatBytes(summary.installSize)
+		row.DownloadSize = util.FormatBytes(summary.downloadSize)
+		row.TotalSavings = util.FormatBytes(entry.Report.TotalSavings)
+		row.SavingsPercentage = util.FormatPercentage(entry.Report.TotalSavings, summary.installSize)
+		row.TopOptimization = summary.topOptimization
+		data.Rows = append(data.Rows, row)
+	}
+
+	comparison := buildCategoryComparison(entries)
+	data.Names = comparison.names
+	for _, category := range comparison.categories {
+		row := indexCategoryRow{Name: category}
+		for _, size := range comparison.sizes[category] {
+			cell := "-"
+			if size > 0 {
+				cell = util.FormatBytes(size)
+			}
+			row.Sizes = append(row.Sizes, cell)
+		}
+		data.Categories = append(data.Categories, row)
+	}
+
+	tmpl, err := template.New("index").Parse(htmlIndexTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return tmpl.Execute(w, data)
+}
+
+// indexSummary holds the per-artifact values shown in the index
+type indexSummary struct {
+	platform        string
+	version         string
+	installSize     int64
+	downloadSize    int64
+	topOptimization string
+}
+
+// summarizeForIndex extracts the index values from a report
+func summarizeForIndex(report *types.Report) indexSummary {
+	summary := indexSummary{
+		platform:     platformName(report),
+		version:      report.ArtifactInfo.Version,
+		installSize:  calculateUncompressedSize(&report.SizeBreakdown),
+		downloadSize: report.ArtifactInfo.Size,
+	}
+
+	var topImpact int64 = -1
+	for _, opt := range report.Optimizations {
+		if opt.Impact > topImpact {
+			topImpact = opt.Impact
+			summary.topOptimization = opt.Title
+		}
+	}
+
+	return summary
+}
+
+// platformName returns the platform of a report, preferring metadata over artifact type
+func platformName(report *types.Report) string {
+	if report.Metadata != nil {
+		if v, ok := report.Metadata["platform"].(string); ok && v != "" {
+			return v
+		}
+	}
+
+	switch report.ArtifactInfo.Type {
+	case types.ArtifactTypeIPA, types.ArtifactTypeApp, types.ArtifactTypeXCArchive:
+		return "iOS"
+	case types.ArtifactTypeAPK, types.ArtifactTypeAAB:
+		return "Android"
+	}
+	return "-"
+}
+
+// categoryComparison holds category sizes aligned by artifact
+type categoryComparison struct {
+	names      []string
+	categories []string
+	sizes      map[string][]int64
+}
+
+// buildCategoryComparison aligns the size breakdown of all successful reports.
+// Categories are sorted by their largest size across artifacts.
+func buildCategoryComparison(entries []IndexEntry) categoryComparison {
+	comparison := categoryComparison{sizes: make(map[string][]int64)}
+
+	var reports []*types.Report
+	for _, entry := range entries {
+		if entry.Report == nil {
+			continue
+		}
+		comparison.names = append(comparison.names, entry.Name)
+		reports = append(reports, entry.Report)
+	}
+
+	maxSize := make(map[string]int64)
+	for i, report := range reports {
+		for _, cat := range categorySizes(&report.SizeBreakdown) {
+			if cat.size == 0 {
+				continue
+			}
+			if _, ok := comparison.sizes[cat.name]; !ok {
+				comparison.sizes[cat.name] = make([]int64, len(reports))
+			}
+			comparison.sizes[cat.name][i] = cat.size
+			if cat.size > maxSize[cat.name] {
+				maxSize[cat.name] = cat.size
+			}
+		}
+	}
+
+	for name := range maxSize {
+		comparison.categories = append(comparison.categories, name)
+	}
+	sort.Slice(comparison.categories, func(i, j int) bool {
+		a, b := comparison.categories[i], comparison.categories[j]
+		if maxSize[a] != maxSize[b] {
+			return maxSize[a] > maxSize[b]
+		}
+		return a < b
+	})
+
+	return comparison
+}
+
+// categorySizes returns the fixed size breakdown categories in display order
+func categorySizes(breakdown *types.SizeBreakdown) []sortedItem {
+	return []sortedItem{
+		{"Executable", breakdown.Executable},
+		{"Frameworks", breakdown.Frameworks},
+		{"Libraries", breakdown.Libraries},
+		{"DEX", breakdown.DEX},
+		{"Assets", breakdown.Assets},
+		{"Resources", breakdown.Resources},
+		{"Other", breakdown.Other},
+	}
+}
+
+// htmlIndexTemplate is a dependency-free page so the index renders anywhere
+const htmlIndexTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Title}}</title>
+    <style>
+        body { font-family: system-ui, -apple-system, sans-serif; margin: 0; background: #f8fafc; color: #0f172a; }
+        main { max-width: 80rem; margin: 0 auto; padding: 1.5rem; }
+        h1 { font-size: 1.75rem; margin: 0 0 1rem; }
+        h2 { font-size: 1.25rem; margin: 2rem 0 0.75rem; }
+        table { width: 100%; border-collapse: collapse; background: #fff; border: 1px solid #e2e8f0; border-radius: 0.5rem; }
+        th, td { padding: 0.5rem 0.75rem; border-bottom: 1px solid #e2e8f0; text-align: left; font-size: 0.875rem; }
+        th { background: #f1f5f9; font-weight: 600; }
+        td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+        .savings { color: #16a34a; font-weight: 600; }
+        .error { color: #dc2626; }
+        a { color: #9247C2; }
+        footer { text-align: center; color: #64748b; font-size: 0.875rem; padding: 1.5rem; }
+    </style>
+</head>
+<body>
+<main>
+    <h1>{{.Title}}</h1>
+    <table>
+        <thead>
+            <tr>
+                <th>Artifact</th>
+                <th>Platform</th>
+                <th>Version</th>
+                <th class="num">Install Size</th>
+                <th class="num">Download Size</th>
+                <th class="num">Potential Savings</th>
+                <th>Top Optimization</th>
+                <th>Reports</th>
+            </tr>
+        </thead>
+        <tbody>
+        {{range .Rows}}
+            <tr>
+                <td><strong>{{.Name}}</strong></td>
+                {{if .Failed}}
+                <td colspan="6" class="error">Analysis failed: {{.Error}}</td>
+                {{else}}
+                <td>{{.Platform}}</td>
+                <td>{{if .Version}}{{.Version}}{{else}}-{{end}}</td>
+                <td class="num">{{.InstallSize}}</td>
+                <td class="num">{{.DownloadSize}}</td>
+                <td class="num savings">{{.TotalSavings}} ({{.SavingsPercentage}})</td>
+                <td>{{if .TopOptimization}}{{.TopOptimization}}{{else}}-{{end}}</td>
+                {{end}}
+                <td>{{range $i, $l := .Links}}{{if $i}}, {{end}}<a href="{{$l.Path}}">{{$l.Format}}</a>{{end}}</td>
+            </tr>
+        {{end}}
+        </tbody>
+    </table>
+
+    {{if .Categories}}
+    <h2>Size Breakdown by Category</h2>
+    <table>
+        <thead>
+            <tr>
+                <th>Category</th>
+                {{range .Names}}<th class="num">{{.}}</th>{{end}}
+            </tr>
+        </thead>
+        <tbody>
+        {{range .Categories}}
+            <tr>
+                <td>{{.Name}}</td>
+                {{range .Sizes}}<td class="num">{{.}}</td>{{end}}
+            </tr>
+        {{end}}
+        </tbody>
+    </table>
+    {{end}}
+</main>
+<footer>Generated by Bundle Inspector</footer>
+</body>
+</html>
+`
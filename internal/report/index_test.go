@@ -0,0 +1,130 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func createTestIndexEntries() []IndexEntry {
+	android := &types.Report{
+		ArtifactInfo: types.ArtifactInfo{
+			Path:    "/build/app-release.apk",
+			Type:    types.ArtifactTypeAPK,
+			Size:    12 * 1024 * 1024,
+			Version: "2.1.0",
+		},
+		SizeBreakdown: types.SizeBreakdown{
+			DEX:       9 * 1024 * 1024,
+			Libraries: 6 * 1024 * 1024,
+			Resources: 3 * 1024 * 1024,
+		},
+		Optimizations: []types.Optimization{
+			{Title: "Optimize PNG images", Impact: 200 * 1024},
+		},
+		TotalSavings: 200 * 1024,
+	}
+
+	return []IndexEntry{
+		{
+			Name:        "TestApp.ipa",
+			Report:      createTestReport(),
+			ReportFiles: map[string]string{"html": "bundle-analysis-TestApp.html", "json": "bundle-analysis-TestApp.json"},
+		},
+		{
+			Name:   "app-release.apk",
+			Report: android,
+			ReportFiles: map[string]string{
+				"markdown":          "bundle-analysis-app-release.md",
+				"csv":               "bundle-analysis-app-release.csv",
+				"csv-optimizations": "bundle-analysis-app-release-optimizations.csv",
+			},
+		},
+		{
+			Name:  "broken.aab",
+			Error: "failed to open AAB",
+		},
+	}
+}
+
+func TestMarkdownIndexFormatter_Format(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewMarkdownIndexFormatter().Format(&buf, createTestIndexEntries()); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"## Bitrise Report (3 artifacts)",
+		"| TestApp.ipa | iOS | - | 52.0 MB | 45.0 MB | 2.3 MB | Strip debug symbols from WMF | [html](bundle-analysis-TestApp.html), [json](bundle-analysis-TestApp.json) |",
+		"| app-release.apk | Android | 2.1.0 |",
+		"[markdown](bundle-analysis-app-release.md), [csv](bundle-analysis-app-release.csv), " +
+			"[csv-optimizations](bundle-analysis-app-release-optimizations.csv) |",
+		"| broken.aab | - | - | - | - | - | ❌ failed to open AAB | - |",
+		"| Category | TestApp.ipa | app-release.apk |",
+		"| Frameworks | 22.0 MB | - |",
+		"| DEX | - | 9.0 MB |",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q\n%s", want, output)
+		}
+	}
+
+	// Categories are ordered by their largest size across artifacts
+	if strings.Index(output, "| Frameworks |") > strings.Index(output, "| DEX |") {
+		t.Error("expected Frameworks (22 MB) before DEX (9 MB)")
+	}
+}
+
+func TestMarkdownIndexFormatter_Format_AllFailed(t *testing.T) {
+	var buf bytes.Buffer
+	entries := []IndexEntry{{Name: "broken.ipa", Error: "boom"}}
+	if err := NewMarkdownIndexFormatter().Format(&buf, entries); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "Size Breakdown by Category") {
+		t.Error("category comparison should be omitted without successful reports")
+	}
+}
+
+func TestHTMLIndexFormatter_Format(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewHTMLIndexFormatter().Format(&buf, createTestIndexEntries()); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"<title>Bundle Analysis Index</title>",
+		"<strong>TestApp.ipa</strong>",
+		`<a href="bundle-analysis-TestApp.html">html</a>`,
+		"Analysis failed: failed to open AAB",
+		"<th class=\"num\">app-release.apk</th>",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+
+	// The index must not depend on external resources
+	if strings.Contains(output, "<script src=") || strings.Contains(output, "<link ") {
+		t.Error("HTML index should not reference external resources")
+	}
+}
+
+func TestHTMLIndexFormatter_EscapesNames(t *testing.T) {
+	var buf bytes.Buffer
+	entries := []IndexEntry{{Name: "<script>alert(1)</script>.ipa", Error: "x"}}
+	if err := NewHTMLIndexFormatter().Format(&buf, entries); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "<script>alert(1)</script>") {
+		t.Error("artifact name was not escaped")
+	}
+}
@@ -0,0 +1,109 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// DecodeJSON reads a report written by the JSON formatter.
+// Older reports are migrated to the current schema version, and reports of a newer
+// schema version than this release supports are rejected.
+func DecodeJSON(r io.Reader) (*types.Report, error) {
+	var report types.Report
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("invalid report JSON: %w", err)
+	}
+
+	if report.SchemaVersion > types.SchemaVersion {
+		return nil, fmt.Errorf("report schema version %d is newer than the supported version %d, upgrade bundle-inspector to read it",
+			report.SchemaVersion, types.SchemaVersion)
+	}
+	if report.SchemaVersion < 0 {
+		return nil, fmt.Errorf("invalid report schema version %d", report.SchemaVersion)
+	}
+
+	if report.SchemaVersion < 2 {
+		if err := migrateLegacyMetadata(&report); err != nil {
+			return nil, err
+		}
+	}
+	report.SchemaVersion = types.SchemaVersion
+
+	return &report, nil
+}
+
+// Metadata keys of version 1 reports that moved into the typed sections.
+// Their JSON names match the fields of IOSDetails and AndroidDetails.
+var (
+	legacyIOSKeys     = []string{"app_bundle", "build_version", "min_os_version", "binaries", "frameworks", "dependency_graph", "asset_catalogs"}
+	legacyAndroidKeys = []string{"modules", "module_details", "module_delivery", "split_estimates"}
+)
+
+// migrateLegacyMetadata moves the platform details and CI info of a version 1 report
+// from the free-form metadata into the typed sections
+func migrateLegacyMetadata(report *types.Report) error {
+	if report.Metadata == nil {
+		return nil
+	}
+
+	ios := &types.IOSDetails{}
+	moved, err := moveLegacyMetadata(report.Metadata, legacyIOSKeys, ios)
+	if err != nil {
+		return fmt.Errorf("invalid iOS metadata: %w", err)
+	}
+	if moved {
+		report.IOS = ios
+	}
+
+	android := &types.AndroidDetails{}
+	moved, err = moveLegacyMetadata(report.Metadata, legacyAndroidKeys, android)
+	if err != nil {
+		return fmt.Errorf("invalid Android metadata: %w", err)
+	}
+	if moved {
+		report.Android = android
+	}
+
+	ci := &types.CIInfo{}
+	if v, ok := report.Metadata["git_branch"].(string); ok {
+		ci.Branch = v
+	}
+	// Older reports used git_commit instead of commit_hash
+	if v, ok := report.Metadata["commit_hash"].(string); ok {
+		ci.CommitHash = v
+	} else if v, ok := report.Metadata["git_commit"].(string); ok {
+		ci.CommitHash = v
+	}
+	for _, key := range []string{"git_branch", "commit_hash", "git_commit"} {
+		delete(report.Metadata, key)
+	}
+	if ci.Branch != "" || ci.CommitHash != "" {
+		report.CI = ci
+	}
+
+	return nil
+}
+
+// moveLegacyMetadata removes the given keys from metadata and decodes them into target.
+// It reports whether any of the keys were present.
+func moveLegacyMetadata(metadata map[string]interface{}, keys []string, target interface{}) (bool, error) {
+	legacy := make(map[string]interface{})
+	for _, key := range keys {
+		if value, ok := metadata[key]; ok {
+			legacy[key] = value
+			delete(metadata, key)
+		}
+	}
+	if len(legacy) == 0 {
+		return false, nil
+	}
+
+	data, err := json.Marshal(legacy)
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, target)
+}
@@ -0,0 +1,230 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// roundTrip writes a report with the JSON formatter and reads it back
+func roundTrip(t *testing.T, report *types.Report) *types.Report {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := NewJSONFormatter(true).Format(&buf, report); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	decoded, err := DecodeJSON(&buf)
+	if err != nil {
+		t.Fatalf("DecodeJSON() failed: %v", err)
+	}
+	return decoded
+}
+
+func TestDecodeJSON_TypedSections(t *testing.T) {
+	binary := &types.BinaryInfo{Architecture: "arm64", Architectures: []string{"arm64"}, Type: "executable", CodeSize: 100, LinkedLibraries: []string{"UIKit"}}
+	original := &types.Report{
+		SchemaVersion: types.SchemaVersion,
+		IOS: &types.IOSDetails{
+			MinOSVersion: "15.0",
+			Binaries:     map[string]*types.BinaryInfo{"App": binary},
+			Frameworks:   []*types.FrameworkInfo{{Name: "Foo", Path: "Frameworks/Foo.framework", Size: 42, BinaryInfo: binary}},
+			AssetCatalogs: []*types.AssetCatalogInfo{
+				{Path: "Assets.car", TotalSize: 10, AssetCount: 1, ByType: map[string]int64{"png": 10}, ByScale: map[string]int64{"2x": 10},
+					Assets: []types.AssetInfo{{Name: "icon", Type: "png", Size: 10}}},
+			},
+			DependencyGraph: map[string][]string{"App": {"Frameworks/Foo.framework"}},
+		},
+		Android: &types.AndroidDetails{
+			Modules:        []string{"base", "camera"},
+			ModuleDetails:  []types.ModuleInfo{{Name: "base", Type: "base", Delivery: "install-time", Size: 5}},
+			ModuleDelivery: &types.ModuleDeliverySizes{InstallTime: 5},
+			SplitEstimates: &types.SplitEstimate{
+				Splits:  []types.SplitSize{{Module: "base", Type: "master", Size: 5}},
+				Devices: []types.DeviceDownloadEstimate{{Device: types.DeviceSpec{Name: "pixel"}, DownloadSize: 5}},
+			},
+		},
+		CI:       &types.CIInfo{Branch: "main", CommitHash: "abc123"},
+		Metadata: map[string]interface{}{"platform": "iOS"},
+	}
+
+	decoded := roundTrip(t, original)
+
+	if !reflect.DeepEqual(decoded.IOS, original.IOS) {
+		t.Errorf("IOS = %+v, want %+v", decoded.IOS, original.IOS)
+	}
+	if !reflect.DeepEqual(decoded.Android, original.Android) {
+		t.Errorf("Android = %+v, want %+v", decoded.Android, original.Android)
+	}
+	if !reflect.DeepEqual(decoded.CI, original.CI) {
+		t.Errorf("CI = %+v, want %+v", decoded.CI, original.CI)
+	}
+	if !reflect.DeepEqual(decoded.Metadata, original.Metadata) {
+		t.Errorf("Metadata = %v, want %v", decoded.Metadata, original.Metadata)
+	}
+}
+
+func TestDecodeJSON_MigratesVersion1(t *testing.T) {
+	f, err := os.Open("testdata/report-v1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	decoded, err := DecodeJSON(f)
+	if err != nil {
+		t.Fatalf("DecodeJSON() failed: %v", err)
+	}
+
+	if decoded.SchemaVersion != types.SchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", decoded.SchemaVersion, types.SchemaVersion)
+	}
+	if decoded.IOS == nil {
+		t.Fatal("IOS not migrated from metadata")
+	}
+	if decoded.IOS.AppBundle != "MyApp.app" || decoded.IOS.MinOSVersion != "15.0" {
+		t.Errorf("IOS = %+v, want app bundle and minimum OS version", decoded.IOS)
+	}
+	if binary := decoded.IOS.Binaries["MyApp"]; binary == nil || binary.CodeSize != 4000 {
+		t.Errorf("Binaries = %+v, want MyApp with code size 4000", decoded.IOS.Binaries)
+	}
+	if len(decoded.IOS.Frameworks) != 1 || decoded.IOS.Frameworks[0].Version != "1.2.0" {
+		t.Errorf("Frameworks = %+v, want Foo 1.2.0", decoded.IOS.Frameworks)
+	}
+	if got := decoded.IOS.DependencyGraph["MyApp"]; len(got) != 1 {
+		t.Errorf("DependencyGraph = %v, want MyApp -> Foo", decoded.IOS.DependencyGraph)
+	}
+	if decoded.Android != nil {
+		t.Errorf("Android = %+v, want nil for an iOS report", decoded.Android)
+	}
+
+	// Version 1 reports stored the commit as git_commit
+	wantCI := &types.CIInfo{Branch: "feature/login", CommitHash: "a1b2c3d"}
+	if !reflect.DeepEqual(decoded.CI, wantCI) {
+		t.Errorf("CI = %+v, want %+v", decoded.CI, wantCI)
+	}
+
+	wantMetadata := map[string]interface{}{"platform": "iOS", "app_name": "MyApp"}
+	if !reflect.DeepEqual(decoded.Metadata, wantMetadata) {
+		t.Errorf("Metadata = %v, want %v", decoded.Metadata, wantMetadata)
+	}
+}
+
+func TestDecodeJSON_MigratesVersion1Android(t *testing.T) {
+	input := `{"metadata":{"platform":"Android","modules":["base","camera"],
+		"module_details":[{"name":"camera","type":"feature","delivery":"on-demand","size":512}],
+		"split_estimates":{"min_download_size":900,"max_download_size":1200},
+		"commit_hash":"abc123"}}`
+
+	decoded, err := DecodeJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("DecodeJSON() failed: %v", err)
+	}
+
+	if decoded.Android == nil {
+		t.Fatal("Android not migrated from metadata")
+	}
+	if len(decoded.Android.Modules) != 2 || len(decoded.Android.ModuleDetails) != 1 || decoded.Android.ModuleDetails[0].Delivery != "on-demand" {
+		t.Errorf("Android = %+v, want two modules and the camera details", decoded.Android)
+	}
+	if decoded.Android.SplitEstimates == nil || decoded.Android.SplitEstimates.MaxDownloadSize != 1200 {
+		t.Errorf("SplitEstimates = %+v, want max download size 1200", decoded.Android.SplitEstimates)
+	}
+	if decoded.IOS != nil {
+		t.Errorf("IOS = %+v, want nil for an Android report", decoded.IOS)
+	}
+	if decoded.CI == nil || decoded.CI.CommitHash != "abc123" || decoded.CI.Branch != "" {
+		t.Errorf("CI = %+v, want commit abc123", decoded.CI)
+	}
+	if len(decoded.Metadata) != 1 {
+		t.Errorf("Metadata = %v, want only the platform", decoded.Metadata)
+	}
+}
+
+func TestDecodeJSON_FormattersMatchOriginal(t *testing.T) {
+	original := &types.Report{
+		SchemaVersion: types.SchemaVersion,
+		ArtifactInfo: types.ArtifactInfo{
+			Path:       "/builds/app.aab",
+			Type:       types.ArtifactTypeAAB,
+			Size:       2048,
+			AnalyzedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		},
+		SizeBreakdown: types.SizeBreakdown{DEX: 1024, Resources: 512},
+		FileTree: []*types.FileNode{
+			{Name: "base", Path: "base", IsDir: true, Size: 1536, Children: []*types.FileNode{
+				{Name: "classes.dex", Path: "base/dex/classes.dex", Size: 1024},
+			}},
+		},
+		Android: &types.AndroidDetails{
+			ModuleDetails: []types.ModuleInfo{
+				{Name: "base", Type: "base", Delivery: "install-time", Size: 1536, CompressedSize: 1024},
+				{Name: "camera", Type: "feature", Delivery: "on-demand", Size: 512, CompressedSize: 256},
+			},
+			SplitEstimates: &types.SplitEstimate{
+				Devices:         []types.DeviceDownloadEstimate{{Device: types.DeviceSpec{Name: "pixel", SDKVersion: 33}, DownloadSize: 900}},
+				MinDownloadSize: 900,
+				MaxDownloadSize: 900,
+			},
+		},
+		CI: &types.CIInfo{Branch: "main", CommitHash: "abc123"},
+	}
+	decoded := roundTrip(t, original)
+
+	for name, format := range map[string]func(*types.Report) string{
+		"markdown": func(r *types.Report) string {
+			var buf bytes.Buffer
+			_ = NewMarkdownFormatter().Format(&buf, r)
+			return buf.String()
+		},
+		"text": func(r *types.Report) string {
+			var buf bytes.Buffer
+			_ = NewTextFormatter().Format(&buf, r)
+			return buf.String()
+		},
+	} {
+		want := format(original)
+		if got := format(decoded); got != want {
+			t.Errorf("%s output of the decoded report differs from the original", name)
+		}
+		if !strings.Contains(want, "camera") {
+			t.Errorf("%s output missing module breakdown, test report is not representative", name)
+		}
+	}
+}
+
+func TestDecodeJSON_SchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{"unversioned", `{"artifact_info":{"path":"app.ipa"}}`, ""},
+		{"version 1", `{"schema_version":1}`, ""},
+		{"current", `{"schema_version":2}`, ""},
+		{"newer", `{"schema_version":99}`, "newer than the supported version"},
+		{"negative", `{"schema_version":-1}`, "invalid report schema version"},
+		{"invalid JSON", `{"artifact_info":`, "invalid report JSON"},
+		{"invalid metadata", `{"metadata":{"binaries":["not","a","map"]}}`, "invalid iOS metadata"},
+		{"invalid Android metadata", `{"metadata":{"modules":"base"}}`, "invalid Android metadata"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeJSON(strings.NewReader(tt.json))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("DecodeJSON() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DecodeJSON() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
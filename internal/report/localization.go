@@ -0,0 +1,11 @@
+package report
+
+import "github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+
+// localeLabel returns the name of a locale, marking the base locale
+func localeLabel(locale types.LocaleInfo) string {
+	if locale.IsBase {
+		return locale.Locale + " (base)"
+	}
+	return locale.Locale
+}
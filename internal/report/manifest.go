@@ -0,0 +1,145 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// manifestChange is a manifest entry added or removed against a baseline, or a setting
+// whose value changed
+type manifestChange struct {
+	Kind     string // e.g. "Permission", "Exported activity" or "Target SDK"
+	Name     string // Entry name, empty for settings
+	Status   string // diffAdded, diffRemoved or diffChanged
+	Baseline string // Previous value of changed settings
+	Current  string // New value of changed settings
+}
+
+// manifestInfo returns the Android manifest details of a report, if any
+func manifestInfo(report *types.Report) *types.ManifestInfo {
+	if report.Android == nil {
+		return nil
+	}
+	return report.Android.Manifest
+}
+
+// manifestSDKLevels formats the SDK levels of a manifest, e.g. "min 24, target 34, compile 34"
+func manifestSDKLevels(m *types.ManifestInfo) string {
+	var parts []string
+	for _, level := range []struct {
+		name  string
+		value int
+	}{{"min", m.MinSDK}, {"target", m.TargetSDK}, {"compile", m.CompileSDK}} {
+		if level.value > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", level.name, level.value))
+		}
+	}
+	if len(parts) == 0 {
+		return "unknown"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// manifestFlags lists the application flags that are set, e.g. "debuggable" or "usesCleartextTraffic=true"
+func manifestFlags(m *types.ManifestInfo) []string {
+	var flags []string
+	if m.Debuggable {
+		flags = append(flags, "debuggable")
+	}
+	if m.ExtractNativeLibs != nil {
+		flags = append(flags, "extractNativeLibs="+strconv.FormatBool(*m.ExtractNativeLibs))
+	}
+	if m.UsesCleartextTraffic != nil {
+		flags = append(flags, "usesCleartextTraffic="+strconv.FormatBool(*m.UsesCleartextTraffic))
+	}
+	return flags
+}
+
+// exportedComponents returns the components other apps can start or bind to
+func exportedComponents(m *types.ManifestInfo) []types.ManifestComponent {
+	var exported []types.ManifestComponent
+	for _, c := range m.Components {
+		if c.Exported {
+			exported = append(exported, c)
+		}
+	}
+	return exported
+}
+
+// diffManifests lists the permissions and exported components added or removed, and the
+// SDK levels and application flags changed against the baseline, in that order.
+// It returns nil unless both reports have manifest details.
+func diffManifests(baseline, current *types.Report) []manifestChange {
+	base, head := manifestInfo(baseline), manifestInfo(current)
+	if base == nil || head == nil {
+		return nil
+	}
+
+	var changes []manifestChange
+	changes = append(changes, diffManifestEntries("Permission", base.Permissions, head.Permissions)...)
+	changes = append(changes, diffManifestEntries("Custom permission", base.CustomPermissions, head.CustomPermissions)...)
+	changes = append(changes, diffManifestEntries("Exported component", exportedComponentNames(base), exportedComponentNames(head))...)
+
+	settings := []struct {
+		kind           string
+		baseline, head string
+	}{
+		{"Min SDK", strconv.Itoa(base.MinSDK), strconv.Itoa(head.MinSDK)},
+		{"Target SDK", strconv.Itoa(base.TargetSDK), strconv.Itoa(head.TargetSDK)},
+		{"Compile SDK", strconv.Itoa(base.CompileSDK), strconv.Itoa(head.CompileSDK)},
+		{"debuggable", strconv.FormatBool(base.Debuggable), strconv.FormatBool(head.Debuggable)},
+		{"extractNativeLibs", formatOptionalBool(base.ExtractNativeLibs), formatOptionalBool(head.ExtractNativeLibs)},
+		{"usesCleartextTraffic", formatOptionalBool(base.UsesCleartextTraffic), formatOptionalBool(head.UsesCleartextTraffic)},
+	}
+	for _, s := range settings {
+		if s.baseline != s.head {
+			changes = append(changes, manifestChange{Kind: s.kind, Status: diffChanged, Baseline: s.baseline, Current: s.head})
+		}
+	}
+	return changes
+}
+
+// diffManifestEntries lists the names added to and removed from a sorted list
+func diffManifestEntries(kind string, baseline, current []string) []manifestChange {
+	inBaseline := make(map[string]bool, len(baseline))
+	for _, name := range baseline {
+		inBaseline[name] = true
+	}
+	inCurrent := make(map[string]bool, len(current))
+	for _, name := range current {
+		inCurrent[name] = true
+	}
+
+	var changes []manifestChange
+	for _, name := range current {
+		if !inBaseline[name] {
+			changes = append(changes, manifestChange{Kind: kind, Name: name, Status: diffAdded})
+		}
+	}
+	for _, name := range baseline {
+		if !inCurrent[name] {
+			changes = append(changes, manifestChange{Kind: kind, Name: name, Status: diffRemoved})
+		}
+	}
+	return changes
+}
+
+// exportedComponentNames names the exported components by type, e.g. "service com.example.SyncService"
+func exportedComponentNames(m *types.ManifestInfo) []string {
+	var names []string
+	for _, c := range exportedComponents(m) {
+		names = append(names, c.Type+" "+c.Name)
+	}
+	return names
+}
+
+// formatOptionalBool formats a flag that may be unset
+func formatOptionalBool(b *bool) string {
+	if b == nil {
+		return "unset"
+	}
+	return strconv.FormatBool(*b)
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// manifestReport returns a report with the given Android manifest details
+func manifestReport(m *types.ManifestInfo) *types.Report {
+	return &types.Report{Android: &types.AndroidDetails{Manifest: m}}
+}
+
+func TestDiffManifests(t *testing.T) {
+	enabled := true
+	base := manifestReport(&types.ManifestInfo{
+		MinSDK:      24,
+		TargetSDK:   33,
+		Permissions: []string{"android.permission.INTERNET", "android.permission.VIBRATE"},
+		Components: []types.ManifestComponent{
+			{Type: "activity", Name: "com.example.MainActivity", Exported: true},
+			{Type: "service", Name: "com.example.SyncService"},
+		},
+	})
+	head := manifestReport(&types.ManifestInfo{
+		MinSDK:               24,
+		TargetSDK:            34,
+		Permissions:          []string{"android.permission.CAMERA", "android.permission.INTERNET"},
+		UsesCleartextTraffic: &enabled,
+		Components: []types.ManifestComponent{
+			{Type: "activity", Name: "com.example.MainActivity", Exported: true},
+			{Type: "service", Name: "com.example.SyncService", Exported: true},
+		},
+	})
+
+	want := []manifestChange{
+		{Kind: "Permission", Name: "android.permission.CAMERA", Status: diffAdded},
+		{Kind: "Permission", Name: "android.permission.VIBRATE", Status: diffRemoved},
+		{Kind: "Exported component", Name: "service com.example.SyncService", Status: diffAdded},
+		{Kind: "Target SDK", Status: diffChanged, Baseline: "33", Current: "34"},
+		{Kind: "usesCleartextTraffic", Status: diffChanged, Baseline: "unset", Current: "true"},
+	}
+	if got := diffManifests(base, head); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffManifests() = %+v, want %+v", got, want)
+	}
+
+	if diffManifests(base, base) != nil {
+		t.Error("diffManifests() of identical manifests should be empty")
+	}
+	if diffManifests(&types.Report{}, head) != nil {
+		t.Error("diffManifests() should be nil without a baseline manifest")
+	}
+}
+
+func TestManifestSDKLevels(t *testing.T) {
+	if got := manifestSDKLevels(&types.ManifestInfo{MinSDK: 21, TargetSDK: 34}); got != "min 21, target 34" {
+		t.Errorf("manifestSDKLevels() = %q", got)
+	}
+	if got := manifestSDKLevels(&types.ManifestInfo{}); got != "unknown" {
+		t.Errorf("manifestSDKLevels() = %q, want unknown", got)
+	}
+}
@@ -11,7 +11,9 @@ import (
 )
 
 // MarkdownFormatter formats reports as GitHub/GitLab compatible markdown
-type MarkdownFormatter struct{}
+type MarkdownFormatter struct {
+	SizeView SizeView // Sizes of the largest files, install by default
+}
 
 // NewMarkdownFormatter creates a new markdown formatter
 func NewMarkdownFormatter() *MarkdownFormatter {
@@ -24,6 +26,72 @@ func (f *MarkdownFormatter) Format(w io.Writer, report *types.Report) error {
 		return err
 	}
 
+	if estimate := splitEstimates(report); estimate != nil {
+		if err := f.writeSplitEstimates(w, estimate); err != nil {
+			return err
+		}
+	}
+
+	if modules := moduleDetails(report); len(modules) > 1 {
+		if err := f.writeModules(w, modules); err != nil {
+			return err
+		}
+	}
+
+	if len(report.Ownership) > 0 {
+		if err := f.writeOwnership(w, report.Ownership); err != nil {
+			return err
+		}
+	}
+
+	if sdks := sdkSizes(report); len(sdks) > 0 {
+		if err := f.writeSDKs(w, sdks, sdkShareTotal(report)); err != nil {
+			return err
+		}
+	}
+
+	if deps := dependencySizes(report); len(deps) > 0 {
+		if err := f.writeDependencies(w, deps, sdkShareTotal(report)); err != nil {
+			return err
+		}
+	}
+
+	if binaries := runtimeMetadata(report); len(binaries) > 0 {
+		if err := f.writeRuntimeMetadata(w, binaries); err != nil {
+			return err
+		}
+	}
+
+	if binaries := stringLiterals(report); len(binaries) > 0 {
+		if err := f.writeStrings(w, binaries, report.IOS.DuplicateStrings); err != nil {
+			return err
+		}
+	}
+
+	if bundles := signedBundles(report); len(bundles) > 0 {
+		if err := f.writeSigning(w, bundles); err != nil {
+			return err
+		}
+	}
+
+	if m := manifestInfo(report); m != nil {
+		if err := f.writeManifest(w, m); err != nil {
+			return err
+		}
+	}
+
+	if hasArchiveLayout(report) {
+		if err := f.writeArchiveLayout(w, report.Android); err != nil {
+			return err
+		}
+	}
+
+	if l := report.Localization; l != nil && len(l.Locales) > 0 {
+		if err := f.writeLocalization(w, l); err != nil {
+			return err
+		}
+	}
+
 	// Group optimizations by category
 	categoryGroups := getCategoryGroups(report.Optimizations)
 
@@ -34,11 +102,20 @@ func (f *MarkdownFormatter) Format(w io.Writer, report *types.Report) error {
 	}{
 		"strip-symbols":      {"Strip Binary Symbols", "🔧"},
 		"frameworks":         {"Unused Frameworks", "📦"},
+		"framework-linking":  {"Framework Linking", "⛓️"},
 		"duplicates":         {"Duplicate Files", "🔄"},
+		"duplicate-code":     {"Duplicate Code", "🧬"},
 		"image-optimization": {"Image Optimization", "🖼️"},
 		"loose-images":       {"Loose Images", "📸"},
 		"unnecessary-files":  {"Unnecessary Files", "🗑️"},
 		"small-files":        {"Small Files", "📄"},
+		"dynamic-delivery":   {"Dynamic Delivery", "🧩"},
+		"swift-metadata":     {"Swift Reflection Metadata", "🪞"},
+		"architecture":       {"Simulator Slices", "📱"},
+		"bitcode":            {"Embedded Bitcode", "🧱"},
+		"build-paths":        {"Build Machine Paths", "🏗️"},
+		"localization":       {"Unsupported Locales", "🌐"},
+		"zip-storage":        {"ZIP Storage", "🗜️"},
 	}
 
 	// Sort categories by total savings (highest first)
@@ -93,15 +170,13 @@ func (f *MarkdownFormatter) writeHeader(w io.Writer, report *types.Report) error
 		artifactName = artifactName[idx+1:]
 	}
 
-	// Get commit hash from metadata if available
+	// Get commit hash from CI info if available
 	commitHash := "-"
-	if report.Metadata != nil {
-		if commit, ok := report.Metadata["commit_hash"].(string); ok && commit != "" {
-			if len(commit) > 7 {
-				commitHash = commit[:7]
-			} else {
-				commitHash = commit
-			}
+	if commit := ciCommitHash(report); commit != "" {
+		if len(commit) > 7 {
+			commitHash = commit[:7]
+		} else {
+			commitHash = commit
 		}
 	}
 
@@ -129,6 +204,433 @@ func (f *MarkdownFormatter) writeHeader(w io.Writer, report *types.Report) error
 		return err
 	}
 
+	for _, warning := range report.Warnings {
+		if _, err := fmt.Fprintf(w, "> ⚠️ %s\n\n", warning); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeSplitEstimates writes estimated per-device download sizes for app bundles
+func (f *MarkdownFormatter) writeSplitEstimates(w io.Writer, estimate *types.SplitEstimate) error {
+	if len(estimate.Devices) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>📱 Estimated Download Size</strong> (%s – %s across %d devices)</summary>\n\n",
+		util.FormatBytes(estimate.MinDownloadSize), util.FormatBytes(estimate.MaxDownloadSize), len(estimate.Devices)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "| Device | Download | Master | ABI | Density | Language |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|--------|---------:|-------:|----:|--------:|---------:|\n"); err != nil {
+		return err
+	}
+	for _, d := range estimate.Devices {
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s |\n",
+			d.Device.Name, util.FormatBytes(d.DownloadSize), util.FormatBytes(d.MasterSize),
+			util.FormatBytes(d.ABISize), util.FormatBytes(d.DensitySize), util.FormatBytes(d.LanguageSize)); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "\nEstimated from the bundle's compressed entries; actual Play Store sizes may differ.\n\n</details>\n\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeModules writes the app bundle module breakdown with delivery types
+func (f *MarkdownFormatter) writeModules(w io.Writer, modules []types.ModuleInfo) error {
+	var installTime, deferred int64
+	for _, m := range modules {
+		if m.Delivery == "install-time" {
+			installTime += m.CompressedSize
+		} else {
+			deferred += m.CompressedSize
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>🧩 App Bundle Modules</strong> (%s install-time, %s deferred)</summary>\n\n",
+		util.FormatBytes(installTime), util.FormatBytes(deferred)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "| Module | Type | Delivery | Size | DEX | Resources | Libraries | Assets |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|--------|------|----------|-----:|----:|----------:|----------:|-------:|\n"); err != nil {
+		return err
+	}
+	for _, m := range modules {
+		delivery := m.Delivery
+		if len(m.Conditions) > 0 {
+			delivery += " (" + strings.Join(m.Conditions, "; ") + ")"
+		}
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			m.Name, m.Type, delivery, util.FormatBytes(m.CompressedSize),
+			util.FormatBytes(m.DEXSize), util.FormatBytes(m.ResourcesSize),
+			util.FormatBytes(m.LibrariesSize), util.FormatBytes(m.AssetsSize)); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "\nSize is compressed; category columns are uncompressed.\n\n</details>\n\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeOwnership writes the sizes, potential savings and duplicate waste by owner
+func (f *MarkdownFormatter) writeOwnership(w io.Writer, owners []types.OwnerSize) error {
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>👥 Size by Owner</strong> (%d owners)</summary>\n\n", len(owners)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "| Owner | Size | Share | Files | Potential Savings | Duplicate Waste |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|-------|-----:|------:|------:|------------------:|----------------:|\n"); err != nil {
+		return err
+	}
+	owned := ownedSize(owners)
+	for _, o := range owners {
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %d | %s | %s |\n",
+			o.Owner, util.FormatBytes(o.Size), util.FormatPercentage(o.Size, owned), o.FileCount,
+			util.FormatBytes(o.PotentialSavings), util.FormatBytes(o.DuplicateWaste)); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "\n</details>\n\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeSDKs writes the sizes of known third-party libraries by kind
+func (f *MarkdownFormatter) writeSDKs(w io.Writer, sdks []types.SDKSize, shareTotal int64) error {
+	total := int64(0)
+	for _, s := range sdks {
+		total += s.Size
+	}
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>📚 SDK Sizes</strong> (%d libraries, %s)</summary>\n\n",
+		len(sdks), util.FormatBytes(total)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "| Library | Size | Share | DEX | Native | Assets | META-INF | Classes | Files |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|---------|-----:|------:|----:|-------:|-------:|---------:|--------:|------:|\n"); err != nil {
+		return err
+	}
+	for _, s := range sdks {
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s | %d | %d |\n",
+			s.Name, util.FormatBytes(s.Size), util.FormatPercentage(s.Size, shareTotal),
+			util.FormatBytes(s.DEXSize), util.FormatBytes(s.NativeSize), util.FormatBytes(s.AssetSize),
+			util.FormatBytes(s.MetaInfSize), s.Classes, s.Files); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "\n</details>\n\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeDependencies writes the sizes of Swift packages, pods and Carthage dependencies by kind
+func (f *MarkdownFormatter) writeDependencies(w io.Writer, deps []types.DependencySize, shareTotal int64) error {
+	total := int64(0)
+	for _, d := range deps {
+		total += d.Size
+	}
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>🔗 Dependency Sizes</strong> (%d dependencies, %s)</summary>\n\n",
+		len(deps), util.FormatBytes(total)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "| Dependency | Source | Version | Size | Share | Framework | Bundles | Static Code |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|------------|--------|---------|-----:|------:|----------:|--------:|------------:|\n"); err != nil {
+		return err
+	}
+	for _, d := range deps {
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			d.Name, valueOrDash(d.Source), valueOrDash(d.Version), util.FormatBytes(d.Size),
+			util.FormatPercentage(d.Size, shareTotal), util.FormatBytes(d.FrameworkSize),
+			util.FormatBytes(d.BundleSize), util.FormatBytes(d.CodeSize)); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "\n</details>\n\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeSigning writes the provisioning profile and entitlements of the app and its extensions
+func (f *MarkdownFormatter) writeSigning(w io.Writer, bundles []signedBundle) error {
+	summary := "unsigned"
+	if p := bundles[0].signing.Profile; p != nil {
+		summary = fmt.Sprintf("%s, %s", p.DistributionType, profileTeam(p))
+	}
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>🔏 Signing</strong> (%s)</summary>\n\n", summary); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "| Bundle | Profile | Distribution | Expires | Devices | Entitlements |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|--------|---------|--------------|---------|--------:|--------------|\n"); err != nil {
+		return err
+	}
+	for _, b := range bundles {
+		profile, distribution, expires, devices := "-", "-", "-", "-"
+		if p := b.signing.Profile; p != nil {
+			profile, distribution = p.Name, p.DistributionType
+			expires = p.ExpiresAt.UTC().Format("2006-01-02")
+			devices = fmt.Sprintf("%d", p.ProvisionedDevices)
+		}
+		entitlements := "-"
+		if keys := entitlementKeys(b.signing.Entitlements); len(keys) > 0 {
+			entitlements = "`" + strings.Join(keys, "`, `") + "`"
+		}
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s |\n",
+			b.name, profile, distribution, expires, devices, entitlements); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "\n</details>\n\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeManifest writes the SDK levels, permissions, flags and components of an Android manifest
+func (f *MarkdownFormatter) writeManifest(w io.Writer, m *types.ManifestInfo) error {
+	summary := fmt.Sprintf("SDK %s, %d permissions", manifestSDKLevels(m), len(m.Permissions))
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>📜 Manifest</strong> (%s)</summary>\n\n", summary); err != nil {
+		return err
+	}
+
+	lists := []struct {
+		name   string
+		values []string
+	}{
+		{"Permissions", m.Permissions},
+		{"Custom permissions", m.CustomPermissions},
+		{"Flags", manifestFlags(m)},
+		{"Meta-data", m.MetaDataKeys},
+	}
+	for _, l := range lists {
+		if len(l.values) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "**%s:** `%s`\n\n", l.name, strings.Join(l.values, "`, `")); err != nil {
+			return err
+		}
+	}
+
+	if len(m.Components) > 0 {
+		if _, err := fmt.Fprintf(w, "| Component | Type | Exported | Intent Filter |\n"); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "|-----------|------|----------|---------------|\n"); err != nil {
+			return err
+		}
+		for _, c := range m.Components {
+			exported, intentFilter := "no", "no"
+			if c.Exported {
+				exported = "**yes**"
+			}
+			if c.HasIntentFilter {
+				intentFilter = "yes"
+			}
+			if _, err := fmt.Fprintf(w, "| `%s` | %s | %s | %s |\n", c.Name, c.Type, exported, intentFilter); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "\n"); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "</details>\n\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeArchiveLayout writes the APK Signing Block entries, META-INF/ files by kind and
+// the ZIP structures of an Android artifact
+func (f *MarkdownFormatter) writeArchiveLayout(w io.Writer, a *types.AndroidDetails) error {
+	summary := "unsigned"
+	if a.Signing != nil {
+		summary = "signature schemes " + signingSchemes(a.Signing)
+	}
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>🗄️ Archive Layout</strong> (%s)</summary>\n\n", summary); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "| Part | Files | Size |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|------|------:|-----:|\n"); err != nil {
+		return err
+	}
+
+	type row struct {
+		part  string
+		files string
+		size  int64
+	}
+	var rows []row
+	if a.Signing != nil {
+		for _, b := range a.Signing.Blocks {
+			rows = append(rows, row{"APK Signing Block: " + b.Name, "-", b.Size})
+		}
+	}
+	for _, g := range a.MetaInf {
+		rows = append(rows, row{"META-INF/ " + g.Kind, fmt.Sprintf("%d", g.Files), g.Size})
+	}
+	if o := a.ZIPOverhead; o != nil {
+		rows = append(rows, row{"ZIP local headers", "-", o.LocalHeaders}, row{"ZIP central directory", "-", o.CentralDirectory})
+	}
+	for _, r := range rows {
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s |\n", r.part, r.files, util.FormatBytes(r.size)); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "\n</details>\n\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeLocalization writes the size, translated keys and missing keys of every locale
+func (f *MarkdownFormatter) writeLocalization(w io.Writer, l *types.LocalizationInfo) error {
+	thirdParty := 0
+	for _, locale := range l.Locales {
+		if locale.ThirdPartyOnly {
+			thirdParty++
+		}
+	}
+	summary := fmt.Sprintf("%d locales, %s", len(l.Locales), util.FormatBytes(l.TotalSize))
+	if thirdParty > 0 {
+		summary += fmt.Sprintf(", %d from third-party code only", thirdParty)
+	}
+
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>🌐 Localization</strong> (%s)</summary>\n\n", summary); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "| Locale | Size | Keys | Missing Keys | Shipped By |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|--------|-----:|-----:|-------------:|------------|\n"); err != nil {
+		return err
+	}
+	for _, locale := range l.Locales {
+		shippedBy := "app"
+		if locale.ThirdPartyOnly {
+			shippedBy = "third-party only"
+		}
+		if _, err := fmt.Fprintf(w, "| %s | %s | %d | %d | %s |\n",
+			localeLabel(locale), util.FormatBytes(locale.Size), locale.Keys, locale.MissingKeys, shippedBy); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "\n</details>\n\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeRuntimeMetadata writes the Swift and Objective-C metadata counts and sizes by binary
+func (f *MarkdownFormatter) writeRuntimeMetadata(w io.Writer, binaries []binaryMetadata) error {
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>🧬 Swift and Objective-C Metadata</strong> (%d binaries)</summary>\n\n", len(binaries)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "| Binary | Swift Types | Protocols | Conformances | ObjC Classes | Selectors | Swift Metadata | Reflection | ObjC Metadata |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|--------|------------:|----------:|-------------:|-------------:|----------:|---------------:|-----------:|--------------:|\n"); err != nil {
+		return err
+	}
+	for _, b := range binaries {
+		m := b.metadata
+		if _, err := fmt.Fprintf(w, "| `%s` | %d | %d | %d | %d | %d | %s | %s | %s |\n",
+			b.path, m.SwiftTypes, m.SwiftProtocols, m.SwiftConformances, m.ObjCClasses, m.ObjCSelectors,
+			util.FormatBytes(m.SwiftMetadataSize), util.FormatBytes(m.ReflectionSize), util.FormatBytes(m.ObjCMetadataSize)); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "\n</details>\n\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeStrings writes the string literals by binary, the largest literals and the strings
+// compiled into several binaries
+func (f *MarkdownFormatter) writeStrings(w io.Writer, binaries []binaryStrings, duplicates []types.DuplicateString) error {
+	total := int64(0)
+	for _, b := range binaries {
+		total += b.strings.TotalSize
+	}
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>🔤 String Literals</strong> (%d binaries, %s)</summary>\n\n",
+		len(binaries), util.FormatBytes(total)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "| Binary | Strings | Size | C Strings | os_log | Selectors | Swift Fields | URLs | Log Formats | Build Paths |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|--------|--------:|-----:|----------:|-------:|----------:|-------------:|-----:|------------:|------------:|\n"); err != nil {
+		return err
+	}
+	for _, b := range binaries {
+		s := b.strings
+		if _, err := fmt.Fprintf(w, "| `%s` | %d | %s | %s | %s | %s | %s | %d | %d | %d |\n",
+			b.path, s.Count, util.FormatBytes(s.TotalSize),
+			util.FormatBytes(stringSection(s, "__cstring").Size), util.FormatBytes(stringSection(s, "__oslogstring").Size),
+			util.FormatBytes(stringSection(s, "__objc_methname").Size), util.FormatBytes(stringSection(s, "__swift5_reflstr").Size),
+			stringCategory(s, "url").Count, stringCategory(s, "log_format").Count, stringCategory(s, "build_path").Count); err != nil {
+			return err
+		}
+	}
+
+	if literals := largestLiterals(binaries, 10); len(literals) > 0 {
+		if _, err := fmt.Fprintf(w, "\n**Largest literals**\n\n| Literal | Section | Binary | Size |\n|---------|---------|--------|-----:|\n"); err != nil {
+			return err
+		}
+		for _, l := range literals {
+			if _, err := fmt.Fprintf(w, "| `%s` | %s | `%s` | %s |\n",
+				printableLiteral(l.Value), l.Section, l.binary, util.FormatBytes(l.Size)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(duplicates) > 0 {
+		if _, err := fmt.Fprintf(w, "\n**Strings in several binaries**\n\n| String | Size | Wasted | Binaries |\n|--------|-----:|-------:|----------|\n"); err != nil {
+			return err
+		}
+		for _, d := range duplicates {
+			if _, err := fmt.Fprintf(w, "| `%s` | %s | %s | %s |\n",
+				printableLiteral(d.Value), util.FormatBytes(d.Size), util.FormatBytes(d.WastedSize), strings.Join(d.Binaries, ", ")); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "\n</details>\n\n"); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -187,35 +689,41 @@ func (f *MarkdownFormatter) writeSizeBreakdown(w io.Writer, report *types.Report
 
 // writeLargestFiles writes the largest files section
 func (f *MarkdownFormatter) writeLargestFiles(w io.Writer, report *types.Report) error {
-	if len(report.LargestFiles) == 0 {
+	view := f.SizeView.resolve(report.FileTree)
+	files := view.largestFiles(report)
+	if len(files) == 0 {
 		return nil
 	}
 
 	// Limit to top 10
 	limit := 10
-	if len(report.LargestFiles) < limit {
-		limit = len(report.LargestFiles)
+	if len(files) < limit {
+		limit = len(files)
 	}
 
 	// Calculate combined size
 	var combinedSize int64
 	for i := 0; i < limit; i++ {
-		combinedSize += report.LargestFiles[i].Size
+		combinedSize += view.nodeSize(&files[i])
 	}
 
 	total := calculateUncompressedSize(&report.SizeBreakdown)
+	if view == SizeViewDownload {
+		total = report.ArtifactInfo.Size
+	}
 
-	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>📦 Top %d Largest Files</strong> (%s combined)</summary>\n\n",
-		limit, util.FormatBytes(combinedSize)); err != nil {
+	if _, err := fmt.Fprintf(w, "<details>\n<summary><strong>📦 Top %d Largest Files%s</strong> (%s combined)</summary>\n\n",
+		limit, view.heading(), util.FormatBytes(combinedSize)); err != nil {
 		return err
 	}
 
 	for i := 0; i < limit; i++ {
-		file := report.LargestFiles[i]
-		percentage := float64(file.Size) / float64(total) * 100
+		file := files[i]
+		size := view.nodeSize(&file)
+		percentage := float64(size) / float64(total) * 100
 		truncated := truncatePath(file.Path, 80)
 		if _, err := fmt.Fprintf(w, "%d. `%s` - %s (%.1f%%)\n",
-			i+1, truncated, util.FormatBytes(file.Size), percentage); err != nil {
+			i+1, truncated, util.FormatBytes(size), percentage); err != nil {
 			return err
 		}
 	}
@@ -526,3 +1034,27 @@ func findLargestCategory(breakdown *types.SizeBreakdown) (string, int64) {
 
 	return maxName, maxSize
 }
+
+// splitEstimates returns the AAB split estimates of a report, if any
+func splitEstimates(report *types.Report) *types.SplitEstimate {
+	if report.Android == nil {
+		return nil
+	}
+	return report.Android.SplitEstimates
+}
+
+// moduleDetails returns the AAB module breakdown of a report, if any
+func moduleDetails(report *types.Report) []types.ModuleInfo {
+	if report.Android == nil {
+		return nil
+	}
+	return report.Android.ModuleDetails
+}
+
+// ciCommitHash returns the commit the report was built from, if known
+func ciCommitHash(report *types.Report) string {
+	if report.CI == nil {
+		return ""
+	}
+	return report.CI.CommitHash
+}
@@ -0,0 +1,266 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// defaultCompactOptimizations is the number of optimizations listed in compact output
+const defaultCompactOptimizations = 3
+
+// defaultCompactOwners is the number of owners listed in compact output without a baseline
+const defaultCompactOwners = 5
+
+// CompactMarkdownFormatter formats a short, delta-focused summary suited for
+// pull request comments. When a baseline report is set, sizes are shown with
+// their change against the baseline and only changed categories and owners are listed.
+type CompactMarkdownFormatter struct {
+	baseline         *types.Report
+	maxOptimizations int
+}
+
+// NewCompactMarkdownFormatter creates a new compact markdown formatter.
+// baseline may be nil, in which case no deltas are shown.
+func NewCompactMarkdownFormatter(baseline *types.Report) *CompactMarkdownFormatter {
+	return &CompactMarkdownFormatter{
+		baseline:         baseline,
+		maxOptimizations: defaultCompactOptimizations,
+	}
+}
+
+// Format writes the compact report in markdown format to the writer
+func (f *CompactMarkdownFormatter) Format(w io.Writer, report *types.Report) error {
+	if err := f.writeSummary(w, report); err != nil {
+		return err
+	}
+
+	if f.baseline != nil {
+		if err := f.writeCategoryChanges(w, report); err != nil {
+			return err
+		}
+		if err := f.writeOwnerChanges(w, report); err != nil {
+			return err
+		}
+		if err := f.writeManifestChanges(w, report); err != nil {
+			return err
+		}
+	} else if err := f.writeTopOwners(w, report); err != nil {
+		return err
+	}
+
+	return f.writeTopOptimizations(w, report)
+}
+
+// writeSummary writes the header and the one-row summary table
+func (f *CompactMarkdownFormatter) writeSummary(w io.Writer, report *types.Report) error {
+	if _, err := fmt.Fprintf(w, "## Bitrise Report\n\n"); err != nil {
+		return err
+	}
+
+	artifactName := report.ArtifactInfo.Path
+	if idx := strings.LastIndex(artifactName, "/"); idx >= 0 {
+		artifactName = artifactName[idx+1:]
+	}
+
+	commitHash := "-"
+	if commit := ciCommitHash(report); commit != "" {
+		commitHash = commit
+		if len(commit) > 7 {
+			commitHash = commit[:7]
+		}
+	}
+
+	installSize := calculateUncompressedSize(&report.SizeBreakdown)
+	downloadSize := report.ArtifactInfo.Size
+
+	installCell := util.FormatBytes(installSize)
+	downloadCell := util.FormatBytes(downloadSize)
+	if f.baseline != nil {
+		installCell += " (" + formatSizeDelta(installSize-calculateUncompressedSize(&f.baseline.SizeBreakdown)) + ")"
+		downloadCell += " (" + formatSizeDelta(downloadSize-f.baseline.ArtifactInfo.Size) + ")"
+	}
+
+	if _, err := fmt.Fprintf(w, "| Bundle | Commit | Install Size | Download Size | Potential Savings |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|--------|--------|--------------|---------------|-------------------|\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "| %s | `%s` | %s | %s | %s |\n\n",
+		artifactName, commitHash, installCell, downloadCell, util.FormatBytes(report.TotalSavings)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeCategoryChanges lists the size categories that changed against the baseline,
+// largest change first
+func (f *CompactMarkdownFormatter) writeCategoryChanges(w io.Writer, report *types.Report) error {
+	type categoryChange struct {
+		name     string
+		baseline int64
+		current  int64
+	}
+
+	baselineSizes := categorySizes(&f.baseline.SizeBreakdown)
+	var changes []categoryChange
+	for i, cat := range categorySizes(&report.SizeBreakdown) {
+		if cat.size != baselineSizes[i].size {
+			changes = append(changes, categoryChange{cat.name, baselineSizes[i].size, cat.size})
+		}
+	}
+
+	if len(changes) == 0 {
+		_, err := fmt.Fprintf(w, "No size changes compared to the baseline.\n\n")
+		return err
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return absInt64(changes[i].current-changes[i].baseline) > absInt64(changes[j].current-changes[j].baseline)
+	})
+
+	if _, err := fmt.Fprintf(w, "| Category | Baseline | Current | Change |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|----------|----------|---------|--------|\n"); err != nil {
+		return err
+	}
+	for _, c := range changes {
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+			c.name, util.FormatBytes(c.baseline), util.FormatBytes(c.current), formatSizeDelta(c.current-c.baseline)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n")
+	return err
+}
+
+// writeOwnerChanges lists the owners whose size changed against the baseline, largest
+// change first. Nothing is written unless both reports have sizes by owner.
+func (f *CompactMarkdownFormatter) writeOwnerChanges(w io.Writer, report *types.Report) error {
+	var changed []ownerChange
+	for _, c := range diffOwners(f.baseline, report) {
+		if c.current != c.baseline {
+			changed = append(changed, c)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "| Owner | Baseline | Current | Change |\n"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "|-------|----------|---------|--------|\n"); err != nil {
+		return err
+	}
+	for _, c := range changed {
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+			c.owner, util.FormatBytes(c.baseline), util.FormatBytes(c.current), formatSizeDelta(c.current-c.baseline)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n")
+	return err
+}
+
+// writeManifestChanges lists the permissions, exported components, SDK levels and flags
+// that changed in the Android manifest, so that they are noticed in review
+func (f *CompactMarkdownFormatter) writeManifestChanges(w io.Writer, report *types.Report) error {
+	changes := diffManifests(f.baseline, report)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "**Manifest changes:**\n"); err != nil {
+		return err
+	}
+	for _, c := range changes {
+		var line string
+		switch c.Status {
+		case diffAdded:
+			line = fmt.Sprintf("Added %s `%s`", strings.ToLower(c.Kind), c.Name)
+		case diffRemoved:
+			line = fmt.Sprintf("Removed %s `%s`", strings.ToLower(c.Kind), c.Name)
+		default:
+			line = fmt.Sprintf("%s: %s → %s", c.Kind, c.Baseline, c.Current)
+		}
+		if _, err := fmt.Fprintf(w, "- %s\n", line); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n")
+	return err
+}
+
+// writeTopOwners lists the largest owners on one line
+func (f *CompactMarkdownFormatter) writeTopOwners(w io.Writer, report *types.Report) error {
+	if len(report.Ownership) == 0 {
+		return nil
+	}
+
+	owners := report.Ownership
+	if len(owners) > defaultCompactOwners {
+		owners = owners[:defaultCompactOwners]
+	}
+	parts := make([]string, 0, len(owners))
+	for _, o := range owners {
+		parts = append(parts, fmt.Sprintf("%s (%s)", o.Owner, util.FormatBytes(o.Size)))
+	}
+
+	_, err := fmt.Fprintf(w, "**Largest owners:** %s\n\n", strings.Join(parts, ", "))
+	return err
+}
+
+// writeTopOptimizations lists the optimizations with the largest impact, one line each
+func (f *CompactMarkdownFormatter) writeTopOptimizations(w io.Writer, report *types.Report) error {
+	if len(report.Optimizations) == 0 {
+		return nil
+	}
+
+	opts := make([]types.Optimization, len(report.Optimizations))
+	copy(opts, report.Optimizations)
+	sort.SliceStable(opts, func(i, j int) bool {
+		return opts[i].Impact > opts[j].Impact
+	})
+	if len(opts) > f.maxOptimizations {
+		opts = opts[:f.maxOptimizations]
+	}
+
+	if _, err := fmt.Fprintf(w, "**Top optimizations:**\n"); err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		if _, err := fmt.Fprintf(w, "- %s (%s)\n", opt.Title, util.FormatBytes(opt.Impact)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n")
+	return err
+}
+
+// formatSizeDelta formats a signed size difference, e.g. "+1.2 MB" or "-300 B"
+func formatSizeDelta(delta int64) string {
+	switch {
+	case delta > 0:
+		return "+" + util.FormatBytes(delta)
+	case delta < 0:
+		return "-" + util.FormatBytes(-delta)
+	default:
+		return "no change"
+	}
+}
+
+// absInt64 returns the absolute value of n
+func absInt64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
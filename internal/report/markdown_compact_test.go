@@ -0,0 +1,156 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func TestCompactMarkdownFormatter_Format_NoBaseline(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewCompactMarkdownFormatter(nil).Format(&buf, createTestReport()); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, "| TestApp.ipa | `-` | 52.0 MB | 45.0 MB |") {
+		t.Errorf("output missing summary row\n%s", output)
+	}
+	if strings.Contains(output, "| Category | Baseline |") {
+		t.Error("category changes should be omitted without a baseline")
+	}
+	if !strings.Contains(output, "- Strip debug symbols from WMF") {
+		t.Errorf("output missing top optimization\n%s", output)
+	}
+}
+
+func TestCompactMarkdownFormatter_Format_WithBaseline(t *testing.T) {
+	current := createTestReport()
+	baseline := createTestReport()
+	baseline.ArtifactInfo.Size -= 2 * 1024 * 1024
+	baseline.SizeBreakdown.Frameworks -= 3 * 1024 * 1024
+	baseline.SizeBreakdown.Assets += 512 * 1024
+
+	var buf bytes.Buffer
+	if err := NewCompactMarkdownFormatter(baseline).Format(&buf, current); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"45.0 MB (+2.0 MB)",
+		"52.0 MB (+2.5 MB)",
+		"| Frameworks | 19.0 MB | 22.0 MB | +3.0 MB |",
+		"| Assets | 7.5 MB | 7.0 MB | -512.0 KB |",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q\n%s", want, output)
+		}
+	}
+
+	if strings.Contains(output, "| Resources |") {
+		t.Error("unchanged categories should not be listed")
+	}
+	if strings.Index(output, "| Frameworks |") > strings.Index(output, "| Assets |") {
+		t.Error("expected largest change first")
+	}
+}
+
+func TestCompactMarkdownFormatter_Format_LimitsOptimizations(t *testing.T) {
+	report := &types.Report{ArtifactInfo: types.ArtifactInfo{Path: "app.apk"}}
+	for _, title := range []string{"a", "b", "c", "d", "e"} {
+		report.Optimizations = append(report.Optimizations, types.Optimization{Title: title, Impact: 100})
+	}
+
+	var buf bytes.Buffer
+	if err := NewCompactMarkdownFormatter(report).Format(&buf, report); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+
+	if got := strings.Count(buf.String(), "\n- "); got != defaultCompactOptimizations {
+		t.Errorf("listed %d optimizations, want %d", got, defaultCompactOptimizations)
+	}
+	if !strings.Contains(buf.String(), "No size changes compared to the baseline.") {
+		t.Error("expected no-change note for identical reports")
+	}
+}
+
+func TestFormatSizeDelta(t *testing.T) {
+	tests := map[int64]string{
+		0:           "no change",
+		512:         "+512 B",
+		-2048:       "-2.0 KB",
+		3 * 1048576: "+3.0 MB",
+	}
+	for delta, want := range tests {
+		if got := formatSizeDelta(delta); got != want {
+			t.Errorf("formatSizeDelta(%d) = %q, want %q", delta, got, want)
+		}
+	}
+}
+
+func TestCompactMarkdownFormatter_Format_OwnerChanges(t *testing.T) {
+	baseline := createTestReport()
+	baseline.Ownership = []types.OwnerSize{{Owner: "payments", Size: 1024 * 1024}, {Owner: "checkout", Size: 2048}}
+	current := createTestReport()
+	current.Ownership = []types.OwnerSize{{Owner: "payments", Size: 4 * 1024 * 1024}, {Owner: "checkout", Size: 2048}}
+
+	var buf bytes.Buffer
+	if err := NewCompactMarkdownFormatter(baseline).Format(&buf, current); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, "| payments | 1.0 MB | 4.0 MB | +3.0 MB |") {
+		t.Errorf("output missing owner change\n%s", output)
+	}
+	if strings.Contains(output, "| checkout |") {
+		t.Error("unchanged owners should not be listed")
+	}
+	if strings.Contains(output, "Largest owners") {
+		t.Error("largest owners should be omitted with a baseline")
+	}
+
+	// Without sizes by owner in the baseline there is nothing to compare
+	baseline.Ownership = nil
+	buf.Reset()
+	if err := NewCompactMarkdownFormatter(baseline).Format(&buf, current); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "| Owner |") {
+		t.Errorf("owner changes listed without baseline ownership\n%s", buf.String())
+	}
+}
+
+func TestCompactMarkdownFormatter_Format_ManifestChanges(t *testing.T) {
+	baseline := createTestReport()
+	baseline.Android = &types.AndroidDetails{Manifest: &types.ManifestInfo{TargetSDK: 33}}
+	current := createTestReport()
+	current.Android = &types.AndroidDetails{Manifest: &types.ManifestInfo{
+		TargetSDK:   34,
+		Permissions: []string{"android.permission.CAMERA"},
+	}}
+
+	var buf bytes.Buffer
+	if err := NewCompactMarkdownFormatter(baseline).Format(&buf, current); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{"**Manifest changes:**", "- Added permission `android.permission.CAMERA`", "- Target SDK: 33 → 34"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q\n%s", want, output)
+		}
+	}
+
+	buf.Reset()
+	if err := NewCompactMarkdownFormatter(current).Format(&buf, current); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "Manifest changes") {
+		t.Error("manifest changes listed for identical manifests")
+	}
+}
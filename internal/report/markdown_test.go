@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
 )
@@ -148,6 +149,22 @@ func TestMarkdownFormatter_writeHeader(t *testing.T) {
 	}
 }
 
+func TestMarkdownFormatter_writeHeader_Warnings(t *testing.T) {
+	report := &types.Report{
+		ArtifactInfo: types.ArtifactInfo{Path: "TestApp.ipa", Type: "ipa"},
+		Warnings:     []string{"Encrypted binaries (App Store FairPlay): TestApp."},
+	}
+
+	var buf bytes.Buffer
+	if err := NewMarkdownFormatter().writeHeader(&buf, report); err != nil {
+		t.Fatalf("writeHeader() failed: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "> ⚠️ Encrypted binaries (App Store FairPlay): TestApp.\n") {
+		t.Errorf("writeHeader() output missing the warning:\n%s", buf.String())
+	}
+}
+
 func TestMarkdownFormatter_writeSizeBreakdown(t *testing.T) {
 	formatter := NewMarkdownFormatter()
 	report := &types.Report{
@@ -713,3 +730,277 @@ func createTestReport() *types.Report {
 		TotalSavings: 2400 * 1024,
 	}
 }
+
+func TestMarkdownFormatter_Format_SplitEstimates(t *testing.T) {
+	report := createTestReport()
+	report.Android = &types.AndroidDetails{
+		SplitEstimates: &types.SplitEstimate{
+			Devices: []types.DeviceDownloadEstimate{
+				{Device: types.DeviceSpec{Name: "arm64-v8a/xxhdpi/sdk34"}, DownloadSize: 3 * 1024 * 1024, MasterSize: 2 * 1024 * 1024, ABISize: 1024 * 1024},
+			},
+			MinDownloadSize: 3 * 1024 * 1024,
+			MaxDownloadSize: 3 * 1024 * 1024,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewMarkdownFormatter().Format(&buf, report); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, "📱 Estimated Download Size</strong> (3.0 MB – 3.0 MB across 1 devices)") {
+		t.Errorf("output missing split estimate summary\n%s", output)
+	}
+	if !strings.Contains(output, "| arm64-v8a/xxhdpi/sdk34 | 3.0 MB | 2.0 MB | 1.0 MB | 0 B | 0 B |") {
+		t.Errorf("output missing device row\n%s", output)
+	}
+}
+
+func TestMarkdownFormatter_Format_Modules(t *testing.T) {
+	report := createTestReport()
+	report.Android = &types.AndroidDetails{
+		ModuleDetails: []types.ModuleInfo{
+			{Name: "base", Type: "base", Delivery: "install-time", CompressedSize: 4 * 1024 * 1024, DEXSize: 3 * 1024 * 1024},
+			{Name: "camera", Type: "feature", Delivery: "on-demand", CompressedSize: 1024 * 1024},
+			{Name: "ar", Type: "feature", Delivery: "conditional", Conditions: []string{"min-sdk 24"}, CompressedSize: 512 * 1024},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewMarkdownFormatter().Format(&buf, report); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, "🧩 App Bundle Modules</strong> (4.0 MB install-time, 1.5 MB deferred)") {
+		t.Errorf("output missing module summary\n%s", output)
+	}
+	if !strings.Contains(output, "| base | base | install-time | 4.0 MB | 3.0 MB | 0 B | 0 B | 0 B |") {
+		t.Errorf("output missing base module row\n%s", output)
+	}
+	if !strings.Contains(output, "| ar | feature | conditional (min-sdk 24) | 512.0 KB |") {
+		t.Errorf("output missing conditional module row\n%s", output)
+	}
+}
+
+func TestMarkdownFormatter_Format_Signing(t *testing.T) {
+	report := createTestReport()
+	report.ArtifactInfo.Signing = &types.SigningInfo{
+		Profile: &types.ProvisioningProfile{
+			Name: "Example App Store", TeamID: "ABCDE12345", TeamName: "Example Inc",
+			DistributionType: "app-store", ExpiresAt: time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Entitlements: map[string]interface{}{"aps-environment": "production", "application-identifier": "ABCDE12345.com.example"},
+		Extensions:   []types.SigningInfo{{Path: "PlugIns/Widget.appex"}},
+	}
+
+	var buf bytes.Buffer
+	if err := NewMarkdownFormatter().Format(&buf, report); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, "🔏 Signing</strong> (app-store, Example Inc (ABCDE12345))") {
+		t.Errorf("output missing signing summary\n%s", output)
+	}
+	if !strings.Contains(output, "| App | Example App Store | app-store | 2027-03-01 | 0 | `application-identifier`, `aps-environment` |") {
+		t.Errorf("output missing app signing row\n%s", output)
+	}
+	if !strings.Contains(output, "| PlugIns/Widget.appex | - | - | - | - | - |") {
+		t.Errorf("output missing extension signing row\n%s", output)
+	}
+}
+
+func TestMarkdownFormatter_Format_Localization(t *testing.T) {
+	report := createTestReport()
+	report.Localization = &types.LocalizationInfo{
+		BaseLocale: "en",
+		TotalSize:  3072,
+		Locales: []types.LocaleInfo{
+			{Locale: "en", Size: 2048, Keys: 40, IsBase: true},
+			{Locale: "de", Size: 512, Keys: 35, MissingKeys: 5},
+			{Locale: "ja", Size: 512, Keys: 4, ThirdPartyOnly: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewMarkdownFormatter().Format(&buf, report); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, "🌐 Localization</strong> (3 locales, 3.0 KB, 1 from third-party code only)") {
+		t.Errorf("output missing localization summary\n%s", output)
+	}
+	if !strings.Contains(output, "| en (base) | 2.0 KB | 40 | 0 | app |") {
+		t.Errorf("output missing base locale row\n%s", output)
+	}
+	if !strings.Contains(output, "| de | 512 B | 35 | 5 | app |") {
+		t.Errorf("output missing locale row with missing keys\n%s", output)
+	}
+	if !strings.Contains(output, "| ja | 512 B | 4 | 0 | third-party only |") {
+		t.Errorf("output missing third-party locale row\n%s", output)
+	}
+}
+
+func TestMarkdownFormatter_Format_Manifest(t *testing.T) {
+	report := createTestReport()
+	report.Android = &types.AndroidDetails{Manifest: &types.ManifestInfo{
+		MinSDK:      24,
+		TargetSDK:   34,
+		Permissions: []string{"android.permission.CAMERA", "android.permission.INTERNET"},
+		Debuggable:  true,
+		Components: []types.ManifestComponent{
+			{Type: "activity", Name: "com.example.MainActivity", Exported: true, HasIntentFilter: true},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := NewMarkdownFormatter().Format(&buf, report); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"📜 Manifest</strong> (SDK min 24, target 34, 2 permissions)",
+		"**Permissions:** `android.permission.CAMERA`, `android.permission.INTERNET`",
+		"**Flags:** `debuggable`",
+		"| `com.example.MainActivity` | activity | **yes** | yes |",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q\n%s", want, output)
+		}
+	}
+}
+
+func TestMarkdownFormatter_Format_ArchiveLayout(t *testing.T) {
+	report := createTestReport()
+	report.Android = &types.AndroidDetails{
+		Signing: &types.APKSigningInfo{
+			Schemes:   []string{"v1", "v2"},
+			BlockSize: 4096,
+			Blocks:    []types.SigningBlockEntry{{ID: "0x7109871a", Name: "v2 signature", Size: 1500}},
+		},
+		MetaInf:     []types.MetaInfGroup{{Kind: "version", Files: 40, Size: 2048}},
+		ZIPOverhead: &types.ZIPOverhead{LocalHeaders: 3072, CentralDirectory: 5120},
+	}
+
+	var buf bytes.Buffer
+	if err := NewMarkdownFormatter().Format(&buf, report); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"🗄️ Archive Layout</strong> (signature schemes v1, v2)",
+		"| APK Signing Block: v2 signature | - | 1.5 KB |",
+		"| META-INF/ version | 40 | 2.0 KB |",
+		"| ZIP central directory | - | 5.0 KB |",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q\n%s", want, output)
+		}
+	}
+}
+
+func TestMarkdownFormatter_Format_SDKs(t *testing.T) {
+	report := createTestReport()
+	report.ArtifactInfo.UncompressedSize = 10 * 1024 * 1024
+	report.Android = &types.AndroidDetails{
+		SDKs: []types.SDKSize{
+			{Name: "Flutter", Size: 8 * 1024 * 1024, NativeSize: 7 * 1024 * 1024, AssetSize: 1024 * 1024, Files: 120},
+			{Name: "OkHttp", Size: 512 * 1024, DEXSize: 512 * 1024, Classes: 300},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewMarkdownFormatter().Format(&buf, report); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"📚 SDK Sizes</strong> (2 libraries, 8.5 MB)",
+		"| Flutter | 8.0 MB | 80.0% | 0 B | 7.0 MB | 1.0 MB | 0 B | 0 | 120 |",
+		"| OkHttp | 512.0 KB | 5.0% | 512.0 KB | 0 B | 0 B | 0 B | 300 | 0 |",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q\n%s", want, output)
+		}
+	}
+}
+
+func TestMarkdownFormatter_Format_Dependencies(t *testing.T) {
+	report := createTestReport()
+	report.ArtifactInfo.UncompressedSize = 10 * 1024 * 1024
+	report.IOS = &types.IOSDetails{
+		Dependencies: []types.DependencySize{
+			{Name: "Alamofire", Source: "spm", Version: "5.9.1", Size: 1024 * 1024, BundleSize: 24 * 1024, CodeSize: 1000 * 1024},
+			{Name: "AcmeKit", Size: 512 * 1024, FrameworkSize: 512 * 1024},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewMarkdownFormatter().Format(&buf, report); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"🔗 Dependency Sizes</strong> (2 dependencies, 1.5 MB)",
+		"| Alamofire | spm | 5.9.1 | 1.0 MB | 10.0% | 0 B | 24.0 KB | 1000.0 KB |",
+		"| AcmeKit | - | - | 512.0 KB | 5.0% | 512.0 KB | 0 B | 0 B |",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q\n%s", want, output)
+		}
+	}
+}
+
+func TestMarkdownFormatter_Format_Strings(t *testing.T) {
+	report := createTestReport()
+	report.IOS = &types.IOSDetails{
+		Binaries: map[string]*types.BinaryInfo{
+			"MyApp": {Strings: &types.StringLiterals{
+				Count:     1200,
+				TotalSize: 64 * 1024,
+				Sections: []types.StringSection{
+					{Name: "__cstring", Count: 800, Size: 48 * 1024},
+					{Name: "__objc_methname", Count: 400, Size: 16 * 1024},
+				},
+				Categories: []types.StringCategory{
+					{Name: "url", Count: 12, Size: 600},
+					{Name: "build_path", Count: 3, Size: 150},
+				},
+				Largest: []types.StringLiteral{{Value: "SELECT a|b\nFROM t", Section: "__cstring", Size: 2048}},
+			}},
+			"Frameworks/Kit.framework/Kit": {},
+		},
+		DuplicateStrings: []types.DuplicateString{
+			{Value: "https://api.example.com/v1", Size: 27, WastedSize: 27, Binaries: []string{"MyApp", "PlugIns/Widget.appex/Widget"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewMarkdownFormatter().Format(&buf, report); err != nil {
+		t.Fatalf("Format() failed: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"🔤 String Literals</strong> (1 binaries, 64.0 KB)",
+		"| `MyApp` | 1200 | 64.0 KB | 48.0 KB | 0 B | 16.0 KB | 0 B | 12 | 0 | 3 |",
+		"| `SELECT a\\|b\\nFROM t` | __cstring | `MyApp` | 2.0 KB |",
+		"| `https://api.example.com/v1` | 27 B | 27 B | MyApp, PlugIns/Widget.appex/Widget |",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q\n%s", want, output)
+		}
+	}
+}
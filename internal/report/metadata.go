@@ -0,0 +1,41 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// binaryMetadata holds the runtime metadata of one binary of a report
+type binaryMetadata struct {
+	path     string
+	metadata *types.RuntimeMetadata
+}
+
+// runtimeMetadata returns the binaries of an iOS report that have Swift or Objective-C
+// metadata, largest metadata first
+func runtimeMetadata(report *types.Report) []binaryMetadata {
+	if report.IOS == nil {
+		return nil
+	}
+
+	var binaries []binaryMetadata
+	for path, binary := range report.IOS.Binaries {
+		if binary != nil && binary.RuntimeMetadata != nil {
+			binaries = append(binaries, binaryMetadata{path: path, metadata: binary.RuntimeMetadata})
+		}
+	}
+	sort.Slice(binaries, func(i, j int) bool {
+		a, b := metadataSize(binaries[i].metadata), metadataSize(binaries[j].metadata)
+		if a != b {
+			return a > b
+		}
+		return binaries[i].path < binaries[j].path
+	})
+	return binaries
+}
+
+// metadataSize returns the size of all Swift and Objective-C metadata sections
+func metadataSize(metadata *types.RuntimeMetadata) int64 {
+	return metadata.SwiftMetadataSize + metadata.ObjCMetadataSize
+}
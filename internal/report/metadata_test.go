@@ -0,0 +1,70 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func runtimeMetadataTestReport() *types.Report {
+	report := createTestReport()
+	report.IOS = &types.IOSDetails{Binaries: map[string]*types.BinaryInfo{
+		"Frameworks/Small.framework/Small": {RuntimeMetadata: &types.RuntimeMetadata{SwiftTypes: 3, SwiftMetadataSize: 1024}},
+		"Frameworks/Plain.framework/Plain": {},
+		"MyApp": {RuntimeMetadata: &types.RuntimeMetadata{
+			SwiftTypes: 1200, SwiftProtocols: 80, SwiftConformances: 900, ObjCClasses: 40, ObjCSelectors: 5000,
+			SwiftMetadataSize: 2 * 1024 * 1024, ReflectionSize: 512 * 1024, ObjCMetadataSize: 256 * 1024,
+			LargestTypes: []types.RuntimeType{
+				{Name: "Settings", Module: "MyApp", Kind: "struct", Fields: 120, ReflectionSize: 4096},
+			},
+		}},
+	}}
+	return report
+}
+
+func TestRuntimeMetadata(t *testing.T) {
+	binaries := runtimeMetadata(runtimeMetadataTestReport())
+	if len(binaries) != 2 {
+		t.Fatalf("runtimeMetadata() returned %d binaries, want 2", len(binaries))
+	}
+	if binaries[0].path != "MyApp" || binaries[1].path != "Frameworks/Small.framework/Small" {
+		t.Errorf("runtimeMetadata() order = %s, %s, want largest metadata first", binaries[0].path, binaries[1].path)
+	}
+
+	if got := runtimeMetadata(createTestReport()); got != nil {
+		t.Errorf("runtimeMetadata() without iOS details = %v, want nil", got)
+	}
+}
+
+func TestFormatters_RuntimeMetadata(t *testing.T) {
+	report := runtimeMetadataTestReport()
+
+	var text bytes.Buffer
+	if err := NewTextFormatter().Format(&text, report); err != nil {
+		t.Fatalf("TextFormatter.Format() failed: %v", err)
+	}
+	for _, want := range []string{
+		"Swift and Objective-C Metadata:",
+		"MyApp: 1200 Swift types, 80 protocols, 900 conformances, 40 ObjC classes, 5000 selectors",
+		"MyApp.Settings (struct, 120 fields): 4.0 KB",
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text output missing %q", want)
+		}
+	}
+
+	var markdown bytes.Buffer
+	if err := NewMarkdownFormatter().Format(&markdown, report); err != nil {
+		t.Fatalf("MarkdownFormatter.Format() failed: %v", err)
+	}
+	for _, want := range []string{
+		"🧬 Swift and Objective-C Metadata</strong> (2 binaries)",
+		"| `MyApp` | 1200 | 80 | 900 | 40 | 5000 | 2.0 MB | 512.0 KB | 256.0 KB |",
+	} {
+		if !strings.Contains(markdown.String(), want) {
+			t.Errorf("markdown output missing %q", want)
+		}
+	}
+}
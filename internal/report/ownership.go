@@ -0,0 +1,50 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// ownerChange holds the baseline and current size of one owner
+type ownerChange struct {
+	owner    string
+	baseline int64
+	current  int64
+}
+
+// diffOwners pairs the sizes by owner of two reports, largest change first.
+// It returns nil unless both reports have sizes by owner.
+func diffOwners(baseline, current *types.Report) []ownerChange {
+	if len(baseline.Ownership) == 0 || len(current.Ownership) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int)
+	var changes []ownerChange
+	for _, o := range current.Ownership {
+		index[o.Owner] = len(changes)
+		changes = append(changes, ownerChange{owner: o.Owner, current: o.Size})
+	}
+	for _, o := range baseline.Ownership {
+		if i, ok := index[o.Owner]; ok {
+			changes[i].baseline = o.Size
+			continue
+		}
+		changes = append(changes, ownerChange{owner: o.Owner, baseline: o.Size})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return absInt64(changes[i].current-changes[i].baseline) > absInt64(changes[j].current-changes[j].baseline)
+	})
+	return changes
+}
+
+// ownedSize returns the total size attributed to owners, the base of owner percentages
+func ownedSize(owners []types.OwnerSize) int64 {
+	var total int64
+	for _, o := range owners {
+		total += o.Size
+	}
+	return total
+}
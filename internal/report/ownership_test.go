@@ -0,0 +1,105 @@
+package report
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func ownershipTestReport() *types.Report {
+	report := createTestReport()
+	report.Ownership = []types.OwnerSize{
+		{Owner: "payments", Size: 3 * 1024 * 1024, FileCount: 12, Optimizations: 2, PotentialSavings: 512 * 1024, DuplicateWaste: 128 * 1024},
+		{Owner: "(unowned)", Size: 1024 * 1024, FileCount: 40},
+	}
+	return report
+}
+
+func TestDiffOwners(t *testing.T) {
+	baseline := &types.Report{Ownership: []types.OwnerSize{
+		{Owner: "payments", Size: 1000},
+		{Owner: "checkout", Size: 500},
+		{Owner: "legacy", Size: 300},
+	}}
+	current := &types.Report{Ownership: []types.OwnerSize{
+		{Owner: "payments", Size: 1100},
+		{Owner: "checkout", Size: 500},
+		{Owner: "onboarding", Size: 2000},
+	}}
+
+	got := diffOwners(baseline, current)
+	want := []ownerChange{
+		{owner: "onboarding", baseline: 0, current: 2000},
+		{owner: "legacy", baseline: 300, current: 0},
+		{owner: "payments", baseline: 1000, current: 1100},
+		{owner: "checkout", baseline: 500, current: 500},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffOwners() = %+v, want %+v", got, want)
+	}
+
+	if got := diffOwners(&types.Report{}, current); got != nil {
+		t.Errorf("diffOwners() = %+v without baseline ownership, want nil", got)
+	}
+}
+
+func TestFormatters_Ownership(t *testing.T) {
+	formatters := map[string]struct {
+		format func(*bytes.Buffer, *types.Report) error
+		want   []string
+	}{
+		"text": {
+			func(buf *bytes.Buffer, r *types.Report) error { return NewTextFormatter().Format(buf, r) },
+			[]string{
+				"Size by Owner:",
+				"  payments: 3.0 MB (75.0%, 12 files), potential savings 512.0 KB, duplicate waste 128.0 KB",
+				"  (unowned): 1.0 MB (25.0%, 40 files)\n",
+			},
+		},
+		"markdown": {
+			func(buf *bytes.Buffer, r *types.Report) error { return NewMarkdownFormatter().Format(buf, r) },
+			[]string{
+				"👥 Size by Owner</strong> (2 owners)",
+				"| payments | 3.0 MB | 75.0% | 12 | 512.0 KB | 128.0 KB |",
+				"| (unowned) | 1.0 MB | 25.0% | 40 | 0 B | 0 B |",
+			},
+		},
+		"compact markdown": {
+			func(buf *bytes.Buffer, r *types.Report) error { return NewCompactMarkdownFormatter(nil).Format(buf, r) },
+			[]string{"**Largest owners:** payments (3.0 MB), (unowned) (1.0 MB)"},
+		},
+		"html": {
+			func(buf *bytes.Buffer, r *types.Report) error { return NewHTMLFormatter().Format(buf, r) },
+			[]string{
+				"Size by Owner</h2>",
+				`<td class="p-4 align-top text-left">payments</td>`,
+				`<td class="p-4 align-top text-right">75.0%</td>`,
+			},
+		},
+	}
+
+	for name, tt := range formatters {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.format(&buf, ownershipTestReport()); err != nil {
+				t.Fatalf("Format() failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q\n%s", want, buf.String())
+				}
+			}
+
+			buf.Reset()
+			if err := tt.format(&buf, createTestReport()); err != nil {
+				t.Fatalf("Format() failed: %v", err)
+			}
+			if strings.Contains(buf.String(), "payments") || strings.Contains(buf.String(), "owners") {
+				t.Errorf("output has an owner section without sizes by owner\n%s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package report
+
+import (
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/util"
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// sdkSizes returns the sizes of the known third-party libraries of a report, if any
+func sdkSizes(report *types.Report) []types.SDKSize {
+	if report.Android == nil {
+		return nil
+	}
+	return report.Android.SDKs
+}
+
+// dependencySizes returns the sizes of the Swift packages, pods and Carthage dependencies
+// of a report, if any
+func dependencySizes(report *types.Report) []types.DependencySize {
+	if report.IOS == nil {
+		return nil
+	}
+	return report.IOS.Dependencies
+}
+
+// dependencyLabel formats a dependency with its source and version, e.g. "Alamofire (spm 5.8.0)"
+func dependencyLabel(d types.DependencySize) string {
+	details := strings.TrimSpace(d.Source + " " + d.Version)
+	if details == "" {
+		return d.Name
+	}
+	return d.Name + " (" + details + ")"
+}
+
+// dependencyBreakdown formats the sizes of a dependency by kind, e.g. "framework 2.1 MB, code 300 KB"
+func dependencyBreakdown(d types.DependencySize) string {
+	var parts []string
+	for _, kind := range []struct {
+		name string
+		size int64
+	}{{"framework", d.FrameworkSize}, {"bundle", d.BundleSize}, {"code", d.CodeSize}} {
+		if kind.size > 0 {
+			parts = append(parts, kind.name+" "+util.FormatBytes(kind.size))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sdkShareTotal is the size library shares are relative to: the installed size, since
+// classes and files are attributed with their uncompressed sizes
+func sdkShareTotal(report *types.Report) int64 {
+	if report.ArtifactInfo.UncompressedSize > 0 {
+		return report.ArtifactInfo.UncompressedSize
+	}
+	return report.ArtifactInfo.Size
+}
+
+// sdkBreakdown formats the sizes of a library by kind, e.g. "DEX 1.2 MB, native 300 KB"
+func sdkBreakdown(s types.SDKSize) string {
+	var parts []string
+	for _, kind := range []struct {
+		name string
+		size int64
+	}{{"DEX", s.DEXSize}, {"native", s.NativeSize}, {"assets", s.AssetSize}, {"META-INF", s.MetaInfSize}} {
+		if kind.size > 0 {
+			parts = append(parts, kind.name+" "+util.FormatBytes(kind.size))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// valueOrDash returns the value, or "-" for an empty table cell
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,50 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// signedBundle holds the signing of the app or one of its extensions
+type signedBundle struct {
+	name    string
+	signing types.SigningInfo
+}
+
+// signedBundles returns the app followed by its extensions
+func signedBundles(report *types.Report) []signedBundle {
+	signing := report.ArtifactInfo.Signing
+	if signing == nil {
+		return nil
+	}
+
+	bundles := []signedBundle{{name: "App", signing: *signing}}
+	for _, extension := range signing.Extensions {
+		bundles = append(bundles, signedBundle{name: extension.Path, signing: extension})
+	}
+	return bundles
+}
+
+// profileTeam formats the team of a provisioning profile
+func profileTeam(profile *types.ProvisioningProfile) string {
+	switch {
+	case profile.TeamName != "" && profile.TeamID != "":
+		return fmt.Sprintf("%s (%s)", profile.TeamName, profile.TeamID)
+	case profile.TeamID != "":
+		return profile.TeamID
+	default:
+		return profile.TeamName
+	}
+}
+
+// entitlementKeys returns the sorted entitlement names
+func entitlementKeys(entitlements map[string]interface{}) []string {
+	keys := make([]string, 0, len(entitlements))
+	for key := range entitlements {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,111 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// SizeView selects which size of the file tree the text, markdown and HTML formatters show
+type SizeView string
+
+const (
+	SizeViewInstall  SizeView = "install"  // Uncompressed size on the device (default)
+	SizeViewDownload SizeView = "download" // Compressed size in the archive
+)
+
+// ParseSizeView validates a size view name; empty selects the install view
+func ParseSizeView(s string) (SizeView, error) {
+	switch SizeView(s) {
+	case "", SizeViewInstall:
+		return SizeViewInstall, nil
+	case SizeViewDownload:
+		return SizeViewDownload, nil
+	default:
+		return "", fmt.Errorf("invalid size view %q (valid: install, download)", s)
+	}
+}
+
+// resolve returns the view that applies to a file tree. Trees without compressed
+// sizes, such as those of .app directories, fall back to the install view.
+func (v SizeView) resolve(fileTree []*types.FileNode) SizeView {
+	if v != SizeViewDownload {
+		return SizeViewInstall
+	}
+	for _, node := range fileTree {
+		if node.CompressedSize > 0 {
+			return SizeViewDownload
+		}
+	}
+	return SizeViewInstall
+}
+
+// nodeSize returns the size of a node in a resolved view
+func (v SizeView) nodeSize(node *types.FileNode) int64 {
+	if v == SizeViewDownload {
+		return node.CompressedSize
+	}
+	return node.Size
+}
+
+// heading returns a section header suffix that names the download view
+func (v SizeView) heading() string {
+	if v == SizeViewDownload {
+		return " (download size)"
+	}
+	return ""
+}
+
+// total returns the artifact size in a resolved view
+func (v SizeView) total(report *types.Report) int64 {
+	if v == SizeViewDownload || report.ArtifactInfo.UncompressedSize == 0 {
+		return report.ArtifactInfo.Size
+	}
+	return report.ArtifactInfo.UncompressedSize
+}
+
+// largestFiles returns the largest files of a report in a resolved view. Like the
+// install view, the download view leaves out archive structures such as the APK
+// Signing Block and ZIP headers.
+func (v SizeView) largestFiles(report *types.Report) []types.FileNode {
+	if v != SizeViewDownload {
+		return report.LargestFiles
+	}
+
+	limit := len(report.LargestFiles)
+	if limit == 0 {
+		return nil
+	}
+
+	var files []types.FileNode
+	var collect func(nodes []*types.FileNode)
+	collect = func(nodes []*types.FileNode) {
+		for _, node := range nodes {
+			if isArchiveStructure(node) {
+				continue
+			}
+			if node.IsDir {
+				collect(node.Children)
+			} else {
+				files = append(files, *node)
+			}
+		}
+	}
+	collect(report.FileTree)
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].CompressedSize > files[j].CompressedSize
+	})
+	if len(files) > limit {
+		files = files[:limit]
+	}
+	return files
+}
+
+// isArchiveStructure reports whether a node holds bytes of the archive itself rather
+// than of an entry, as marked by the APK layout analysis
+func isArchiveStructure(node *types.FileNode) bool {
+	archive, _ := node.Metadata[types.MetadataArchiveStructure].(bool)
+	return node.IsVirtual && archive
+}
@@ -0,0 +1,91 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func sizeViewTestReport() *types.Report {
+	image := &types.FileNode{Name: "image.png", Path: "res/image.png", Size: 1000, CompressedSize: 990, CompressionMethod: "stored"}
+	dex := &types.FileNode{Name: "classes.dex", Path: "classes.dex", Size: 3000, CompressedSize: 600, CompressionMethod: "deflate"}
+	return &types.Report{
+		ArtifactInfo: types.ArtifactInfo{Size: 1590, UncompressedSize: 4000},
+		FileTree: []*types.FileNode{
+			dex,
+			{Name: "res", Path: "res", IsDir: true, Size: 1000, CompressedSize: 990, Children: []*types.FileNode{image}},
+		},
+		LargestFiles: []types.FileNode{*dex, *image},
+	}
+}
+
+func TestParseSizeView(t *testing.T) {
+	for input, want := range map[string]SizeView{"": SizeViewInstall, "install": SizeViewInstall, "download": SizeViewDownload} {
+		got, err := ParseSizeView(input)
+		if err != nil || got != want {
+			t.Errorf("ParseSizeView(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseSizeView("compressed"); err == nil {
+		t.Error("ParseSizeView(\"compressed\") should fail")
+	}
+}
+
+func TestSizeView_Resolve(t *testing.T) {
+	report := sizeViewTestReport()
+	if got := SizeViewDownload.resolve(report.FileTree); got != SizeViewDownload {
+		t.Errorf("resolve() = %q, want download for archive trees", got)
+	}
+	directory := []*types.FileNode{{Name: "App", Size: 100}}
+	if got := SizeViewDownload.resolve(directory); got != SizeViewInstall {
+		t.Errorf("resolve() = %q, want install for trees without compressed sizes", got)
+	}
+}
+
+func TestSizeView_LargestFiles(t *testing.T) {
+	report := sizeViewTestReport()
+	report.FileTree = append(report.FileTree, &types.FileNode{
+		Name: "APK Signing Block", Path: "APK Signing Block", Size: 5000, CompressedSize: 5000, CompressionMethod: "stored", IsVirtual: true,
+		Metadata: map[string]interface{}{types.MetadataArchiveStructure: true},
+	})
+	files := SizeViewDownload.largestFiles(report)
+	if len(files) != 2 || files[0].Name != "image.png" || files[1].Name != "classes.dex" {
+		t.Errorf("largestFiles() = %v, want image.png before classes.dex by compressed size", files)
+	}
+
+	files = SizeViewInstall.largestFiles(sizeViewTestReport())
+	if files[0].Name != "classes.dex" {
+		t.Errorf("largestFiles() = %v, want the report order in the install view", files)
+	}
+}
+
+func TestTextFormatter_DownloadView(t *testing.T) {
+	var buf strings.Builder
+	formatter := &TextFormatter{SizeView: SizeViewDownload}
+	if err := formatter.Format(&buf, sizeViewTestReport()); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "Largest Files (download size):") {
+		t.Error("output does not name the download view")
+	}
+	if !strings.Contains(output, "classes.dex - 600 B") || !strings.Contains(output, "[2.9 KB installed, deflate]") {
+		t.Errorf("output does not show the compressed and installed sizes:\n%s", output)
+	}
+}
+
+func TestPrepareTreemapData_DownloadView(t *testing.T) {
+	formatter := NewHTMLFormatter()
+	formatter.SizeView = SizeViewDownload
+
+	result := formatter.prepareTreemapData(sizeViewTestReport().FileTree).(map[string]interface{})
+	if result["value"] != int64(1590) {
+		t.Errorf("treemap root value = %v, want the compressed total 1590", result["value"])
+	}
+	dex := result["children"].([]interface{})[0].(map[string]interface{})
+	if dex["value"] != int64(600) {
+		t.Errorf("treemap value = %v, want the compressed size 600", dex["value"])
+	}
+}
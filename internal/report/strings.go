@@ -0,0 +1,85 @@
+package report
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// binaryStrings holds the string literals of one binary of a report
+type binaryStrings struct {
+	path    string
+	strings *types.StringLiterals
+}
+
+// taggedLiteral is a string literal with the binary that contains it
+type taggedLiteral struct {
+	types.StringLiteral
+	binary string
+}
+
+// literalReplacer makes a string literal printable in a single line or table cell
+var literalReplacer = strings.NewReplacer("|", `\|`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "`", "'")
+
+// stringLiterals returns the binaries of an iOS report that have string literals,
+// largest total first
+func stringLiterals(report *types.Report) []binaryStrings {
+	if report.IOS == nil {
+		return nil
+	}
+
+	var binaries []binaryStrings
+	for path, binary := range report.IOS.Binaries {
+		if binary != nil && binary.Strings != nil {
+			binaries = append(binaries, binaryStrings{path: path, strings: binary.Strings})
+		}
+	}
+	sort.Slice(binaries, func(i, j int) bool {
+		if binaries[i].strings.TotalSize != binaries[j].strings.TotalSize {
+			return binaries[i].strings.TotalSize > binaries[j].strings.TotalSize
+		}
+		return binaries[i].path < binaries[j].path
+	})
+	return binaries
+}
+
+// largestLiterals returns the n largest string literals over all binaries
+func largestLiterals(binaries []binaryStrings, n int) []taggedLiteral {
+	var literals []taggedLiteral
+	for _, b := range binaries {
+		for _, l := range b.strings.Largest {
+			literals = append(literals, taggedLiteral{StringLiteral: l, binary: b.path})
+		}
+	}
+	sort.SliceStable(literals, func(i, j int) bool { return literals[i].Size > literals[j].Size })
+	if len(literals) > n {
+		literals = literals[:n]
+	}
+	return literals
+}
+
+// stringSection returns the literals of a binary in one section
+func stringSection(s *types.StringLiterals, name string) types.StringSection {
+	for _, section := range s.Sections {
+		if section.Name == name {
+			return section
+		}
+	}
+	return types.StringSection{Name: name}
+}
+
+// stringCategory returns the literals of a binary in one heuristic category
+func stringCategory(s *types.StringLiterals, name string) types.StringCategory {
+	for _, category := range s.Categories {
+		if category.Name == name {
+			return category
+		}
+	}
+	return types.StringCategory{Name: name}
+}
+
+// printableLiteral escapes line breaks, tabs, pipes and backticks of a literal
+func printableLiteral(s string) string {
+	return literalReplacer.Replace(s)
+}
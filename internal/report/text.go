@@ -11,7 +11,9 @@ import (
 )
 
 // TextFormatter formats reports as human-readable text.
-type TextFormatter struct{}
+type TextFormatter struct {
+	SizeView SizeView // Sizes of the largest files, install by default
+}
 
 // NewTextFormatter creates a new text formatter.
 func NewTextFormatter() *TextFormatter {
@@ -36,6 +38,86 @@ func (f *TextFormatter) Format(w io.Writer, report *types.Report) error {
 	}
 	fmt.Fprintf(w, "\n")
 
+	// Signing (iOS only)
+	if bundles := signedBundles(report); len(bundles) > 0 {
+		fmt.Fprintf(w, "Signing:\n")
+		for _, b := range bundles {
+			if p := b.signing.Profile; p != nil {
+				fmt.Fprintf(w, "  %s: %s (%s), team %s, expires %s",
+					b.name, p.Name, p.DistributionType, profileTeam(p), p.ExpiresAt.UTC().Format("2006-01-02"))
+				if p.ProvisionedDevices > 0 {
+					fmt.Fprintf(w, ", %d devices", p.ProvisionedDevices)
+				}
+				fmt.Fprintf(w, "\n")
+			} else {
+				fmt.Fprintf(w, "  %s: no provisioning profile\n", b.name)
+			}
+			if keys := entitlementKeys(b.signing.Entitlements); len(keys) > 0 {
+				fmt.Fprintf(w, "    Entitlements: %s\n", strings.Join(keys, ", "))
+			}
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Manifest (Android only)
+	if m := manifestInfo(report); m != nil {
+		fmt.Fprintf(w, "Manifest:\n")
+		fmt.Fprintf(w, "  SDK: %s\n", manifestSDKLevels(m))
+		if len(m.Permissions) > 0 {
+			fmt.Fprintf(w, "  Permissions (%d): %s\n", len(m.Permissions), strings.Join(m.Permissions, ", "))
+		}
+		if len(m.CustomPermissions) > 0 {
+			fmt.Fprintf(w, "  Custom permissions: %s\n", strings.Join(m.CustomPermissions, ", "))
+		}
+		if len(m.Components) > 0 {
+			exported := exportedComponents(m)
+			fmt.Fprintf(w, "  Components: %d (%d exported)\n", len(m.Components), len(exported))
+			for _, c := range exported {
+				fmt.Fprintf(w, "    %s %s (exported)\n", c.Type, c.Name)
+			}
+		}
+		if flags := manifestFlags(m); len(flags) > 0 {
+			fmt.Fprintf(w, "  Flags: %s\n", strings.Join(flags, ", "))
+		}
+		if len(m.MetaDataKeys) > 0 {
+			fmt.Fprintf(w, "  Meta-data: %s\n", strings.Join(m.MetaDataKeys, ", "))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Signing block, META-INF/ and ZIP structures (Android only)
+	if hasArchiveLayout(report) {
+		a := report.Android
+		fmt.Fprintf(w, "Archive Layout:\n")
+		if a.Signing != nil {
+			fmt.Fprintf(w, "  Signature schemes: %s\n", signingSchemes(a.Signing))
+			if a.Signing.BlockSize > 0 {
+				fmt.Fprintf(w, "  APK Signing Block: %s", util.FormatBytes(a.Signing.BlockSize))
+				if summary := signingBlockSummary(a.Signing); summary != "" {
+					fmt.Fprintf(w, " (%s)", summary)
+				}
+				fmt.Fprintf(w, "\n")
+			}
+		}
+		for _, g := range a.MetaInf {
+			fmt.Fprintf(w, "  META-INF/ %s: %d files, %s\n", g.Kind, g.Files, util.FormatBytes(g.Size))
+		}
+		if o := a.ZIPOverhead; o != nil {
+			fmt.Fprintf(w, "  ZIP overhead: %s (local headers %s, central directory %s)\n",
+				util.FormatBytes(o.LocalHeaders+o.CentralDirectory), util.FormatBytes(o.LocalHeaders), util.FormatBytes(o.CentralDirectory))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Warnings
+	if len(report.Warnings) > 0 {
+		fmt.Fprintf(w, "Warnings:\n")
+		for _, warning := range report.Warnings {
+			fmt.Fprintf(w, "  ! %s\n", warning)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
 	// Size Breakdown
 	fmt.Fprintf(w, "Size Breakdown:\n")
 	totalSize := report.ArtifactInfo.UncompressedSize
@@ -76,6 +158,128 @@ func (f *TextFormatter) Format(w io.Writer, report *types.Report) error {
 	}
 	fmt.Fprintf(w, "\n")
 
+	// Split APK download estimates (AAB only)
+	if estimate := splitEstimates(report); estimate != nil && len(estimate.Devices) > 0 {
+		fmt.Fprintf(w, "Estimated Download Size (split APKs): %s - %s\n",
+			util.FormatBytes(estimate.MinDownloadSize), util.FormatBytes(estimate.MaxDownloadSize))
+		for _, d := range estimate.Devices {
+			fmt.Fprintf(w, "  %s: %s (master %s, abi %s, density %s, language %s)\n",
+				d.Device.Name,
+				util.FormatBytes(d.DownloadSize),
+				util.FormatBytes(d.MasterSize),
+				util.FormatBytes(d.ABISize),
+				util.FormatBytes(d.DensitySize),
+				util.FormatBytes(d.LanguageSize))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// App bundle modules (AAB only)
+	if modules := moduleDetails(report); len(modules) > 1 {
+		fmt.Fprintf(w, "App Bundle Modules:\n")
+		for _, m := range modules {
+			delivery := m.Delivery
+			if len(m.Conditions) > 0 {
+				delivery += ": " + strings.Join(m.Conditions, ", ")
+			}
+			fmt.Fprintf(w, "  %s (%s, %s): %s\n", m.Name, m.Type, delivery, util.FormatBytes(m.CompressedSize))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Swift and Objective-C runtime metadata (iOS only)
+	if binaries := runtimeMetadata(report); len(binaries) > 0 {
+		fmt.Fprintf(w, "Swift and Objective-C Metadata:\n")
+		for _, b := range binaries {
+			m := b.metadata
+			fmt.Fprintf(w, "  %s: %d Swift types, %d protocols, %d conformances, %d ObjC classes, %d selectors\n",
+				b.path, m.SwiftTypes, m.SwiftProtocols, m.SwiftConformances, m.ObjCClasses, m.ObjCSelectors)
+			fmt.Fprintf(w, "    Swift metadata: %s (reflection %s), ObjC metadata: %s\n",
+				util.FormatBytes(m.SwiftMetadataSize), util.FormatBytes(m.ReflectionSize), util.FormatBytes(m.ObjCMetadataSize))
+			for _, t := range m.LargestTypes {
+				fmt.Fprintf(w, "    %s.%s (%s, %d fields): %s\n", t.Module, t.Name, t.Kind, t.Fields, util.FormatBytes(t.ReflectionSize))
+			}
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// String literals (iOS only)
+	if binaries := stringLiterals(report); len(binaries) > 0 {
+		fmt.Fprintf(w, "String Literals:\n")
+		for _, b := range binaries {
+			str := b.strings
+			fmt.Fprintf(w, "  %s: %d strings, %s", b.path, str.Count, util.FormatBytes(str.TotalSize))
+			for _, c := range str.Categories {
+				fmt.Fprintf(w, ", %d %s (%s)", c.Count, c.Name, util.FormatBytes(c.Size))
+			}
+			fmt.Fprintf(w, "\n")
+			for _, path := range str.BuildPaths {
+				fmt.Fprintf(w, "    build path: %s\n", path)
+			}
+		}
+		for _, d := range report.IOS.DuplicateStrings {
+			fmt.Fprintf(w, "  in %d binaries (%s wasted): %s\n", len(d.Binaries), util.FormatBytes(d.WastedSize), printableLiteral(d.Value))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Localization
+	if l := report.Localization; l != nil && len(l.Locales) > 0 {
+		fmt.Fprintf(w, "Localization (%d locales, base %s): %s\n", len(l.Locales), l.BaseLocale, util.FormatBytes(l.TotalSize))
+		for _, locale := range l.Locales {
+			fmt.Fprintf(w, "  %s: %s", localeLabel(locale), util.FormatBytes(locale.Size))
+			if locale.Keys > 0 {
+				fmt.Fprintf(w, ", %d keys", locale.Keys)
+			}
+			if locale.MissingKeys > 0 {
+				fmt.Fprintf(w, ", %d missing", locale.MissingKeys)
+			}
+			if locale.ThirdPartyOnly {
+				fmt.Fprintf(w, " [third-party only]")
+			}
+			fmt.Fprintf(w, "\n")
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Sizes by owner (when an ownership file was given)
+	if len(report.Ownership) > 0 {
+		fmt.Fprintf(w, "Size by Owner:\n")
+		owned := ownedSize(report.Ownership)
+		for _, o := range report.Ownership {
+			fmt.Fprintf(w, "  %s: %s (%s, %d files)", o.Owner,
+				util.FormatBytes(o.Size), util.FormatPercentage(o.Size, owned), o.FileCount)
+			if o.PotentialSavings > 0 {
+				fmt.Fprintf(w, ", potential savings %s", util.FormatBytes(o.PotentialSavings))
+			}
+			if o.DuplicateWaste > 0 {
+				fmt.Fprintf(w, ", duplicate waste %s", util.FormatBytes(o.DuplicateWaste))
+			}
+			fmt.Fprintf(w, "\n")
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Sizes of known third-party libraries (Android only)
+	if sdks := sdkSizes(report); len(sdks) > 0 {
+		fmt.Fprintf(w, "SDK Sizes:\n")
+		for _, s := range sdks {
+			fmt.Fprintf(w, "  %s: %s (%s) - %s\n", s.Name,
+				util.FormatBytes(s.Size), util.FormatPercentage(s.Size, sdkShareTotal(report)), sdkBreakdown(s))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Sizes of Swift packages, pods and Carthage dependencies (iOS only)
+	if deps := dependencySizes(report); len(deps) > 0 {
+		fmt.Fprintf(w, "Dependency Sizes:\n")
+		for _, d := range deps {
+			fmt.Fprintf(w, "  %s: %s (%s) - %s\n", dependencyLabel(d),
+				util.FormatBytes(d.Size), util.FormatPercentage(d.Size, sdkShareTotal(report)), dependencyBreakdown(d))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
 	// Category Breakdown
 	if len(report.SizeBreakdown.ByCategory) > 0 {
 		fmt.Fprintf(w, "Detailed Breakdown by Category:\n")
@@ -89,14 +293,25 @@ func (f *TextFormatter) Format(w io.Writer, report *types.Report) error {
 	}
 
 	// Largest Files
-	if len(report.LargestFiles) > 0 {
-		fmt.Fprintf(w, "Top %d Largest Files:\n", len(report.LargestFiles))
-		for i, file := range report.LargestFiles {
-			fmt.Fprintf(w, "  %2d. %s - %s (%s)\n",
+	view := f.SizeView.resolve(report.FileTree)
+	if files := view.largestFiles(report); len(files) > 0 {
+		fmt.Fprintf(w, "Top %d Largest Files%s:\n", len(files), view.heading())
+		for i, file := range files {
+			fmt.Fprintf(w, "  %2d. %s - %s (%s)",
 				i+1,
 				file.Path,
-				util.FormatBytes(file.Size),
-				util.FormatPercentage(file.Size, totalSize))
+				util.FormatBytes(view.nodeSize(&file)),
+				util.FormatPercentage(view.nodeSize(&file), view.total(report)))
+			switch {
+			case file.CompressionMethod == "":
+			case file.CompressionMethod == "stored":
+				fmt.Fprintf(w, " [stored]")
+			case view == SizeViewDownload:
+				fmt.Fprintf(w, " [%s installed, %s]", util.FormatBytes(file.Size), file.CompressionMethod)
+			default:
+				fmt.Fprintf(w, " [%s compressed, %s]", util.FormatBytes(file.CompressedSize), file.CompressionMethod)
+			}
+			fmt.Fprintf(w, "\n")
 		}
 		fmt.Fprintf(w, "\n")
 	}
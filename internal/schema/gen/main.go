@@ -0,0 +1,33 @@
+// Command gen writes the JSON Schema of the report, run by go generate in pkg/types.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/schema"
+)
+
+func main() {
+	output := flag.String("o", "report.schema.json", "Output file")
+	dir := flag.String("types", ".", "Directory of the report types, for doc comments")
+	flag.Parse()
+
+	if err := run(*dir, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output string) error {
+	docs, err := schema.ParseDocs(dir)
+	if err != nil {
+		return err
+	}
+	data, err := schema.Generate(docs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, data, 0644)
+}
@@ -0,0 +1,198 @@
+// Package schema generates the JSON Schema of the JSON report from the Go type definitions.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// Docs holds the doc comments of types and their fields, keyed by "Type" and "Type.Field"
+type Docs map[string]string
+
+// ParseDocs reads the doc comments of the types declared in the Go files of a directory
+func ParseDocs(dir string) (Docs, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", dir, err)
+	}
+
+	docs := make(Docs)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					doc := typeSpec.Doc
+					if doc == nil {
+						doc = gen.Doc
+					}
+					docs[typeSpec.Name.Name] = commentText(doc)
+
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+					for _, field := range structType.Fields.List {
+						text := commentText(field.Doc)
+						if text == "" {
+							text = commentText(field.Comment)
+						}
+						for _, name := range field.Names {
+							docs[typeSpec.Name.Name+"."+name.Name] = text
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return docs, nil
+}
+
+// commentText returns a comment group as a single line
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
+
+// Generate returns the JSON Schema of types.Report, with descriptions from docs
+func Generate(docs Docs) ([]byte, error) {
+	g := &generator{docs: docs, defs: make(map[string]interface{})}
+	root := g.structSchema(reflect.TypeOf(types.Report{}))
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "Bundle Inspector Report"
+	root["$defs"] = g.defs
+
+	// Pin the version so consumers can tell which schema a report follows
+	properties := root["properties"].(map[string]interface{})
+	version := properties["schema_version"].(map[string]interface{})
+	version["const"] = types.SchemaVersion
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// generator builds schemas for Go types, collecting named structs as definitions
+type generator struct {
+	docs Docs
+	defs map[string]interface{}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// typeSchema returns the schema of a Go type. Nil pointers, slices and maps are encoded
+// as null unless omitted when empty, in which case allowNull extends the schema with null.
+func (g *generator) typeSchema(t reflect.Type, allowNull bool) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		if allowNull {
+			return nullable(g.typeSchema(t.Elem(), false))
+		}
+		return g.typeSchema(t.Elem(), false)
+	case reflect.Struct:
+		if t == timeType {
+			return map[string]interface{}{"type": "string", "format": "date-time"}
+		}
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = true // Placeholder for recursive types
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		schema := map[string]interface{}{"type": "array", "items": g.typeSchema(t.Elem(), false)}
+		if allowNull {
+			return nullable(schema)
+		}
+		return schema
+	case reflect.Map:
+		schema := map[string]interface{}{"type": "object", "additionalProperties": g.typeSchema(t.Elem(), false)}
+		if allowNull {
+			return nullable(schema)
+		}
+		return schema
+	case reflect.Interface:
+		return map[string]interface{}{}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{}
+}
+
+// structSchema returns the object schema of a struct. Fields without omitempty are required.
+func (g *generator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		omitEmpty := strings.Contains(options, "omitempty")
+
+		property := g.typeSchema(field.Type, !omitEmpty)
+		if doc := g.docs[t.Name()+"."+field.Name]; doc != "" {
+			if _, isRef := property["$ref"]; isRef {
+				// Siblings of $ref are allowed since draft 2019-09
+				property = map[string]interface{}{"$ref": property["$ref"], "description": doc}
+			} else {
+				property["description"] = doc
+			}
+		}
+		properties[name] = property
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	if doc := g.docs[t.Name()]; doc != "" {
+		schema["description"] = doc
+	}
+	return schema
+}
+
+// nullable extends a schema to also allow null
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+		return schema
+	}
+	return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+}
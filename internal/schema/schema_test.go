@@ -0,0 +1,342 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+func generate(t *testing.T) map[string]interface{} {
+	t.Helper()
+	docs, err := ParseDocs("../../pkg/types")
+	if err != nil {
+		t.Fatalf("ParseDocs() failed: %v", err)
+	}
+	data, err := Generate(docs)
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	return decode(t, data)
+}
+
+func decode(t *testing.T, data []byte) map[string]interface{} {
+	t.Helper()
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("invalid schema JSON: %v", err)
+	}
+	return schema
+}
+
+func TestGenerate_MatchesPublishedSchema(t *testing.T) {
+	docs, err := ParseDocs("../../pkg/types")
+	if err != nil {
+		t.Fatalf("ParseDocs() failed: %v", err)
+	}
+	data, err := Generate(docs)
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	if !bytes.Equal(data, types.JSONSchema) {
+		t.Error("pkg/types/report.schema.json is out of date, run: go generate ./pkg/types")
+	}
+}
+
+// TestSchema_CompatibleWithFrozenVersion checks the schema against the snapshot frozen for the
+// current schema version. Reports of the same version must stay readable, so fields may be
+// added but not removed, retyped or made required. Such changes need a new schema version,
+// a migration in report.DecodeJSON and a new snapshot in testdata.
+func TestSchema_CompatibleWithFrozenVersion(t *testing.T) {
+	path := filepath.Join("testdata", fmt.Sprintf("report.v%d.schema.json", types.SchemaVersion))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("no frozen schema for version %d, copy pkg/types/report.schema.json to %s: %v",
+			types.SchemaVersion, path, err)
+	}
+
+	for _, problem := range incompatibilities("", decode(t, data), generate(t)) {
+		t.Error(problem)
+	}
+}
+
+func TestIncompatibilities_DetectsBreakingChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(schema map[string]interface{})
+		want   string
+	}{
+		{"removed field", func(s map[string]interface{}) {
+			delete(definition(s, "ArtifactInfo")["properties"].(map[string]interface{}), "bundle_id")
+		}, "$defs.ArtifactInfo.properties.bundle_id: removed"},
+		{"retyped field", func(s map[string]interface{}) {
+			property(s, "ArtifactInfo", "size")["type"] = "string"
+		}, "$defs.ArtifactInfo.properties.size.type: changed"},
+		{"new required field", func(s map[string]interface{}) {
+			d := definition(s, "ArtifactInfo")
+			d["required"] = append(d["required"].([]interface{}), "bundle_id")
+		}, "$defs.ArtifactInfo.required: bundle_id is newly required"},
+		{"removed definition", func(s map[string]interface{}) {
+			delete(s["$defs"].(map[string]interface{}), "CIInfo")
+		}, "$defs.CIInfo: removed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := generate(t)
+			tt.change(changed)
+			problems := incompatibilities("", generate(t), changed)
+			if len(problems) != 1 || !strings.HasPrefix(problems[0], tt.want) {
+				t.Errorf("incompatibilities() = %v, want %q", problems, tt.want)
+			}
+		})
+	}
+
+	added := generate(t)
+	property(added, "ArtifactInfo", "size")["description"] = "changed"
+	definition(added, "ArtifactInfo")["properties"].(map[string]interface{})["new_field"] = map[string]interface{}{"type": "string"}
+	if problems := incompatibilities("", generate(t), added); len(problems) != 0 {
+		t.Errorf("incompatibilities() = %v for compatible changes", problems)
+	}
+}
+
+func definition(schema map[string]interface{}, name string) map[string]interface{} {
+	return schema["$defs"].(map[string]interface{})[name].(map[string]interface{})
+}
+
+func property(schema map[string]interface{}, def, name string) map[string]interface{} {
+	return definition(schema, def)["properties"].(map[string]interface{})[name].(map[string]interface{})
+}
+
+// incompatibilities lists the changes from a frozen schema that break reports valid against it
+func incompatibilities(path string, frozen, current interface{}) []string {
+	frozenMap, ok := frozen.(map[string]interface{})
+	if !ok {
+		if !reflect.DeepEqual(frozen, current) {
+			return []string{fmt.Sprintf("%s: changed from %v to %v", path, frozen, current)}
+		}
+		return nil
+	}
+	currentMap, ok := current.(map[string]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("%s: changed from %v to %v", path, frozen, current)}
+	}
+
+	var problems []string
+	for _, key := range sortedKeys(frozenMap) {
+		keyPath := strings.TrimPrefix(path+"."+key, ".")
+		switch key {
+		case "description", "title":
+			continue
+		case "required":
+			frozenRequired := make(map[string]bool)
+			for _, name := range frozenMap[key].([]interface{}) {
+				frozenRequired[name.(string)] = true
+			}
+			for _, name := range currentMap[key].([]interface{}) {
+				if !frozenRequired[name.(string)] {
+					problems = append(problems, fmt.Sprintf("%s: %s is newly required", keyPath, name))
+				}
+			}
+		case "properties", "$defs":
+			frozenChildren := frozenMap[key].(map[string]interface{})
+			currentChildren, _ := currentMap[key].(map[string]interface{})
+			for _, name := range sortedKeys(frozenChildren) {
+				childPath := keyPath + "." + name
+				if _, ok := currentChildren[name]; !ok {
+					problems = append(problems, childPath+": removed")
+					continue
+				}
+				problems = append(problems, incompatibilities(childPath, frozenChildren[name], currentChildren[name])...)
+			}
+		default:
+			problems = append(problems, incompatibilities(keyPath, frozenMap[key], currentMap[key])...)
+		}
+	}
+	return problems
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestSchema_DescribesReportOutput(t *testing.T) {
+	var report types.Report
+	populate(reflect.ValueOf(&report).Elem(), 0)
+	report.SchemaVersion = types.SchemaVersion
+
+	data, err := json.Marshal(&report)
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+
+	schema := generate(t)
+	v := &validator{defs: schema["$defs"].(map[string]interface{})}
+	v.validate("report", value, schema)
+	for _, problem := range v.problems {
+		t.Error(problem)
+	}
+
+	// An empty report has nil slices and maps, encoded as null
+	data, _ = json.Marshal(&types.Report{SchemaVersion: types.SchemaVersion})
+	_ = json.Unmarshal(data, &value)
+	v.problems = nil
+	v.validate("empty report", value, schema)
+	for _, problem := range v.problems {
+		t.Error(problem)
+	}
+}
+
+// populate sets every field of a value, so that each one is present in its JSON encoding
+func populate(v reflect.Value, depth int) {
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		v.Set(reflect.ValueOf(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		populate(v.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				populate(v.Field(i), depth)
+			}
+		}
+	case reflect.Slice:
+		// Limits recursive types, e.g. the children of file nodes
+		if depth > 2 {
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		populate(v.Index(0), depth+1)
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		populate(key, depth)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		populate(elem, depth+1)
+		v.SetMapIndex(key, elem)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf("value"))
+	case reflect.String:
+		v.SetString("value")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	}
+}
+
+// validator checks decoded JSON against the subset of JSON Schema used by Generate.
+// Unlike JSON Schema, object keys missing from the properties are reported, so that
+// every field of the report is documented.
+type validator struct {
+	defs     map[string]interface{}
+	problems []string
+}
+
+func (v *validator) validate(path string, value interface{}, schema map[string]interface{}) bool {
+	if ref, ok := schema["$ref"].(string); ok {
+		return v.validate(path, value, v.defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{}))
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		for _, option := range anyOf {
+			nested := &validator{defs: v.defs}
+			if nested.validate(path, value, option.(map[string]interface{})) {
+				return true
+			}
+		}
+		return v.fail("%s: no anyOf option matches %v", path, value)
+	}
+	if want, ok := schema["const"]; ok && !reflect.DeepEqual(value, want) {
+		return v.fail("%s: %v is not %v", path, value, want)
+	}
+
+	if typ, ok := schema["type"]; ok {
+		allowed := fmt.Sprint(typ)
+		if !strings.Contains(allowed, jsonType(value)) &&
+			!(jsonType(value) == "integer" && strings.Contains(allowed, "number")) {
+			return v.fail("%s: %s is not %s", path, jsonType(value), allowed)
+		}
+	}
+
+	ok := true
+	switch value := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		if required, has := schema["required"].([]interface{}); has {
+			for _, name := range required {
+				if _, present := value[name.(string)]; !present {
+					ok = v.fail("%s: missing required %s", path, name)
+				}
+			}
+		}
+		for key, child := range value {
+			switch {
+			case properties[key] != nil:
+				ok = v.validate(path+"."+key, child, properties[key].(map[string]interface{})) && ok
+			case additional != nil:
+				ok = v.validate(path+"."+key, child, additional) && ok
+			default:
+				ok = v.fail("%s.%s: not in the schema", path, key)
+			}
+		}
+	case []interface{}:
+		if items, has := schema["items"].(map[string]interface{}); has {
+			for i, child := range value {
+				ok = v.validate(fmt.Sprintf("%s[%d]", path, i), child, items) && ok
+			}
+		}
+	}
+	return ok
+}
+
+func (v *validator) fail(format string, args ...interface{}) bool {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+	return false
+}
+
+func jsonType(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if value == float64(int64(value)) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
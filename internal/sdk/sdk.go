@@ -0,0 +1,250 @@
+// Package sdk attributes the contents of Android artifacts to third-party libraries
+// with a database of package, file name and path signatures
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// Category is the SizeBreakdown.ByCategory key of the size attributed to libraries
+const Category = "sdk"
+
+// dexRoot is the virtual directory of the DEX classes in the file tree
+const dexRoot = "Dex"
+
+// Signature identifies the classes and files of a library
+type Signature struct {
+	Name         string   `json:"name"`
+	Packages     []string `json:"packages,omitempty"`      // DEX package prefixes, e.g. okhttp3
+	NativeLibs   []string `json:"native_libs,omitempty"`   // Native library file names, e.g. libsentry*.so
+	Assets       []string `json:"assets,omitempty"`        // Files or directories relative to assets/, e.g. flutter_assets
+	VersionFiles []string `json:"version_files,omitempty"` // META-INF/ version file names, e.g. androidx.*.version
+}
+
+// Database is an ordered list of library signatures. Files are attributed to the first
+// library with a matching pattern, classes to the library with the longest package prefix.
+type Database struct {
+	Signatures []Signature `json:"signatures"`
+}
+
+// Default returns the built-in signature database
+func Default() *Database {
+	return &Database{Signatures: append([]Signature(nil), builtinSignatures...)}
+}
+
+// Load reads a signature file and applies it to the built-in database
+func Load(filename string) (*Database, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SDK signature file: %w", err)
+	}
+	defer f.Close()
+
+	overrides, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SDK signature file %s: %w", filename, err)
+	}
+	return Default().Override(overrides), nil
+}
+
+// Parse reads a JSON signature database:
+//
+//	{"signatures": [{"name": "Acme Payments", "packages": ["com.acme.payments"], "native_libs": ["libacmepay*.so"]}]}
+//
+// Native library, asset and version file patterns use path.Match syntax.
+func Parse(r io.Reader) (*Database, error) {
+	var db Database
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&db); err != nil {
+		return nil, err
+	}
+
+	for i, s := range db.Signatures {
+		if s.Name == "" {
+			return nil, fmt.Errorf("signature %d has no name", i+1)
+		}
+		patterns := append(append(append([]string(nil), s.NativeLibs...), s.Assets...), s.VersionFiles...)
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("signature %q: invalid pattern %q: %w", s.Name, pattern, err)
+			}
+		}
+	}
+	return &db, nil
+}
+
+// Override returns a database with the signatures of overrides first, replacing the
+// signatures of the same name. A signature without patterns disables a library.
+func (d *Database) Override(overrides *Database) *Database {
+	replaced := make(map[string]bool, len(overrides.Signatures))
+	result := &Database{}
+	for _, s := range overrides.Signatures {
+		replaced[s.Name] = true
+		result.Signatures = append(result.Signatures, s)
+	}
+	for _, s := range d.Signatures {
+		if !replaced[s.Name] {
+			result.Signatures = append(result.Signatures, s)
+		}
+	}
+	return result
+}
+
+// Attribute sums the DEX classes, native libraries, assets and META-INF/ version files of
+// an Android file tree per library, largest library first. Contents no signature matches
+// are not reported.
+func Attribute(nodes []*types.FileNode, db *Database) []types.SDKSize {
+	packages := make(map[string]string)
+	for i := len(db.Signatures) - 1; i >= 0; i-- {
+		for _, prefix := range db.Signatures[i].Packages {
+			packages[strings.Trim(prefix, ".")] = db.Signatures[i].Name
+		}
+	}
+
+	bySDK := make(map[string]*types.SDKSize)
+	get := func(name string) *types.SDKSize {
+		if bySDK[name] == nil {
+			bySDK[name] = &types.SDKSize{Name: name}
+		}
+		return bySDK[name]
+	}
+
+	// walkDEX passes the library of the longest package prefix matched so far down the tree
+	var walkDEX func(node *types.FileNode, pkg, library string)
+	walkDEX = func(node *types.FileNode, pkg, library string) {
+		for _, child := range node.Children {
+			if child == nil {
+				continue
+			}
+			if !child.IsDir {
+				if library != "" {
+					s := get(library)
+					s.DEXSize += child.Size
+					s.Size += child.Size
+					s.Classes++
+				}
+				continue
+			}
+			childPkg := child.Name
+			if pkg != "" {
+				childPkg = pkg + "." + child.Name
+			}
+			childLibrary := library
+			if name, ok := packages[childPkg]; ok {
+				childLibrary = name
+			}
+			walkDEX(child, childPkg, childLibrary)
+		}
+	}
+
+	var walk func(nodes []*types.FileNode)
+	walk = func(nodes []*types.FileNode) {
+		for _, node := range nodes {
+			if node == nil {
+				continue
+			}
+			if node.IsVirtual && node.Path == dexRoot {
+				walkDEX(node, "", "")
+				continue
+			}
+			if len(node.Children) > 0 {
+				walk(node.Children)
+				continue
+			}
+			if node.IsDir {
+				continue
+			}
+			library, size := matchFile(db, node.Path)
+			if library == "" {
+				continue
+			}
+			s := get(library)
+			*size(s) += node.Size
+			s.Size += node.Size
+			s.Files++
+		}
+	}
+	walk(nodes)
+
+	result := make([]types.SDKSize, 0, len(bySDK))
+	for _, s := range bySDK {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Size != result[j].Size {
+			return result[i].Size > result[j].Size
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// TotalSize sums the sizes of all libraries
+func TotalSize(sdks []types.SDKSize) int64 {
+	var total int64
+	for _, s := range sdks {
+		total += s.Size
+	}
+	return total
+}
+
+// matchFile finds the library of a native library, asset or META-INF/ version file and
+// returns the size field of its kind. Paths of app bundles are prefixed with the module,
+// e.g. base/lib/arm64-v8a/libflutter.so or base/root/META-INF/androidx.core_core.version.
+func matchFile(db *Database, filePath string) (string, func(*types.SDKSize) *int64) {
+	segments := strings.Split(filePath, "/")
+	name := segments[len(segments)-1]
+
+	for i := 0; i < len(segments)-1 && i < 3; i++ {
+		rel := segments[i+1:]
+		switch segments[i] {
+		case "lib":
+			if len(rel) == 2 && strings.HasSuffix(name, ".so") {
+				return firstMatch(db, func(s Signature) []string { return s.NativeLibs }, name),
+					func(s *types.SDKSize) *int64 { return &s.NativeSize }
+			}
+		case "assets":
+			return firstMatch(db, func(s Signature) []string { return s.Assets }, parentPaths(rel)...),
+				func(s *types.SDKSize) *int64 { return &s.AssetSize }
+		case "META-INF":
+			if len(rel) == 1 && strings.HasSuffix(name, ".version") {
+				return firstMatch(db, func(s Signature) []string { return s.VersionFiles }, name),
+					func(s *types.SDKSize) *int64 { return &s.MetaInfSize }
+			}
+		}
+	}
+	return "", nil
+}
+
+// firstMatch returns the first library with a pattern matching one of the names
+func firstMatch(db *Database, patterns func(Signature) []string, names ...string) string {
+	for _, s := range db.Signatures {
+		for _, pattern := range patterns(s) {
+			for _, name := range names {
+				if ok, _ := path.Match(pattern, name); ok {
+					return s.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// parentPaths returns a relative path and its parent directories, e.g.
+// flutter_assets/fonts/a.ttf, flutter_assets/fonts and flutter_assets
+func parentPaths(segments []string) []string {
+	paths := make([]string, 0, len(segments))
+	for end := len(segments); end > 0; end-- {
+		paths = append(paths, strings.Join(segments[:end], "/"))
+	}
+	return paths
+}
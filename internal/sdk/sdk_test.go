@@ -0,0 +1,187 @@
+package sdk
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
+)
+
+// testNode builds a file tree node from its path, with children for directories
+func testNode(path string, size int64, children ...*types.FileNode) *types.FileNode {
+	name := path[strings.LastIndex(path, "/")+1:]
+	node := &types.FileNode{Path: path, Name: name, Size: size, IsDir: len(children) > 0, Children: children}
+	for _, child := range children {
+		node.Size += child.Size
+	}
+	return node
+}
+
+func testDEXTree() *types.FileNode {
+	dex := testNode("Dex", 0,
+		testNode("Dex/kotlin", 0,
+			testNode("Dex/kotlin/Unit.class", 100),
+			testNode("Dex/kotlin/collections", 0, testNode("Dex/kotlin/collections/ArraysKt.class", 400)),
+		),
+		testNode("Dex/kotlinx", 0,
+			testNode("Dex/kotlinx/coroutines", 0, testNode("Dex/kotlinx/coroutines/Job.class", 300)),
+		),
+		testNode("Dex/okhttp3", 0, testNode("Dex/okhttp3/OkHttpClient.class", 1000)),
+		testNode("Dex/com", 0,
+			testNode("Dex/com/facebook", 0,
+				testNode("Dex/com/facebook/login", 0, testNode("Dex/com/facebook/login/LoginManager.class", 200)),
+				testNode("Dex/com/facebook/react", 0, testNode("Dex/com/facebook/react/ReactActivity.class", 600)),
+			),
+			testNode("Dex/com/example", 0, testNode("Dex/com/example/MainActivity.class", 5000)),
+		),
+		testNode("Dex/_Unmapped", 700),
+	)
+	dex.IsVirtual = true
+	return dex
+}
+
+func TestAttribute(t *testing.T) {
+	tree := []*types.FileNode{
+		testDEXTree(),
+		testNode("lib", 0,
+			testNode("lib/arm64-v8a", 0,
+				testNode("lib/arm64-v8a/libflutter.so", 8000),
+				testNode("lib/arm64-v8a/libsentry.so", 900),
+				testNode("lib/arm64-v8a/libapp.so", 3000),
+			),
+		),
+		testNode("assets", 0,
+			testNode("assets/flutter_assets", 0,
+				testNode("assets/flutter_assets/AssetManifest.json", 50),
+				testNode("assets/flutter_assets/fonts", 0, testNode("assets/flutter_assets/fonts/Roboto.ttf", 150)),
+			),
+			testNode("assets/config.json", 20),
+		),
+		testNode("META-INF", 0,
+			testNode("META-INF/androidx.core_core.version", 6),
+			testNode("META-INF/kotlinx_coroutines_core.version", 5),
+			testNode("META-INF/MANIFEST.MF", 500),
+		),
+	}
+
+	got := Attribute(tree, Default())
+	want := []types.SDKSize{
+		{Name: "Flutter", Size: 8200, NativeSize: 8000, AssetSize: 200, Files: 3},
+		{Name: "OkHttp", Size: 1000, DEXSize: 1000, Classes: 1},
+		{Name: "Sentry", Size: 900, NativeSize: 900, Files: 1},
+		{Name: "React Native", Size: 600, DEXSize: 600, Classes: 1},
+		{Name: "Kotlin stdlib", Size: 500, DEXSize: 500, Classes: 2},
+		{Name: "Kotlin Coroutines", Size: 305, DEXSize: 300, MetaInfSize: 5, Classes: 1, Files: 1},
+		{Name: "Facebook SDK", Size: 200, DEXSize: 200, Classes: 1},
+		{Name: "AndroidX", Size: 6, MetaInfSize: 6, Files: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Attribute() =\n%+v\nwant\n%+v", got, want)
+	}
+	if total := TotalSize(got); total != 11711 {
+		t.Errorf("TotalSize() = %d, want 11711", total)
+	}
+}
+
+func TestAttribute_AppBundlePaths(t *testing.T) {
+	tree := []*types.FileNode{
+		testNode("base", 0,
+			testNode("base/lib", 0, testNode("base/lib/arm64-v8a", 0, testNode("base/lib/arm64-v8a/librealm-jni.so", 700))),
+			testNode("base/assets", 0, testNode("base/assets/index.android.bundle", 400)),
+			testNode("base/root", 0, testNode("base/root/META-INF", 0, testNode("base/root/META-INF/androidx.activity_activity.version", 6))),
+		),
+	}
+
+	got := Attribute(tree, Default())
+	want := []types.SDKSize{
+		{Name: "Realm", Size: 700, NativeSize: 700, Files: 1},
+		{Name: "React Native", Size: 400, AssetSize: 400, Files: 1},
+		{Name: "AndroidX", Size: 6, MetaInfSize: 6, Files: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Attribute() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"valid", `{"signatures": [{"name": "Acme", "packages": ["com.acme"], "native_libs": ["libacme*.so"]}]}`, ""},
+		{"missing name", `{"signatures": [{"packages": ["com.acme"]}]}`, "has no name"},
+		{"invalid pattern", `{"signatures": [{"name": "Acme", "assets": ["[acme"]}]}`, "invalid pattern"},
+		{"unknown field", `{"signatures": [{"name": "Acme", "prefixes": ["com.acme"]}]}`, "unknown field"},
+		{"invalid JSON", `{"signatures": [`, "EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Parse() failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_OverridesBuiltins(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "sdks.json")
+	content := `{"signatures": [
+		{"name": "Acme Networking", "packages": ["okhttp3.internal"]},
+		{"name": "Sentry", "packages": ["io.sentry"]},
+		{"name": "Kotlin stdlib"}
+	]}`
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := Load(filename)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if db.Signatures[0].Name != "Acme Networking" || len(db.Signatures) != len(builtinSignatures)+1 {
+		t.Fatalf("Load() = %d signatures starting with %q, want the overrides first", len(db.Signatures), db.Signatures[0].Name)
+	}
+
+	tree := []*types.FileNode{
+		testDEXTree(),
+		testNode("lib", 0, testNode("lib/x86_64", 0, testNode("lib/x86_64/libsentry.so", 900))),
+	}
+	okhttp := tree[0].Children[2]
+	okhttp.Children = append(okhttp.Children,
+		testNode("Dex/okhttp3/internal", 0, testNode("Dex/okhttp3/internal/Util.class", 50)))
+
+	byName := make(map[string]types.SDKSize)
+	for _, s := range Attribute(tree, db) {
+		byName[s.Name] = s
+	}
+	if _, ok := byName["Kotlin stdlib"]; ok {
+		t.Error("Kotlin stdlib is disabled by the override but was attributed")
+	}
+	if _, ok := byName["Sentry"]; ok {
+		t.Error("Sentry native libraries are no longer in its signature but were attributed")
+	}
+	if got := byName["Acme Networking"].DEXSize; got != 50 {
+		t.Errorf("Acme Networking DEX size = %d, want 50", got)
+	}
+	if got := byName["OkHttp"].DEXSize; got != 1000 {
+		t.Errorf("OkHttp DEX size = %d, want 1000", got)
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Load() of a missing file succeeded")
+	}
+}
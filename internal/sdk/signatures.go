@@ -0,0 +1,51 @@
+package sdk
+
+// builtinSignatures identify widely used Android libraries. Package prefixes are matched
+// on package boundaries, so "kotlin" does not match kotlinx.coroutines, and the longest
+// matching prefix wins, so com.facebook.react is React Native rather than the Facebook SDK.
+var builtinSignatures = []Signature{
+	{Name: "AndroidX", Packages: []string{"androidx"}, VersionFiles: []string{"androidx.*.version"}},
+	{Name: "Android Support Library", Packages: []string{"android.support", "android.arch"}, VersionFiles: []string{"android.support.*.version", "android.arch.*.version"}},
+	{Name: "Material Components", Packages: []string{"com.google.android.material"}, VersionFiles: []string{"com.google.android.material_*.version"}},
+	{Name: "Google Play Services", Packages: []string{"com.google.android.gms"}},
+	{Name: "Google Play Core", Packages: []string{"com.google.android.play"}},
+	{Name: "Firebase", Packages: []string{"com.google.firebase"}, NativeLibs: []string{"libcrashlytics*.so"}, Assets: []string{"crashlytics-build.properties"}},
+	{Name: "ML Kit", Packages: []string{"com.google.mlkit"}, NativeLibs: []string{"libbarhopper*.so", "libmlkit*.so"}, Assets: []string{"mlkit_*"}},
+	{Name: "ExoPlayer", Packages: []string{"com.google.android.exoplayer2"}},
+	{Name: "Guava", Packages: []string{"com.google.common", "com.google.thirdparty"}},
+	{Name: "Gson", Packages: []string{"com.google.gson"}},
+	{Name: "Protocol Buffers", Packages: []string{"com.google.protobuf"}},
+	{Name: "Kotlin stdlib", Packages: []string{"kotlin"}},
+	{Name: "Kotlin Coroutines", Packages: []string{"kotlinx.coroutines"}, VersionFiles: []string{"kotlinx_coroutines_*.version"}},
+	{Name: "Kotlin Serialization", Packages: []string{"kotlinx.serialization"}},
+	{Name: "OkHttp", Packages: []string{"okhttp3"}},
+	{Name: "Okio", Packages: []string{"okio"}},
+	{Name: "Retrofit", Packages: []string{"retrofit2"}},
+	{Name: "Moshi", Packages: []string{"com.squareup.moshi"}},
+	{Name: "Picasso", Packages: []string{"com.squareup.picasso"}},
+	{Name: "Glide", Packages: []string{"com.bumptech.glide"}},
+	{Name: "Coil", Packages: []string{"coil", "coil3"}},
+	{Name: "Lottie", Packages: []string{"com.airbnb.lottie"}},
+	{Name: "Dagger", Packages: []string{"dagger", "javax.inject"}},
+	{Name: "RxJava", Packages: []string{"io.reactivex", "rx"}},
+	{Name: "Timber", Packages: []string{"timber.log"}},
+	{Name: "Jackson", Packages: []string{"com.fasterxml.jackson"}},
+	{Name: "Bouncy Castle", Packages: []string{"org.bouncycastle"}},
+	{Name: "Apache Commons", Packages: []string{"org.apache.commons"}},
+	{Name: "Facebook SDK", Packages: []string{"com.facebook"}},
+	{Name: "Fresco", Packages: []string{"com.facebook.imagepipeline", "com.facebook.drawee", "com.facebook.imageformat"}, NativeLibs: []string{"libimagepipeline.so", "libnative-imagetranscoder.so", "libnative-filters.so"}},
+	{Name: "React Native", Packages: []string{"com.facebook.react", "com.facebook.hermes", "com.facebook.jni", "com.facebook.yoga", "com.facebook.soloader"}, NativeLibs: []string{"libreactnative*.so", "libhermes*.so", "libjsc*.so", "libfbjni.so", "libyoga.so", "libfolly*.so", "libglog*.so", "libjsi*.so", "libturbomodule*.so", "libfabric*.so", "libreact_*.so"}, Assets: []string{"index.android.bundle"}},
+	{Name: "Flutter", Packages: []string{"io.flutter"}, NativeLibs: []string{"libflutter.so"}, Assets: []string{"flutter_assets"}},
+	{Name: "Unity", Packages: []string{"com.unity3d"}, NativeLibs: []string{"libunity.so", "libil2cpp.so", "libmain.so"}, Assets: []string{"bin/Data"}},
+	{Name: "Sentry", Packages: []string{"io.sentry"}, NativeLibs: []string{"libsentry*.so"}},
+	{Name: "Realm", Packages: []string{"io.realm"}, NativeLibs: []string{"librealm*.so"}},
+	{Name: "SQLCipher", Packages: []string{"net.sqlcipher", "net.zetetic"}, NativeLibs: []string{"libsqlcipher.so"}},
+	{Name: "Mapbox", Packages: []string{"com.mapbox"}, NativeLibs: []string{"libmapbox*.so"}},
+	{Name: "Stripe", Packages: []string{"com.stripe"}},
+	{Name: "AppsFlyer", Packages: []string{"com.appsflyer"}},
+	{Name: "Adjust", Packages: []string{"com.adjust.sdk"}},
+	{Name: "Braze", Packages: []string{"com.braze", "com.appboy"}},
+	{Name: "Amplitude", Packages: []string{"com.amplitude"}},
+	{Name: "Segment", Packages: []string{"com.segment.analytics"}},
+	{Name: "Datadog", Packages: []string{"com.datadog"}, NativeLibs: []string{"libdatadog*.so"}},
+}
@@ -2,9 +2,11 @@ package util
 
 import (
 	"archive/zip"
+	"fmt"
 	"sort"
 	"strings"
 
+	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/internal/zipmethod"
 	"github.com/bitrise-io/bitrise-plugins-bundle-inspector/pkg/types"
 )
 
@@ -72,9 +74,79 @@ func BuildZipFileTree(zipReader *zip.Reader) ([]*types.FileNode, int64) {
 		}
 	}
 
+	ApplyCompressedSizes(rootNodes, zipReader.File, "")
+
 	return rootNodes, totalSize
 }
 
+// ApplyCompressedSizes sets the compressed size and compression method of file tree
+// nodes from the archive entries at prefix+path, and sums them up for directories.
+// Virtual nodes, such as Mach-O segments or DEX classes, get an estimate from the
+// compression ratio of their archive entry.
+func ApplyCompressedSizes(nodes []*types.FileNode, files []*zip.File, prefix string) {
+	entries := make(map[string]*zip.File, len(files))
+	for _, f := range files {
+		if strings.HasPrefix(f.Name, prefix) && !f.FileInfo().IsDir() {
+			entries[strings.TrimPrefix(f.Name, prefix)] = f
+		}
+	}
+
+	for _, node := range nodes {
+		applyCompressedSize(node, entries)
+	}
+}
+
+// applyCompressedSize sets the compressed size of a node and returns it
+func applyCompressedSize(node *types.FileNode, entries map[string]*zip.File) int64 {
+	if entry := entries[node.Path]; entry != nil {
+		method := CompressionMethodName(entry.Method)
+		for _, child := range node.Children {
+			EstimateCompressedSizes(child, int64(entry.CompressedSize64), int64(entry.UncompressedSize64), method)
+		}
+		node.CompressedSize = int64(entry.CompressedSize64)
+		node.CompressionMethod = method
+		return node.CompressedSize
+	}
+
+	if entry := entries[node.SourceFile]; node.IsVirtual && entry != nil {
+		EstimateCompressedSizes(node, int64(entry.CompressedSize64), int64(entry.UncompressedSize64), CompressionMethodName(entry.Method))
+		return node.CompressedSize
+	}
+
+	node.CompressedSize = 0
+	for _, child := range node.Children {
+		node.CompressedSize += applyCompressedSize(child, entries)
+	}
+	return node.CompressedSize
+}
+
+// EstimateCompressedSizes sets the compressed size of a virtual node and its children
+// from the compression ratio of the archive data they were parsed from
+func EstimateCompressedSizes(node *types.FileNode, compressedSize, uncompressedSize int64, method string) {
+	node.CompressedSize = 0
+	if uncompressedSize > 0 {
+		node.CompressedSize = int64(float64(node.Size) * float64(compressedSize) / float64(uncompressedSize))
+	}
+	node.CompressionMethod = method
+	for _, child := range node.Children {
+		EstimateCompressedSizes(child, compressedSize, uncompressedSize, method)
+	}
+}
+
+// CompressionMethodName returns the name of a ZIP compression method
+func CompressionMethodName(method uint16) string {
+	switch method {
+	case zip.Store:
+		return "stored"
+	case zip.Deflate:
+		return "deflate"
+	case zipmethod.LZFSE:
+		return "lzfse"
+	default:
+		return fmt.Sprintf("method-%d", method)
+	}
+}
+
 // CalculateDirectorySize recursively calculates directory size
 func CalculateDirectorySize(node *types.FileNode) int64 {
 	if !node.IsDir {
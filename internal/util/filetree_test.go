@@ -240,3 +240,66 @@ func TestFindLargestFiles(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyCompressedSizes(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	entries := []struct {
+		name   string
+		method uint16
+	}{
+		{"Payload/App.app/App", zip.Store},
+		{"Payload/App.app/Assets.car", zip.Deflate},
+	}
+	for _, e := range entries {
+		fw, err := w.CreateHeader(&zip.FileHeader{Name: e.name, Method: e.method})
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		if _, err := fw.Write(make([]byte, 4096)); err != nil {
+			t.Fatalf("Failed to write data: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip writer: %v", err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Failed to open zip: %v", err)
+	}
+
+	// The asset catalog is expanded into virtual children, like the .car parser does
+	assets := &types.FileNode{Path: "Assets.car", Size: 4096, Children: []*types.FileNode{
+		{Path: "Assets.car/Icon", Size: 3072, IsVirtual: true},
+		{Path: "Assets.car/Logo", Size: 1024, IsVirtual: true},
+	}}
+	binary := &types.FileNode{Path: "App", Size: 4096}
+	root := &types.FileNode{Path: "Frameworks", IsDir: true, Children: []*types.FileNode{
+		{Path: "Frameworks/Missing.dylib", Size: 10},
+	}}
+
+	ApplyCompressedSizes([]*types.FileNode{binary, assets, root}, reader.File, "Payload/App.app/")
+
+	if binary.CompressedSize != 4096 || binary.CompressionMethod != "stored" {
+		t.Errorf("binary = %d %s, want 4096 stored", binary.CompressedSize, binary.CompressionMethod)
+	}
+	if assets.CompressionMethod != "deflate" || assets.CompressedSize == 0 || assets.CompressedSize >= 4096 {
+		t.Errorf("assets = %d %s, want a deflated size below 4096", assets.CompressedSize, assets.CompressionMethod)
+	}
+	icon, logo := assets.Children[0], assets.Children[1]
+	if icon.CompressionMethod != "deflate" || icon.CompressedSize != 3*logo.CompressedSize {
+		t.Errorf("virtual children = %d, %d, want sizes scaled by the archive entry ratio", icon.CompressedSize, logo.CompressedSize)
+	}
+	if root.CompressedSize != 0 {
+		t.Errorf("directory without archive entries = %d, want 0", root.CompressedSize)
+	}
+}
+
+func TestCompressionMethodName(t *testing.T) {
+	tests := map[uint16]string{zip.Store: "stored", zip.Deflate: "deflate", 99: "lzfse", 12: "method-12"}
+	for method, want := range tests {
+		if got := CompressionMethodName(method); got != want {
+			t.Errorf("CompressionMethodName(%d) = %q, want %q", method, got, want)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+// Package zipmethod defines the ZIP compression method codes that archive/zip does not.
+package zipmethod
+
+// LZFSE is the compression method code of LZFSE-compressed entries in IPA files
+const LZFSE = 99
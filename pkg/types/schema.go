@@ -0,0 +1,10 @@
+package types
+
+import _ "embed"
+
+//go:generate go run ../../internal/schema/gen -o report.schema.json
+
+// JSONSchema is the JSON Schema of Report, generated from the type definitions in this package
+//
+//go:embed report.schema.json
+var JSONSchema []byte
@@ -25,6 +25,34 @@ type ArtifactInfo struct {
 	AppName          string       `json:"app_name,omitempty"`   // App display name
 	BundleID         string       `json:"bundle_id,omitempty"`  // Bundle/package identifier
 	Version          string       `json:"version,omitempty"`    // App version
+	Signing          *SigningInfo `json:"signing,omitempty"`   // Provisioning and entitlements of iOS apps
+}
+
+// SigningInfo describes how an iOS app bundle or app extension was signed.
+type SigningInfo struct {
+	Path         string                 `json:"path,omitempty"`         // Bundle path relative to the app, empty for the app itself
+	Profile      *ProvisioningProfile   `json:"profile,omitempty"`      // From embedded.mobileprovision
+	Entitlements map[string]interface{} `json:"entitlements,omitempty"` // From the code signature of the bundle executable
+	Extensions   []SigningInfo          `json:"extensions,omitempty"`   // App extensions (.appex)
+}
+
+// ProvisioningProfile contains the details of an embedded provisioning profile.
+type ProvisioningProfile struct {
+	Name               string               `json:"name"`
+	UUID               string               `json:"uuid,omitempty"`
+	TeamID             string               `json:"team_id,omitempty"`
+	TeamName           string               `json:"team_name,omitempty"`
+	DistributionType   string               `json:"distribution_type"` // development, ad-hoc, app-store or enterprise
+	CreatedAt          time.Time            `json:"created_at"`
+	ExpiresAt          time.Time            `json:"expires_at"`
+	ProvisionedDevices int                  `json:"provisioned_devices,omitempty"` // Devices allowed to install development and ad-hoc builds
+	Certificates       []SigningCertificate `json:"certificates,omitempty"`        // Developer certificates allowed to sign
+}
+
+// SigningCertificate is a developer certificate of a provisioning profile.
+type SigningCertificate struct {
+	Subject   string    `json:"subject"` // Common name, e.g. "Apple Distribution: Example Inc (ABCDE12345)"
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 // SizeBreakdown provides a categorized breakdown of artifact size.
@@ -52,8 +80,16 @@ type FileNode struct {
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`    // Additional metadata (e.g., for DEX classes)
 	Hash        string                 `json:"hash,omitempty"`         // SHA-256 hash (set for files that appear in duplicate sets)
 	IsDuplicate bool                   `json:"is_duplicate,omitempty"` // True if file appears in a duplicate set
+
+	// Archive-based artifacts only (IPA, APK, AAB)
+	CompressedSize    int64  `json:"compressed_size,omitempty"`    // Size in the archive, estimated for virtual nodes and summed for directories
+	CompressionMethod string `json:"compression_method,omitempty"` // Archive entry method: stored, deflate, lzfse, ...
 }
 
+// MetadataArchiveStructure is the FileNode metadata key set to true on virtual nodes of
+// archive bytes that belong to no entry, such as the APK Signing Block and ZIP headers
+const MetadataArchiveStructure = "archive_structure"
+
 // DuplicateSet represents a group of duplicate files.
 type DuplicateSet struct {
 	Hash       string   `json:"hash"`
@@ -74,16 +110,176 @@ type Optimization struct {
 	Action      string   `json:"action"`       // Suggested action
 }
 
+// SchemaVersion is the version of the JSON report format written by this release.
+// Reports written before versioning was introduced have no version and are read as version 1.
+//
+// Version history:
+//   - 1: platform details in the free-form metadata map
+//   - 2: platform details in the typed ios, android and ci sections
+const SchemaVersion = 2
+
 // Report contains the complete analysis results.
 type Report struct {
-	ArtifactInfo   ArtifactInfo           `json:"artifact_info"`
-	SizeBreakdown  SizeBreakdown          `json:"size_breakdown"`
-	FileTree       []*FileNode            `json:"file_tree"`
-	Duplicates     []DuplicateSet         `json:"duplicates,omitempty"`
-	Optimizations  []Optimization         `json:"optimizations,omitempty"`
-	Metadata       map[string]interface{} `json:"metadata,omitempty"`
-	LargestFiles   []FileNode             `json:"largest_files,omitempty"`
-	TotalSavings   int64                  `json:"total_savings,omitempty"`
+	SchemaVersion int                    `json:"schema_version"`
+	ArtifactInfo  ArtifactInfo           `json:"artifact_info"`
+	SizeBreakdown SizeBreakdown          `json:"size_breakdown"`
+	FileTree      []*FileNode            `json:"file_tree"`
+	Duplicates    []DuplicateSet         `json:"duplicates,omitempty"`
+	Optimizations []Optimization         `json:"optimizations,omitempty"`
+	IOS           *IOSDetails            `json:"ios,omitempty"`      // Set for iOS artifacts
+	Android       *AndroidDetails        `json:"android,omitempty"`  // Set for Android artifacts
+	Localization  *LocalizationInfo      `json:"localization,omitempty"` // Set when the artifact contains localized resources
+	CI            *CIInfo                `json:"ci,omitempty"`       // Set when built on CI
+	Metadata      map[string]interface{} `json:"metadata,omitempty"` // Additional free-form values, e.g. from the manifest
+	LargestFiles  []FileNode             `json:"largest_files,omitempty"`
+	TotalSavings  int64                  `json:"total_savings,omitempty"`
+	Ownership     []OwnerSize            `json:"ownership,omitempty"` // Sizes by owner, set when an ownership file is given
+	Warnings      []string               `json:"warnings,omitempty"`  // Caveats about the accuracy of the analysis
+}
+
+// OwnerSize contains the sizes attributed to one owner of an ownership file.
+type OwnerSize struct {
+	Owner            string `json:"owner"`                       // Team name, or "(unowned)" for files no rule matches
+	Size             int64  `json:"size"`                        // Size of the owned files
+	FileCount        int    `json:"file_count"`                  // Owned files, including virtual entries like DEX classes
+	Optimizations    int    `json:"optimizations,omitempty"`     // Optimizations that involve owned files
+	PotentialSavings int64  `json:"potential_savings,omitempty"` // Share of the optimization impact
+	DuplicateWaste   int64  `json:"duplicate_waste,omitempty"`   // Share of the space wasted by duplicate files
+}
+
+// LocalizationInfo inventories the locales an artifact ships.
+type LocalizationInfo struct {
+	BaseLocale string       `json:"base_locale"` // Development region on iOS, "default" for unqualified Android resources
+	TotalSize  int64        `json:"total_size"`  // Size of all localized content, base locale included
+	Locales    []LocaleInfo `json:"locales"`     // Sorted by size, largest first
+}
+
+// LocaleInfo contains the localized content of one locale.
+type LocaleInfo struct {
+	Locale         string   `json:"locale"`                     // BCP 47 tag, e.g. "pt-BR" or "zh-Hans"
+	Size           int64    `json:"size"`                       // Uncompressed size of the locale's files and resource values
+	Keys           int      `json:"keys,omitempty"`             // Localized string keys
+	MissingKeys    int      `json:"missing_keys,omitempty"`     // Keys of the base locale this locale does not translate
+	IsBase         bool     `json:"is_base,omitempty"`          // Whether this is the base locale
+	ThirdPartyOnly bool     `json:"third_party_only,omitempty"` // Only frameworks and libraries ship this locale, not the app itself
+	Sources        []string `json:"sources,omitempty"`          // .lproj directories or resource files that contain the locale
+}
+
+// IOSDetails contains iOS specific analysis results.
+type IOSDetails struct {
+	AppBundle       string                 `json:"app_bundle,omitempty"`     // Name of the .app bundle
+	BuildVersion    string                 `json:"build_version,omitempty"`  // CFBundleVersion
+	MinOSVersion    string                 `json:"min_os_version,omitempty"` // MinimumOSVersion
+	Binaries        map[string]*BinaryInfo `json:"binaries,omitempty"`       // Mach-O binaries by path
+	Frameworks      []*FrameworkInfo       `json:"frameworks,omitempty"`
+	DependencyGraph map[string][]string    `json:"dependency_graph,omitempty"` // Binary path -> linked libraries
+	AssetCatalogs   []*AssetCatalogInfo    `json:"asset_catalogs,omitempty"`
+	Dependencies    []DependencySize       `json:"dependencies,omitempty"` // Sizes of Swift packages, pods and Carthage dependencies
+
+	DuplicateStrings []DuplicateString `json:"duplicate_strings,omitempty"` // C strings in several binaries, most wasted bytes first
+}
+
+// DuplicateString is a C string literal compiled into more than one binary.
+type DuplicateString struct {
+	Value      string   `json:"value"`       // Truncated to 100 characters
+	Size       int64    `json:"size"`        // Bytes of one copy, including the NUL terminator
+	WastedSize int64    `json:"wasted_size"` // Bytes of all copies but one
+	Binaries   []string `json:"binaries"`
+}
+
+// DependencySize contains the sizes attributed to one Swift package, pod or Carthage dependency.
+type DependencySize struct {
+	Name          string   `json:"name"`
+	Source        string   `json:"source,omitempty"`         // spm, cocoapods or carthage, when a lockfile lists the dependency
+	Version       string   `json:"version,omitempty"`        // From the lockfile, or the framework's Info.plist
+	Size          int64    `json:"size"`                     // Total size of the frameworks, resource bundles and code
+	FrameworkSize int64    `json:"framework_size,omitempty"` // Embedded .framework bundles
+	BundleSize    int64    `json:"bundle_size,omitempty"`    // Resource bundles, e.g. <Package>_<Target>.bundle
+	CodeSize      int64    `json:"code_size,omitempty"`      // Code statically linked into the main executable, from its symbols
+	Modules       []string `json:"modules,omitempty"`        // Targets and Swift modules attributed to the dependency
+	Paths         []string `json:"paths,omitempty"`          // Frameworks and resource bundles
+}
+
+// AndroidDetails contains Android specific analysis results.
+type AndroidDetails struct {
+	Modules        []string             `json:"modules,omitempty"`         // App bundle module names
+	ModuleDetails  []ModuleInfo         `json:"module_details,omitempty"`  // App bundle modules with delivery and sizes
+	ModuleDelivery *ModuleDeliverySizes `json:"module_delivery,omitempty"` // App bundle module sizes by delivery type
+	SplitEstimates *SplitEstimate       `json:"split_estimates,omitempty"` // App bundle download size estimates
+	Manifest       *ManifestInfo        `json:"manifest,omitempty"`        // Base AndroidManifest.xml details
+	Signing        *APKSigningInfo      `json:"signing,omitempty"`         // APK signature schemes and signing block
+	MetaInf        []MetaInfGroup       `json:"meta_inf,omitempty"`        // META-INF/ files by kind
+	ZIPOverhead    *ZIPOverhead         `json:"zip_overhead,omitempty"`    // Archive bytes outside entry data
+	SDKs           []SDKSize            `json:"sdks,omitempty"`            // Sizes of known third-party libraries
+}
+
+// SDKSize contains the sizes attributed to one third-party library by its signatures.
+type SDKSize struct {
+	Name        string `json:"name"`                    // Library name, e.g. "OkHttp" or "Firebase"
+	Size        int64  `json:"size"`                    // Total size of the attributed classes and files
+	DEXSize     int64  `json:"dex_size,omitempty"`      // Classes matched by package prefix
+	NativeSize  int64  `json:"native_size,omitempty"`   // Native libraries
+	AssetSize   int64  `json:"asset_size,omitempty"`    // Files under assets/
+	MetaInfSize int64  `json:"meta_inf_size,omitempty"` // META-INF/*.version files
+	Classes     int    `json:"classes,omitempty"`       // Attributed DEX classes
+	Files       int    `json:"files,omitempty"`         // Attributed native libraries, assets and META-INF files
+}
+
+// APKSigningInfo describes the signature schemes of an APK and its APK Signing Block.
+type APKSigningInfo struct {
+	Schemes   []string            `json:"schemes"`              // v1 (JAR), v2, v3, v3.1 and v4 (.idsig file)
+	BlockSize int64               `json:"block_size,omitempty"` // Signing block between the last entry and the central directory
+	Blocks    []SigningBlockEntry `json:"blocks,omitempty"`
+}
+
+// SigningBlockEntry is an ID-value pair of the APK Signing Block.
+type SigningBlockEntry struct {
+	ID   string `json:"id"`   // Hexadecimal block ID, e.g. 0x7109871a
+	Name string `json:"name"` // e.g. "v2 signature", "verity padding" or "unknown"
+	Size int64  `json:"size"`
+}
+
+// MetaInfGroup sums the META-INF/ files of one kind.
+type MetaInfGroup struct {
+	Kind           string `json:"kind"` // v1-signature, version, kotlin-module, service or other
+	Files          int    `json:"files"`
+	Size           int64  `json:"size"`
+	CompressedSize int64  `json:"compressed_size"`
+}
+
+// ZIPOverhead contains the archive bytes that hold no entry data.
+type ZIPOverhead struct {
+	LocalHeaders     int64 `json:"local_headers"`     // Local file headers, data descriptors and alignment padding
+	CentralDirectory int64 `json:"central_directory"` // Central directory and end of central directory record
+}
+
+// ManifestInfo contains the SDK levels, permissions, components and application flags
+// declared in an AndroidManifest.xml.
+type ManifestInfo struct {
+	MinSDK               int                 `json:"min_sdk,omitempty"`
+	TargetSDK            int                 `json:"target_sdk,omitempty"`
+	CompileSDK           int                 `json:"compile_sdk,omitempty"`
+	Permissions          []string            `json:"permissions,omitempty"`        // uses-permission names, sorted
+	CustomPermissions    []string            `json:"custom_permissions,omitempty"` // Permissions the app defines, sorted
+	Components           []ManifestComponent `json:"components,omitempty"`
+	MetaDataKeys         []string            `json:"meta_data_keys,omitempty"` // Application meta-data names, sorted
+	Debuggable           bool                `json:"debuggable"`
+	ExtractNativeLibs    *bool               `json:"extract_native_libs,omitempty"`    // Unset uses the platform default
+	UsesCleartextTraffic *bool               `json:"uses_cleartext_traffic,omitempty"` // Unset uses the platform default
+}
+
+// ManifestComponent is an activity, service, broadcast receiver or content provider.
+type ManifestComponent struct {
+	Type            string `json:"type"` // activity, service, receiver or provider
+	Name            string `json:"name"` // Fully qualified class name
+	Exported        bool   `json:"exported"`
+	HasIntentFilter bool   `json:"has_intent_filter,omitempty"`
+}
+
+// CIInfo contains Git information of the CI build that produced the artifact.
+type CIInfo struct {
+	Branch     string `json:"branch,omitempty"`
+	CommitHash string `json:"commit_hash,omitempty"`
 }
 
 // BinaryInfo contains parsed Mach-O metadata (iOS binaries).
@@ -97,6 +293,100 @@ type BinaryInfo struct {
 	RPaths           []string `json:"rpaths,omitempty"`
 	HasDebugSymbols  bool     `json:"has_debug_symbols"`
 	DebugSymbolsSize int64    `json:"debug_symbols_size,omitempty"`
+
+	RuntimeMetadata *RuntimeMetadata `json:"runtime_metadata,omitempty"` // Swift and Objective-C metadata
+	Strings         *StringLiterals  `json:"strings,omitempty"`          // C strings, selectors and Swift field names
+
+	// Fat binaries: the fields above describe the first device slice
+	Platform              string        `json:"platform,omitempty"`                // From LC_BUILD_VERSION, e.g. ios or ios-simulator
+	Slices                []BinarySlice `json:"slices,omitempty"`                  // All slices of a fat binary
+	SimulatorSliceSavings int64         `json:"simulator_slice_savings,omitempty"` // Bytes saved by removing simulator slices with lipo
+
+	// Payload besides code and data, over all slices
+	BitcodeSize       int64 `json:"bitcode_size,omitempty"`        // Embedded bitcode (__LLVM segment) of device slices
+	CodeSignatureSize int64 `json:"code_signature_size,omitempty"` // LC_CODE_SIGNATURE data
+	IsEncrypted       bool  `json:"is_encrypted,omitempty"`        // Encrypted by the App Store (cryptid 1); section data is unreliable
+}
+
+// BinarySlice contains the metadata of one architecture slice of a fat Mach-O binary.
+type BinarySlice struct {
+	Architecture    string   `json:"architecture"`
+	Platform        string   `json:"platform,omitempty"`
+	IsSimulator     bool     `json:"is_simulator,omitempty"`
+	Size            int64    `json:"size"` // Bytes of the slice in the file
+	CodeSize        int64    `json:"code_size"`
+	DataSize        int64    `json:"data_size"`
+	LinkedLibraries []string `json:"linked_libraries"`
+	HasDebugSymbols bool     `json:"has_debug_symbols"`
+}
+
+// RuntimeMetadata contains the Swift and Objective-C runtime metadata of a Mach-O binary.
+type RuntimeMetadata struct {
+	SwiftTypes        int   `json:"swift_types"`
+	SwiftProtocols    int   `json:"swift_protocols"`
+	SwiftConformances int   `json:"swift_conformances"`
+	ObjCClasses       int   `json:"objc_classes"`
+	ObjCCategories    int   `json:"objc_categories"`
+	ObjCProtocols     int   `json:"objc_protocols"`
+	ObjCSelectors     int   `json:"objc_selectors"`      // Unique method names
+	SwiftMetadataSize int64 `json:"swift_metadata_size"` // All __swift5_* sections
+	ReflectionSize    int64 `json:"reflection_size"`     // Sections dropped by -disable-reflection-metadata
+	ObjCMetadataSize  int64 `json:"objc_metadata_size"`  // All __objc_* sections
+
+	Modules      []RuntimeModule `json:"modules,omitempty"`       // Largest reflection size first
+	LargestTypes []RuntimeType   `json:"largest_types,omitempty"` // Swift types with the most reflection metadata
+}
+
+// RuntimeModule contains the runtime metadata of one Swift module in a binary.
+// Objective-C classes without a Swift module are counted for the binary's name.
+type RuntimeModule struct {
+	Name              string `json:"name"`
+	SwiftTypes        int    `json:"swift_types"`
+	SwiftProtocols    int    `json:"swift_protocols"`
+	SwiftConformances int    `json:"swift_conformances"`
+	ObjCClasses       int    `json:"objc_classes"`
+	ReflectionSize    int64  `json:"reflection_size"` // Field descriptors and field names
+}
+
+// RuntimeType contains the reflection metadata of a single Swift type.
+type RuntimeType struct {
+	Name           string `json:"name"`
+	Module         string `json:"module"`
+	Kind           string `json:"kind"` // class, struct or enum
+	Fields         int    `json:"fields"`
+	ReflectionSize int64  `json:"reflection_size"` // Field descriptor and field names
+}
+
+// StringLiterals contains the string literals of a Mach-O binary: C strings, os_log
+// formats, Objective-C method names and Swift reflection field names.
+type StringLiterals struct {
+	Count      int              `json:"count"`
+	TotalSize  int64            `json:"total_size"` // Including NUL terminators
+	Sections   []StringSection  `json:"sections"`
+	Categories []StringCategory `json:"categories,omitempty"`  // Heuristic categories of C strings and os_log formats, largest first
+	Largest    []StringLiteral  `json:"largest,omitempty"`     // Largest literals first
+	BuildPaths []string         `json:"build_paths,omitempty"` // Absolute paths of the build machine, e.g. from #file or __FILE__
+}
+
+// StringSection contains the string literals of one section of a Mach-O binary.
+type StringSection struct {
+	Name  string `json:"name"` // __cstring, __oslogstring, __objc_methname or __swift5_reflstr
+	Count int    `json:"count"`
+	Size  int64  `json:"size"`
+}
+
+// StringCategory contains the string literals of a binary that match a category.
+type StringCategory struct {
+	Name  string `json:"name"` // url, build_path, log_format, sql or json
+	Count int    `json:"count"`
+	Size  int64  `json:"size"`
+}
+
+// StringLiteral is a single string literal of a Mach-O binary.
+type StringLiteral struct {
+	Value   string `json:"value"` // Truncated to 100 characters
+	Section string `json:"section"`
+	Size    int64  `json:"size"` // Including the NUL terminator
 }
 
 // FrameworkInfo contains metadata about an iOS framework.
@@ -162,3 +452,63 @@ type MergedDEXInfo struct {
 	TotalFileSize    int64      `json:"total_file_size"`
 	DEXFileCount     int        `json:"dex_file_count"`
 }
+
+// DeviceSpec describes a device for split APK size estimation.
+// JSON field names follow bundletool's device-spec format.
+type DeviceSpec struct {
+	Name             string   `json:"name,omitempty"`
+	SupportedABIs    []string `json:"supportedAbis"`
+	SupportedLocales []string `json:"supportedLocales"`
+	ScreenDensity    int      `json:"screenDensity"`
+	SDKVersion       int      `json:"sdkVersion"`
+}
+
+// SplitSize is the estimated compressed size of one split APK generated from an app bundle.
+type SplitSize struct {
+	Module string `json:"module"`
+	Type   string `json:"type"`           // "master", "abi", "density", "language"
+	Name   string `json:"name,omitempty"` // ABI, density bucket or language
+	Size   int64  `json:"size"`
+}
+
+// DeviceDownloadEstimate is the estimated download size of an app bundle for one device.
+type DeviceDownloadEstimate struct {
+	Device       DeviceSpec `json:"device"`
+	DownloadSize int64      `json:"download_size"`
+	MasterSize   int64      `json:"master_size"`
+	ABISize      int64      `json:"abi_size"`
+	DensitySize  int64      `json:"density_size"`
+	LanguageSize int64      `json:"language_size"`
+}
+
+// SplitEstimate contains bundletool-style split APK size estimates for an app bundle.
+type SplitEstimate struct {
+	Splits          []SplitSize              `json:"splits"`
+	Devices         []DeviceDownloadEstimate `json:"devices"`
+	MinDownloadSize int64                    `json:"min_download_size"`
+	MaxDownloadSize int64                    `json:"max_download_size"`
+}
+
+// ModuleInfo describes a module of an Android App Bundle.
+type ModuleInfo struct {
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`                 // "base", "feature" or "asset-pack"
+	Delivery       string   `json:"delivery"`             // "install-time", "conditional", "fast-follow" or "on-demand"
+	Fusing         bool     `json:"fusing"`               // Included in standalone APKs for pre-Lollipop devices
+	Conditions     []string `json:"conditions,omitempty"` // Conditional delivery requirements, e.g. "min-sdk 24"
+	Size           int64    `json:"size"`
+	CompressedSize int64    `json:"compressed_size"`
+	DEXSize        int64    `json:"dex_size"`
+	ResourcesSize  int64    `json:"resources_size"`
+	LibrariesSize  int64    `json:"libraries_size"`
+	AssetsSize     int64    `json:"assets_size"`
+	OtherSize      int64    `json:"other_size"`
+}
+
+// ModuleDeliverySizes sums the compressed size of app bundle modules by delivery type.
+type ModuleDeliverySizes struct {
+	InstallTime int64 `json:"install_time"`
+	Conditional int64 `json:"conditional"`
+	FastFollow  int64 `json:"fast_follow"`
+	OnDemand    int64 `json:"on_demand"`
+}